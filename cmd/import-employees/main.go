@@ -0,0 +1,73 @@
+// Command import-employees bulk creates employees from a CSV or XLSX file.
+//
+//	import-employees -company <id> -file staff.xlsx [-dry-run]
+//
+// Rows are validated first; if any fail, the per-row report is printed and
+// nothing is written.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/config"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/services"
+)
+
+func main() {
+	companyFlag := flag.String("company", "", "ID of the company to import into")
+	fileFlag := flag.String("file", "", "path to the CSV or XLSX file")
+	formatFlag := flag.String("format", "", "csv or xlsx (defaults to the file extension)")
+	dryRun := flag.Bool("dry-run", false, "validate the file without creating employees")
+	flag.Parse()
+
+	companyID, err := uuid.Parse(*companyFlag)
+	if err != nil || *fileFlag == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	format := *formatFlag
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*fileFlag)), ".")
+	}
+
+	data, err := os.ReadFile(*fileFlag)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *fileFlag, err)
+	}
+
+	pool, err := config.InitDB()
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer pool.Close()
+
+	importService := services.NewEmployeeImportService(repositories.NewEmployeeImportRepository(pool))
+	report, err := importService.Import(context.Background(), companyID, nil, format, data, *dryRun)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Printf("%d rows checked, %d problems found; no employees were created\n", report.TotalRows, len(report.Errors))
+		for _, e := range report.Errors {
+			fmt.Printf("  row %d, %s: %s\n", e.Row, e.Field, e.Message)
+		}
+		os.Exit(1)
+	}
+
+	if report.DryRun {
+		fmt.Printf("✓ %d rows are valid\n", report.TotalRows)
+		return
+	}
+	fmt.Printf("✓ Imported %d employees\n", len(report.Employees))
+}
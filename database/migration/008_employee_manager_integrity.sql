@@ -0,0 +1,37 @@
+ALTER TABLE employees
+    ADD CONSTRAINT employees_not_own_manager CHECK (manager_id IS NULL OR manager_id <> id);
+
+-- Guards every manager change, whichever code path performs it: the manager
+-- must exist in the same company and must not sit below the employee.
+CREATE OR REPLACE FUNCTION check_employee_manager()
+RETURNS TRIGGER AS $$
+DECLARE
+    manager_company UUID;
+    creates_cycle BOOLEAN;
+BEGIN
+    IF NEW.manager_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT company_id INTO manager_company FROM employees WHERE id = NEW.manager_id;
+    IF manager_company IS DISTINCT FROM NEW.company_id THEN
+        RAISE EXCEPTION 'manager % does not belong to company %', NEW.manager_id, NEW.company_id;
+    END IF;
+
+    WITH RECURSIVE chain AS (
+        SELECT id, manager_id FROM employees WHERE id = NEW.manager_id
+        UNION
+        SELECT e.id, e.manager_id FROM employees e JOIN chain c ON e.id = c.manager_id
+    )
+    SELECT EXISTS (SELECT 1 FROM chain WHERE id = NEW.id) INTO creates_cycle;
+
+    IF creates_cycle THEN
+        RAISE EXCEPTION 'assigning manager % to employee % creates a reporting cycle', NEW.manager_id, NEW.id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER check_employees_manager BEFORE INSERT OR UPDATE OF manager_id, company_id ON employees
+    FOR EACH ROW EXECUTE FUNCTION check_employee_manager();
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS employee_status_transitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    employee_id UUID NOT NULL,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL CHECK (to_status IN ('active', 'inactive', 'on_leave', 'terminated', 'probation')),
+    effective_date DATE NOT NULL,
+    termination_date DATE,
+    reason TEXT,
+    status VARCHAR(50) DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'applied', 'cancelled', 'failed')),
+    requested_by UUID,
+    applied_at TIMESTAMP WITH TIME ZONE,
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+    FOREIGN KEY (requested_by) REFERENCES employees(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_status_transitions_employee ON employee_status_transitions(employee_id);
+CREATE INDEX idx_status_transitions_due ON employee_status_transitions(effective_date) WHERE status = 'scheduled';
+
+CREATE TRIGGER update_employee_status_transitions_updated_at BEFORE UPDATE ON employee_status_transitions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
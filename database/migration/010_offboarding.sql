@@ -0,0 +1,48 @@
+-- Tokens issued before this instant are no longer accepted for the employee.
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS sessions_revoked_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS offboarding_checklists (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    employee_id UUID NOT NULL,
+    transition_id UUID,
+    termination_date DATE NOT NULL,
+    new_manager_id UUID,
+    hod_successor_id UUID,
+    approver_successor_id UUID,
+    status VARCHAR(50) DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'in_progress', 'completed')),
+    initiated_by UUID,
+    automation_completed_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+    FOREIGN KEY (transition_id) REFERENCES employee_status_transitions(id) ON DELETE SET NULL,
+    FOREIGN KEY (new_manager_id) REFERENCES employees(id) ON DELETE SET NULL,
+    FOREIGN KEY (hod_successor_id) REFERENCES employees(id) ON DELETE SET NULL,
+    FOREIGN KEY (approver_successor_id) REFERENCES employees(id) ON DELETE SET NULL,
+    FOREIGN KEY (initiated_by) REFERENCES employees(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_offboarding_checklists_employee ON offboarding_checklists(employee_id);
+CREATE INDEX idx_offboarding_checklists_status ON offboarding_checklists(status);
+
+CREATE TABLE IF NOT EXISTS offboarding_checklist_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    checklist_id UUID NOT NULL,
+    code VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    is_automated BOOLEAN DEFAULT false,
+    status VARCHAR(50) DEFAULT 'pending' CHECK (status IN ('pending', 'completed', 'skipped')),
+    notes TEXT,
+    completed_by UUID,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (checklist_id) REFERENCES offboarding_checklists(id) ON DELETE CASCADE,
+    FOREIGN KEY (completed_by) REFERENCES employees(id) ON DELETE SET NULL,
+    UNIQUE (checklist_id, code)
+);
+
+CREATE TRIGGER update_offboarding_checklists_updated_at BEFORE UPDATE ON offboarding_checklists
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,69 @@
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS default_probation_days INTEGER NOT NULL DEFAULT 90 CHECK (default_probation_days >= 0);
+ALTER TABLE levels ADD COLUMN IF NOT EXISTS probation_days INTEGER CHECK (probation_days >= 0); -- Overrides the company default
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS probation_end_date DATE;
+
+CREATE INDEX idx_employees_probation_end ON employees(probation_end_date) WHERE status = 'probation';
+
+-- Employees entering probation without an explicit end date get one from
+-- their level, falling back to the company default.
+CREATE OR REPLACE FUNCTION set_probation_end_date()
+RETURNS TRIGGER AS $$
+DECLARE
+    days INTEGER;
+BEGIN
+    IF NEW.status <> 'probation' OR NEW.probation_end_date IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' AND OLD.status = 'probation' THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT COALESCE(l.probation_days, c.default_probation_days) INTO days
+    FROM companies c
+    LEFT JOIN levels l ON l.id = NEW.level_id
+    WHERE c.id = NEW.company_id;
+
+    NEW.probation_end_date := NEW.hire_date + COALESCE(days, 90);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_employees_probation_end_date BEFORE INSERT OR UPDATE OF status ON employees
+    FOR EACH ROW EXECUTE FUNCTION set_probation_end_date();
+
+CREATE TABLE IF NOT EXISTS probation_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    employee_id UUID NOT NULL,
+    decision VARCHAR(50) NOT NULL CHECK (decision IN ('confirmed', 'extended', 'terminated')),
+    previous_end_date DATE,
+    new_end_date DATE,
+    comments TEXT NOT NULL,
+    decided_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+    FOREIGN KEY (decided_by) REFERENCES employees(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_probation_reviews_employee ON probation_reviews(employee_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    recipient_id UUID NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT,
+    entity_type VARCHAR(100),
+    entity_id UUID,
+    dedupe_key VARCHAR(255), -- Prevents a job from sending the same notice twice
+    is_read BOOLEAN DEFAULT false,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (recipient_id) REFERENCES employees(id) ON DELETE CASCADE,
+    UNIQUE (recipient_id, dedupe_key)
+);
+
+CREATE INDEX idx_notifications_recipient ON notifications(recipient_id, is_read);
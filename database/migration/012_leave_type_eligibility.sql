@@ -0,0 +1,51 @@
+-- Eligibility rules: an empty array means every employment type / gender.
+ALTER TABLE leave_types
+    ADD COLUMN IF NOT EXISTS eligible_employment_types TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS eligible_genders TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS min_tenure_days INTEGER NOT NULL DEFAULT 0 CHECK (min_tenure_days >= 0),
+    ADD COLUMN IF NOT EXISTS allowed_during_probation BOOLEAN NOT NULL DEFAULT true;
+
+ALTER TABLE leave_types
+    ADD CONSTRAINT leave_types_status_check CHECK (status IN ('active', 'inactive')),
+    ADD CONSTRAINT leave_types_days_check CHECK (days_allowed >= 0 AND max_carry_forward_days >= 0);
+
+CREATE UNIQUE INDEX idx_leave_types_company_code ON leave_types(company_id, code) WHERE code IS NOT NULL;
+
+-- Every company starts with a standard set of leave types it can then edit.
+CREATE OR REPLACE FUNCTION seed_default_leave_types(target_company_id UUID)
+RETURNS VOID AS $$
+BEGIN
+    INSERT INTO leave_types (
+        company_id, name, code, description, days_allowed, is_paid, requires_documentation,
+        carry_forward_allowed, max_carry_forward_days, color_code,
+        eligible_employment_types, eligible_genders, min_tenure_days, allowed_during_probation
+    )
+    VALUES
+        (target_company_id, 'Annual Leave', 'ANNUAL', 'Paid time off for rest and personal matters', 20, true, false,
+            true, 5, '#3498db', '{full_time,part_time,contract}', '{}', 0, false),
+        (target_company_id, 'Sick Leave', 'SICK', 'Time off for illness or injury', 10, true, true,
+            false, 0, '#e74c3c', '{}', '{}', 0, true),
+        (target_company_id, 'Maternity Leave', 'MATERNITY', 'Leave before and after childbirth', 90, true, true,
+            false, 0, '#9b59b6', '{full_time,part_time}', '{female}', 0, true),
+        (target_company_id, 'Paternity Leave', 'PATERNITY', 'Leave following the birth or adoption of a child', 10, true, false,
+            false, 0, '#1abc9c', '{full_time,part_time}', '{male}', 0, true),
+        (target_company_id, 'Compassionate Leave', 'COMPASSIONATE', 'Leave following the death or serious illness of a family member', 5, true, false,
+            false, 0, '#7f8c8d', '{}', '{}', 0, true),
+        (target_company_id, 'Unpaid Leave', 'UNPAID', 'Leave without pay', 30, false, false,
+            false, 0, '#95a5a6', '{full_time,part_time,contract}', '{}', 180, false)
+    ON CONFLICT (company_id, name) DO NOTHING;
+END;
+$$ language 'plpgsql';
+
+CREATE OR REPLACE FUNCTION seed_company_leave_types()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM seed_default_leave_types(NEW.id);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER seed_companies_leave_types AFTER INSERT ON companies
+    FOR EACH ROW EXECUTE FUNCTION seed_company_leave_types();
+
+SELECT seed_default_leave_types(id) FROM companies;
@@ -0,0 +1,27 @@
+-- A half day at the start means the leave begins after midday; at the end,
+-- that it finishes at midday. A one-day request uses either flag.
+ALTER TABLE leave_requests
+    ADD COLUMN IF NOT EXISTS start_half_day BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS end_half_day BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE leave_requests
+    ADD CONSTRAINT leave_requests_days_check CHECK (days_requested > 0);
+
+ALTER TABLE leave_balances
+    ADD CONSTRAINT leave_balances_non_negative CHECK (used_days >= 0 AND pending_days >= 0);
+
+CREATE INDEX idx_leave_requests_employee_dates ON leave_requests(employee_id, start_date, end_date)
+    WHERE status IN ('pending', 'approved');
+
+CREATE TABLE IF NOT EXISTS company_holidays (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    holiday_date DATE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    UNIQUE (company_id, holiday_date, name)
+);
+
+CREATE INDEX idx_company_holidays_date ON company_holidays(company_id, holiday_date);
@@ -0,0 +1,5 @@
+-- Withdrawals and cancellations are recorded in approval_history next to
+-- approver decisions so the full life of a request is in one place.
+ALTER TABLE approval_history DROP CONSTRAINT IF EXISTS approval_history_action_check;
+ALTER TABLE approval_history ADD CONSTRAINT approval_history_action_check
+    CHECK (action IN ('approved', 'rejected', 'requested_changes', 'cancelled', 'withdrawn'));
@@ -0,0 +1,14 @@
+-- 'yearly' grants the full (prorated) entitlement when the year's balance is
+-- opened; 'monthly' credits a twelfth at the start of each month.
+ALTER TABLE leave_types
+    ADD COLUMN IF NOT EXISTS accrual_policy VARCHAR(20) NOT NULL DEFAULT 'yearly'
+        CHECK (accrual_policy IN ('yearly', 'monthly'));
+
+-- accrued_days is the part of total_days granted by accrual, so re-running the
+-- accrual job only credits the difference and leaves manual adjustments alone.
+ALTER TABLE leave_balances
+    ADD COLUMN IF NOT EXISTS accrued_days DECIMAL(6,1) NOT NULL DEFAULT 0;
+
+UPDATE leave_balances SET accrued_days = total_days WHERE accrued_days = 0;
+
+CREATE INDEX IF NOT EXISTS idx_leave_balances_year ON leave_balances(year, leave_type_id);
@@ -0,0 +1,56 @@
+-- A holiday calendar holds a work week and a set of holidays. Every company
+-- has a default calendar; departments can be assigned their own (for
+-- example an office in another country) and sub-departments inherit it.
+CREATE TABLE IF NOT EXISTS holiday_calendars (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    country VARCHAR(100),
+    work_days SMALLINT[] NOT NULL DEFAULT '{1,2,3,4,5}', -- 0 = Sunday ... 6 = Saturday
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    UNIQUE (company_id, name),
+    CONSTRAINT holiday_calendars_work_days_check
+        CHECK (cardinality(work_days) > 0 AND work_days <@ '{0,1,2,3,4,5,6}'::SMALLINT[])
+);
+
+CREATE UNIQUE INDEX idx_holiday_calendars_default ON holiday_calendars(company_id) WHERE is_default;
+
+ALTER TABLE departments
+    ADD COLUMN IF NOT EXISTS holiday_calendar_id UUID REFERENCES holiday_calendars(id) ON DELETE SET NULL;
+
+-- A recurring holiday repeats every year on the month and day of holiday_date.
+ALTER TABLE company_holidays
+    ADD COLUMN IF NOT EXISTS calendar_id UUID REFERENCES holiday_calendars(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS recurring BOOLEAN NOT NULL DEFAULT false;
+
+CREATE OR REPLACE FUNCTION create_default_holiday_calendar()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO holiday_calendars (company_id, name, country, is_default)
+    VALUES (NEW.id, 'Default', NEW.country, true)
+    ON CONFLICT DO NOTHING;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER create_companies_holiday_calendar AFTER INSERT ON companies
+    FOR EACH ROW EXECUTE FUNCTION create_default_holiday_calendar();
+
+INSERT INTO holiday_calendars (company_id, name, country, is_default)
+SELECT id, 'Default', country, true FROM companies
+ON CONFLICT DO NOTHING;
+
+UPDATE company_holidays h
+SET calendar_id = c.id
+FROM holiday_calendars c
+WHERE c.company_id = h.company_id AND c.is_default AND h.calendar_id IS NULL;
+
+ALTER TABLE company_holidays ALTER COLUMN calendar_id SET NOT NULL;
+ALTER TABLE company_holidays DROP CONSTRAINT IF EXISTS company_holidays_company_id_holiday_date_name_key;
+ALTER TABLE company_holidays ADD CONSTRAINT company_holidays_calendar_date_name_key UNIQUE (calendar_id, holiday_date, name);
+
+DROP INDEX IF EXISTS idx_company_holidays_date;
+CREATE INDEX idx_company_holidays_calendar ON company_holidays(calendar_id, holiday_date);
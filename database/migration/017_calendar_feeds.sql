@@ -0,0 +1,9 @@
+-- Private iCalendar feed tokens. Only a hash of the token is stored; the
+-- subscription URL is shown once when the token is created or rotated.
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    employee_id UUID PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    last_accessed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE
+);
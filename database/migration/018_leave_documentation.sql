@@ -0,0 +1,63 @@
+-- Supporting documentation (e.g. medical certificates) for leave types with
+-- requires_documentation. A request longer than documentation_after_days
+-- consecutive days (0: every request) needs an attachment, which may follow
+-- up to documentation_grace_days after the leave ends. When it never arrives
+-- the request is flagged, or with 'revert' rejected and its days returned.
+ALTER TABLE leave_types
+    ADD COLUMN IF NOT EXISTS documentation_after_days INTEGER NOT NULL DEFAULT 0 CHECK (documentation_after_days >= 0),
+    ADD COLUMN IF NOT EXISTS documentation_grace_days INTEGER NOT NULL DEFAULT 0 CHECK (documentation_grace_days >= 0),
+    ADD COLUMN IF NOT EXISTS documentation_overdue_action VARCHAR(20) NOT NULL DEFAULT 'flag'
+        CHECK (documentation_overdue_action IN ('flag', 'revert'));
+
+ALTER TABLE leave_requests
+    ADD COLUMN IF NOT EXISTS documentation_status VARCHAR(20) NOT NULL DEFAULT 'not_required'
+        CHECK (documentation_status IN ('not_required', 'provided', 'awaiting', 'overdue')),
+    ADD COLUMN IF NOT EXISTS documentation_due_date DATE;
+
+UPDATE leave_requests lr
+SET documentation_status = 'provided'
+FROM leave_types lt
+WHERE lt.id = lr.leave_type_id AND lt.requires_documentation AND lr.attachment_url IS NOT NULL;
+
+CREATE INDEX idx_leave_requests_documentation_due ON leave_requests(documentation_due_date)
+    WHERE documentation_status IN ('awaiting', 'overdue');
+
+-- Sick notes are customarily needed from the third day and accepted up to a
+-- week after returning.
+UPDATE leave_types
+SET documentation_after_days = 2, documentation_grace_days = 7
+WHERE code = 'SICK' AND requires_documentation;
+
+UPDATE leave_types
+SET documentation_grace_days = 30
+WHERE code = 'MATERNITY' AND requires_documentation;
+
+CREATE OR REPLACE FUNCTION seed_default_leave_types(target_company_id UUID)
+RETURNS VOID AS $$
+BEGIN
+    INSERT INTO leave_types (
+        company_id, name, code, description, days_allowed, is_paid, requires_documentation,
+        carry_forward_allowed, max_carry_forward_days, color_code,
+        eligible_employment_types, eligible_genders, min_tenure_days, allowed_during_probation,
+        documentation_after_days, documentation_grace_days
+    )
+    VALUES
+        (target_company_id, 'Annual Leave', 'ANNUAL', 'Paid time off for rest and personal matters', 20, true, false,
+            true, 5, '#3498db', '{full_time,part_time,contract}', '{}', 0, false, 0, 0),
+        (target_company_id, 'Sick Leave', 'SICK', 'Time off for illness or injury', 10, true, true,
+            false, 0, '#e74c3c', '{}', '{}', 0, true, 2, 7),
+        (target_company_id, 'Maternity Leave', 'MATERNITY', 'Leave before and after childbirth', 90, true, true,
+            false, 0, '#9b59b6', '{full_time,part_time}', '{female}', 0, true, 0, 30),
+        (target_company_id, 'Paternity Leave', 'PATERNITY', 'Leave following the birth or adoption of a child', 10, true, false,
+            false, 0, '#1abc9c', '{full_time,part_time}', '{male}', 0, true, 0, 0),
+        (target_company_id, 'Compassionate Leave', 'COMPASSIONATE', 'Leave following the death or serious illness of a family member', 5, true, false,
+            false, 0, '#7f8c8d', '{}', '{}', 0, true, 0, 0),
+        (target_company_id, 'Unpaid Leave', 'UNPAID', 'Leave without pay', 30, false, false,
+            false, 0, '#95a5a6', '{full_time,part_time,contract}', '{}', 180, false, 0, 0)
+    ON CONFLICT (company_id, name) DO NOTHING;
+END;
+$$ language 'plpgsql';
+
+-- Automatic actions, such as reverting an approval whose documentation never
+-- arrived, are recorded without an approver.
+ALTER TABLE approval_history ALTER COLUMN approver_id DROP NOT NULL;
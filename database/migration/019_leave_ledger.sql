@@ -0,0 +1,50 @@
+-- Every change to a leave balance is recorded as a ledger entry holding the
+-- change to each running total, so a balance can be explained (and
+-- recomputed) from its history. Entries are never updated or deleted, except
+-- through the cascades of their balance, request or actor being removed.
+CREATE TABLE IF NOT EXISTS leave_ledger_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    balance_id UUID NOT NULL,
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN (
+        'opening', 'accrual', 'reservation', 'consumption', 'release', 'carry_forward', 'adjustment'
+    )),
+    total_delta DECIMAL(6,1) NOT NULL DEFAULT 0,
+    used_delta DECIMAL(6,1) NOT NULL DEFAULT 0,
+    pending_delta DECIMAL(6,1) NOT NULL DEFAULT 0,
+    carried_forward_delta DECIMAL(6,1) NOT NULL DEFAULT 0,
+    leave_request_id UUID,
+    actor_id UUID,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (balance_id) REFERENCES leave_balances(id) ON DELETE CASCADE,
+    FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE SET NULL,
+    FOREIGN KEY (actor_id) REFERENCES employees(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_leave_ledger_balance ON leave_ledger_entries(balance_id, created_at);
+CREATE INDEX idx_leave_ledger_request ON leave_ledger_entries(leave_request_id) WHERE leave_request_id IS NOT NULL;
+
+-- Foreign key actions run as nested triggers, so pg_trigger_depth() tells
+-- them apart from direct statements.
+CREATE OR REPLACE FUNCTION prevent_leave_ledger_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF pg_trigger_depth() > 1 THEN
+        IF TG_OP = 'DELETE' THEN
+            RETURN OLD;
+        END IF;
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'leave_ledger_entries is append-only';
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER leave_ledger_entries_append_only BEFORE UPDATE OR DELETE ON leave_ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION prevent_leave_ledger_changes();
+
+-- Balances that predate the ledger start from an opening entry holding
+-- their current totals.
+INSERT INTO leave_ledger_entries (balance_id, entry_type, total_delta, used_delta, pending_delta, carried_forward_delta, reason, created_at)
+SELECT id, 'opening', total_days, used_days, pending_days, carried_forward_days, 'Balance before ledger', created_at
+FROM leave_balances
+WHERE total_days <> 0 OR used_days <> 0 OR pending_days <> 0 OR carried_forward_days <> 0;
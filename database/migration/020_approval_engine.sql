@@ -0,0 +1,61 @@
+-- Approval workflows. Each step of approval_workflows.steps names who
+-- approves it through approver_type:
+--   employee: approver_id
+--   role:     every employee holding role_id
+--   manager:  the requester's manager
+--   hod:      the head of the requester's department
+-- Steps without approver_type are read as 'employee' when approver_id is
+-- set, else 'role' when role_id is set, else 'manager'.
+ALTER TABLE approval_workflows
+    ADD COLUMN IF NOT EXISTS name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE approval_workflows SET name = initcap(workflow_type) || ' approval' WHERE name IS NULL;
+ALTER TABLE approval_workflows ALTER COLUMN name SET NOT NULL;
+
+-- Only the newest of any duplicate active workflows stays active.
+UPDATE approval_workflows w
+SET is_active = false
+WHERE w.is_active AND EXISTS (
+    SELECT 1 FROM approval_workflows o
+    WHERE o.is_active AND o.id <> w.id
+        AND o.company_id = w.company_id AND o.workflow_type = w.workflow_type
+        AND o.department_id IS NOT DISTINCT FROM w.department_id
+        AND (o.created_at, o.id) > (w.created_at, w.id)
+);
+
+-- At most one active workflow per type for the whole company and per
+-- department.
+CREATE UNIQUE INDEX idx_approval_workflows_active_company ON approval_workflows(company_id, workflow_type)
+    WHERE is_active AND department_id IS NULL;
+CREATE UNIQUE INDEX idx_approval_workflows_active_department ON approval_workflows(company_id, workflow_type, department_id)
+    WHERE is_active AND department_id IS NOT NULL;
+
+-- The progress of one entity (leave request, memo, ...) through a workflow.
+-- steps is a copy of the workflow's steps taken at submission so later edits
+-- to the workflow do not affect requests already under way.
+CREATE TABLE IF NOT EXISTS approval_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    workflow_id UUID,
+    requester_id UUID NOT NULL,
+    steps JSONB NOT NULL,
+    current_step INTEGER NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled', 'withdrawn')),
+    step_started_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entity_type, entity_id),
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (workflow_id) REFERENCES approval_workflows(id) ON DELETE SET NULL,
+    FOREIGN KEY (requester_id) REFERENCES employees(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_approval_requests_pending ON approval_requests(company_id, status) WHERE status = 'pending';
+
+ALTER TABLE approval_history DROP CONSTRAINT IF EXISTS approval_history_entity_type_check;
+ALTER TABLE approval_history ADD CONSTRAINT approval_history_entity_type_check
+    CHECK (entity_type IN ('leave_request', 'memo', 'expense_claim'));
@@ -0,0 +1,33 @@
+-- Approvers hand their approval authority to a delegate for a date range,
+-- for one workflow type or (workflow_type NULL) all of them. An on_leave_only
+-- delegation applies only on the days the delegator is on approved leave, so
+-- it can be set up once and left in place.
+CREATE TABLE IF NOT EXISTS approval_delegations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    delegator_id UUID NOT NULL,
+    delegate_id UUID NOT NULL,
+    workflow_type VARCHAR(50) CHECK (workflow_type IN ('leave', 'memo', 'expense')),
+    start_date DATE NOT NULL,
+    end_date DATE, -- Open-ended when NULL
+    on_leave_only BOOLEAN NOT NULL DEFAULT false,
+    reason TEXT,
+    created_by UUID,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (delegator_id <> delegate_id),
+    CHECK (end_date IS NULL OR end_date >= start_date),
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegator_id) REFERENCES employees(id) ON DELETE CASCADE,
+    FOREIGN KEY (delegate_id) REFERENCES employees(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES employees(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_approval_delegations_delegator ON approval_delegations(delegator_id, start_date)
+    WHERE revoked_at IS NULL;
+CREATE INDEX idx_approval_delegations_delegate ON approval_delegations(delegate_id)
+    WHERE revoked_at IS NULL;
+
+-- Decisions taken by a delegate name the approver they stood in for.
+ALTER TABLE approval_history
+    ADD COLUMN IF NOT EXISTS on_behalf_of UUID REFERENCES employees(id) ON DELETE SET NULL;
@@ -0,0 +1,13 @@
+-- Approval SLAs. A workflow step may carry, in approval_workflows.steps:
+--   sla_hours:           hours the approvers have to decide (0 = no SLA)
+--   remind_before_hours: how long before the deadline to remind them
+--                        (0 = a quarter of sla_hours, at least one hour)
+--   timeout_action:      escalate (default), approve or reject once the
+--                        deadline passes
+-- Escalation first adds the approvers' own managers to the step, then HR
+-- one further SLA period later.
+ALTER TABLE approval_requests
+    ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS escalation_level INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS escalated_to UUID[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,39 @@
+-- Approvers may send an item back to its requester for changes instead of
+-- deciding it. The item stays changes_requested, editable by the requester
+-- and still holding any leave days it reserved, until it is resubmitted or
+-- withdrawn. approval_requests.current_step keeps the step that asked for
+-- the changes while the item is away.
+ALTER TABLE leave_requests DROP CONSTRAINT IF EXISTS leave_requests_status_check;
+ALTER TABLE leave_requests ADD CONSTRAINT leave_requests_status_check
+    CHECK (status IN ('pending', 'changes_requested', 'approved', 'rejected', 'cancelled', 'withdrawn'));
+
+ALTER TABLE memos DROP CONSTRAINT IF EXISTS memos_status_check;
+ALTER TABLE memos ADD CONSTRAINT memos_status_check
+    CHECK (status IN ('draft', 'pending', 'changes_requested', 'approved', 'rejected', 'archived'));
+
+ALTER TABLE approval_requests DROP CONSTRAINT IF EXISTS approval_requests_status_check;
+ALTER TABLE approval_requests ADD CONSTRAINT approval_requests_status_check
+    CHECK (status IN ('pending', 'changes_requested', 'approved', 'rejected', 'cancelled', 'withdrawn'));
+
+ALTER TABLE approval_history DROP CONSTRAINT IF EXISTS approval_history_action_check;
+ALTER TABLE approval_history ADD CONSTRAINT approval_history_action_check
+    CHECK (action IN ('approved', 'rejected', 'requested_changes', 'resubmitted', 'cancelled', 'withdrawn'));
+
+DROP INDEX IF EXISTS idx_leave_requests_employee_dates;
+CREATE INDEX idx_leave_requests_employee_dates ON leave_requests(employee_id, start_date, end_date)
+    WHERE status IN ('pending', 'changes_requested', 'approved');
+
+-- What an item looked like each time it was put before approvers, so they
+-- can see what changed between rounds. data holds the item's reviewable
+-- fields as JSON; revision 1 is the original submission.
+CREATE TABLE IF NOT EXISTS approval_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type VARCHAR(50) NOT NULL CHECK (entity_type IN ('leave_request', 'memo', 'expense_claim')),
+    entity_id UUID NOT NULL,
+    revision INTEGER NOT NULL CHECK (revision > 0),
+    data JSONB NOT NULL,
+    submitted_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entity_type, entity_id, revision),
+    FOREIGN KEY (submitted_by) REFERENCES employees(id) ON DELETE SET NULL
+);
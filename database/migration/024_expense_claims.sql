@@ -0,0 +1,94 @@
+-- Categories expense claim items are filed under. Items of a category that
+-- requires receipts cannot be submitted without one.
+CREATE TABLE IF NOT EXISTS expense_categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    requires_receipt BOOLEAN NOT NULL DEFAULT true,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    UNIQUE (company_id, name)
+);
+
+-- How much employees at a level may claim, per claim or per calendar month.
+-- A limit without a category caps everything claimed; one with a category
+-- caps that category only.
+CREATE TABLE IF NOT EXISTS expense_limits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    level_id UUID NOT NULL,
+    category_id UUID,
+    period VARCHAR(20) NOT NULL CHECK (period IN ('claim', 'month')),
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (level_id) REFERENCES levels(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES expense_categories(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_expense_limits_unique
+    ON expense_limits(level_id, COALESCE(category_id, '00000000-0000-0000-0000-000000000000'::uuid), period);
+
+-- Approved claims paid out together. reference is unique per company, e.g.
+-- PAY/2026/0007.
+CREATE TABLE IF NOT EXISTS expense_payout_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    reference VARCHAR(50) NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    total_amount DECIMAL(14, 2) NOT NULL,
+    claim_count INTEGER NOT NULL,
+    created_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES employees(id) ON DELETE SET NULL,
+    UNIQUE (company_id, reference)
+);
+
+-- Claims are in the company's currency at the time they were created.
+-- total_amount is kept equal to the sum of the claim's items.
+CREATE TABLE IF NOT EXISTS expense_claims (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    employee_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    currency VARCHAR(10) NOT NULL,
+    total_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(50) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'pending', 'changes_requested', 'approved', 'rejected', 'withdrawn', 'reimbursed')),
+    current_step INTEGER NOT NULL DEFAULT 1,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    approved_by UUID,
+    approved_at TIMESTAMP WITH TIME ZONE,
+    rejection_reason TEXT,
+    payout_batch_id UUID,
+    reimbursed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+    FOREIGN KEY (approved_by) REFERENCES employees(id) ON DELETE SET NULL,
+    FOREIGN KEY (payout_batch_id) REFERENCES expense_payout_batches(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_expense_claims_employee ON expense_claims(employee_id, status);
+CREATE INDEX idx_expense_claims_company ON expense_claims(company_id, status);
+CREATE INDEX idx_expense_claims_payout ON expense_claims(payout_batch_id) WHERE payout_batch_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS expense_claim_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    claim_id UUID NOT NULL,
+    category_id UUID NOT NULL,
+    expense_date DATE NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    merchant VARCHAR(255),
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    receipt_url TEXT,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (claim_id) REFERENCES expense_claims(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES expense_categories(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_expense_claim_items_claim ON expense_claim_items(claim_id, position);
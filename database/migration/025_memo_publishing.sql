@@ -0,0 +1,29 @@
+-- Memos are written as drafts and published to their recipients. Publishing
+-- stamps the memo with a reference number and expands its targets into
+-- memo_recipients.
+ALTER TABLE memos DROP CONSTRAINT IF EXISTS memos_status_check;
+ALTER TABLE memos ADD CONSTRAINT memos_status_check
+    CHECK (status IN ('draft', 'pending', 'changes_requested', 'approved', 'rejected', 'published', 'archived'));
+ALTER TABLE memos ALTER COLUMN status SET DEFAULT 'draft';
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_memos_reference
+    ON memos(company_id, reference_number) WHERE reference_number IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_memo_recipients_employee ON memo_recipients(employee_id);
+
+-- How reference numbers are built, e.g. HR/{YYYY}/{SEQ} gives HR/2026/0042.
+-- {SEQ} counts the company's published memos within the year.
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS memo_reference_pattern VARCHAR(100) NOT NULL DEFAULT 'MEMO/{YYYY}/{SEQ}';
+
+-- Who a memo is addressed to. target_id is an employee, department (with
+-- its sub-departments), role or level; company targets have none.
+CREATE TABLE IF NOT EXISTS memo_targets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    memo_id UUID NOT NULL,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('individual', 'department', 'role', 'level', 'company')),
+    target_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (memo_id) REFERENCES memos(id) ON DELETE CASCADE,
+    CHECK ((target_type = 'company') = (target_id IS NULL))
+);
+CREATE INDEX idx_memo_targets_memo ON memo_targets(memo_id);
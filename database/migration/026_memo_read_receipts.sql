@@ -0,0 +1,10 @@
+-- Recipients mark memos read. Memos that require acknowledgement, which
+-- disciplinary memos always do, must also be explicitly confirmed by each
+-- recipient. Recipients of urgent memos are reminded until they have done
+-- so; reminder_count and last_reminded_at pace the reminders.
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS requires_acknowledgement BOOLEAN NOT NULL DEFAULT false;
+UPDATE memos SET requires_acknowledgement = true WHERE memo_type = 'disciplinary';
+
+ALTER TABLE memo_recipients ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE memo_recipients ADD COLUMN IF NOT EXISTS reminder_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE memo_recipients ADD COLUMN IF NOT EXISTS last_reminded_at TIMESTAMP WITH TIME ZONE;
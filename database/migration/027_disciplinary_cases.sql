@@ -0,0 +1,48 @@
+-- Disciplinary memos are about one employee, their subject, and are only
+-- visible to the subject, the subject's management chain, HR and the
+-- sender. They are kept out of the general memo list.
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS subject_employee_id UUID REFERENCES employees(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_memos_subject ON memos(subject_employee_id) WHERE subject_employee_id IS NOT NULL;
+
+-- A disciplinary matter concerning one employee, gathering the memos sent
+-- about it. outcome is recorded when the case is closed.
+CREATE TABLE IF NOT EXISTS disciplinary_cases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL,
+    employee_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    outcome VARCHAR(50) CHECK (outcome IN (
+        'no_action', 'verbal_warning', 'written_warning', 'final_warning', 'suspension', 'demotion', 'termination'
+    )),
+    outcome_notes TEXT,
+    opened_by UUID,
+    closed_by UUID,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+    FOREIGN KEY (opened_by) REFERENCES employees(id) ON DELETE SET NULL,
+    FOREIGN KEY (closed_by) REFERENCES employees(id) ON DELETE SET NULL,
+    CHECK ((status = 'closed') = (outcome IS NOT NULL))
+);
+CREATE INDEX idx_disciplinary_cases_employee ON disciplinary_cases(employee_id, status);
+CREATE INDEX idx_disciplinary_cases_company ON disciplinary_cases(company_id, status);
+
+ALTER TABLE memos ADD COLUMN IF NOT EXISTS case_id UUID REFERENCES disciplinary_cases(id) ON DELETE SET NULL;
+
+-- The thread under a disciplinary memo: the subject's responses and
+-- appeals, and replies from HR and management.
+CREATE TABLE IF NOT EXISTS memo_responses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    memo_id UUID NOT NULL,
+    author_id UUID,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('response', 'appeal', 'reply')),
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (memo_id) REFERENCES memos(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES employees(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_memo_responses_memo ON memo_responses(memo_id, created_at);
@@ -0,0 +1,35 @@
+-- Two concurrent manager changes (A under B, B under A) could each pass the
+-- cycle check before the other committed. Manager changes within a company
+-- now take a transaction-scoped advisory lock first, so each check sees the
+-- chain as the previous change left it.
+CREATE OR REPLACE FUNCTION check_employee_manager()
+RETURNS TRIGGER AS $$
+DECLARE
+    manager_company UUID;
+    creates_cycle BOOLEAN;
+BEGIN
+    IF NEW.manager_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    PERFORM pg_advisory_xact_lock(hashtext('employee_manager:' || NEW.company_id::text));
+
+    SELECT company_id INTO manager_company FROM employees WHERE id = NEW.manager_id;
+    IF manager_company IS DISTINCT FROM NEW.company_id THEN
+        RAISE EXCEPTION 'manager % does not belong to company %', NEW.manager_id, NEW.company_id;
+    END IF;
+
+    WITH RECURSIVE chain AS (
+        SELECT id, manager_id FROM employees WHERE id = NEW.manager_id
+        UNION
+        SELECT e.id, e.manager_id FROM employees e JOIN chain c ON e.id = c.manager_id
+    )
+    SELECT EXISTS (SELECT 1 FROM chain WHERE id = NEW.id) INTO creates_cycle;
+
+    IF creates_cycle THEN
+        RAISE EXCEPTION 'assigning manager % to employee % creates a reporting cycle', NEW.manager_id, NEW.id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
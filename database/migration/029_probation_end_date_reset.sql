@@ -0,0 +1,37 @@
+-- probation_end_date used to be set only while it was NULL, so an employee
+-- rehired on probation, or moved back onto it, kept the date from their
+-- earlier stint. It is now recomputed on every entry into probation unless
+-- the same change sets it explicitly, and cleared on termination.
+CREATE OR REPLACE FUNCTION set_probation_end_date()
+RETURNS TRIGGER AS $$
+DECLARE
+    days INTEGER;
+BEGIN
+    IF NEW.status = 'terminated' THEN
+        NEW.probation_end_date := NULL;
+        RETURN NEW;
+    END IF;
+    IF NEW.status <> 'probation' THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' AND OLD.status = 'probation' THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'INSERT' AND NEW.probation_end_date IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' AND NEW.probation_end_date IS DISTINCT FROM OLD.probation_end_date THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT COALESCE(l.probation_days, c.default_probation_days) INTO days
+    FROM companies c
+    LEFT JOIN levels l ON l.id = NEW.level_id
+    WHERE c.id = NEW.company_id;
+
+    NEW.probation_end_date := NEW.hire_date + COALESCE(days, 90);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+UPDATE employees SET probation_end_date = NULL WHERE status = 'terminated' AND probation_end_date IS NOT NULL;
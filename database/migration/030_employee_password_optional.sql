@@ -0,0 +1,5 @@
+-- Imported employees are created without a login credential until they set
+-- one up, so the hash is nullable rather than an empty string.
+ALTER TABLE employees ALTER COLUMN password_hash DROP NOT NULL;
+
+UPDATE employees SET password_hash = NULL WHERE password_hash = '';
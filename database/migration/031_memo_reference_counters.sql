@@ -0,0 +1,37 @@
+-- Memo reference sequences used to be the count of the year's published
+-- memos plus one, which repeats a number once a published memo is deleted.
+-- Each company now keeps a counter per year that only moves forward.
+CREATE TABLE IF NOT EXISTS memo_reference_counters (
+    company_id UUID NOT NULL,
+    year INT NOT NULL,
+    last_value INT NOT NULL,
+    PRIMARY KEY (company_id, year),
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE
+);
+
+-- Counters start from the highest sequence already issued, read back out of
+-- the reference numbers with the company's pattern rather than counted,
+-- since deleted memos leave gaps in the count. Where the pattern has changed
+-- since, the count of the year's memos is the floor.
+WITH patterns AS (
+    SELECT id AS company_id,
+        '^' || replace(replace(replace(replace(
+            regexp_replace(memo_reference_pattern, '([.^$*+?()\[\]{}|\\])', '\\\1', 'g'),
+            '\{SEQ\}', '(\d+)'),
+            '\{YYYY\}', '\d{4}'),
+            '\{YY\}', '\d{2}'),
+            '\{MM\}', '\d{2}') || '$' AS pattern
+    FROM companies
+),
+issued AS (
+    SELECT m.company_id, EXTRACT(YEAR FROM m.published_at)::INT AS year,
+        substring(m.reference_number FROM p.pattern)::INT AS sequence
+    FROM memos m
+    JOIN patterns p ON p.company_id = m.company_id
+    WHERE m.reference_number IS NOT NULL AND m.published_at IS NOT NULL
+)
+INSERT INTO memo_reference_counters (company_id, year, last_value)
+SELECT company_id, year, GREATEST(COALESCE(MAX(sequence), 0), COUNT(*))
+FROM issued
+GROUP BY company_id, year
+ON CONFLICT (company_id, year) DO NOTHING;
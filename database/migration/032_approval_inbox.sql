@@ -0,0 +1,91 @@
+-- The approval inbox is filtered in SQL rather than by resolving every
+-- pending request in the application. These functions are how both the
+-- inbox and the approval service resolve a step's approvers and an
+-- approver's delegate.
+
+-- approval_step_approvers returns who decides target_step of a request
+-- raised by target_requester_id, the requester excluded. An employee step
+-- whose approver has left falls back to its role; a head of department
+-- deciding on their own request passes it up to the next head.
+CREATE OR REPLACE FUNCTION approval_step_approvers(target_step JSONB, target_requester_id UUID)
+RETURNS SETOF UUID AS $$
+    WITH RECURSIVE requester AS (
+        SELECT id, company_id, manager_id, department_id
+        FROM employees
+        WHERE id = target_requester_id
+    ),
+    kind AS (
+        SELECT CASE
+            WHEN COALESCE(target_step->>'approver_type', '') <> '' THEN target_step->>'approver_type'
+            WHEN target_step->>'approver_id' IS NOT NULL THEN 'employee'
+            WHEN target_step->>'role_id' IS NOT NULL THEN 'role'
+            ELSE 'manager'
+        END AS approver_type
+        WHERE target_step IS NOT NULL
+    ),
+    named AS (
+        SELECT e.id
+        FROM employees e, requester q, kind k
+        WHERE k.approver_type = 'employee' AND e.id = (target_step->>'approver_id')::uuid
+            AND e.company_id = q.company_id AND e.status <> 'terminated'
+    ),
+    chain AS (
+        SELECT d.id, d.parent_department_id, d.hod_id, 0 AS depth
+        FROM departments d, requester q, kind k
+        WHERE k.approver_type = 'hod' AND d.id = q.department_id
+        UNION ALL
+        SELECT d.id, d.parent_department_id, d.hod_id, c.depth + 1
+        FROM departments d
+        JOIN chain c ON d.id = c.parent_department_id
+        WHERE c.depth < 20
+    ),
+    approvers AS (
+        SELECT id FROM named
+        UNION ALL
+        SELECT e.id
+        FROM employees e, requester q, kind k
+        WHERE (k.approver_type = 'role' OR (k.approver_type = 'employee' AND NOT EXISTS (SELECT 1 FROM named)))
+            AND e.company_id = q.company_id AND e.role_id = (target_step->>'role_id')::uuid
+            AND e.status <> 'terminated'
+        UNION ALL
+        SELECT e.id
+        FROM employees e, requester q, kind k
+        WHERE k.approver_type = 'manager' AND e.id = q.manager_id
+            AND e.company_id = q.company_id AND e.status <> 'terminated'
+        UNION ALL
+        (
+            SELECT c.hod_id
+            FROM chain c
+            JOIN employees e ON e.id = c.hod_id AND e.status <> 'terminated'
+            WHERE c.hod_id <> target_requester_id
+            ORDER BY c.depth
+            LIMIT 1
+        )
+    )
+    SELECT id FROM approvers WHERE id <> target_requester_id;
+$$ LANGUAGE sql STABLE;
+
+-- approval_active_delegate returns who holds target_delegator_id's authority
+-- over target_workflow_type on target_day, or NULL. Date-bound delegations
+-- win over on-leave-only ones, and delegations for the workflow type over
+-- those covering all types.
+CREATE OR REPLACE FUNCTION approval_active_delegate(
+    target_delegator_id UUID,
+    target_workflow_type VARCHAR,
+    target_day DATE
+)
+RETURNS UUID AS $$
+    SELECT d.delegate_id
+    FROM approval_delegations d
+    JOIN employees e ON e.id = d.delegate_id AND e.status <> 'terminated'
+    WHERE d.delegator_id = target_delegator_id AND d.revoked_at IS NULL
+        AND (d.workflow_type IS NULL OR d.workflow_type = target_workflow_type)
+        AND d.start_date <= target_day AND (d.end_date IS NULL OR d.end_date >= target_day)
+        AND (NOT d.on_leave_only OR EXISTS (
+            SELECT 1 FROM leave_requests lr
+            WHERE lr.employee_id = d.delegator_id AND lr.status = 'approved'
+                AND lr.start_date <= target_day AND lr.end_date >= target_day
+        ))
+    ORDER BY d.on_leave_only, d.workflow_type NULLS LAST, d.created_at DESC
+    LIMIT 1;
+$$ LANGUAGE sql STABLE;
@@ -0,0 +1,6 @@
+-- Nothing validates login tokens against employees.sessions_revoked_at yet,
+-- so revoking sessions is left to HR as a manual checklist item. Calendar
+-- feed tokens are still revoked automatically under their own item.
+UPDATE offboarding_checklist_items
+SET is_automated = false
+WHERE code = 'revoke_sessions' AND status = 'pending';
@@ -0,0 +1,194 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// ApprovalStepRequest is one step of a workflow. Steps are numbered in the
+// order given.
+type ApprovalStepRequest struct {
+	Name         string `json:"name" validate:"omitempty,max=100"`
+	ApproverType string `json:"approver_type" validate:"required,oneof=employee role manager hod"`
+	ApproverID   string `json:"approver_id" validate:"omitempty,uuid"` // Required for employee steps
+	RoleID       string `json:"role_id" validate:"omitempty,uuid"`     // Required for role steps; fallback for employee steps
+	// Deadline for the step; 0 has none.
+	SLAHours          int    `json:"sla_hours" validate:"gte=0"`
+	RemindBeforeHours int    `json:"remind_before_hours" validate:"gte=0"`                              // 0 reminds a quarter of the SLA before the deadline
+	TimeoutAction     string `json:"timeout_action" validate:"omitempty,oneof=escalate approve reject"` // Defaults to escalate
+}
+
+type CreateApprovalWorkflowRequest struct {
+	Name         string                `json:"name" validate:"required,max=100"`
+	WorkflowType string                `json:"workflow_type" validate:"required,oneof=leave memo expense"`
+	DepartmentID string                `json:"department_id" validate:"omitempty,uuid"` // Empty applies company-wide
+	Steps        []ApprovalStepRequest `json:"steps" validate:"required,min=1,dive"`
+	IsActive     *bool                 `json:"is_active" validate:"omitempty"` // Defaults to true
+}
+
+type UpdateApprovalWorkflowRequest struct {
+	Name         *string                `json:"name" validate:"omitempty,max=100"`
+	DepartmentID *string                `json:"department_id" validate:"omitempty"` // "" makes the workflow company-wide
+	Steps        *[]ApprovalStepRequest `json:"steps" validate:"omitempty,min=1,dive"`
+	IsActive     *bool                  `json:"is_active" validate:"omitempty"`
+}
+
+type ApprovalStepResponse struct {
+	Step         int     `json:"step"`
+	Name         string  `json:"name,omitempty"`
+	ApproverType string  `json:"approver_type"`
+	ApproverID   *string `json:"approver_id"`
+	RoleID       *string `json:"role_id"`
+
+	SLAHours          int    `json:"sla_hours"`
+	RemindBeforeHours int    `json:"remind_before_hours"`
+	TimeoutAction     string `json:"timeout_action"`
+}
+
+type ApprovalWorkflowResponse struct {
+	ID           string                  `json:"id"`
+	CompanyID    string                  `json:"company_id"`
+	Name         string                  `json:"name"`
+	WorkflowType string                  `json:"workflow_type"`
+	DepartmentID *string                 `json:"department_id"`
+	Steps        []*ApprovalStepResponse `json:"steps"`
+	IsActive     bool                    `json:"is_active"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+// ApprovalStatusResponse shows where an entity is in its workflow and who
+// can act on it next.
+type ApprovalStatusResponse struct {
+	EntityType       string                  `json:"entity_type"`
+	EntityID         string                  `json:"entity_id"`
+	WorkflowID       *string                 `json:"workflow_id"`
+	Status           string                  `json:"status"`
+	CurrentStep      int                     `json:"current_step"`
+	Steps            []*ApprovalStepResponse `json:"steps"`
+	CurrentApprovers []string                `json:"current_approvers"` // Empty once decided
+	StepStartedAt    time.Time               `json:"step_started_at"`
+}
+
+type CreateApprovalDelegationRequest struct {
+	DelegateID   string `json:"delegate_id" validate:"required,uuid"`
+	WorkflowType string `json:"workflow_type" validate:"omitempty,oneof=leave memo expense"` // Empty covers all
+	StartDate    string `json:"start_date" validate:"required"`                              // YYYY-MM-DD
+	EndDate      string `json:"end_date" validate:"omitempty"`                               // YYYY-MM-DD; empty is open-ended
+	OnLeaveOnly  bool   `json:"on_leave_only" validate:"omitempty"`                          // Apply only while on approved leave
+	Reason       string `json:"reason" validate:"omitempty"`
+}
+
+type ApprovalDelegationResponse struct {
+	ID           string     `json:"id"`
+	DelegatorID  string     `json:"delegator_id"`
+	DelegateID   string     `json:"delegate_id"`
+	WorkflowType string     `json:"workflow_type,omitempty"`
+	StartDate    time.Time  `json:"start_date"`
+	EndDate      *time.Time `json:"end_date"`
+	OnLeaveOnly  bool       `json:"on_leave_only"`
+	Reason       string     `json:"reason,omitempty"`
+	CreatedBy    *string    `json:"created_by"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type OverdueApprovalResponse struct {
+	EntityType      string    `json:"entity_type"`
+	EntityID        string    `json:"entity_id"`
+	Title           string    `json:"title"`
+	Step            int       `json:"step"`
+	StepName        string    `json:"step_name,omitempty"`
+	DueAt           time.Time `json:"due_at"`
+	HoursOverdue    int       `json:"hours_overdue"`
+	EscalationLevel int       `json:"escalation_level"` // 0 = not escalated, 1 = approvers' managers, 2 = HR
+}
+
+// ApproverOverdueResponse is one approver's overdue items.
+type ApproverOverdueResponse struct {
+	ApproverID   string                     `json:"approver_id"`
+	ApproverName string                     `json:"approver_name"`
+	OverdueCount int                        `json:"overdue_count"`
+	Items        []*OverdueApprovalResponse `json:"items"`
+}
+
+// ApprovalRevisionResponse is an entity as it was put before approvers, with
+// what changed since the previous revision.
+type ApprovalRevisionResponse struct {
+	Revision        int                       `json:"revision"` // 1 for the original submission
+	Data            map[string]any            `json:"data"`
+	Changes         []*RevisionChangeResponse `json:"changes"` // Empty for the first revision
+	SubmittedBy     *string                   `json:"submitted_by"`
+	SubmittedByName string                    `json:"submitted_by_name,omitempty"`
+	CreatedAt       time.Time                 `json:"created_at"`
+}
+
+type RevisionChangeResponse struct {
+	Field string `json:"field"`
+	From  any    `json:"from"` // Null when the field was added
+	To    any    `json:"to"`   // Null when the field was removed
+}
+
+// ApprovalInboxRequest filters and pages an approver's inbox.
+type ApprovalInboxRequest struct {
+	utils.PaginationParams
+	Type        string `json:"type" validate:"omitempty,oneof=leave memo expense"` // Empty covers all
+	RequesterID string `json:"requester_id" validate:"omitempty,uuid"`
+	OverdueOnly bool   `json:"overdue_only" validate:"omitempty"`
+}
+
+// ApprovalInboxItemResponse is an item awaiting the caller's decision.
+type ApprovalInboxItemResponse struct {
+	EntityType    string     `json:"entity_type"`
+	EntityID      string     `json:"entity_id"`
+	Type          string     `json:"type"` // leave, memo, expense
+	Title         string     `json:"title"`
+	RequesterID   string     `json:"requester_id"`
+	RequesterName string     `json:"requester_name"`
+	Step          int        `json:"step"`
+	StepName      string     `json:"step_name,omitempty"`
+	TotalSteps    int        `json:"total_steps"`
+	WaitingSince  time.Time  `json:"waiting_since"`
+	DueAt         *time.Time `json:"due_at"` // Null when the step has no SLA
+	Overdue       bool       `json:"overdue"`
+	OnBehalfOf    *string    `json:"on_behalf_of"` // Set when the caller decides as a delegate
+}
+
+type ApprovalInboxResponse struct {
+	Counts     map[string]int               `json:"counts"` // Items by type, before filtering by type
+	Total      int64                        `json:"total"`  // Items across every page
+	Page       int                          `json:"page"`
+	PageSize   int                          `json:"page_size"`
+	TotalPages int                          `json:"total_pages"`
+	HasNext    bool                         `json:"has_next"`
+	HasPrev    bool                         `json:"has_prev"`
+	Items      []*ApprovalInboxItemResponse `json:"items"`
+}
+
+type BulkApprovalItemRequest struct {
+	EntityType string `json:"entity_type" validate:"required"`
+	EntityID   string `json:"entity_id" validate:"required,uuid"`
+}
+
+type BulkApprovalDecisionRequest struct {
+	Action   string                    `json:"action" validate:"required,oneof=approve reject"`
+	Comments string                    `json:"comments" validate:"omitempty"` // Required when rejecting
+	Items    []BulkApprovalItemRequest `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// BulkApprovalResultResponse is the outcome of one item of a bulk decision.
+type BulkApprovalResultResponse struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Success    bool   `json:"success"`
+	Status     string `json:"status,omitempty"`    // approved, rejected, or pending when moved on to NextStep
+	NextStep   int    `json:"next_step,omitempty"` // Set when approval moved the item on
+	Error      string `json:"error,omitempty"`
+}
+
+type BulkApprovalDecisionResponse struct {
+	Succeeded int                           `json:"succeeded"`
+	Failed    int                           `json:"failed"`
+	Results   []*BulkApprovalResultResponse `json:"results"`
+}
@@ -65,6 +65,7 @@ type EmployeeResponse struct {
 	DateOfBirth           *time.Time `json:"date_of_birth"`
 	HireDate              time.Time  `json:"hire_date"`
 	TerminationDate       *time.Time `json:"termination_date"`
+	ProbationEndDate      *time.Time `json:"probation_end_date"`
 	Gender                string     `json:"gender"`
 	Address               string     `json:"address"`
 	EmergencyContactName  string     `json:"emergency_contact_name"`
@@ -83,3 +84,19 @@ type EmployeeListRequest struct {
 	EmploymentType string `json:"employment_type" validate:"omitempty"` // Filter by employment type
 	Search         string `json:"search" validate:"omitempty"`          // Search in name/email
 }
+
+type ChangeManagerRequest struct {
+	ManagerID *string `json:"manager_id" validate:"omitempty,uuid"` // null removes the manager
+}
+
+type ReportingLineResponse struct {
+	EmployeeResponse
+	Depth int `json:"depth"` // 1 = direct report / direct manager
+}
+
+type SpanOfControlResponse struct {
+	ManagerID     string `json:"manager_id"`
+	DirectReports int    `json:"direct_reports"`
+	TotalReports  int    `json:"total_reports"`
+	Depth         int    `json:"depth"`
+}
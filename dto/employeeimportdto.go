@@ -0,0 +1,23 @@
+package dto
+
+type ImportRowError struct {
+	Row     int    `json:"row"` // Spreadsheet row number; the header is row 1
+	Field   string `json:"field"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportedEmployee struct {
+	Row          int    `json:"row"`
+	ID           string `json:"id,omitempty"` // Empty for dry runs
+	EmployeeCode string `json:"employee_code"`
+	Email        string `json:"email"`
+}
+
+type EmployeeImportReport struct {
+	TotalRows int                 `json:"total_rows"`
+	Committed bool                `json:"committed"` // False when validation failed or for dry runs
+	DryRun    bool                `json:"dry_run"`
+	Employees []*ImportedEmployee `json:"employees"`
+	Errors    []*ImportRowError   `json:"errors"`
+}
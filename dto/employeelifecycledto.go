@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type StatusTransitionRequest struct {
+	ToStatus        string `json:"to_status" validate:"required,oneof=active inactive on_leave terminated probation"`
+	EffectiveDate   string `json:"effective_date" validate:"omitempty"`   // Format: YYYY-MM-DD, defaults to today
+	TerminationDate string `json:"termination_date" validate:"omitempty"` // Format: YYYY-MM-DD, required for terminated
+	Reason          string `json:"reason" validate:"omitempty"`           // Required for terminated
+}
+
+type RehireRequest struct {
+	ToStatus      string `json:"to_status" validate:"required,oneof=active probation"`
+	EffectiveDate string `json:"effective_date" validate:"required"` // Becomes the new hire_date
+	Reason        string `json:"reason" validate:"omitempty"`
+}
+
+type StatusTransitionResponse struct {
+	ID              string     `json:"id"`
+	EmployeeID      string     `json:"employee_id"`
+	FromStatus      string     `json:"from_status"`
+	ToStatus        string     `json:"to_status"`
+	EffectiveDate   time.Time  `json:"effective_date"`
+	TerminationDate *time.Time `json:"termination_date"`
+	Reason          string     `json:"reason"`
+	Status          string     `json:"status"`
+	RequestedBy     *string    `json:"requested_by"`
+	AppliedAt       *time.Time `json:"applied_at"`
+	FailureReason   string     `json:"failure_reason,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
@@ -0,0 +1,125 @@
+package dto
+
+import "time"
+
+type CreateExpenseCategoryRequest struct {
+	Name            string `json:"name" validate:"required,max=100"`
+	Description     string `json:"description" validate:"omitempty"`
+	RequiresReceipt *bool  `json:"requires_receipt" validate:"omitempty"` // Defaults to true
+	IsActive        *bool  `json:"is_active" validate:"omitempty"`        // Defaults to true
+}
+
+type UpdateExpenseCategoryRequest struct {
+	Name            *string `json:"name" validate:"omitempty,max=100"`
+	Description     *string `json:"description" validate:"omitempty"`
+	RequiresReceipt *bool   `json:"requires_receipt" validate:"omitempty"`
+	IsActive        *bool   `json:"is_active" validate:"omitempty"` // Inactive categories cannot be used on new items
+}
+
+type ExpenseCategoryResponse struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	RequiresReceipt bool      `json:"requires_receipt"`
+	IsActive        bool      `json:"is_active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type ExpenseLimitRequest struct {
+	CategoryID string  `json:"category_id" validate:"omitempty,uuid"` // Empty caps every category together
+	Period     string  `json:"period" validate:"required,oneof=claim month"`
+	Amount     float64 `json:"amount" validate:"gte=0"`
+}
+
+// SetExpenseLimitsRequest replaces every limit of a level.
+type SetExpenseLimitsRequest struct {
+	Limits []ExpenseLimitRequest `json:"limits" validate:"dive"`
+}
+
+type ExpenseLimitResponse struct {
+	ID         string  `json:"id"`
+	LevelID    string  `json:"level_id"`
+	CategoryID *string `json:"category_id"`
+	Period     string  `json:"period"`
+	Amount     float64 `json:"amount"`
+}
+
+type ExpenseClaimItemRequest struct {
+	CategoryID  string  `json:"category_id" validate:"required,uuid"`
+	ExpenseDate string  `json:"expense_date" validate:"required"` // Format: YYYY-MM-DD
+	Description string  `json:"description" validate:"required,max=255"`
+	Merchant    string  `json:"merchant" validate:"omitempty,max=255"`
+	Amount      float64 `json:"amount" validate:"gt=0"` // In the company's currency
+	ReceiptURL  string  `json:"receipt_url" validate:"omitempty,url"`
+}
+
+type CreateExpenseClaimRequest struct {
+	Title       string                    `json:"title" validate:"required,max=255"`
+	Description string                    `json:"description" validate:"omitempty"`
+	Items       []ExpenseClaimItemRequest `json:"items" validate:"dive"`
+}
+
+// UpdateExpenseClaimRequest edits a draft or a claim sent back for changes.
+// Omitted fields keep their current values; items, when given, replace
+// every item of the claim.
+type UpdateExpenseClaimRequest struct {
+	Title       *string                    `json:"title" validate:"omitempty,max=255"`
+	Description *string                    `json:"description" validate:"omitempty"`
+	Items       *[]ExpenseClaimItemRequest `json:"items" validate:"omitempty,dive"`
+}
+
+type AttachExpenseReceiptRequest struct {
+	ReceiptURL string `json:"receipt_url" validate:"required,url"`
+}
+
+type ExpenseActionRequest struct {
+	Comments string `json:"comments" validate:"omitempty"` // Required when rejecting or requesting changes
+}
+
+type ExpenseClaimItemResponse struct {
+	ID          string    `json:"id"`
+	CategoryID  string    `json:"category_id"`
+	ExpenseDate time.Time `json:"expense_date"`
+	Description string    `json:"description"`
+	Merchant    string    `json:"merchant"`
+	Amount      float64   `json:"amount"`
+	ReceiptURL  string    `json:"receipt_url"`
+}
+
+type ExpenseClaimResponse struct {
+	ID              string                      `json:"id"`
+	EmployeeID      string                      `json:"employee_id"`
+	Title           string                      `json:"title"`
+	Description     string                      `json:"description"`
+	Currency        string                      `json:"currency"`
+	TotalAmount     float64                     `json:"total_amount"`
+	Status          string                      `json:"status"` // draft, pending, changes_requested, approved, rejected, withdrawn, reimbursed
+	CurrentStep     int                         `json:"current_step"`
+	SubmittedAt     *time.Time                  `json:"submitted_at"`
+	ApprovedBy      *string                     `json:"approved_by"`
+	ApprovedAt      *time.Time                  `json:"approved_at"`
+	RejectionReason string                      `json:"rejection_reason"`
+	PayoutBatchID   *string                     `json:"payout_batch_id"`
+	ReimbursedAt    *time.Time                  `json:"reimbursed_at"`
+	Items           []*ExpenseClaimItemResponse `json:"items"`
+	CreatedAt       time.Time                   `json:"created_at"`
+	UpdatedAt       time.Time                   `json:"updated_at"`
+}
+
+// CreateExpensePayoutRequest pays the listed approved claims, or every
+// approved claim not yet paid when ClaimIDs is empty.
+type CreateExpensePayoutRequest struct {
+	ClaimIDs []string `json:"claim_ids" validate:"omitempty,dive,uuid"`
+}
+
+type ExpensePayoutBatchResponse struct {
+	ID          string                  `json:"id"`
+	Reference   string                  `json:"reference"`
+	Currency    string                  `json:"currency"`
+	TotalAmount float64                 `json:"total_amount"`
+	ClaimCount  int                     `json:"claim_count"`
+	CreatedBy   *string                 `json:"created_by"`
+	CreatedAt   time.Time               `json:"created_at"`
+	Claims      []*ExpenseClaimResponse `json:"claims,omitempty"` // Only when the batch is created
+}
@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+type CreateHolidayCalendarRequest struct {
+	Name          string   `json:"name" validate:"required,max=255"`
+	Country       string   `json:"country" validate:"omitempty,max=100"`
+	WorkDays      []string `json:"work_days" validate:"omitempty"` // e.g. ["sunday", ..., "thursday"]; defaults to Monday–Friday
+	IsDefault     bool     `json:"is_default" validate:"omitempty"`
+	DepartmentIDs []string `json:"department_ids" validate:"omitempty,dive,uuid"`
+}
+
+type UpdateHolidayCalendarRequest struct {
+	Name          *string   `json:"name" validate:"omitempty,max=255"`
+	Country       *string   `json:"country" validate:"omitempty,max=100"`
+	WorkDays      *[]string `json:"work_days" validate:"omitempty"`
+	IsDefault     *bool     `json:"is_default" validate:"omitempty"`
+	DepartmentIDs *[]string `json:"department_ids" validate:"omitempty,dive,uuid"`
+}
+
+type HolidayCalendarResponse struct {
+	ID            string    `json:"id"`
+	CompanyID     string    `json:"company_id"`
+	Name          string    `json:"name"`
+	Country       string    `json:"country"`
+	WorkDays      []string  `json:"work_days"`
+	IsDefault     bool      `json:"is_default"`
+	DepartmentIDs []string  `json:"department_ids"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type CreateHolidayRequest struct {
+	Date      string `json:"date" validate:"required"` // Format: YYYY-MM-DD
+	Name      string `json:"name" validate:"required,max=255"`
+	Recurring bool   `json:"recurring" validate:"omitempty"`
+}
+
+type HolidayResponse struct {
+	ID        string    `json:"id"`
+	Date      string    `json:"date"` // The occurrence within the requested range
+	Name      string    `json:"name"`
+	Recurring bool      `json:"recurring"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type HolidayImportResponse struct {
+	Imported   int                `json:"imported"`
+	Duplicates int                `json:"duplicates"` // Already in the calendar
+	Skipped    []string           `json:"skipped"`    // Events that could not be imported, with the reason
+	Holidays   []*HolidayResponse `json:"holidays"`
+}
+
+type WorkCalendarResponse struct {
+	CalendarID   *string            `json:"calendar_id"` // Nil when the built-in Monday–Friday week applies
+	CalendarName string             `json:"calendar_name"`
+	WorkDays     []string           `json:"work_days"`
+	From         string             `json:"from"`
+	To           string             `json:"to"`
+	WorkingDays  int                `json:"working_days"`
+	Holidays     []*HolidayResponse `json:"holidays"`
+}
@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+type LeaveAdjustmentRequest struct {
+	LeaveTypeID string  `json:"leave_type_id" validate:"required,uuid"`
+	Year        int     `json:"year" validate:"omitempty"` // Defaults to the current year
+	Days        float64 `json:"days" validate:"required"`  // Added to total_days; negative to deduct
+	Reason      string  `json:"reason" validate:"required"`
+}
+
+type LeaveLedgerEntryResponse struct {
+	ID                  string    `json:"id"`
+	EntryType           string    `json:"entry_type"`
+	TotalDelta          float64   `json:"total_delta"`
+	UsedDelta           float64   `json:"used_delta"`
+	PendingDelta        float64   `json:"pending_delta"`
+	CarriedForwardDelta float64   `json:"carried_forward_delta"`
+	AvailableAfter      float64   `json:"available_after"` // Running available balance
+	LeaveRequestID      *string   `json:"leave_request_id"`
+	ActorID             *string   `json:"actor_id"`
+	Reason              string    `json:"reason"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// LeaveTypeStatement explains one balance: its ledger entries in order and
+// the totals they add up to.
+type LeaveTypeStatement struct {
+	LeaveTypeID   string                      `json:"leave_type_id"`
+	LeaveTypeName string                      `json:"leave_type_name"`
+	Entries       []*LeaveLedgerEntryResponse `json:"entries"`
+	Balance       *LeaveBalanceResponse       `json:"balance"`
+	Consistent    bool                        `json:"consistent"` // The entries add up to the stored balance
+}
+
+type LeaveStatementResponse struct {
+	EmployeeID string                `json:"employee_id"`
+	Year       int                   `json:"year"`
+	LeaveTypes []*LeaveTypeStatement `json:"leave_types"`
+}
+
+type LeaveBalanceDiscrepancyResponse struct {
+	Balance              *LeaveBalanceResponse `json:"balance"`
+	EmployeeID           string                `json:"employee_id"`
+	LedgerTotal          float64               `json:"ledger_total"`
+	LedgerUsed           float64               `json:"ledger_used"`
+	LedgerPending        float64               `json:"ledger_pending"`
+	LedgerCarriedForward float64               `json:"ledger_carried_forward"`
+}
+
+type LedgerConsistencyResponse struct {
+	Year          int                                `json:"year"`
+	Consistent    bool                               `json:"consistent"`
+	Discrepancies []*LeaveBalanceDiscrepancyResponse `json:"discrepancies"`
+}
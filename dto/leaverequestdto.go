@@ -0,0 +1,84 @@
+package dto
+
+import "time"
+
+type SubmitLeaveRequest struct {
+	LeaveTypeID   string `json:"leave_type_id" validate:"required,uuid"`
+	StartDate     string `json:"start_date" validate:"required"` // Format: YYYY-MM-DD
+	EndDate       string `json:"end_date" validate:"required"`   // Format: YYYY-MM-DD
+	StartHalfDay  bool   `json:"start_half_day" validate:"omitempty"`
+	EndHalfDay    bool   `json:"end_half_day" validate:"omitempty"`
+	Reason        string `json:"reason" validate:"omitempty"`
+	AttachmentURL string `json:"attachment_url" validate:"omitempty,url"`
+}
+
+type LeaveRequestResponse struct {
+	ID                   string     `json:"id"`
+	EmployeeID           string     `json:"employee_id"`
+	LeaveTypeID          string     `json:"leave_type_id"`
+	StartDate            time.Time  `json:"start_date"`
+	EndDate              time.Time  `json:"end_date"`
+	StartHalfDay         bool       `json:"start_half_day"`
+	EndHalfDay           bool       `json:"end_half_day"`
+	DaysRequested        float64    `json:"days_requested"`
+	Reason               string     `json:"reason"`
+	AttachmentURL        string     `json:"attachment_url"`
+	DocumentationStatus  string     `json:"documentation_status"`   // not_required, provided, awaiting, overdue
+	DocumentationDueDate *time.Time `json:"documentation_due_date"` // Set while documentation is outstanding
+	Status               string     `json:"status"`
+	CurrentStep          int        `json:"current_step"`
+	ApprovedBy           *string    `json:"approved_by"`
+	ApprovedAt           *time.Time `json:"approved_at"`
+	RejectionReason      string     `json:"rejection_reason"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+type LeaveBalanceResponse struct {
+	LeaveTypeID        string  `json:"leave_type_id"`
+	Year               int     `json:"year"`
+	TotalDays          float64 `json:"total_days"`
+	CarriedForwardDays float64 `json:"carried_forward_days"`
+	UsedDays           float64 `json:"used_days"`
+	PendingDays        float64 `json:"pending_days"`
+	AvailableDays      float64 `json:"available_days"` // total + carried forward - used - pending
+}
+
+// LeaveRequestBalanceResponse returns a request together with the balance it
+// was booked against.
+type LeaveRequestBalanceResponse struct {
+	Request *LeaveRequestResponse `json:"request"`
+	Balance *LeaveBalanceResponse `json:"balance"`
+}
+
+type AttachLeaveDocumentRequest struct {
+	AttachmentURL string `json:"attachment_url" validate:"required,url"`
+}
+
+// UpdateLeaveRequest edits a request sent back for changes. Omitted fields
+// keep their current values.
+type UpdateLeaveRequest struct {
+	StartDate     *string `json:"start_date" validate:"omitempty"` // Format: YYYY-MM-DD
+	EndDate       *string `json:"end_date" validate:"omitempty"`   // Format: YYYY-MM-DD
+	StartHalfDay  *bool   `json:"start_half_day" validate:"omitempty"`
+	EndHalfDay    *bool   `json:"end_half_day" validate:"omitempty"`
+	Reason        *string `json:"reason" validate:"omitempty"`
+	AttachmentURL *string `json:"attachment_url" validate:"omitempty,url"`
+}
+
+type LeaveActionRequest struct {
+	Comments string `json:"comments" validate:"omitempty"` // Required when rejecting or requesting changes
+}
+
+type ApprovalHistoryResponse struct {
+	ID             string    `json:"id"`
+	StepNumber     int       `json:"step_number"`
+	ApproverID     *string   `json:"approver_id"` // Null for automatic actions
+	ApproverName   string    `json:"approver_name,omitempty"`
+	OnBehalfOf     *string   `json:"on_behalf_of"` // Approver a delegate acted for
+	OnBehalfOfName string    `json:"on_behalf_of_name,omitempty"`
+	Action         string    `json:"action"`
+	Summary        string    `json:"summary"` // e.g. "approved by Ada Obi on behalf of Tunde Bello"
+	Comments       string    `json:"comments"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+type CreateLeaveTypeRequest struct {
+	Name                    string   `json:"name" validate:"required,max=100"`
+	Code                    string   `json:"code" validate:"omitempty,max=50"`
+	Description             string   `json:"description" validate:"omitempty"`
+	DaysAllowed             float64  `json:"days_allowed" validate:"gte=0"`
+	IsPaid                  *bool    `json:"is_paid" validate:"omitempty"` // Defaults to true
+	RequiresDocumentation   bool     `json:"requires_documentation" validate:"omitempty"`
+	CarryForwardAllowed     bool     `json:"carry_forward_allowed" validate:"omitempty"`
+	MaxCarryForwardDays     float64  `json:"max_carry_forward_days" validate:"gte=0"`
+	ColorCode               string   `json:"color_code" validate:"omitempty,hexcolor"` // Defaults to #3498db
+	EligibleEmploymentTypes []string `json:"eligible_employment_types" validate:"omitempty,dive,oneof=full_time part_time contract intern"`
+	EligibleGenders         []string `json:"eligible_genders" validate:"omitempty"`
+	MinTenureDays           int      `json:"min_tenure_days" validate:"gte=0"`
+	AllowedDuringProbation  *bool    `json:"allowed_during_probation" validate:"omitempty"`            // Defaults to true
+	AccrualPolicy           string   `json:"accrual_policy" validate:"omitempty,oneof=yearly monthly"` // Defaults to yearly
+	// Documentation rules apply only when requires_documentation is set.
+	DocumentationAfterDays     int    `json:"documentation_after_days" validate:"gte=0"`                           // 0 requires it for every request
+	DocumentationGraceDays     int    `json:"documentation_grace_days" validate:"gte=0"`                           // Days after the leave ends; 0 requires it at submission
+	DocumentationOverdueAction string `json:"documentation_overdue_action" validate:"omitempty,oneof=flag revert"` // Defaults to flag
+}
+
+type UpdateLeaveTypeRequest struct {
+	Name                       *string   `json:"name" validate:"omitempty,max=100"`
+	Code                       *string   `json:"code" validate:"omitempty,max=50"`
+	Description                *string   `json:"description" validate:"omitempty"`
+	DaysAllowed                *float64  `json:"days_allowed" validate:"omitempty,gte=0"`
+	IsPaid                     *bool     `json:"is_paid" validate:"omitempty"`
+	RequiresDocumentation      *bool     `json:"requires_documentation" validate:"omitempty"`
+	CarryForwardAllowed        *bool     `json:"carry_forward_allowed" validate:"omitempty"`
+	MaxCarryForwardDays        *float64  `json:"max_carry_forward_days" validate:"omitempty,gte=0"`
+	ColorCode                  *string   `json:"color_code" validate:"omitempty,hexcolor"`
+	Status                     *string   `json:"status" validate:"omitempty,oneof=active inactive"`
+	EligibleEmploymentTypes    *[]string `json:"eligible_employment_types" validate:"omitempty"`
+	EligibleGenders            *[]string `json:"eligible_genders" validate:"omitempty"`
+	MinTenureDays              *int      `json:"min_tenure_days" validate:"omitempty,gte=0"`
+	AllowedDuringProbation     *bool     `json:"allowed_during_probation" validate:"omitempty"`
+	AccrualPolicy              *string   `json:"accrual_policy" validate:"omitempty,oneof=yearly monthly"`
+	DocumentationAfterDays     *int      `json:"documentation_after_days" validate:"omitempty,gte=0"`
+	DocumentationGraceDays     *int      `json:"documentation_grace_days" validate:"omitempty,gte=0"`
+	DocumentationOverdueAction *string   `json:"documentation_overdue_action" validate:"omitempty,oneof=flag revert"`
+}
+
+type LeaveTypeResponse struct {
+	ID                         string    `json:"id"`
+	CompanyID                  string    `json:"company_id"`
+	Name                       string    `json:"name"`
+	Code                       string    `json:"code"`
+	Description                string    `json:"description"`
+	DaysAllowed                float64   `json:"days_allowed"`
+	IsPaid                     bool      `json:"is_paid"`
+	RequiresDocumentation      bool      `json:"requires_documentation"`
+	CarryForwardAllowed        bool      `json:"carry_forward_allowed"`
+	MaxCarryForwardDays        float64   `json:"max_carry_forward_days"`
+	ColorCode                  string    `json:"color_code"`
+	Status                     string    `json:"status"`
+	EligibleEmploymentTypes    []string  `json:"eligible_employment_types"`
+	EligibleGenders            []string  `json:"eligible_genders"`
+	MinTenureDays              int       `json:"min_tenure_days"`
+	AllowedDuringProbation     bool      `json:"allowed_during_probation"`
+	AccrualPolicy              string    `json:"accrual_policy"`
+	DocumentationAfterDays     int       `json:"documentation_after_days"`
+	DocumentationGraceDays     int       `json:"documentation_grace_days"`
+	DocumentationOverdueAction string    `json:"documentation_overdue_action"`
+	CreatedAt                  time.Time `json:"created_at"`
+	UpdatedAt                  time.Time `json:"updated_at"`
+}
+
+type LeaveEligibilityResponse struct {
+	LeaveType *LeaveTypeResponse `json:"leave_type"`
+	Eligible  bool               `json:"eligible"`
+	Reasons   []string           `json:"reasons"` // Why the employee is not eligible
+}
@@ -12,6 +12,7 @@ type CreateLevelRequest struct {
 	MinSalary      *float64 `json:"min_salary" validate:"omitempty,min=0"`
 	MaxSalary      *float64 `json:"max_salary" validate:"omitempty,min=0"`
 	Description    string   `json:"description" validate:"omitempty"`
+	ProbationDays  *int     `json:"probation_days" validate:"omitempty,min=0"`
 }
 
 type UpdateLevelRequest struct {
@@ -20,6 +21,7 @@ type UpdateLevelRequest struct {
 	MinSalary      *float64 `json:"min_salary" validate:"omitempty,min=0"`
 	MaxSalary      *float64 `json:"max_salary" validate:"omitempty,min=0"`
 	Description    *string  `json:"description" validate:"omitempty"`
+	ProbationDays  *int     `json:"probation_days" validate:"omitempty,min=0"`
 }
 
 type LevelResponse struct {
@@ -30,6 +32,7 @@ type LevelResponse struct {
 	MinSalary      *float64  `json:"min_salary"`
 	MaxSalary      *float64  `json:"max_salary"`
 	Description    string    `json:"description"`
+	ProbationDays  *int      `json:"probation_days"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
@@ -0,0 +1,167 @@
+package dto
+
+import "time"
+
+type MemoTargetRequest struct {
+	Type string `json:"type" validate:"required,oneof=individual department role level company"`
+	ID   string `json:"id" validate:"omitempty,uuid"` // Employee, department, role or level; empty for company
+}
+
+type CreateMemoRequest struct {
+	MemoType string              `json:"memo_type" validate:"required,oneof=request disciplinary announcement general"`
+	Title    string              `json:"title" validate:"required,max=255"`
+	Content  string              `json:"content" validate:"required"`
+	Priority string              `json:"priority" validate:"omitempty,oneof=low normal high urgent"` // Defaults to normal
+	Targets  []MemoTargetRequest `json:"targets" validate:"dive"`
+
+	// RequiresAcknowledgement makes recipients explicitly confirm the memo,
+	// e.g. for policies. Disciplinary memos always require it.
+	RequiresAcknowledgement bool `json:"requires_acknowledgement"`
+
+	// SubjectID is the employee a disciplinary memo is about, and its only
+	// recipient. Other memo types have no subject.
+	SubjectID string `json:"subject_id" validate:"omitempty,uuid"`
+}
+
+// UpdateMemoRequest edits a draft. Omitted fields keep their current
+// values; targets, when given, replace every target of the memo.
+type UpdateMemoRequest struct {
+	MemoType *string              `json:"memo_type" validate:"omitempty,oneof=request disciplinary announcement general"`
+	Title    *string              `json:"title" validate:"omitempty,max=255"`
+	Content  *string              `json:"content" validate:"omitempty"`
+	Priority *string              `json:"priority" validate:"omitempty,oneof=low normal high urgent"`
+	Targets  *[]MemoTargetRequest `json:"targets" validate:"omitempty,dive"`
+
+	RequiresAcknowledgement *bool   `json:"requires_acknowledgement" validate:"omitempty"`
+	SubjectID               *string `json:"subject_id" validate:"omitempty,uuid"`
+}
+
+type MemoActionRequest struct {
+	Comments string `json:"comments" validate:"omitempty"` // Required when rejecting or requesting changes
+}
+
+type MemoTargetResponse struct {
+	Type string  `json:"type"`
+	ID   *string `json:"id"`
+}
+
+type MemoResponse struct {
+	ID                      string                `json:"id"`
+	SenderID                string                `json:"sender_id"`
+	MemoType                string                `json:"memo_type"`
+	Title                   string                `json:"title"`
+	Content                 string                `json:"content"`
+	ReferenceNumber         string                `json:"reference_number,omitempty"`
+	Status                  string                `json:"status"`
+	CurrentStep             int                   `json:"current_step"`
+	Priority                string                `json:"priority"`
+	RequiresAcknowledgement bool                  `json:"requires_acknowledgement"`
+	SubjectID               *string               `json:"subject_id,omitempty"`
+	CaseID                  *string               `json:"case_id,omitempty"`
+	Targets                 []*MemoTargetResponse `json:"targets"`
+	RecipientCount          int                   `json:"recipient_count"`
+	PublishedAt             *time.Time            `json:"published_at"`
+	CreatedAt               time.Time             `json:"created_at"`
+	UpdatedAt               time.Time             `json:"updated_at"`
+}
+
+// MemoSettingsRequest sets how memo reference numbers are built. The
+// pattern must contain {SEQ} and a year, {YYYY} or {YY}, and may contain
+// {MM}, e.g. HR/{YYYY}/{SEQ}.
+type MemoSettingsRequest struct {
+	ReferencePattern string `json:"reference_pattern" validate:"required,max=100"`
+}
+
+type MemoSettingsResponse struct {
+	ReferencePattern string `json:"reference_pattern"`
+	Example          string `json:"example"` // The first reference of this year under the pattern
+}
+
+// ReceivedMemoResponse is a published memo as one of its recipients sees it.
+type ReceivedMemoResponse struct {
+	ID                      string     `json:"id"`
+	SenderID                string     `json:"sender_id"`
+	MemoType                string     `json:"memo_type"`
+	Title                   string     `json:"title"`
+	Content                 string     `json:"content"`
+	ReferenceNumber         string     `json:"reference_number"`
+	Priority                string     `json:"priority"`
+	RequiresAcknowledgement bool       `json:"requires_acknowledgement"`
+	PublishedAt             *time.Time `json:"published_at"`
+	IsRead                  bool       `json:"is_read"`
+	ReadAt                  *time.Time `json:"read_at"`
+	AcknowledgedAt          *time.Time `json:"acknowledged_at"`
+}
+
+type MemoReceiptResponse struct {
+	EmployeeID     string     `json:"employee_id"`
+	EmployeeName   string     `json:"employee_name"`
+	IsRead         bool       `json:"is_read"`
+	ReadAt         *time.Time `json:"read_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+	ReminderCount  int        `json:"reminder_count"`
+}
+
+// MemoDeliveryReport shows who has read a published memo and, when it
+// requires acknowledgement, who has confirmed it.
+type MemoDeliveryReport struct {
+	MemoID                  string `json:"memo_id"`
+	ReferenceNumber         string `json:"reference_number"`
+	RequiresAcknowledgement bool   `json:"requires_acknowledgement"`
+	RecipientCount          int    `json:"recipient_count"`
+	ReadCount               int    `json:"read_count"`
+	UnreadCount             int    `json:"unread_count"`
+	AcknowledgedCount       int    `json:"acknowledged_count"`
+
+	Read           []*MemoReceiptResponse `json:"read"`
+	Unread         []*MemoReceiptResponse `json:"unread"`
+	Unacknowledged []*MemoReceiptResponse `json:"unacknowledged,omitempty"` // Only for memos requiring acknowledgement
+}
+
+// CreateMemoThreadEntryRequest adds to the thread under a disciplinary memo.
+// The subject writes a response or an appeal; HR and management reply.
+type CreateMemoThreadEntryRequest struct {
+	Kind    string `json:"kind" validate:"omitempty,oneof=response appeal reply"` // Defaults to response for the subject, reply for others
+	Content string `json:"content" validate:"required"`
+}
+
+type MemoThreadEntryResponse struct {
+	ID         string    `json:"id"`
+	AuthorID   *string   `json:"author_id"`
+	AuthorName string    `json:"author_name"`
+	Kind       string    `json:"kind"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateDisciplinaryCaseRequest struct {
+	EmployeeID  string   `json:"employee_id" validate:"required,uuid"`
+	Title       string   `json:"title" validate:"required,max=255"`
+	Description string   `json:"description" validate:"omitempty"`
+	MemoIDs     []string `json:"memo_ids" validate:"omitempty,dive,uuid"` // Disciplinary memos about the employee to link
+}
+
+type LinkCaseMemoRequest struct {
+	MemoID string `json:"memo_id" validate:"required,uuid"`
+}
+
+type CloseDisciplinaryCaseRequest struct {
+	Outcome string `json:"outcome" validate:"required,oneof=no_action verbal_warning written_warning final_warning suspension demotion termination"`
+	Notes   string `json:"notes" validate:"omitempty"`
+}
+
+type DisciplinaryCaseResponse struct {
+	ID           string          `json:"id"`
+	EmployeeID   string          `json:"employee_id"`
+	Title        string          `json:"title"`
+	Description  string          `json:"description"`
+	Status       string          `json:"status"`
+	Outcome      string          `json:"outcome,omitempty"`
+	OutcomeNotes string          `json:"outcome_notes,omitempty"`
+	OpenedBy     *string         `json:"opened_by"`
+	ClosedBy     *string         `json:"closed_by"`
+	ClosedAt     *time.Time      `json:"closed_at"`
+	Memos        []*MemoResponse `json:"memos,omitempty"` // Only when fetching a single case
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
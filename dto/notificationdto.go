@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+type NotificationResponse struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	EntityType string     `json:"entity_type,omitempty"`
+	EntityID   *string    `json:"entity_id"`
+	IsRead     bool       `json:"is_read"`
+	ReadAt     *time.Time `json:"read_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+type OffboardingRequest struct {
+	TerminationDate     string `json:"termination_date" validate:"required"` // Format: YYYY-MM-DD
+	Reason              string `json:"reason" validate:"required"`
+	NewManagerID        string `json:"new_manager_id" validate:"omitempty,uuid"`        // Required when the employee has direct reports
+	HODSuccessorID      string `json:"hod_successor_id" validate:"omitempty,uuid"`      // Empty clears their HOD posts
+	ApproverSuccessorID string `json:"approver_successor_id" validate:"omitempty,uuid"` // Defaults to new_manager_id
+}
+
+type UpdateChecklistItemRequest struct {
+	Status string `json:"status" validate:"required,oneof=completed skipped"`
+	Notes  string `json:"notes" validate:"omitempty"`
+}
+
+type OffboardingChecklistItemResponse struct {
+	ID          string     `json:"id"`
+	Code        string     `json:"code"`
+	Title       string     `json:"title"`
+	IsAutomated bool       `json:"is_automated"`
+	Status      string     `json:"status"`
+	Notes       string     `json:"notes"`
+	CompletedBy *string    `json:"completed_by"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+type OffboardingChecklistResponse struct {
+	ID                    string                              `json:"id"`
+	EmployeeID            string                              `json:"employee_id"`
+	TerminationDate       time.Time                           `json:"termination_date"`
+	NewManagerID          *string                             `json:"new_manager_id"`
+	HODSuccessorID        *string                             `json:"hod_successor_id"`
+	ApproverSuccessorID   *string                             `json:"approver_successor_id"`
+	Status                string                              `json:"status"`
+	AutomationCompletedAt *time.Time                          `json:"automation_completed_at"`
+	CompletedAt           *time.Time                          `json:"completed_at"`
+	Items                 []*OffboardingChecklistItemResponse `json:"items"`
+	CreatedAt             time.Time                           `json:"created_at"`
+}
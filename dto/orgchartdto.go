@@ -0,0 +1,19 @@
+package dto
+
+type OrgChartRequest struct {
+	Type     string `json:"type" validate:"required,oneof=people department"`
+	RootID   string `json:"root_id" validate:"omitempty,uuid"`    // Employee or department; empty for the whole company
+	MaxDepth int    `json:"max_depth" validate:"omitempty,min=0"` // 0 = unlimited
+	Format   string `json:"format" validate:"required,oneof=json dot svg"`
+}
+
+type OrgChartNode struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"` // employee, department
+	Name        string          `json:"name"`
+	Designation string          `json:"designation,omitempty"`
+	PhotoURL    string          `json:"photo_url,omitempty"`
+	HeadName    string          `json:"head_name,omitempty"`
+	Depth       int             `json:"depth"`
+	Children    []*OrgChartNode `json:"children"`
+}
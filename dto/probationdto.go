@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+type ProbationDecisionRequest struct {
+	Decision        string `json:"decision" validate:"required,oneof=confirm extend terminate"`
+	Comments        string `json:"comments" validate:"required"`
+	ExtendUntil     string `json:"extend_until" validate:"omitempty"`     // Format: YYYY-MM-DD; required for extend
+	TerminationDate string `json:"termination_date" validate:"omitempty"` // Format: YYYY-MM-DD; defaults to today for terminate
+	NewManagerID    string `json:"new_manager_id" validate:"omitempty,uuid"`
+}
+
+type ProbationReviewResponse struct {
+	ID              string     `json:"id"`
+	EmployeeID      string     `json:"employee_id"`
+	Decision        string     `json:"decision"`
+	PreviousEndDate *time.Time `json:"previous_end_date"`
+	NewEndDate      *time.Time `json:"new_end_date"`
+	Comments        string     `json:"comments"`
+	DecidedBy       *string    `json:"decided_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type UpcomingProbationResponse struct {
+	EmployeeID       string    `json:"employee_id"`
+	EmployeeCode     string    `json:"employee_code"`
+	FullName         string    `json:"full_name"`
+	ManagerID        *string   `json:"manager_id"`
+	HireDate         time.Time `json:"hire_date"`
+	ProbationEndDate time.Time `json:"probation_end_date"`
+	DaysRemaining    int       `json:"days_remaining"` // Negative once overdue
+}
@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+// TeamCalendarRequest selects the team shown on a calendar: a manager's
+// reports or the members of a department.
+type TeamCalendarRequest struct {
+	ManagerID             string `json:"manager_id" validate:"omitempty,uuid"`
+	DepartmentID          string `json:"department_id" validate:"omitempty,uuid"`
+	DirectOnly            bool   `json:"direct_only" validate:"omitempty"`            // Manager teams only
+	IncludeSubDepartments bool   `json:"include_subdepartments" validate:"omitempty"` // Department teams only
+	IncludePending        bool   `json:"include_pending" validate:"omitempty"`
+	MaxAbsent             int    `json:"max_absent" validate:"omitempty,min=0"` // 0 uses the default of 30% of the team
+}
+
+type TeamLeaveEntryResponse struct {
+	RequestID     string    `json:"request_id"`
+	EmployeeID    string    `json:"employee_id"`
+	EmployeeName  string    `json:"employee_name"`
+	LeaveTypeID   string    `json:"leave_type_id"`
+	LeaveTypeName string    `json:"leave_type_name"`
+	ColorCode     string    `json:"color_code"`
+	StartDate     time.Time `json:"start_date"`
+	EndDate       time.Time `json:"end_date"`
+	StartHalfDay  bool      `json:"start_half_day"`
+	EndHalfDay    bool      `json:"end_half_day"`
+	DaysRequested float64   `json:"days_requested"`
+	Status        string    `json:"status"`
+}
+
+// TeamCalendarDayResponse counts who is away on a working day.
+type TeamCalendarDayResponse struct {
+	Date        string   `json:"date"` // Format: YYYY-MM-DD
+	Absent      int      `json:"absent"`
+	EmployeeIDs []string `json:"employee_ids"`
+	OverLimit   bool     `json:"over_limit"`
+}
+
+type TeamCalendarResponse struct {
+	From      string                     `json:"from"`
+	To        string                     `json:"to"`
+	TeamSize  int                        `json:"team_size"`
+	MaxAbsent int                        `json:"max_absent"`
+	Entries   []*TeamLeaveEntryResponse  `json:"entries"`
+	Days      []*TeamCalendarDayResponse `json:"days"`
+	Warnings  []string                   `json:"warnings"`
+}
+
+// CalendarFeedResponse carries a private iCalendar subscription URL. The URL
+// embeds a secret token and is only returned when the token is created.
+type CalendarFeedResponse struct {
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type ApprovalHandler struct {
+	approvalService *services.ApprovalService
+}
+
+func NewApprovalHandler(approvalService *services.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalService: approvalService,
+	}
+}
+
+func (h *ApprovalHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/approval-workflows", h.ListWorkflows).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/approval-workflows", h.CreateWorkflow).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/approval-workflows/{workflowID}", h.GetWorkflow).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/approval-workflows/{workflowID}", h.UpdateWorkflow).Methods(http.MethodPatch)
+	r.HandleFunc("/companies/{companyID}/approval-workflows/{workflowID}", h.DeleteWorkflow).Methods(http.MethodDelete)
+	r.HandleFunc("/companies/{companyID}/approvals/overdue", h.ListOverdueApprovals).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/approvals/inbox", h.Inbox).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/approvals/inbox/decisions", h.BulkDecide).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/approvals/{entityType}/{entityID}", h.GetApprovalStatus).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/approvals/{entityType}/{entityID}/revisions", h.ListRevisions).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/approval-delegations", h.ListDelegations).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/approval-delegations", h.CreateDelegation).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/approval-delegations/{delegationID}", h.RevokeDelegation).Methods(http.MethodDelete)
+}
+
+// companyWorkflowParams parses the {companyID} and {workflowID} path
+// parameters.
+func companyWorkflowParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	workflowID, err := utils.ParseUUIDParam(r, "workflowID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid workflow id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return companyID, workflowID, true
+}
+
+func (h *ApprovalHandler) ListWorkflows(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	workflows, err := h.approvalService.ListWorkflows(r.Context(), companyID, r.URL.Query().Get("workflow_type"))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: workflows})
+}
+
+func (h *ApprovalHandler) CreateWorkflow(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	var req dto.CreateApprovalWorkflowRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	workflow, err := h.approvalService.CreateWorkflow(r.Context(), companyID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "approval workflow created", Data: workflow})
+}
+
+func (h *ApprovalHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	companyID, workflowID, ok := companyWorkflowParams(w, r)
+	if !ok {
+		return
+	}
+
+	workflow, err := h.approvalService.GetWorkflow(r.Context(), companyID, workflowID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: workflow})
+}
+
+func (h *ApprovalHandler) UpdateWorkflow(w http.ResponseWriter, r *http.Request) {
+	companyID, workflowID, ok := companyWorkflowParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateApprovalWorkflowRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	workflow, err := h.approvalService.UpdateWorkflow(r.Context(), companyID, workflowID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "approval workflow updated", Data: workflow})
+}
+
+func (h *ApprovalHandler) DeleteWorkflow(w http.ResponseWriter, r *http.Request) {
+	companyID, workflowID, ok := companyWorkflowParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.approvalService.DeleteWorkflow(r.Context(), companyID, workflowID); err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "approval workflow deleted"})
+}
+
+func (h *ApprovalHandler) GetApprovalStatus(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	entityID, err := utils.ParseUUIDParam(r, "entityID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid entity id")
+		return
+	}
+
+	status, err := h.approvalService.GetApprovalStatus(r.Context(), companyID, mux.Vars(r)["entityType"], entityID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: status})
+}
+
+func (h *ApprovalHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	entityID, err := utils.ParseUUIDParam(r, "entityID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid entity id")
+		return
+	}
+
+	revisions, err := h.approvalService.ListRevisions(r.Context(), companyID, mux.Vars(r)["entityType"], entityID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: revisions})
+}
+
+// Inbox lists what awaits the acting employee's decision, filtered by the
+// type, requester_id and overdue query parameters and paged by page and
+// page_size.
+func (h *ApprovalHandler) Inbox(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	overdue, err := queryBool(r, "overdue", false)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	req := &dto.ApprovalInboxRequest{
+		Type:        r.URL.Query().Get("type"),
+		RequesterID: r.URL.Query().Get("requester_id"),
+		OverdueOnly: overdue,
+	}
+	if req.Page, err = queryInt(r, "page", 1); err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+	if req.PageSize, err = queryInt(r, "page_size", 0); err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+	inbox, err := h.approvalService.Inbox(r.Context(), companyID, actorID(r), req, time.Now())
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: inbox})
+}
+
+func (h *ApprovalHandler) BulkDecide(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	var req dto.BulkApprovalDecisionRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.approvalService.BulkDecide(r.Context(), companyID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	message := fmt.Sprintf("%d decided, %d failed", result.Succeeded, result.Failed)
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: message, Data: result})
+}
+
+func (h *ApprovalHandler) ListOverdueApprovals(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	overdue, err := h.approvalService.OverdueApprovals(r.Context(), companyID, time.Now())
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: overdue})
+}
+
+func (h *ApprovalHandler) ListDelegations(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	delegations, err := h.approvalService.ListDelegations(r.Context(), companyID, employeeID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: delegations})
+}
+
+func (h *ApprovalHandler) CreateDelegation(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+	var req dto.CreateApprovalDelegationRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	delegation, err := h.approvalService.CreateDelegation(r.Context(), companyID, employeeID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "approval delegation created", Data: delegation})
+}
+
+func (h *ApprovalHandler) RevokeDelegation(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+	delegationID, err := utils.ParseUUIDParam(r, "delegationID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid delegation id")
+		return
+	}
+	delegation, err := h.approvalService.RevokeDelegation(r.Context(), companyID, employeeID, delegationID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "approval delegation revoked", Data: delegation})
+}
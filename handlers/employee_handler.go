@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type EmployeeHandler struct {
+	employeeService *services.EmployeeService
+}
+
+func NewEmployeeHandler(employeeService *services.EmployeeService) *EmployeeHandler {
+	return &EmployeeHandler{
+		employeeService: employeeService,
+	}
+}
+
+func (h *EmployeeHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/manager", h.ChangeManager).Methods(http.MethodPut)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/direct-reports", h.GetDirectReports).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/reports", h.GetAllReports).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/management-chain", h.GetManagementChain).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/skip-level-manager", h.GetSkipLevelManager).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/span-of-control", h.GetSpanOfControl).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/span-of-control", h.GetCompanySpanOfControl).Methods(http.MethodGet)
+}
+
+// companyEmployeeParams parses the {companyID} and {employeeID} path
+// parameters shared by the employee routes.
+func companyEmployeeParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	employeeID, err := utils.ParseUUIDParam(r, "employeeID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid employee id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return companyID, employeeID, true
+}
+
+func (h *EmployeeHandler) ChangeManager(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.ChangeManagerRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var managerID *uuid.UUID
+	if req.ManagerID != nil {
+		id, err := uuid.Parse(*req.ManagerID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "invalid manager_id")
+			return
+		}
+		managerID = &id
+	}
+
+	employee, err := h.employeeService.ChangeManager(r.Context(), companyID, employeeID, managerID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "manager updated", Data: employee})
+}
+
+func (h *EmployeeHandler) GetDirectReports(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	reports, err := h.employeeService.GetDirectReports(r.Context(), companyID, employeeID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: reports})
+}
+
+func (h *EmployeeHandler) GetAllReports(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	maxDepth, err := queryInt(r, "max_depth", 0)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	reports, err := h.employeeService.GetAllReports(r.Context(), companyID, employeeID, maxDepth)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: reports})
+}
+
+func (h *EmployeeHandler) GetManagementChain(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	chain, err := h.employeeService.GetManagementChain(r.Context(), companyID, employeeID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: chain})
+}
+
+func (h *EmployeeHandler) GetSkipLevelManager(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	manager, err := h.employeeService.GetSkipLevelManager(r.Context(), companyID, employeeID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+	if manager == nil {
+		utils.RespondWithError(w, http.StatusNotFound, "employee has no skip-level manager")
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: manager})
+}
+
+func (h *EmployeeHandler) GetSpanOfControl(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	span, err := h.employeeService.GetSpanOfControl(r.Context(), companyID, employeeID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: span})
+}
+
+func (h *EmployeeHandler) GetCompanySpanOfControl(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	spans, err := h.employeeService.GetCompanySpanOfControl(r.Context(), companyID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: spans})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// maxImportSize caps uploaded import files.
+const maxImportSize = 10 << 20
+
+type EmployeeImportHandler struct {
+	importService *services.EmployeeImportService
+}
+
+func NewEmployeeImportHandler(importService *services.EmployeeImportService) *EmployeeImportHandler {
+	return &EmployeeImportHandler{
+		importService: importService,
+	}
+}
+
+func (h *EmployeeImportHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/employees/import", h.Import).Methods(http.MethodPost)
+}
+
+// Import accepts either a multipart upload in the "file" field or the raw
+// file as the request body. The format comes from ?format=, then the file
+// extension, then the content type.
+func (h *EmployeeImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	format := strings.ToLower(r.URL.Query().Get("format"))
+
+	var data []byte
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "missing file upload")
+			return
+		}
+		defer file.Close()
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+		}
+		data, err = io.ReadAll(file)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "could not read file")
+			return
+		}
+	} else {
+		if data, err = io.ReadAll(r.Body); err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "could not read request body")
+			return
+		}
+	}
+
+	if format == "" {
+		switch r.Header.Get("Content-Type") {
+		case "text/csv":
+			format = "csv"
+		case "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":
+			format = "xlsx"
+		}
+	}
+
+	report, err := h.importService.Import(r.Context(), companyID, actorID(r), format, data, r.URL.Query().Get("dry_run") == "true")
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	switch {
+	case len(report.Errors) > 0:
+		utils.RespondWithJSON(w, http.StatusUnprocessableEntity, utils.APIResponse{Success: false, Message: "import failed validation; no employees were created", Data: report})
+	case report.DryRun:
+		utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "import is valid", Data: report})
+	default:
+		utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "employees imported", Data: report})
+	}
+}
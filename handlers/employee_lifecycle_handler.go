@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type EmployeeLifecycleHandler struct {
+	lifecycleService *services.EmployeeLifecycleService
+}
+
+func NewEmployeeLifecycleHandler(lifecycleService *services.EmployeeLifecycleService) *EmployeeLifecycleHandler {
+	return &EmployeeLifecycleHandler{
+		lifecycleService: lifecycleService,
+	}
+}
+
+func (h *EmployeeLifecycleHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/status-transitions", h.TransitionStatus).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/status-transitions", h.ListTransitions).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/status-transitions/{transitionID}", h.CancelTransition).Methods(http.MethodDelete)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/rehire", h.Rehire).Methods(http.MethodPost)
+}
+
+func (h *EmployeeLifecycleHandler) TransitionStatus(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.StatusTransitionRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	transition, err := h.lifecycleService.TransitionStatus(r.Context(), companyID, employeeID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	message := "status updated"
+	if transition.Status == "scheduled" {
+		message = "status transition scheduled"
+	}
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: message, Data: transition})
+}
+
+func (h *EmployeeLifecycleHandler) Rehire(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.RehireRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	transition, err := h.lifecycleService.Rehire(r.Context(), companyID, employeeID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "employee rehired", Data: transition})
+}
+
+func (h *EmployeeLifecycleHandler) ListTransitions(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	transitions, err := h.lifecycleService.ListTransitions(r.Context(), companyID, employeeID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: transitions})
+}
+
+func (h *EmployeeLifecycleHandler) CancelTransition(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+	transitionID, err := utils.ParseUUIDParam(r, "transitionID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid transition id")
+		return
+	}
+
+	if err := h.lifecycleService.CancelScheduledTransition(r.Context(), companyID, employeeID, transitionID); err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "status transition cancelled"})
+}
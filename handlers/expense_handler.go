@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type ExpenseHandler struct {
+	expenseService *services.ExpenseService
+}
+
+func NewExpenseHandler(expenseService *services.ExpenseService) *ExpenseHandler {
+	return &ExpenseHandler{
+		expenseService: expenseService,
+	}
+}
+
+func (h *ExpenseHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/expense-categories", h.ListExpenseCategories).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/expense-categories", h.CreateExpenseCategory).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/expense-categories/{categoryID}", h.UpdateExpenseCategory).Methods(http.MethodPatch)
+	r.HandleFunc("/companies/{companyID}/levels/{levelID}/expense-limits", h.ListExpenseLimits).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/levels/{levelID}/expense-limits", h.SetExpenseLimits).Methods(http.MethodPut)
+	r.HandleFunc("/companies/{companyID}/expense-claims", h.ListCompanyExpenseClaims).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/expense-claims", h.CreateExpenseClaim).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/expense-claims", h.ListEmployeeExpenseClaims).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/expense-claims/{claimID}", h.GetExpenseClaim).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/expense-claims/{claimID}", h.UpdateExpenseClaim).Methods(http.MethodPatch)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/expense-claims/{claimID}/history", h.ListApprovalHistory).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/expense-claims/{claimID}/items/{itemID}/receipt", h.AttachExpenseReceipt).Methods(http.MethodPut)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/expense-claims/{claimID}/{action:submit|approve|reject|request-changes|resubmit|withdraw}", h.ActOnExpenseClaim).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/expense-payouts", h.ListExpensePayouts).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/expense-payouts", h.CreateExpensePayout).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/expense-payouts/{batchID}", h.GetExpensePayout).Methods(http.MethodGet)
+}
+
+// companyEmployeeClaimParams parses the {companyID}, {employeeID} and
+// {claimID} path parameters.
+func companyEmployeeClaimParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, uuid.UUID, bool) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	claimID, err := utils.ParseUUIDParam(r, "claimID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid expense claim id")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	return companyID, employeeID, claimID, true
+}
+
+func (h *ExpenseHandler) ListExpenseCategories(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	activeOnly, err := queryBool(r, "active", false)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	categories, err := h.expenseService.ListExpenseCategories(r.Context(), companyID, activeOnly)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: categories})
+}
+
+func (h *ExpenseHandler) CreateExpenseCategory(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	var req dto.CreateExpenseCategoryRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	category, err := h.expenseService.CreateExpenseCategory(r.Context(), companyID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "expense category created", Data: category})
+}
+
+func (h *ExpenseHandler) UpdateExpenseCategory(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	categoryID, err := utils.ParseUUIDParam(r, "categoryID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid expense category id")
+		return
+	}
+
+	var req dto.UpdateExpenseCategoryRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	category, err := h.expenseService.UpdateExpenseCategory(r.Context(), companyID, categoryID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "expense category updated", Data: category})
+}
+
+func (h *ExpenseHandler) ListExpenseLimits(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	levelID, err := utils.ParseUUIDParam(r, "levelID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid level id")
+		return
+	}
+
+	limits, err := h.expenseService.ListExpenseLimits(r.Context(), companyID, levelID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: limits})
+}
+
+func (h *ExpenseHandler) SetExpenseLimits(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	levelID, err := utils.ParseUUIDParam(r, "levelID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid level id")
+		return
+	}
+
+	var req dto.SetExpenseLimitsRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	limits, err := h.expenseService.SetExpenseLimits(r.Context(), companyID, levelID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "expense limits updated", Data: limits})
+}
+
+func (h *ExpenseHandler) ListCompanyExpenseClaims(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	q := r.URL.Query()
+	claims, err := h.expenseService.ListCompanyExpenseClaims(r.Context(), companyID, q.Get("employee_id"), q.Get("status"))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: claims})
+}
+
+func (h *ExpenseHandler) CreateExpenseClaim(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateExpenseClaimRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	claim, err := h.expenseService.CreateExpenseClaim(r.Context(), companyID, employeeID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "expense claim created", Data: claim})
+}
+
+func (h *ExpenseHandler) ListEmployeeExpenseClaims(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	claims, err := h.expenseService.ListEmployeeExpenseClaims(r.Context(), companyID, employeeID, r.URL.Query().Get("status"))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: claims})
+}
+
+func (h *ExpenseHandler) GetExpenseClaim(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, claimID, ok := companyEmployeeClaimParams(w, r)
+	if !ok {
+		return
+	}
+
+	claim, err := h.expenseService.GetExpenseClaim(r.Context(), companyID, employeeID, claimID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: claim})
+}
+
+func (h *ExpenseHandler) UpdateExpenseClaim(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, claimID, ok := companyEmployeeClaimParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateExpenseClaimRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	claim, err := h.expenseService.UpdateExpenseClaim(r.Context(), companyID, employeeID, claimID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "expense claim updated", Data: claim})
+}
+
+func (h *ExpenseHandler) AttachExpenseReceipt(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, claimID, ok := companyEmployeeClaimParams(w, r)
+	if !ok {
+		return
+	}
+	itemID, err := utils.ParseUUIDParam(r, "itemID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid expense item id")
+		return
+	}
+
+	var req dto.AttachExpenseReceiptRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	claim, err := h.expenseService.AttachExpenseReceipt(r.Context(), companyID, employeeID, claimID, itemID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "receipt attached", Data: claim})
+}
+
+func (h *ExpenseHandler) ActOnExpenseClaim(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, claimID, ok := companyEmployeeClaimParams(w, r)
+	if !ok {
+		return
+	}
+
+	// The body is optional; only rejections and requests for changes need
+	// comments.
+	var req dto.ExpenseActionRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	action := mux.Vars(r)["action"]
+	claim, err := h.expenseService.ActOnExpenseClaim(r.Context(), companyID, employeeID, claimID, actorID(r), action, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "expense claim " + claim.Status, Data: claim})
+}
+
+func (h *ExpenseHandler) ListApprovalHistory(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, claimID, ok := companyEmployeeClaimParams(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.expenseService.ListApprovalHistory(r.Context(), companyID, employeeID, claimID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: history})
+}
+
+func (h *ExpenseHandler) ListExpensePayouts(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	batches, err := h.expenseService.ListExpensePayouts(r.Context(), companyID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: batches})
+}
+
+func (h *ExpenseHandler) CreateExpensePayout(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	// The body is optional; without claim_ids every approved claim is paid.
+	var req dto.CreateExpensePayoutRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	batches, err := h.expenseService.CreateExpensePayout(r.Context(), companyID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "expense payouts created", Data: batches})
+}
+
+func (h *ExpenseHandler) GetExpensePayout(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	batchID, err := utils.ParseUUIDParam(r, "batchID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid payout batch id")
+		return
+	}
+
+	batch, err := h.expenseService.GetExpensePayout(r.Context(), companyID, batchID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: batch})
+}
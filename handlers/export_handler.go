@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type ExportHandler struct {
+	exportService *services.ExportService
+}
+
+func NewExportHandler(exportService *services.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+func (h *ExportHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/employees/export", h.ExportEmployees).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/departments/export", h.ExportDepartments).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/expense-payouts/{batchID}/export", h.ExportExpensePayout).Methods(http.MethodGet)
+}
+
+func (h *ExportHandler) ExportEmployees(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	q := r.URL.Query()
+	listRequest := &dto.EmployeeListRequest{
+		Status:         q.Get("status"),
+		DepartmentID:   q.Get("department_id"),
+		ManagerID:      q.Get("manager_id"),
+		EmploymentType: q.Get("employment_type"),
+		Search:         q.Get("search"),
+	}
+
+	h.stream(w, r, companyID, "employees", func(format string, columns []string, out *exportResponseWriter) error {
+		return h.exportService.ExportEmployees(r.Context(), companyID, listRequest, format, columns, out)
+	})
+}
+
+func (h *ExportHandler) ExportDepartments(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	q := r.URL.Query()
+	listRequest := &dto.DepartmentListRequest{
+		Status: q.Get("status"),
+		Search: q.Get("search"),
+	}
+
+	h.stream(w, r, companyID, "departments", func(format string, columns []string, out *exportResponseWriter) error {
+		return h.exportService.ExportDepartments(r.Context(), companyID, listRequest, format, columns, out)
+	})
+}
+
+func (h *ExportHandler) ExportExpensePayout(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	batchID, err := utils.ParseUUIDParam(r, "batchID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid payout batch id")
+		return
+	}
+
+	h.stream(w, r, companyID, "expense-payout", func(format string, columns []string, out *exportResponseWriter) error {
+		return h.exportService.ExportExpensePayout(r.Context(), companyID, batchID, format, columns, out)
+	})
+}
+
+// stream sets the download headers and runs export. Errors raised before the
+// first byte is written become normal JSON error responses; later ones can
+// only be logged because the status line has already been sent.
+func (h *ExportHandler) stream(
+	w http.ResponseWriter,
+	r *http.Request,
+	companyID uuid.UUID,
+	name string,
+	export func(format string, columns []string, out *exportResponseWriter) error,
+) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+	contentType, ok := services.ExportContentType(format)
+	if !ok {
+		utils.RespondWithError(w, http.StatusBadRequest, "format must be csv, xlsx or jsonl")
+		return
+	}
+
+	var columns []string
+	if c := r.URL.Query().Get("columns"); c != "" {
+		columns = strings.Split(c, ",")
+	}
+
+	out := &exportResponseWriter{ResponseWriter: w}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, name, time.Now().UTC().Format("20060102"), format))
+
+	if err := export(format, columns, out); err != nil {
+		if out.written {
+			log.Printf("export of %s for company %s aborted: %v", name, companyID, err)
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		w.Header().Del("Content-Type")
+		respondWithServiceError(w, err)
+	}
+}
+
+// exportResponseWriter records whether any of the body has been sent.
+type exportResponseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (e *exportResponseWriter) Write(p []byte) (int, error) {
+	e.written = true
+	return e.ResponseWriter.Write(p)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// respondWithServiceError maps errors returned by the service layer onto HTTP
+// status codes. Unexpected errors are logged and hidden from the client.
+func respondWithServiceError(w http.ResponseWriter, err error) {
+	var validationErr *utils.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.RespondWithError(w, http.StatusBadRequest, validationErr.Error())
+	case errors.Is(err, services.ErrNotFound):
+		utils.RespondWithError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		utils.RespondWithError(w, http.StatusForbidden, err.Error())
+	default:
+		log.Printf("internal error: %v", err)
+		utils.RespondWithError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// actorHeader carries the ID of the employee performing the request until an
+// authentication middleware populates it from a verified token.
+const actorHeader = "X-Employee-ID"
+
+// actorID returns the acting employee, or nil when the header is absent or
+// malformed (e.g. system callers).
+func actorID(r *http.Request) *uuid.UUID {
+	id, err := uuid.Parse(r.Header.Get(actorHeader))
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// queryInt reads an optional integer query parameter, falling back to def
+// when it is absent.
+func queryInt(r *http.Request, key string, def int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &utils.ValidationError{Field: key, Message: "invalid " + key}
+	}
+	return n, nil
+}
+
+// queryDateRange reads the optional ?from= and ?to= dates (YYYY-MM-DD),
+// defaulting to the current calendar year.
+func queryDateRange(r *http.Request) (time.Time, time.Time, error) {
+	today := utils.Today()
+	from := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	for key, dest := range map[string]*time.Time{"from": &from, "to": &to} {
+		value := r.URL.Query().Get(key)
+		if value == "" {
+			continue
+		}
+		date, err := utils.ParseDate(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, &utils.ValidationError{Field: key, Message: key + " must be YYYY-MM-DD"}
+		}
+		*dest = date
+	}
+	return from, to, nil
+}
+
+// queryBool reads an optional boolean query parameter, falling back to def
+// when it is absent.
+func queryBool(r *http.Request, key string, def bool) (bool, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &utils.ValidationError{Field: key, Message: "invalid " + key}
+	}
+	return b, nil
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type HolidayHandler struct {
+	holidayService *services.HolidayService
+}
+
+func NewHolidayHandler(holidayService *services.HolidayService) *HolidayHandler {
+	return &HolidayHandler{
+		holidayService: holidayService,
+	}
+}
+
+func (h *HolidayHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/holiday-calendars", h.ListCalendars).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/holiday-calendars", h.CreateCalendar).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/holiday-calendars/{calendarID}", h.GetCalendar).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/holiday-calendars/{calendarID}", h.UpdateCalendar).Methods(http.MethodPatch)
+	r.HandleFunc("/companies/{companyID}/holiday-calendars/{calendarID}", h.DeleteCalendar).Methods(http.MethodDelete)
+	r.HandleFunc("/companies/{companyID}/holiday-calendars/{calendarID}/holidays", h.ListHolidays).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/holiday-calendars/{calendarID}/holidays", h.AddHoliday).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/holiday-calendars/{calendarID}/holidays/{holidayID}", h.DeleteHoliday).Methods(http.MethodDelete)
+	r.HandleFunc("/companies/{companyID}/holiday-calendars/{calendarID}/import", h.ImportICal).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/work-calendar", h.GetEmployeeWorkCalendar).Methods(http.MethodGet)
+}
+
+// companyCalendarParams parses the {companyID} and {calendarID} path
+// parameters.
+func companyCalendarParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	calendarID, err := utils.ParseUUIDParam(r, "calendarID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid calendar id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return companyID, calendarID, true
+}
+
+func (h *HolidayHandler) ListCalendars(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	calendars, err := h.holidayService.ListCalendars(r.Context(), companyID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: calendars})
+}
+
+func (h *HolidayHandler) CreateCalendar(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	var req dto.CreateHolidayCalendarRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	calendar, err := h.holidayService.CreateCalendar(r.Context(), companyID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "holiday calendar created", Data: calendar})
+}
+
+func (h *HolidayHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := companyCalendarParams(w, r)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.holidayService.GetCalendar(r.Context(), companyID, calendarID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: calendar})
+}
+
+func (h *HolidayHandler) UpdateCalendar(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := companyCalendarParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateHolidayCalendarRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	calendar, err := h.holidayService.UpdateCalendar(r.Context(), companyID, calendarID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "holiday calendar updated", Data: calendar})
+}
+
+func (h *HolidayHandler) DeleteCalendar(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := companyCalendarParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.holidayService.DeleteCalendar(r.Context(), companyID, calendarID); err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "holiday calendar deleted"})
+}
+
+func (h *HolidayHandler) ListHolidays(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := companyCalendarParams(w, r)
+	if !ok {
+		return
+	}
+
+	from, to, err := queryDateRange(r)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	holidays, err := h.holidayService.ListHolidays(r.Context(), companyID, calendarID, from, to)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: holidays})
+}
+
+func (h *HolidayHandler) AddHoliday(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := companyCalendarParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateHolidayRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	holiday, err := h.holidayService.AddHoliday(r.Context(), companyID, calendarID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "holiday added", Data: holiday})
+}
+
+func (h *HolidayHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := companyCalendarParams(w, r)
+	if !ok {
+		return
+	}
+	holidayID, err := utils.ParseUUIDParam(r, "holidayID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid holiday id")
+		return
+	}
+
+	if err := h.holidayService.DeleteHoliday(r.Context(), companyID, calendarID, holidayID); err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "holiday deleted"})
+}
+
+// ImportICal accepts an .ics file either as a multipart upload in the "file"
+// field or as the raw request body.
+func (h *HolidayHandler) ImportICal(w http.ResponseWriter, r *http.Request) {
+	companyID, calendarID, ok := companyCalendarParams(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "missing file upload")
+			return
+		}
+		defer file.Close()
+		if data, err = io.ReadAll(file); err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, "could not read file")
+			return
+		}
+	} else if data, err = io.ReadAll(r.Body); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "could not read request body")
+		return
+	}
+
+	report, err := h.holidayService.ImportICal(r.Context(), companyID, calendarID, data)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "holidays imported", Data: report})
+}
+
+func (h *HolidayHandler) GetEmployeeWorkCalendar(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	from, to, err := queryDateRange(r)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	calendar, err := h.holidayService.EmployeeWorkCalendar(r.Context(), companyID, employeeID, from, to)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: calendar})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type LeaveLedgerHandler struct {
+	ledgerService *services.LeaveLedgerService
+}
+
+func NewLeaveLedgerHandler(ledgerService *services.LeaveLedgerService) *LeaveLedgerHandler {
+	return &LeaveLedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+func (h *LeaveLedgerHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-statement", h.GetBalanceStatement).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-balances/adjustments", h.AdjustBalance).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/leave-ledger/consistency", h.CheckConsistency).Methods(http.MethodGet)
+}
+
+func (h *LeaveLedgerHandler) GetBalanceStatement(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	year, err := queryInt(r, "year", utils.Today().Year())
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	statement, err := h.ledgerService.BalanceStatement(r.Context(), companyID, employeeID, year)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: statement})
+}
+
+func (h *LeaveLedgerHandler) AdjustBalance(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.LeaveAdjustmentRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	balance, err := h.ledgerService.AdjustBalance(r.Context(), companyID, employeeID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "leave balance adjusted", Data: balance})
+}
+
+func (h *LeaveLedgerHandler) CheckConsistency(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	year, err := queryInt(r, "year", utils.Today().Year())
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	report, err := h.ledgerService.CheckConsistency(r.Context(), companyID, year)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: report})
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type LeaveRequestHandler struct {
+	leaveRequestService *services.LeaveRequestService
+}
+
+func NewLeaveRequestHandler(leaveRequestService *services.LeaveRequestService) *LeaveRequestHandler {
+	return &LeaveRequestHandler{
+		leaveRequestService: leaveRequestService,
+	}
+}
+
+func (h *LeaveRequestHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-requests", h.SubmitLeaveRequest).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-requests", h.ListLeaveRequests).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-requests/{requestID}", h.GetLeaveRequest).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-requests/{requestID}", h.UpdateLeaveRequest).Methods(http.MethodPatch)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-requests/{requestID}/history", h.ListApprovalHistory).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-requests/{requestID}/{action:approve|reject|request-changes|resubmit|cancel|withdraw}", h.ActOnLeaveRequest).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-requests/{requestID}/attachment", h.AttachLeaveDocument).Methods(http.MethodPut)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-balances", h.ListLeaveBalances).Methods(http.MethodGet)
+}
+
+func (h *LeaveRequestHandler) SubmitLeaveRequest(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.SubmitLeaveRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.leaveRequestService.SubmitLeaveRequest(r.Context(), companyID, employeeID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "leave request submitted", Data: result})
+}
+
+func (h *LeaveRequestHandler) ListLeaveRequests(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	requests, err := h.leaveRequestService.ListLeaveRequests(r.Context(), companyID, employeeID, r.URL.Query().Get("status"))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: requests})
+}
+
+func (h *LeaveRequestHandler) GetLeaveRequest(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+	requestID, err := utils.ParseUUIDParam(r, "requestID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid leave request id")
+		return
+	}
+
+	request, err := h.leaveRequestService.GetLeaveRequest(r.Context(), companyID, employeeID, requestID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: request})
+}
+
+func (h *LeaveRequestHandler) UpdateLeaveRequest(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+	requestID, err := utils.ParseUUIDParam(r, "requestID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid leave request id")
+		return
+	}
+
+	var req dto.UpdateLeaveRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.leaveRequestService.UpdateLeaveRequest(r.Context(), companyID, employeeID, requestID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "leave request updated", Data: result})
+}
+
+func (h *LeaveRequestHandler) AttachLeaveDocument(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+	requestID, err := utils.ParseUUIDParam(r, "requestID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid leave request id")
+		return
+	}
+
+	var req dto.AttachLeaveDocumentRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	request, err := h.leaveRequestService.AttachLeaveDocument(r.Context(), companyID, employeeID, requestID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "document attached", Data: request})
+}
+
+func (h *LeaveRequestHandler) ActOnLeaveRequest(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+	requestID, err := utils.ParseUUIDParam(r, "requestID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid leave request id")
+		return
+	}
+
+	// The body is optional; only rejections and requests for changes need
+	// comments.
+	var req dto.LeaveActionRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	action := mux.Vars(r)["action"]
+	result, err := h.leaveRequestService.ActOnLeaveRequest(r.Context(), companyID, employeeID, requestID, actorID(r), action, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "leave request " + result.Request.Status, Data: result})
+}
+
+func (h *LeaveRequestHandler) ListApprovalHistory(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+	requestID, err := utils.ParseUUIDParam(r, "requestID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid leave request id")
+		return
+	}
+
+	history, err := h.leaveRequestService.ListApprovalHistory(r.Context(), companyID, employeeID, requestID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: history})
+}
+
+func (h *LeaveRequestHandler) ListLeaveBalances(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	year, err := queryInt(r, "year", utils.Today().Year())
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	balances, err := h.leaveRequestService.ListLeaveBalances(r.Context(), companyID, employeeID, year)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: balances})
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type LeaveTypeHandler struct {
+	leaveTypeService *services.LeaveTypeService
+}
+
+func NewLeaveTypeHandler(leaveTypeService *services.LeaveTypeService) *LeaveTypeHandler {
+	return &LeaveTypeHandler{
+		leaveTypeService: leaveTypeService,
+	}
+}
+
+func (h *LeaveTypeHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/leave-types", h.ListLeaveTypes).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/leave-types", h.CreateLeaveType).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/leave-types/{leaveTypeID}", h.GetLeaveType).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/leave-types/{leaveTypeID}", h.UpdateLeaveType).Methods(http.MethodPatch)
+	r.HandleFunc("/companies/{companyID}/leave-types/{leaveTypeID}", h.DeleteLeaveType).Methods(http.MethodDelete)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/leave-eligibility", h.ListEligibility).Methods(http.MethodGet)
+}
+
+// companyLeaveTypeParams parses the {companyID} and {leaveTypeID} path
+// parameters.
+func companyLeaveTypeParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	leaveTypeID, err := utils.ParseUUIDParam(r, "leaveTypeID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid leave type id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return companyID, leaveTypeID, true
+}
+
+func (h *LeaveTypeHandler) ListLeaveTypes(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	leaveTypes, err := h.leaveTypeService.ListLeaveTypes(r.Context(), companyID, r.URL.Query().Get("status"))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: leaveTypes})
+}
+
+func (h *LeaveTypeHandler) CreateLeaveType(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	var req dto.CreateLeaveTypeRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	leaveType, err := h.leaveTypeService.CreateLeaveType(r.Context(), companyID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "leave type created", Data: leaveType})
+}
+
+func (h *LeaveTypeHandler) GetLeaveType(w http.ResponseWriter, r *http.Request) {
+	companyID, leaveTypeID, ok := companyLeaveTypeParams(w, r)
+	if !ok {
+		return
+	}
+
+	leaveType, err := h.leaveTypeService.GetLeaveType(r.Context(), companyID, leaveTypeID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: leaveType})
+}
+
+func (h *LeaveTypeHandler) UpdateLeaveType(w http.ResponseWriter, r *http.Request) {
+	companyID, leaveTypeID, ok := companyLeaveTypeParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateLeaveTypeRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	leaveType, err := h.leaveTypeService.UpdateLeaveType(r.Context(), companyID, leaveTypeID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "leave type updated", Data: leaveType})
+}
+
+func (h *LeaveTypeHandler) DeleteLeaveType(w http.ResponseWriter, r *http.Request) {
+	companyID, leaveTypeID, ok := companyLeaveTypeParams(w, r)
+	if !ok {
+		return
+	}
+	hardDelete := r.URL.Query().Get("hard") == "true"
+
+	if err := h.leaveTypeService.DeleteLeaveType(r.Context(), companyID, leaveTypeID, hardDelete); err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	message := "leave type deactivated"
+	if hardDelete {
+		message = "leave type deleted"
+	}
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: message})
+}
+
+func (h *LeaveTypeHandler) ListEligibility(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	eligibility, err := h.leaveTypeService.ListEligibility(r.Context(), companyID, employeeID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: eligibility})
+}
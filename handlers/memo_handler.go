@@ -0,0 +1,493 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type MemoHandler struct {
+	memoService *services.MemoService
+}
+
+func NewMemoHandler(memoService *services.MemoService) *MemoHandler {
+	return &MemoHandler{
+		memoService: memoService,
+	}
+}
+
+func (h *MemoHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/memo-settings", h.GetMemoSettings).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/memo-settings", h.SetMemoSettings).Methods(http.MethodPut)
+	r.HandleFunc("/companies/{companyID}/memos", h.ListMemos).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/memos", h.CreateMemo).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/memos/{memoID}", h.GetMemo).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/memos/{memoID}", h.UpdateMemo).Methods(http.MethodPatch)
+	r.HandleFunc("/companies/{companyID}/memos/{memoID}/publish", h.PublishMemo).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/memos/{memoID}/delivery", h.GetMemoDeliveryReport).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/memos/{memoID}/history", h.ListMemoApprovalHistory).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/memos/{memoID}/{action:submit|approve|reject|request-changes|resubmit}", h.ActOnMemo).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/memos", h.ListReceivedMemos).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/memos/{memoID}/read", h.MarkMemoRead).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/memos/{memoID}/acknowledge", h.AcknowledgeMemo).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/disciplinary-memos", h.ListDisciplinaryMemos).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/memos/{memoID}/responses", h.ListMemoThread).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/memos/{memoID}/responses", h.AddMemoThreadEntry).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/disciplinary-cases", h.ListDisciplinaryCases).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/disciplinary-cases", h.CreateDisciplinaryCase).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/disciplinary-cases/{caseID}", h.GetDisciplinaryCase).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/disciplinary-cases/{caseID}/memos", h.LinkCaseMemo).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/disciplinary-cases/{caseID}/memos/{memoID}", h.UnlinkCaseMemo).Methods(http.MethodDelete)
+	r.HandleFunc("/companies/{companyID}/disciplinary-cases/{caseID}/close", h.CloseDisciplinaryCase).Methods(http.MethodPost)
+}
+
+// companyMemoParams parses the {companyID} and {memoID} path parameters.
+func companyMemoParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	memoID, err := utils.ParseUUIDParam(r, "memoID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid memo id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return companyID, memoID, true
+}
+
+// companyEmployeeMemoParams parses the {companyID}, {employeeID} and
+// {memoID} path parameters.
+func companyEmployeeMemoParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, uuid.UUID, bool) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	memoID, err := utils.ParseUUIDParam(r, "memoID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid memo id")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	return companyID, employeeID, memoID, true
+}
+
+// companyCaseParams parses the {companyID} and {caseID} path parameters.
+func companyCaseParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	caseID, err := utils.ParseUUIDParam(r, "caseID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid case id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return companyID, caseID, true
+}
+
+func (h *MemoHandler) GetMemoSettings(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	settings, err := h.memoService.GetMemoSettings(r.Context(), companyID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: settings})
+}
+
+func (h *MemoHandler) SetMemoSettings(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	var req dto.MemoSettingsRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	settings, err := h.memoService.SetMemoSettings(r.Context(), companyID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "memo settings updated", Data: settings})
+}
+
+func (h *MemoHandler) ListMemos(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	q := r.URL.Query()
+	memos, err := h.memoService.ListMemos(r.Context(), companyID, actorID(r), q.Get("sender_id"), q.Get("status"), q.Get("type"))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: memos})
+}
+
+func (h *MemoHandler) CreateMemo(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	var req dto.CreateMemoRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	memo, err := h.memoService.CreateMemo(r.Context(), companyID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "memo drafted", Data: memo})
+}
+
+func (h *MemoHandler) GetMemo(w http.ResponseWriter, r *http.Request) {
+	companyID, memoID, ok := companyMemoParams(w, r)
+	if !ok {
+		return
+	}
+
+	memo, err := h.memoService.GetMemo(r.Context(), companyID, memoID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: memo})
+}
+
+func (h *MemoHandler) UpdateMemo(w http.ResponseWriter, r *http.Request) {
+	companyID, memoID, ok := companyMemoParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateMemoRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	memo, err := h.memoService.UpdateMemo(r.Context(), companyID, memoID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "memo updated", Data: memo})
+}
+
+func (h *MemoHandler) PublishMemo(w http.ResponseWriter, r *http.Request) {
+	companyID, memoID, ok := companyMemoParams(w, r)
+	if !ok {
+		return
+	}
+
+	memo, err := h.memoService.PublishMemo(r.Context(), companyID, memoID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "memo published", Data: memo})
+}
+
+func (h *MemoHandler) ActOnMemo(w http.ResponseWriter, r *http.Request) {
+	companyID, memoID, ok := companyMemoParams(w, r)
+	if !ok {
+		return
+	}
+
+	// The body is optional; only rejections and requests for changes need
+	// comments.
+	var req dto.MemoActionRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	action := mux.Vars(r)["action"]
+	memo, err := h.memoService.ActOnMemo(r.Context(), companyID, memoID, actorID(r), action, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "memo " + memo.Status, Data: memo})
+}
+
+func (h *MemoHandler) ListMemoApprovalHistory(w http.ResponseWriter, r *http.Request) {
+	companyID, memoID, ok := companyMemoParams(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.memoService.ListMemoApprovalHistory(r.Context(), companyID, memoID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: history})
+}
+
+func (h *MemoHandler) GetMemoDeliveryReport(w http.ResponseWriter, r *http.Request) {
+	companyID, memoID, ok := companyMemoParams(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.memoService.GetMemoDeliveryReport(r.Context(), companyID, memoID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: report})
+}
+
+func (h *MemoHandler) ListReceivedMemos(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+	unreadOnly, err := queryBool(r, "unread", false)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	memos, err := h.memoService.ListReceivedMemos(r.Context(), companyID, employeeID, actorID(r), unreadOnly)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: memos})
+}
+
+func (h *MemoHandler) MarkMemoRead(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, memoID, ok := companyEmployeeMemoParams(w, r)
+	if !ok {
+		return
+	}
+
+	memo, err := h.memoService.MarkMemoRead(r.Context(), companyID, employeeID, memoID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "memo marked as read", Data: memo})
+}
+
+func (h *MemoHandler) AcknowledgeMemo(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, memoID, ok := companyEmployeeMemoParams(w, r)
+	if !ok {
+		return
+	}
+
+	memo, err := h.memoService.AcknowledgeMemo(r.Context(), companyID, employeeID, memoID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "memo acknowledged", Data: memo})
+}
+
+func (h *MemoHandler) ListDisciplinaryMemos(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	memos, err := h.memoService.ListDisciplinaryMemos(r.Context(), companyID, employeeID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: memos})
+}
+
+func (h *MemoHandler) ListMemoThread(w http.ResponseWriter, r *http.Request) {
+	companyID, memoID, ok := companyMemoParams(w, r)
+	if !ok {
+		return
+	}
+
+	thread, err := h.memoService.ListMemoThread(r.Context(), companyID, memoID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: thread})
+}
+
+func (h *MemoHandler) AddMemoThreadEntry(w http.ResponseWriter, r *http.Request) {
+	companyID, memoID, ok := companyMemoParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateMemoThreadEntryRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	entry, err := h.memoService.AddMemoThreadEntry(r.Context(), companyID, memoID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "response posted", Data: entry})
+}
+
+func (h *MemoHandler) ListDisciplinaryCases(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	q := r.URL.Query()
+	cases, err := h.memoService.ListDisciplinaryCases(r.Context(), companyID, actorID(r), q.Get("employee_id"), q.Get("status"))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: cases})
+}
+
+func (h *MemoHandler) CreateDisciplinaryCase(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	var req dto.CreateDisciplinaryCaseRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	c, err := h.memoService.CreateDisciplinaryCase(r.Context(), companyID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "disciplinary case opened", Data: c})
+}
+
+func (h *MemoHandler) GetDisciplinaryCase(w http.ResponseWriter, r *http.Request) {
+	companyID, caseID, ok := companyCaseParams(w, r)
+	if !ok {
+		return
+	}
+
+	c, err := h.memoService.GetDisciplinaryCase(r.Context(), companyID, caseID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: c})
+}
+
+func (h *MemoHandler) LinkCaseMemo(w http.ResponseWriter, r *http.Request) {
+	companyID, caseID, ok := companyCaseParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.LinkCaseMemoRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	c, err := h.memoService.LinkCaseMemo(r.Context(), companyID, caseID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "memo linked to case", Data: c})
+}
+
+func (h *MemoHandler) UnlinkCaseMemo(w http.ResponseWriter, r *http.Request) {
+	companyID, caseID, ok := companyCaseParams(w, r)
+	if !ok {
+		return
+	}
+	memoID, err := utils.ParseUUIDParam(r, "memoID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid memo id")
+		return
+	}
+
+	c, err := h.memoService.UnlinkCaseMemo(r.Context(), companyID, caseID, memoID, actorID(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "memo unlinked from case", Data: c})
+}
+
+func (h *MemoHandler) CloseDisciplinaryCase(w http.ResponseWriter, r *http.Request) {
+	companyID, caseID, ok := companyCaseParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CloseDisciplinaryCaseRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	c, err := h.memoService.CloseDisciplinaryCase(r.Context(), companyID, caseID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "disciplinary case closed", Data: c})
+}
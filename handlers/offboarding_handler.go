@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type OffboardingHandler struct {
+	offboardingService *services.OffboardingService
+}
+
+func NewOffboardingHandler(offboardingService *services.OffboardingService) *OffboardingHandler {
+	return &OffboardingHandler{
+		offboardingService: offboardingService,
+	}
+}
+
+func (h *OffboardingHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/offboarding", h.Initiate).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/offboarding", h.GetChecklist).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/offboarding/{checklistID}/items/{itemID}", h.UpdateChecklistItem).Methods(http.MethodPatch)
+}
+
+func (h *OffboardingHandler) Initiate(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.OffboardingRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	checklist, err := h.offboardingService.Initiate(r.Context(), companyID, employeeID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "offboarding initiated", Data: checklist})
+}
+
+func (h *OffboardingHandler) GetChecklist(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	checklist, err := h.offboardingService.GetChecklist(r.Context(), companyID, employeeID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: checklist})
+}
+
+func (h *OffboardingHandler) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	checklistID, err := utils.ParseUUIDParam(r, "checklistID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid checklist id")
+		return
+	}
+	itemID, err := utils.ParseUUIDParam(r, "itemID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid item id")
+		return
+	}
+
+	var req dto.UpdateChecklistItemRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	checklist, err := h.offboardingService.UpdateChecklistItem(r.Context(), companyID, checklistID, itemID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "checklist item updated", Data: checklist})
+}
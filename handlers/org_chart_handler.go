@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type OrgChartHandler struct {
+	orgChartService *services.OrgChartService
+}
+
+func NewOrgChartHandler(orgChartService *services.OrgChartService) *OrgChartHandler {
+	return &OrgChartHandler{
+		orgChartService: orgChartService,
+	}
+}
+
+func (h *OrgChartHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/org-chart", h.GetOrgChart).Methods(http.MethodGet)
+}
+
+// GetOrgChart serves the org chart as nested JSON, Graphviz DOT or SVG.
+// Query parameters: type (people|department), root_id, max_depth and
+// format (json|dot|svg).
+func (h *OrgChartHandler) GetOrgChart(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	query := r.URL.Query()
+	req := dto.OrgChartRequest{
+		Type:   query.Get("type"),
+		RootID: query.Get("root_id"),
+		Format: query.Get("format"),
+	}
+	if req.MaxDepth, err = queryInt(r, "max_depth", 0); err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	var contentType string
+	switch req.Format {
+	case "", "json":
+	case "dot":
+		contentType = "text/vnd.graphviz; charset=utf-8"
+	case "svg":
+		contentType = "image/svg+xml"
+	default:
+		utils.RespondWithError(w, http.StatusBadRequest, "format must be json, dot or svg")
+		return
+	}
+
+	chart, err := h.orgChartService.GetOrgChart(r.Context(), companyID, &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	var body []byte
+	switch req.Format {
+	case "dot":
+		body = services.RenderOrgChartDOT(chart)
+	case "svg":
+		body = services.RenderOrgChartSVG(chart)
+	default:
+		utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: chart})
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
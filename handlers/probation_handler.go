@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type ProbationHandler struct {
+	probationService *services.ProbationService
+}
+
+func NewProbationHandler(probationService *services.ProbationService) *ProbationHandler {
+	return &ProbationHandler{
+		probationService: probationService,
+	}
+}
+
+func (h *ProbationHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/probations/upcoming", h.ListUpcoming).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/probation/decision", h.Decide).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/probation/reviews", h.ListReviews).Methods(http.MethodGet)
+}
+
+func (h *ProbationHandler) ListUpcoming(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+	withinDays, err := queryInt(r, "within_days", 30)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	upcoming, err := h.probationService.ListUpcoming(r.Context(), companyID, withinDays)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: upcoming})
+}
+
+func (h *ProbationHandler) Decide(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.ProbationDecisionRequest
+	if err := utils.DecodeJSONBody(r, &req); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	review, err := h.probationService.Decide(r.Context(), companyID, employeeID, actorID(r), &req)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{Success: true, Message: "probation " + review.Decision, Data: review})
+}
+
+func (h *ProbationHandler) ListReviews(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.probationService.ListReviews(r.Context(), companyID, employeeID)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: reviews})
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type TeamCalendarHandler struct {
+	teamCalendarService *services.TeamCalendarService
+}
+
+func NewTeamCalendarHandler(teamCalendarService *services.TeamCalendarService) *TeamCalendarHandler {
+	return &TeamCalendarHandler{
+		teamCalendarService: teamCalendarService,
+	}
+}
+
+func (h *TeamCalendarHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/companies/{companyID}/team-calendar", h.GetTeamCalendar).Methods(http.MethodGet)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/calendar-feed", h.RotateCalendarFeed).Methods(http.MethodPost)
+	r.HandleFunc("/companies/{companyID}/employees/{employeeID}/calendar-feed", h.RevokeCalendarFeed).Methods(http.MethodDelete)
+	// Calendar apps cannot send credentials; the token in the path is the
+	// only authentication.
+	r.HandleFunc("/calendar-feeds/{token}.ics", h.GetCalendarFeed).Methods(http.MethodGet)
+}
+
+func (h *TeamCalendarHandler) GetTeamCalendar(w http.ResponseWriter, r *http.Request) {
+	companyID, err := utils.ParseUUIDParam(r, "companyID")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, "invalid company id")
+		return
+	}
+
+	from, to, err := queryDateRange(r)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	req := dto.TeamCalendarRequest{
+		ManagerID:    query.Get("manager_id"),
+		DepartmentID: query.Get("department_id"),
+	}
+	for _, opt := range []struct {
+		key  string
+		def  bool
+		dest *bool
+	}{
+		{"direct_only", false, &req.DirectOnly},
+		{"include_subdepartments", true, &req.IncludeSubDepartments},
+		{"include_pending", true, &req.IncludePending},
+	} {
+		if *opt.dest, err = queryBool(r, opt.key, opt.def); err != nil {
+			respondWithServiceError(w, err)
+			return
+		}
+	}
+	if req.MaxAbsent, err = queryInt(r, "max_absent", 0); err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	calendar, err := h.teamCalendarService.TeamCalendar(r.Context(), companyID, &req, from, to)
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Data: calendar})
+}
+
+func (h *TeamCalendarHandler) RotateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	feed, err := h.teamCalendarService.RotateCalendarFeed(r.Context(), companyID, employeeID, actorID(r), feedBaseURL(r))
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, utils.APIResponse{
+		Success: true,
+		Message: "calendar feed created; any previous feed URL no longer works",
+		Data:    feed,
+	})
+}
+
+func (h *TeamCalendarHandler) RevokeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	companyID, employeeID, ok := companyEmployeeParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.teamCalendarService.RevokeCalendarFeed(r.Context(), companyID, employeeID, actorID(r)); err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, utils.APIResponse{Success: true, Message: "calendar feed revoked"})
+}
+
+func (h *TeamCalendarHandler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.teamCalendarService.CalendarFeed(r.Context(), mux.Vars(r)["token"])
+	if err != nil {
+		respondWithServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.WriteHeader(http.StatusOK)
+	w.Write(feed)
+}
+
+// feedBaseURL is the absolute URL the API is served under, derived from the
+// request so feed links work behind a proxy that sets X-Forwarded-Proto.
+func feedBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	prefix, _, _ := strings.Cut(r.URL.Path, "/companies/")
+	return scheme + "://" + r.Host + prefix
+}
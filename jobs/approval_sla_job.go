@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/services"
+)
+
+// EnforceApprovalSLAs reminds approvers ahead of step deadlines and
+// escalates or decides steps once they expire.
+func EnforceApprovalSLAs(approvalService *services.ApprovalService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		_, err := approvalService.EnforceApprovalSLAs(ctx, time.Now())
+		return err
+	}
+}
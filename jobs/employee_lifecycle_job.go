@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// ApplyScheduledStatusTransitions applies effective-dated employee status
+// changes once their date arrives.
+func ApplyScheduledStatusTransitions(lifecycleService *services.EmployeeLifecycleService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		_, err := lifecycleService.ApplyDueTransitions(ctx, utils.Today())
+		return err
+	}
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// AccrueLeaveBalances opens and tops up the current year's leave balances,
+// including the carry-forward from the previous year.
+func AccrueLeaveBalances(accrualService *services.LeaveAccrualService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		_, err := accrualService.RunAccruals(ctx, utils.Today())
+		return err
+	}
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// EnforceLeaveDocumentation flags, or reverts, leave requests whose required
+// documentation is past its grace period.
+func EnforceLeaveDocumentation(leaveRequestService *services.LeaveRequestService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		_, err := leaveRequestService.EnforceLeaveDocumentation(ctx, utils.Today())
+		return err
+	}
+}
@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/services"
+)
+
+// RemindUnreadMemos reminds recipients who have not read, or not
+// acknowledged, urgent memos.
+func RemindUnreadMemos(memoService *services.MemoService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		_, err := memoService.SendMemoReminders(ctx, time.Now())
+		return err
+	}
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// RunScheduledOffboardings runs the automated offboarding steps for
+// terminations that were scheduled ahead and have now taken effect.
+func RunScheduledOffboardings(offboardingService *services.OffboardingService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		_, err := offboardingService.RunDueOffboardings(ctx, utils.Today())
+		return err
+	}
+}
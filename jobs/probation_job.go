@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/services"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// NotifyProbationExpiries reminds managers and HR about probations ending
+// soon or awaiting a decision.
+func NotifyProbationExpiries(probationService *services.ProbationService) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+
+		_, err := probationService.NotifyUpcomingExpiries(ctx, utils.Today())
+		return err
+	}
+}
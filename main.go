@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/falasefemi2/companyflowlow/config"
 	"github.com/falasefemi2/companyflowlow/database"
+	"github.com/falasefemi2/companyflowlow/handlers"
+	"github.com/falasefemi2/companyflowlow/jobs"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/services"
 )
 
 func main() {
@@ -23,11 +29,69 @@ func main() {
 		log.Fatalf("Migration failed: %v", err)
 	}
 
+	employeeRepo := repositories.NewEmployeeRepository(pool)
+	employeeService := services.NewEmployeeService(employeeRepo)
+	orgChartService := services.NewOrgChartService(repositories.NewOrgChartRepository(pool))
+	lifecycleService := services.NewEmployeeLifecycleService(employeeRepo, repositories.NewEmployeeLifecycleRepository(pool))
+	offboardingService := services.NewOffboardingService(employeeRepo, repositories.NewOffboardingRepository(pool), lifecycleService)
+	importService := services.NewEmployeeImportService(repositories.NewEmployeeImportRepository(pool))
+	exportService := services.NewExportService(repositories.NewExportRepository(pool))
+	leaveTypeRepo := repositories.NewLeaveTypeRepository(pool)
+	leaveTypeService := services.NewLeaveTypeService(leaveTypeRepo, employeeRepo)
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(pool), employeeRepo)
+	departmentRepo := repositories.NewDepartmentRepository(pool)
+	holidayService := services.NewHolidayService(repositories.NewHolidayRepository(pool), employeeRepo, departmentRepo)
+	leaveAccrualService := services.NewLeaveAccrualService(repositories.NewLeaveAccrualRepository(pool))
+	approvalService := services.NewApprovalService(repositories.NewApprovalRepository(pool), employeeRepo, departmentRepo, notificationService)
+	leaveRequestRepo := repositories.NewLeaveRequestRepository(pool)
+	leaveRequestService := services.NewLeaveRequestService(
+		employeeRepo, leaveTypeRepo, leaveRequestRepo,
+		holidayService, repositories.NewApprovalHistoryRepository(pool), notificationService, approvalService,
+	)
+	leaveLedgerService := services.NewLeaveLedgerService(employeeRepo, leaveTypeRepo, leaveRequestRepo, repositories.NewLeaveLedgerRepository(pool))
+	teamCalendarService := services.NewTeamCalendarService(employeeRepo, departmentRepo, repositories.NewLeaveCalendarRepository(pool), holidayService)
+	expenseService := services.NewExpenseService(
+		employeeRepo, repositories.NewExpenseRepository(pool),
+		repositories.NewApprovalHistoryRepository(pool), notificationService, approvalService,
+	)
+	memoService := services.NewMemoService(
+		employeeRepo, repositories.NewMemoRepository(pool),
+		repositories.NewApprovalHistoryRepository(pool), notificationService, approvalService,
+	)
+	probationService := services.NewProbationService(employeeRepo, repositories.NewProbationRepository(pool), notificationService, lifecycleService, offboardingService)
+
+	scheduler := jobs.NewScheduler()
+	scheduler.Register("employee-status-transitions", time.Hour, jobs.ApplyScheduledStatusTransitions(lifecycleService))
+	scheduler.Register("offboarding", time.Hour, jobs.RunScheduledOffboardings(offboardingService))
+	scheduler.Register("probation-reminders", 24*time.Hour, jobs.NotifyProbationExpiries(probationService))
+	scheduler.Register("leave-accrual", 24*time.Hour, jobs.AccrueLeaveBalances(leaveAccrualService))
+	scheduler.Register("leave-documentation", 24*time.Hour, jobs.EnforceLeaveDocumentation(leaveRequestService))
+	scheduler.Register("approval-slas", time.Hour, jobs.EnforceApprovalSLAs(approvalService))
+	scheduler.Register("memo-reminders", time.Hour, jobs.RemindUnreadMemos(memoService))
+	scheduler.Start(context.Background())
+
 	router := mux.NewRouter()
+	api := router.PathPrefix("/api").Subrouter()
+	handlers.NewEmployeeHandler(employeeService).RegisterRoutes(api)
+	handlers.NewEmployeeImportHandler(importService).RegisterRoutes(api)
+	handlers.NewExportHandler(exportService).RegisterRoutes(api)
+	handlers.NewOrgChartHandler(orgChartService).RegisterRoutes(api)
+	handlers.NewEmployeeLifecycleHandler(lifecycleService).RegisterRoutes(api)
+	handlers.NewOffboardingHandler(offboardingService).RegisterRoutes(api)
+	handlers.NewLeaveTypeHandler(leaveTypeService).RegisterRoutes(api)
+	handlers.NewLeaveRequestHandler(leaveRequestService).RegisterRoutes(api)
+	handlers.NewApprovalHandler(approvalService).RegisterRoutes(api)
+	handlers.NewHolidayHandler(holidayService).RegisterRoutes(api)
+	handlers.NewTeamCalendarHandler(teamCalendarService).RegisterRoutes(api)
+	handlers.NewLeaveLedgerHandler(leaveLedgerService).RegisterRoutes(api)
+	handlers.NewProbationHandler(probationService).RegisterRoutes(api)
+	handlers.NewExpenseHandler(expenseService).RegisterRoutes(api)
+	handlers.NewMemoHandler(memoService).RegisterRoutes(api)
+	handlers.NewNotificationHandler(notificationService).RegisterRoutes(api)
 
 	port := ":8080"
 	fmt.Printf("\n✓ Server starting on http://localhost%s\n", port)
-	fmt.Println("Press Ctrl+C to stop the server\n")
+	fmt.Print("Press Ctrl+C to stop the server\n\n")
 
 	if err := http.ListenAndServe(port, router); err != nil {
 		log.Fatalf("Server error: %v", err)
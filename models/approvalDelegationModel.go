@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalDelegation lets DelegateID decide in DelegatorID's place.
+type ApprovalDelegation struct {
+	ID           uuid.UUID  `db:"id"`
+	CompanyID    uuid.UUID  `db:"company_id"`
+	DelegatorID  uuid.UUID  `db:"delegator_id"`
+	DelegateID   uuid.UUID  `db:"delegate_id"`
+	WorkflowType string     `db:"workflow_type"` // leave, memo, expense; empty covers all
+	StartDate    time.Time  `db:"start_date"`
+	EndDate      *time.Time `db:"end_date"`      // Nil is open-ended
+	OnLeaveOnly  bool       `db:"on_leave_only"` // Only while the delegator is on approved leave
+	Reason       string     `db:"reason"`
+	CreatedBy    *uuid.UUID `db:"created_by"`
+	RevokedAt    *time.Time `db:"revoked_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ApprovalHistory struct {
+	ID         uuid.UUID  `db:"id"`
+	EntityType string     `db:"entity_type"` // leave_request, memo
+	EntityID   uuid.UUID  `db:"entity_id"`
+	StepNumber int        `db:"step_number"`
+	ApproverID *uuid.UUID `db:"approver_id"`  // Employee who acted, including requesters withdrawing; nil for automatic actions
+	OnBehalfOf *uuid.UUID `db:"on_behalf_of"` // Approver a delegate acted for
+	Action     string     `db:"action"`       // approved, rejected, requested_changes, resubmitted, cancelled, withdrawn
+	Comments   string     `db:"comments"`
+	CreatedAt  time.Time  `db:"created_at"`
+
+	ApproverName   string `db:"approver_name"`     // Set when listing history
+	OnBehalfOfName string `db:"on_behalf_of_name"` // Set when listing history
+}
+
+// ApprovalRevision is what an entity looked like when it was submitted, or
+// resubmitted after changes were requested.
+type ApprovalRevision struct {
+	ID          uuid.UUID      `db:"id"`
+	EntityType  string         `db:"entity_type"`
+	EntityID    uuid.UUID      `db:"entity_id"`
+	Revision    int            `db:"revision"` // 1 for the original submission
+	Data        map[string]any `db:"data"`     // The entity's reviewable fields
+	SubmittedBy *uuid.UUID     `db:"submitted_by"`
+	CreatedAt   time.Time      `db:"created_at"`
+
+	SubmittedByName string `db:"submitted_by_name"` // Set when listing revisions
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalStep is one entry of an approval workflow's steps.
+type ApprovalStep struct {
+	Step         int        `json:"step"`
+	Name         string     `json:"name,omitempty"`
+	ApproverType string     `json:"approver_type,omitempty"` // employee, role, manager, hod
+	ApproverID   *uuid.UUID `json:"approver_id"`             // For employee steps
+	RoleID       *uuid.UUID `json:"role_id,omitempty"`       // For role steps, and employee steps whose approver left
+
+	SLAHours          int    `json:"sla_hours,omitempty"`           // Hours to decide; 0 has no deadline
+	RemindBeforeHours int    `json:"remind_before_hours,omitempty"` // 0 reminds a quarter of the SLA before the deadline
+	TimeoutAction     string `json:"timeout_action,omitempty"`      // escalate (default), approve, reject
+}
+
+type ApprovalWorkflow struct {
+	ID           uuid.UUID      `db:"id"`
+	CompanyID    uuid.UUID      `db:"company_id"`
+	Name         string         `db:"name"`
+	WorkflowType string         `db:"workflow_type"` // leave, memo, expense
+	DepartmentID *uuid.UUID     `db:"department_id"` // Nil applies company-wide
+	Steps        []ApprovalStep `db:"steps"`
+	IsActive     bool           `db:"is_active"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at"`
+}
+
+// ApprovalRequest tracks one entity through the steps of a workflow.
+type ApprovalRequest struct {
+	ID            uuid.UUID      `db:"id"`
+	CompanyID     uuid.UUID      `db:"company_id"`
+	EntityType    string         `db:"entity_type"` // leave_request, memo, expense_claim
+	EntityID      uuid.UUID      `db:"entity_id"`
+	WorkflowID    *uuid.UUID     `db:"workflow_id"` // Nil when the built-in default applied
+	RequesterID   uuid.UUID      `db:"requester_id"`
+	Steps         []ApprovalStep `db:"steps"`        // Copied from the workflow at submission
+	CurrentStep   int            `db:"current_step"` // While changes_requested, the step that asked for them
+	Status        string         `db:"status"`       // pending, changes_requested, approved, rejected, cancelled, withdrawn
+	StepStartedAt time.Time      `db:"step_started_at"`
+	// SLA progress of the current step, reset when the step changes.
+	RemindedAt      *time.Time  `db:"reminded_at"`
+	EscalationLevel int         `db:"escalation_level"` // 1 = approvers' managers, 2 = HR
+	EscalatedTo     []uuid.UUID `db:"escalated_to"`     // Added as approvers of the current step
+	EscalatedAt     *time.Time  `db:"escalated_at"`
+	CreatedAt       time.Time   `db:"created_at"`
+	UpdatedAt       time.Time   `db:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditLog struct {
+	ID               uuid.UUID      `db:"id"`
+	CompanyID        uuid.UUID      `db:"company_id"`
+	UserID           *uuid.UUID     `db:"user_id"`            // Who performed the action; nil for system jobs
+	TargetEmployeeID *uuid.UUID     `db:"target_employee_id"` // Employee affected, if any
+	Action           string         `db:"action"`
+	EntityType       string         `db:"entity_type"`
+	EntityID         *uuid.UUID     `db:"entity_id"`
+	OldValues        map[string]any `db:"old_values"`
+	NewValues        map[string]any `db:"new_values"`
+	IPAddress        string         `db:"ip_address"`
+	UserAgent        string         `db:"user_agent"`
+	Metadata         map[string]any `db:"metadata"`
+	CreatedAt        time.Time      `db:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "github.com/google/uuid"
+
+// EmployeeReport is an employee positioned relative to another employee in
+// the reporting line. Depth 1 is a direct report (or direct manager).
+type EmployeeReport struct {
+	Employee
+	Depth int `db:"depth"`
+}
+
+type SpanOfControl struct {
+	ManagerID     uuid.UUID `db:"manager_id"`
+	DirectReports int       `db:"direct_reports"`
+	TotalReports  int       `db:"total_reports"`
+	Depth         int       `db:"depth"` // Levels of management below the manager
+}
@@ -0,0 +1,29 @@
+package models
+
+import "github.com/google/uuid"
+
+// ImportReference is a department, designation, level or role that import
+// rows may refer to by ID, name or code.
+type ImportReference struct {
+	ID   uuid.UUID `db:"id"`
+	Name string    `db:"name"`
+	Code string    `db:"code"` // Empty for tables without a code column
+}
+
+// ImportEmployeeRef identifies an existing employee that import rows may name
+// as their manager, and guards against duplicate emails and codes.
+type ImportEmployeeRef struct {
+	ID           uuid.UUID `db:"id"`
+	Email        string    `db:"email"`
+	EmployeeCode string    `db:"employee_code"`
+	Status       string    `db:"status"`
+}
+
+// ImportReferenceData is everything an employee import resolves rows against.
+type ImportReferenceData struct {
+	Departments  []ImportReference
+	Designations []ImportReference
+	Levels       []ImportReference
+	Roles        []ImportReference // Company roles and system roles
+	Employees    []ImportEmployeeRef
+}
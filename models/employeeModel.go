@@ -10,7 +10,7 @@ type Employee struct {
 	ID                    uuid.UUID  `db:"id"`
 	CompanyID             uuid.UUID  `db:"company_id"`
 	Email                 string     `db:"email"`
-	PasswordHash          string     `db:"password_hash"`
+	PasswordHash          *string    `db:"password_hash"`
 	Phone                 string     `db:"phone"`
 	FirstName             string     `db:"first_name"`
 	LastName              string     `db:"last_name"`
@@ -24,6 +24,7 @@ type Employee struct {
 	EmploymentType        string     `db:"employment_type"`
 	HireDate              time.Time  `db:"hire_date"`
 	TerminationDate       *time.Time `db:"termination_date"`
+	ProbationEndDate      *time.Time `db:"probation_end_date"`
 	DateOfBirth           *time.Time `db:"date_of_birth"`
 	Gender                string     `db:"gender"`
 	Address               string     `db:"address"`
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmployeeStatusTransition struct {
+	ID              uuid.UUID  `db:"id"`
+	CompanyID       uuid.UUID  `db:"company_id"`
+	EmployeeID      uuid.UUID  `db:"employee_id"`
+	FromStatus      string     `db:"from_status"`
+	ToStatus        string     `db:"to_status"`
+	EffectiveDate   time.Time  `db:"effective_date"`
+	TerminationDate *time.Time `db:"termination_date"`
+	Reason          string     `db:"reason"`
+	Status          string     `db:"status"` // scheduled, applied, cancelled, failed
+	RequestedBy     *uuid.UUID `db:"requested_by"`
+	AppliedAt       *time.Time `db:"applied_at"`
+	FailureReason   string     `db:"failure_reason"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExpenseCategory struct {
+	ID              uuid.UUID `db:"id"`
+	CompanyID       uuid.UUID `db:"company_id"`
+	Name            string    `db:"name"`
+	Description     string    `db:"description"`
+	RequiresReceipt bool      `db:"requires_receipt"`
+	IsActive        bool      `db:"is_active"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+// ExpenseLimit caps what employees at a level may claim.
+type ExpenseLimit struct {
+	ID         uuid.UUID  `db:"id"`
+	CompanyID  uuid.UUID  `db:"company_id"`
+	LevelID    uuid.UUID  `db:"level_id"`
+	CategoryID *uuid.UUID `db:"category_id"` // Nil caps every category together
+	Period     string     `db:"period"`      // claim, month
+	Amount     float64    `db:"amount"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+type ExpenseClaim struct {
+	ID              uuid.UUID  `db:"id"`
+	CompanyID       uuid.UUID  `db:"company_id"`
+	EmployeeID      uuid.UUID  `db:"employee_id"`
+	Title           string     `db:"title"`
+	Description     string     `db:"description"`
+	Currency        string     `db:"currency"`
+	TotalAmount     float64    `db:"total_amount"` // Sum of the items
+	Status          string     `db:"status"`       // draft, pending, changes_requested, approved, rejected, withdrawn, reimbursed
+	CurrentStep     int        `db:"current_step"`
+	SubmittedAt     *time.Time `db:"submitted_at"`
+	ApprovedBy      *uuid.UUID `db:"approved_by"`
+	ApprovedAt      *time.Time `db:"approved_at"`
+	RejectionReason string     `db:"rejection_reason"`
+	PayoutBatchID   *uuid.UUID `db:"payout_batch_id"`
+	ReimbursedAt    *time.Time `db:"reimbursed_at"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+
+	Items []*ExpenseClaimItem
+}
+
+type ExpenseClaimItem struct {
+	ID          uuid.UUID `db:"id"`
+	ClaimID     uuid.UUID `db:"claim_id"`
+	CategoryID  uuid.UUID `db:"category_id"`
+	ExpenseDate time.Time `db:"expense_date"`
+	Description string    `db:"description"`
+	Merchant    string    `db:"merchant"`
+	Amount      float64   `db:"amount"`
+	ReceiptURL  string    `db:"receipt_url"`
+	Position    int       `db:"position"` // Order within the claim
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// ExpenseSpend is what an employee has claimed in a category in one month
+// across their live claims.
+type ExpenseSpend struct {
+	Month      time.Time // First day of the month
+	CategoryID uuid.UUID
+	Amount     float64
+}
+
+type ExpensePayoutBatch struct {
+	ID          uuid.UUID  `db:"id"`
+	CompanyID   uuid.UUID  `db:"company_id"`
+	Reference   string     `db:"reference"` // e.g. PAY/2026/0007
+	Currency    string     `db:"currency"`
+	TotalAmount float64    `db:"total_amount"`
+	ClaimCount  int        `db:"claim_count"`
+	CreatedBy   *uuid.UUID `db:"created_by"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
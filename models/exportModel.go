@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmployeeExportRow is an employee joined with the names of the records it
+// references. It deliberately has no password or session fields so they
+// cannot leak into an export.
+type EmployeeExportRow struct {
+	ID                    uuid.UUID
+	EmployeeCode          string
+	Email                 string
+	Phone                 string
+	FirstName             string
+	LastName              string
+	DepartmentName        string
+	DesignationName       string
+	LevelName             string
+	RoleName              string
+	ManagerName           string
+	ManagerCode           string
+	Status                string
+	EmploymentType        string
+	HireDate              time.Time
+	TerminationDate       *time.Time
+	ProbationEndDate      *time.Time
+	DateOfBirth           *time.Time
+	Gender                string
+	Address               string
+	EmergencyContactName  string
+	EmergencyContactPhone string
+	CreatedAt             time.Time
+}
+
+type DepartmentExportRow struct {
+	ID                   uuid.UUID
+	Name                 string
+	Code                 string
+	Description          string
+	ParentDepartmentName string
+	HODName              string
+	CostCenter           string
+	Status               string
+	EmployeeCount        int
+	CreatedAt            time.Time
+}
+
+// ExpensePayoutExportRow is one claim of a payout batch with who to pay.
+type ExpensePayoutExportRow struct {
+	BatchReference string
+	ClaimID        uuid.UUID
+	EmployeeCode   string
+	EmployeeName   string
+	Email          string
+	Title          string
+	Currency       string
+	Amount         float64
+	ApprovedAt     *time.Time
+	ReimbursedAt   *time.Time
+}
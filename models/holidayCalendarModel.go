@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HolidayCalendar struct {
+	ID            uuid.UUID   `db:"id"`
+	CompanyID     uuid.UUID   `db:"company_id"`
+	Name          string      `db:"name"`
+	Country       string      `db:"country"`
+	WorkDays      []int       `db:"work_days"` // time.Weekday values, 0 = Sunday
+	IsDefault     bool        `db:"is_default"`
+	DepartmentIDs []uuid.UUID `db:"department_ids"` // Departments assigned this calendar
+	CreatedAt     time.Time   `db:"created_at"`
+	UpdatedAt     time.Time   `db:"updated_at"`
+}
+
+type CompanyHoliday struct {
+	ID          uuid.UUID `db:"id"`
+	CompanyID   uuid.UUID `db:"company_id"`
+	CalendarID  uuid.UUID `db:"calendar_id"`
+	HolidayDate time.Time `db:"holiday_date"` // First occurrence for recurring holidays
+	Name        string    `db:"name"`
+	Recurring   bool      `db:"recurring"` // Repeats yearly on the same month and day
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
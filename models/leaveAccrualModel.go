@@ -0,0 +1,13 @@
+package models
+
+import "github.com/google/uuid"
+
+// LeaveAccrual is the accrued entitlement and carry-forward an employee
+// should have for a leave type in a year, as computed by the accrual job.
+type LeaveAccrual struct {
+	EmployeeID         uuid.UUID
+	LeaveTypeID        uuid.UUID
+	Year               int
+	AccruedDays        float64 // Entitlement earned so far this year
+	CarriedForwardDays float64 // Unused days brought over from the previous year
+}
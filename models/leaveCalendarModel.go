@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeamLeaveEntry is a leave request as shown on a team calendar.
+type TeamLeaveEntry struct {
+	RequestID     uuid.UUID `db:"request_id"`
+	EmployeeID    uuid.UUID `db:"employee_id"`
+	EmployeeName  string    `db:"employee_name"`
+	LeaveTypeID   uuid.UUID `db:"leave_type_id"`
+	LeaveTypeName string    `db:"leave_type_name"`
+	ColorCode     string    `db:"color_code"`
+	StartDate     time.Time `db:"start_date"`
+	EndDate       time.Time `db:"end_date"`
+	StartHalfDay  bool      `db:"start_half_day"`
+	EndHalfDay    bool      `db:"end_half_day"`
+	DaysRequested float64   `db:"days_requested"`
+	Status        string    `db:"status"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LeaveLedgerEntry records one change to a leave balance. The deltas are
+// what the change added to each of the balance's running totals.
+type LeaveLedgerEntry struct {
+	ID                  uuid.UUID  `db:"id"`
+	BalanceID           uuid.UUID  `db:"balance_id"`
+	EntryType           string     `db:"entry_type"` // opening, accrual, reservation, consumption, release, carry_forward, adjustment
+	TotalDelta          float64    `db:"total_delta"`
+	UsedDelta           float64    `db:"used_delta"`
+	PendingDelta        float64    `db:"pending_delta"`
+	CarriedForwardDelta float64    `db:"carried_forward_delta"`
+	LeaveRequestID      *uuid.UUID `db:"leave_request_id"` // The request that caused the change, if any
+	ActorID             *uuid.UUID `db:"actor_id"`         // Nil for automatic changes
+	Reason              string     `db:"reason"`
+	CreatedAt           time.Time  `db:"created_at"`
+}
+
+// LeaveBalanceDiscrepancy is a balance whose stored totals differ from the
+// sums of its ledger entries.
+type LeaveBalanceDiscrepancy struct {
+	Balance              LeaveBalance
+	LedgerTotal          float64 `db:"ledger_total"`
+	LedgerUsed           float64 `db:"ledger_used"`
+	LedgerPending        float64 `db:"ledger_pending"`
+	LedgerCarriedForward float64 `db:"ledger_carried_forward"`
+}
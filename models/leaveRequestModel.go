@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LeaveRequest struct {
+	ID            uuid.UUID `db:"id"`
+	EmployeeID    uuid.UUID `db:"employee_id"`
+	LeaveTypeID   uuid.UUID `db:"leave_type_id"`
+	StartDate     time.Time `db:"start_date"`
+	EndDate       time.Time `db:"end_date"`
+	StartHalfDay  bool      `db:"start_half_day"` // Leave starts after midday
+	EndHalfDay    bool      `db:"end_half_day"`   // Leave ends at midday
+	DaysRequested float64   `db:"days_requested"` // Working days, in half-day steps
+	Reason        string    `db:"reason"`
+	AttachmentURL string    `db:"attachment_url"`
+	// DocumentationStatus tracks the attachment the leave type requires:
+	// not_required, provided, awaiting (until DocumentationDueDate) or overdue.
+	DocumentationStatus  string     `db:"documentation_status"`
+	DocumentationDueDate *time.Time `db:"documentation_due_date"`
+	Status               string     `db:"status"` // pending, changes_requested, approved, rejected, cancelled, withdrawn
+	CurrentStep          int        `db:"current_step"`
+	ApprovedBy           *uuid.UUID `db:"approved_by"`
+	ApprovedAt           *time.Time `db:"approved_at"`
+	RejectionReason      string     `db:"rejection_reason"`
+	CreatedAt            time.Time  `db:"created_at"`
+	UpdatedAt            time.Time  `db:"updated_at"`
+}
+
+type LeaveBalance struct {
+	ID                 uuid.UUID `db:"id"`
+	EmployeeID         uuid.UUID `db:"employee_id"`
+	LeaveTypeID        uuid.UUID `db:"leave_type_id"`
+	Year               int       `db:"year"`
+	TotalDays          float64   `db:"total_days"`
+	UsedDays           float64   `db:"used_days"`
+	PendingDays        float64   `db:"pending_days"` // Reserved by pending requests
+	CarriedForwardDays float64   `db:"carried_forward_days"`
+	AccruedDays        float64   `db:"accrued_days"` // Part of total_days granted by accrual
+	CreatedAt          time.Time `db:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
+
+// OverdueLeaveDocumentation is a request whose required documentation is
+// past its due date, with what its leave type does about it.
+type OverdueLeaveDocumentation struct {
+	LeaveRequest
+	CompanyID     uuid.UUID `db:"company_id"`
+	LeaveTypeName string    `db:"leave_type_name"`
+	OverdueAction string    `db:"documentation_overdue_action"` // flag, revert
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LeaveType struct {
+	ID                      uuid.UUID `db:"id"`
+	CompanyID               uuid.UUID `db:"company_id"`
+	Name                    string    `db:"name"`
+	Code                    string    `db:"code"`
+	Description             string    `db:"description"`
+	DaysAllowed             float64   `db:"days_allowed"`
+	IsPaid                  bool      `db:"is_paid"`
+	RequiresDocumentation   bool      `db:"requires_documentation"`
+	CarryForwardAllowed     bool      `db:"carry_forward_allowed"`
+	MaxCarryForwardDays     float64   `db:"max_carry_forward_days"`
+	ColorCode               string    `db:"color_code"`
+	Status                  string    `db:"status"`                    // active, inactive
+	EligibleEmploymentTypes []string  `db:"eligible_employment_types"` // Empty means all
+	EligibleGenders         []string  `db:"eligible_genders"`          // Empty means all
+	MinTenureDays           int       `db:"min_tenure_days"`
+	AllowedDuringProbation  bool      `db:"allowed_during_probation"`
+	AccrualPolicy           string    `db:"accrual_policy"` // yearly, monthly
+	// Documentation is required for requests spanning more than
+	// DocumentationAfterDays consecutive days (0 means every request), and may
+	// be supplied up to DocumentationGraceDays after the leave ends.
+	DocumentationAfterDays     int       `db:"documentation_after_days"`
+	DocumentationGraceDays     int       `db:"documentation_grace_days"`
+	DocumentationOverdueAction string    `db:"documentation_overdue_action"` // flag, revert
+	CreatedAt                  time.Time `db:"created_at"`
+	UpdatedAt                  time.Time `db:"updated_at"`
+}
@@ -14,6 +14,7 @@ type Level struct {
 	MinSalary      *float64  `db:"min_salary"`
 	MaxSalary      *float64  `db:"max_salary"`
 	Description    string    `db:"description"`
+	ProbationDays  *int      `db:"probation_days"` // nil falls back to the company default
 	CreatedAt      time.Time `db:"created_at"`
 	UpdatedAt      time.Time `db:"updated_at"`
 }
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Memo struct {
+	ID                      uuid.UUID  `db:"id"`
+	CompanyID               uuid.UUID  `db:"company_id"`
+	EmployeeID              uuid.UUID  `db:"employee_id"` // Sender
+	MemoType                string     `db:"memo_type"`   // request, disciplinary, announcement, general
+	Title                   string     `db:"title"`
+	Content                 string     `db:"content"`
+	ReferenceNumber         string     `db:"reference_number"` // Assigned on publish, e.g. HR/2026/0042
+	Status                  string     `db:"status"`           // draft, pending, changes_requested, approved, rejected, published, archived
+	CurrentStep             int        `db:"current_step"`
+	Priority                string     `db:"priority"` // low, normal, high, urgent
+	RequiresAcknowledgement bool       `db:"requires_acknowledgement"`
+	SubjectEmployeeID       *uuid.UUID `db:"subject_employee_id"` // Set on disciplinary memos
+	CaseID                  *uuid.UUID `db:"case_id"`             // Disciplinary case the memo belongs to
+	PublishedAt             *time.Time `db:"published_at"`
+	CreatedAt               time.Time  `db:"created_at"`
+	UpdatedAt               time.Time  `db:"updated_at"`
+
+	Targets        []*MemoTarget
+	RecipientCount int
+}
+
+// MemoTarget addresses a memo to an employee, department, role, level or
+// the whole company.
+type MemoTarget struct {
+	ID         uuid.UUID  `db:"id"`
+	MemoID     uuid.UUID  `db:"memo_id"`
+	TargetType string     `db:"target_type"` // individual, department, role, level, company
+	TargetID   *uuid.UUID `db:"target_id"`   // Nil for company
+}
+
+// MemoRecipient is one employee a memo was published to and what they have
+// done with it.
+type MemoRecipient struct {
+	ID             uuid.UUID  `db:"id"`
+	MemoID         uuid.UUID  `db:"memo_id"`
+	EmployeeID     uuid.UUID  `db:"employee_id"`
+	EmployeeName   string     `db:"employee_name"`
+	IsRead         bool       `db:"is_read"`
+	ReadAt         *time.Time `db:"read_at"`
+	AcknowledgedAt *time.Time `db:"acknowledged_at"`
+	ReminderCount  int        `db:"reminder_count"`
+	LastRemindedAt *time.Time `db:"last_reminded_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+// ReceivedMemo is a memo as one of its recipients sees it.
+type ReceivedMemo struct {
+	Memo
+	Receipt MemoRecipient
+}
+
+// MemoThreadEntry is an entry in the thread under a disciplinary memo.
+type MemoThreadEntry struct {
+	ID         uuid.UUID  `db:"id"`
+	MemoID     uuid.UUID  `db:"memo_id"`
+	AuthorID   *uuid.UUID `db:"author_id"`
+	AuthorName string     `db:"author_name"`
+	Kind       string     `db:"kind"` // response, appeal (both by the subject), reply
+	Content    string     `db:"content"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// DisciplinaryCase gathers the disciplinary memos about one matter
+// concerning an employee.
+type DisciplinaryCase struct {
+	ID           uuid.UUID  `db:"id"`
+	CompanyID    uuid.UUID  `db:"company_id"`
+	EmployeeID   uuid.UUID  `db:"employee_id"` // Subject
+	Title        string     `db:"title"`
+	Description  string     `db:"description"`
+	Status       string     `db:"status"`  // open, closed
+	Outcome      string     `db:"outcome"` // Set once closed, e.g. written_warning
+	OutcomeNotes string     `db:"outcome_notes"`
+	OpenedBy     *uuid.UUID `db:"opened_by"`
+	ClosedBy     *uuid.UUID `db:"closed_by"`
+	ClosedAt     *time.Time `db:"closed_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Notification struct {
+	ID          uuid.UUID  `db:"id"`
+	CompanyID   uuid.UUID  `db:"company_id"`
+	RecipientID uuid.UUID  `db:"recipient_id"`
+	Type        string     `db:"type"` // e.g. probation_expiry
+	Title       string     `db:"title"`
+	Body        string     `db:"body"`
+	EntityType  string     `db:"entity_type"`
+	EntityID    *uuid.UUID `db:"entity_id"`
+	DedupeKey   string     `db:"dedupe_key"`
+	IsRead      bool       `db:"is_read"`
+	ReadAt      *time.Time `db:"read_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
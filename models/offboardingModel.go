@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OffboardingChecklist struct {
+	ID                    uuid.UUID  `db:"id"`
+	CompanyID             uuid.UUID  `db:"company_id"`
+	EmployeeID            uuid.UUID  `db:"employee_id"`
+	TransitionID          *uuid.UUID `db:"transition_id"` // The termination status transition
+	TerminationDate       time.Time  `db:"termination_date"`
+	NewManagerID          *uuid.UUID `db:"new_manager_id"`        // Takes over direct reports
+	HODSuccessorID        *uuid.UUID `db:"hod_successor_id"`      // Takes over HOD posts; nil clears them
+	ApproverSuccessorID   *uuid.UUID `db:"approver_successor_id"` // Takes over approval workflow steps
+	Status                string     `db:"status"`                // scheduled, in_progress, completed
+	InitiatedBy           *uuid.UUID `db:"initiated_by"`
+	AutomationCompletedAt *time.Time `db:"automation_completed_at"`
+	CompletedAt           *time.Time `db:"completed_at"`
+	CreatedAt             time.Time  `db:"created_at"`
+	UpdatedAt             time.Time  `db:"updated_at"`
+	Items                 []*OffboardingChecklistItem
+}
+
+type OffboardingChecklistItem struct {
+	ID          uuid.UUID  `db:"id"`
+	ChecklistID uuid.UUID  `db:"checklist_id"`
+	Code        string     `db:"code"`
+	Title       string     `db:"title"`
+	IsAutomated bool       `db:"is_automated"`
+	Status      string     `db:"status"` // pending, completed, skipped
+	Notes       string     `db:"notes"`
+	CompletedBy *uuid.UUID `db:"completed_by"`
+	CompletedAt *time.Time `db:"completed_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
+
+// OffboardingResult summarises the automated offboarding steps.
+type OffboardingResult struct {
+	ReassignedReports      int
+	TransferredHODPosts    int
+	CancelledLeaveRequests int
+	ReassignedWorkflows    int
+}
@@ -0,0 +1,14 @@
+package models
+
+import "github.com/google/uuid"
+
+// OrgChartNode is a flat row of an org chart tree. Depth 0 is the root.
+type OrgChartNode struct {
+	ID          uuid.UUID  `db:"id"`
+	ParentID    *uuid.UUID `db:"parent_id"`
+	Depth       int        `db:"depth"`
+	Name        string     `db:"name"`
+	Designation string     `db:"designation"`
+	PhotoURL    string     `db:"photo_url"`
+	HeadName    string     `db:"head_name"` // Department charts only: the HOD
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProbationReview struct {
+	ID              uuid.UUID  `db:"id"`
+	CompanyID       uuid.UUID  `db:"company_id"`
+	EmployeeID      uuid.UUID  `db:"employee_id"`
+	Decision        string     `db:"decision"` // confirmed, extended, terminated
+	PreviousEndDate *time.Time `db:"previous_end_date"`
+	NewEndDate      *time.Time `db:"new_end_date"` // Set when the probation is extended
+	Comments        string     `db:"comments"`
+	DecidedBy       *uuid.UUID `db:"decided_by"`
+	CreatedAt       time.Time  `db:"created_at"`
+}
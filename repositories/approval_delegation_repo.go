@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+const approvalDelegationColumns = `
+	id, company_id, delegator_id, delegate_id, COALESCE(workflow_type, ''), start_date, end_date,
+	on_leave_only, COALESCE(reason, ''), created_by, revoked_at, created_at`
+
+func scanApprovalDelegation(row pgx.Row, d *models.ApprovalDelegation) error {
+	return row.Scan(
+		&d.ID, &d.CompanyID, &d.DelegatorID, &d.DelegateID, &d.WorkflowType, &d.StartDate, &d.EndDate,
+		&d.OnLeaveOnly, &d.Reason, &d.CreatedBy, &d.RevokedAt, &d.CreatedAt,
+	)
+}
+
+func (a *ApprovalRepository) CreateDelegation(ctx context.Context, d *models.ApprovalDelegation) (*models.ApprovalDelegation, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var created models.ApprovalDelegation
+	if err := scanApprovalDelegation(a.pool.QueryRow(ctx, `
+		INSERT INTO approval_delegations (
+			company_id, delegator_id, delegate_id, workflow_type, start_date, end_date,
+			on_leave_only, reason, created_by
+		)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, NULLIF($8, ''), $9)
+		RETURNING `+approvalDelegationColumns,
+		d.CompanyID, d.DelegatorID, d.DelegateID, d.WorkflowType, d.StartDate, d.EndDate,
+		d.OnLeaveOnly, d.Reason, d.CreatedBy,
+	), &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (a *ApprovalRepository) GetDelegationByID(ctx context.Context, delegationID uuid.UUID) (*models.ApprovalDelegation, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var d models.ApprovalDelegation
+	if err := scanApprovalDelegation(a.pool.QueryRow(ctx,
+		"SELECT "+approvalDelegationColumns+" FROM approval_delegations WHERE id = $1",
+		delegationID,
+	), &d); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// ListDelegations returns the delegations an employee has given or
+// received, newest first.
+func (a *ApprovalRepository) ListDelegations(ctx context.Context, employeeID uuid.UUID) ([]*models.ApprovalDelegation, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := a.pool.Query(ctx, `
+		SELECT `+approvalDelegationColumns+`
+		FROM approval_delegations
+		WHERE delegator_id = $1 OR delegate_id = $1
+		ORDER BY start_date DESC, created_at DESC
+	`, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var delegations []*models.ApprovalDelegation
+	for rows.Next() {
+		var d models.ApprovalDelegation
+		if err := scanApprovalDelegation(rows, &d); err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, &d)
+	}
+
+	return delegations, rows.Err()
+}
+
+// RevokeDelegation ends a delegation immediately. Revoking it twice is a
+// no-op.
+func (a *ApprovalRepository) RevokeDelegation(ctx context.Context, delegationID uuid.UUID) (*models.ApprovalDelegation, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var d models.ApprovalDelegation
+	if err := scanApprovalDelegation(a.pool.QueryRow(ctx, `
+		UPDATE approval_delegations
+		SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+		WHERE id = $1
+		RETURNING `+approvalDelegationColumns,
+		delegationID,
+	), &d); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// HasOverlappingDelegation reports whether the delegator already has a live
+// delegation of the same kind (date-bound or on-leave-only) covering any of
+// d's days and workflow types.
+func (a *ApprovalRepository) HasOverlappingDelegation(ctx context.Context, d *models.ApprovalDelegation) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var overlaps bool
+	err := a.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM approval_delegations
+			WHERE delegator_id = $1 AND revoked_at IS NULL AND on_leave_only = $5
+				AND (workflow_type IS NULL OR $2 = '' OR workflow_type = $2)
+				AND start_date <= COALESCE($4::date, 'infinity'::date)
+				AND COALESCE(end_date, 'infinity'::date) >= $3
+		)
+	`, d.DelegatorID, d.WorkflowType, d.StartDate, d.EndDate, d.OnLeaveOnly).Scan(&overlaps)
+	return overlaps, err
+}
+
+// ActiveDelegates maps each of approverIDs who has delegated workflowType
+// on day to their delegate. Date-bound delegations win over on-leave-only
+// ones, and delegations for the workflow type over those covering all
+// types. Terminated delegates are skipped.
+func (a *ApprovalRepository) ActiveDelegates(ctx context.Context, approverIDs []uuid.UUID, workflowType string, day time.Time) (map[uuid.UUID]uuid.UUID, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := a.pool.Query(ctx, `
+		SELECT a.id, d.id
+		FROM unnest($1::uuid[]) AS a(id)
+		CROSS JOIN LATERAL approval_active_delegate(a.id, $2, $3) AS d(id)
+		WHERE d.id IS NOT NULL
+	`, approverIDs, workflowType, day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	delegates := make(map[uuid.UUID]uuid.UUID)
+	for rows.Next() {
+		var delegator, delegate uuid.UUID
+		if err := rows.Scan(&delegator, &delegate); err != nil {
+			return nil, err
+		}
+		delegates[delegator] = delegate
+	}
+
+	return delegates, rows.Err()
+}
+
+// CanApprove reports whether the employee's role grants approval rights
+// over resource (leaves, memos, expenses): a role with company-wide, HR or
+// team management access, or an explicit approve or manage permission.
+func (a *ApprovalRepository) CanApprove(ctx context.Context, employeeID uuid.UUID, resource string) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var allowed bool
+	err := a.pool.QueryRow(ctx, `
+		SELECT COALESCE(r.permissions_cache, '[]'::jsonb) ?| array['all', 'hr_full', 'team_management']
+			OR EXISTS (
+				SELECT 1 FROM permissions p
+				WHERE p.role_id = r.id AND p.action IN ('approve', 'manage') AND p.resource = $2
+			)
+		FROM employees e
+		JOIN roles r ON r.id = e.role_id
+		WHERE e.id = $1
+	`, employeeID, resource).Scan(&allowed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return allowed, err
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type ApprovalHistoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewApprovalHistoryRepository(pool *pgxpool.Pool) *ApprovalHistoryRepository {
+	return &ApprovalHistoryRepository{
+		pool: pool,
+	}
+}
+
+const approvalHistoryColumns = `
+	h.id, h.entity_type, h.entity_id, h.step_number, h.approver_id, h.on_behalf_of, h.action,
+	COALESCE(h.comments, ''), h.created_at`
+
+func scanApprovalHistory(row pgx.Row, h *models.ApprovalHistory, extra ...any) error {
+	dest := []any{&h.ID, &h.EntityType, &h.EntityID, &h.StepNumber, &h.ApproverID, &h.OnBehalfOf, &h.Action, &h.Comments, &h.CreatedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// ListApprovalHistory returns the actions taken on an entity, oldest first,
+// with the names of the approver and of anyone they stood in for.
+func (a *ApprovalHistoryRepository) ListApprovalHistory(ctx context.Context, entityType string, entityID uuid.UUID) ([]*models.ApprovalHistory, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT ` + approvalHistoryColumns + `,
+			COALESCE(a.first_name || ' ' || a.last_name, ''),
+			COALESCE(b.first_name || ' ' || b.last_name, '')
+		FROM approval_history h
+		LEFT JOIN employees a ON a.id = h.approver_id
+		LEFT JOIN employees b ON b.id = h.on_behalf_of
+		WHERE h.entity_type = $1 AND h.entity_id = $2
+		ORDER BY h.created_at, h.step_number
+	`
+
+	rows, err := a.pool.Query(ctx, query, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*models.ApprovalHistory
+	for rows.Next() {
+		var h models.ApprovalHistory
+		if err := scanApprovalHistory(rows, &h, &h.ApproverName, &h.OnBehalfOfName); err != nil {
+			return nil, err
+		}
+		history = append(history, &h)
+	}
+
+	return history, rows.Err()
+}
+
+// insertApprovalHistory records h through db so that it is written in the
+// same transaction as the status change it describes.
+func insertApprovalHistory(ctx context.Context, db dbExecutor, h *models.ApprovalHistory) error {
+	return db.QueryRow(ctx, `
+		INSERT INTO approval_history (entity_type, entity_id, step_number, approver_id, on_behalf_of, action, comments)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+		RETURNING id, created_at
+	`, h.EntityType, h.EntityID, h.StepNumber, h.ApproverID, h.OnBehalfOf, h.Action, h.Comments).Scan(&h.ID, &h.CreatedAt)
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// approvalInboxQuery selects the company's pending requests whose current
+// step approverID ($2) may decide on day $3: as a step approver, as the
+// delegate of one, through an escalation, or, when $4 says they are HR, for
+// steps nobody else can decide. $5 narrows it to one requester and $6 to
+// steps overdue at $7. Step approvers and delegates are resolved by the
+// functions of migration 032, as they are for the approval service.
+const approvalInboxQuery = `
+	WITH inbox AS (
+		SELECT r.*, wt.workflow_type
+		FROM approval_requests r
+		CROSS JOIN LATERAL (
+			SELECT CASE r.entity_type
+				WHEN 'leave_request' THEN 'leave'
+				WHEN 'expense_claim' THEN 'expense'
+				ELSE r.entity_type
+			END AS workflow_type
+		) wt
+		LEFT JOIN LATERAL (
+			SELECT s.step
+			FROM jsonb_array_elements(r.steps) AS s(step)
+			WHERE (s.step->>'step')::int = r.current_step
+			LIMIT 1
+		) cur ON true
+		CROSS JOIN LATERAL (
+			SELECT ARRAY(SELECT approval_step_approvers(cur.step, r.requester_id)) AS ids
+		) ap
+		WHERE r.company_id = $1 AND r.status = 'pending' AND r.requester_id <> $2
+			AND ($5::uuid IS NULL OR r.requester_id = $5)
+			AND (NOT $6 OR (
+				COALESCE((cur.step->>'sla_hours')::int, 0) > 0
+				AND r.step_started_at + make_interval(hours => (cur.step->>'sla_hours')::int) <= $7
+			))
+			AND (
+				$2 = ANY(ap.ids)
+				OR $2 = ANY(r.escalated_to)
+				OR EXISTS (
+					SELECT 1 FROM unnest(ap.ids) AS a(id)
+					WHERE approval_active_delegate(a.id, wt.workflow_type, $3) = $2
+				)
+				OR ($4 AND cardinality(ap.ids) = 0 AND NOT EXISTS (
+					SELECT 1 FROM unnest(r.escalated_to) AS x(id) WHERE x.id <> r.requester_id
+				))
+			)
+	)`
+
+// ListInboxApprovalRequests returns one page of the pending requests
+// approverID may decide, longest waiting first, narrowed to req.Type when
+// set, together with how many there are of each workflow type before that
+// narrowing. asHR says whether approverID holds the HR role, which decides
+// the steps that resolve to nobody else.
+func (a *ApprovalRepository) ListInboxApprovalRequests(
+	ctx context.Context,
+	companyID, approverID uuid.UUID,
+	asHR bool,
+	requesterID *uuid.UUID,
+	req *dto.ApprovalInboxRequest,
+	now time.Time,
+) (*utils.PaginatedResponse[*models.ApprovalRequest], map[string]int, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	args := []any{companyID, approverID, utils.Today(), asHR, requesterID, req.OverdueOnly, now}
+
+	rows, err := a.pool.Query(ctx, approvalInboxQuery+`
+		SELECT workflow_type, COUNT(*) FROM inbox GROUP BY workflow_type
+	`, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	var total int64
+	for rows.Next() {
+		var workflowType string
+		var count int
+		if err := rows.Scan(&workflowType, &count); err != nil {
+			return nil, nil, err
+		}
+		counts[workflowType] = count
+		if req.Type == "" || workflowType == req.Type {
+			total += int64(count)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	rows.Close()
+
+	offset := (req.Page - 1) * req.PageSize
+	rows, err = a.pool.Query(ctx, approvalInboxQuery+fmt.Sprintf(`
+		SELECT %s
+		FROM inbox
+		WHERE $8 = '' OR workflow_type = $8
+		ORDER BY step_started_at, created_at
+		LIMIT $9 OFFSET $10
+	`, approvalRequestColumns), append(args, req.Type, req.PageSize, offset)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var requests []*models.ApprovalRequest
+	for rows.Next() {
+		var r models.ApprovalRequest
+		if err := scanApprovalRequest(rows, &r); err != nil {
+			return nil, nil, err
+		}
+		requests = append(requests, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &utils.PaginatedResponse[*models.ApprovalRequest]{
+		Data:       requests,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: totalPages,
+		HasNext:    req.Page < totalPages,
+		HasPrev:    req.Page > 1,
+	}, counts, nil
+}
@@ -0,0 +1,438 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// ErrApprovalRequestStatusChanged is returned when an approval request is no
+// longer pending at the step a decision was made for.
+var ErrApprovalRequestStatusChanged = errors.New("approval request has moved on")
+
+type ApprovalRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewApprovalRepository(pool *pgxpool.Pool) *ApprovalRepository {
+	return &ApprovalRepository{
+		pool: pool,
+	}
+}
+
+const approvalWorkflowColumns = `
+	id, company_id, name, workflow_type, department_id, steps, COALESCE(is_active, true),
+	created_at, COALESCE(updated_at, created_at)`
+
+func scanApprovalWorkflow(row pgx.Row, w *models.ApprovalWorkflow) error {
+	return row.Scan(
+		&w.ID, &w.CompanyID, &w.Name, &w.WorkflowType, &w.DepartmentID, &w.Steps, &w.IsActive,
+		&w.CreatedAt, &w.UpdatedAt,
+	)
+}
+
+const approvalRequestColumns = `
+	id, company_id, entity_type, entity_id, workflow_id, requester_id, steps, current_step,
+	status, step_started_at, reminded_at, escalation_level, escalated_to, escalated_at,
+	created_at, updated_at`
+
+func scanApprovalRequest(row pgx.Row, r *models.ApprovalRequest) error {
+	return row.Scan(
+		&r.ID, &r.CompanyID, &r.EntityType, &r.EntityID, &r.WorkflowID, &r.RequesterID, &r.Steps, &r.CurrentStep,
+		&r.Status, &r.StepStartedAt, &r.RemindedAt, &r.EscalationLevel, &r.EscalatedTo, &r.EscalatedAt,
+		&r.CreatedAt, &r.UpdatedAt,
+	)
+}
+
+func (a *ApprovalRepository) CreateWorkflow(ctx context.Context, w *models.ApprovalWorkflow) (*models.ApprovalWorkflow, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var created models.ApprovalWorkflow
+	if err := scanApprovalWorkflow(a.pool.QueryRow(ctx, `
+		INSERT INTO approval_workflows (company_id, name, workflow_type, department_id, steps, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+approvalWorkflowColumns,
+		w.CompanyID, w.Name, w.WorkflowType, w.DepartmentID, w.Steps, w.IsActive,
+	), &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (a *ApprovalRepository) GetWorkflowByID(ctx context.Context, workflowID uuid.UUID) (*models.ApprovalWorkflow, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var w models.ApprovalWorkflow
+	if err := scanApprovalWorkflow(a.pool.QueryRow(ctx,
+		"SELECT "+approvalWorkflowColumns+" FROM approval_workflows WHERE id = $1",
+		workflowID,
+	), &w); err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// ListWorkflows returns a company's workflows, company-wide ones first. An
+// empty workflowType returns every type.
+func (a *ApprovalRepository) ListWorkflows(ctx context.Context, companyID uuid.UUID, workflowType string) ([]*models.ApprovalWorkflow, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := a.pool.Query(ctx, `
+		SELECT `+approvalWorkflowColumns+`
+		FROM approval_workflows
+		WHERE company_id = $1 AND ($2 = '' OR workflow_type = $2)
+		ORDER BY workflow_type, department_id NULLS FIRST, name
+	`, companyID, workflowType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workflows []*models.ApprovalWorkflow
+	for rows.Next() {
+		var w models.ApprovalWorkflow
+		if err := scanApprovalWorkflow(rows, &w); err != nil {
+			return nil, err
+		}
+		workflows = append(workflows, &w)
+	}
+
+	return workflows, rows.Err()
+}
+
+// UpdateWorkflow overwrites every editable column of the workflow.
+func (a *ApprovalRepository) UpdateWorkflow(ctx context.Context, w *models.ApprovalWorkflow) (*models.ApprovalWorkflow, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var updated models.ApprovalWorkflow
+	if err := scanApprovalWorkflow(a.pool.QueryRow(ctx, `
+		UPDATE approval_workflows
+		SET name = $2, department_id = $3, steps = $4, is_active = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+approvalWorkflowColumns,
+		w.ID, w.Name, w.DepartmentID, w.Steps, w.IsActive,
+	), &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (a *ApprovalRepository) DeleteWorkflow(ctx context.Context, workflowID uuid.UUID) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	result, err := a.pool.Exec(ctx, "DELETE FROM approval_workflows WHERE id = $1", workflowID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("approval workflow not found")
+	}
+	return nil
+}
+
+// FindWorkflow returns the active workflow that governs workflowType for a
+// requester in departmentID: the workflow of the nearest department up the
+// department tree, else the company-wide one. It returns pgx.ErrNoRows when
+// none applies.
+func (a *ApprovalRepository) FindWorkflow(ctx context.Context, companyID uuid.UUID, workflowType string, departmentID *uuid.UUID) (*models.ApprovalWorkflow, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var w models.ApprovalWorkflow
+	if err := scanApprovalWorkflow(a.pool.QueryRow(ctx, `
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_department_id, 0 AS depth
+			FROM departments
+			WHERE id = $3
+			UNION ALL
+			SELECT d.id, d.parent_department_id, c.depth + 1
+			FROM departments d
+			JOIN chain c ON d.id = c.parent_department_id
+			WHERE c.depth < 20
+		)
+		SELECT `+approvalWorkflowColumns+`
+		FROM approval_workflows w
+		LEFT JOIN chain c ON c.id = w.department_id
+		WHERE w.company_id = $1 AND w.workflow_type = $2 AND w.is_active
+			AND (w.department_id IS NULL OR c.id IS NOT NULL)
+		ORDER BY c.depth NULLS LAST
+		LIMIT 1
+	`, companyID, workflowType, departmentID), &w); err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// RoleAvailable reports whether roleID is one of the company's roles or a
+// system role shared by every company.
+func (a *ApprovalRepository) RoleAvailable(ctx context.Context, companyID, roleID uuid.UUID) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var exists bool
+	err := a.pool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM roles WHERE id = $2 AND (company_id = $1 OR company_id IS NULL))",
+		companyID, roleID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// StepApprovers returns who decides step of a request raised by
+// requesterID, the requester excluded. It resolves them with the
+// approval_step_approvers function the approval inbox filters on, so both
+// agree on who an approver is.
+func (a *ApprovalRepository) StepApprovers(ctx context.Context, step *models.ApprovalStep, requesterID uuid.UUID) ([]uuid.UUID, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := a.pool.Query(ctx, "SELECT id FROM approval_step_approvers($1::jsonb, $2) AS a(id)", step, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+}
+
+// CreateApprovalRequest starts tracking an entity through r.Steps. An entity
+// that is already being tracked keeps its existing request, which is
+// returned instead.
+func (a *ApprovalRepository) CreateApprovalRequest(ctx context.Context, r *models.ApprovalRequest) (*models.ApprovalRequest, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	created, err := insertApprovalRequest(ctx, a.pool, r)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return a.GetApprovalRequest(ctx, r.EntityType, r.EntityID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// insertApprovalRequest stores r as pending through db. It returns
+// pgx.ErrNoRows when the entity already has an approval request.
+func insertApprovalRequest(ctx context.Context, db dbExecutor, r *models.ApprovalRequest) (*models.ApprovalRequest, error) {
+	var created models.ApprovalRequest
+	err := scanApprovalRequest(db.QueryRow(ctx, `
+		INSERT INTO approval_requests (company_id, entity_type, entity_id, workflow_id, requester_id, steps, current_step, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'pending')
+		ON CONFLICT (entity_type, entity_id) DO NOTHING
+		RETURNING `+approvalRequestColumns,
+		r.CompanyID, r.EntityType, r.EntityID, r.WorkflowID, r.RequesterID, r.Steps, max(r.CurrentStep, 1),
+	), &created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (a *ApprovalRepository) GetApprovalRequest(ctx context.Context, entityType string, entityID uuid.UUID) (*models.ApprovalRequest, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var r models.ApprovalRequest
+	if err := scanApprovalRequest(a.pool.QueryRow(ctx,
+		"SELECT "+approvalRequestColumns+" FROM approval_requests WHERE entity_type = $1 AND entity_id = $2",
+		entityType, entityID,
+	), &r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// advanceApprovalRequest moves a pending approval request from fromStep to
+// toStep through db, so that it happens in the same transaction as the
+// entity's own update. It returns ErrApprovalRequestStatusChanged when the
+// request is no longer pending at fromStep.
+func advanceApprovalRequest(ctx context.Context, db dbExecutor, entityType string, entityID uuid.UUID, fromStep, toStep int) error {
+	result, err := db.Exec(ctx, `
+		UPDATE approval_requests
+		SET current_step = $4, step_started_at = CURRENT_TIMESTAMP, reminded_at = NULL,
+			escalation_level = 0, escalated_to = '{}', escalated_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE entity_type = $1 AND entity_id = $2 AND status = 'pending' AND current_step = $3
+	`, entityType, entityID, fromStep, toStep)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrApprovalRequestStatusChanged
+	}
+	return nil
+}
+
+// returnApprovalRequest sends a pending approval request at step back to
+// its requester through db. current_step keeps the step that asked for the
+// changes. It returns ErrApprovalRequestStatusChanged when the request is no
+// longer pending at step.
+func returnApprovalRequest(ctx context.Context, db dbExecutor, entityType string, entityID uuid.UUID, step int) error {
+	result, err := db.Exec(ctx, `
+		UPDATE approval_requests
+		SET status = 'changes_requested', reminded_at = NULL,
+			escalation_level = 0, escalated_to = '{}', escalated_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE entity_type = $1 AND entity_id = $2 AND status = 'pending' AND current_step = $3
+	`, entityType, entityID, step)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrApprovalRequestStatusChanged
+	}
+	return nil
+}
+
+// resubmitApprovalRequest puts a returned approval request back before the
+// approvers of toStep through db. It returns
+// ErrApprovalRequestStatusChanged when the request is not awaiting changes.
+func resubmitApprovalRequest(ctx context.Context, db dbExecutor, entityType string, entityID uuid.UUID, toStep int) error {
+	result, err := db.Exec(ctx, `
+		UPDATE approval_requests
+		SET status = 'pending', current_step = $3, step_started_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE entity_type = $1 AND entity_id = $2 AND status = 'changes_requested'
+	`, entityType, entityID, toStep)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrApprovalRequestStatusChanged
+	}
+	return nil
+}
+
+// closeApprovalRequest records the final status of an entity's approval
+// request through db. Entities without one are left alone.
+func closeApprovalRequest(ctx context.Context, db dbExecutor, entityType string, entityID uuid.UUID, status string) error {
+	_, err := db.Exec(ctx, `
+		UPDATE approval_requests
+		SET status = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE entity_type = $1 AND entity_id = $2 AND status IN ('pending', 'changes_requested')
+	`, entityType, entityID, status)
+	return err
+}
+
+// ListSLAApprovalRequests returns the pending approval requests whose
+// current step has an SLA, oldest step first. A nil companyID covers every
+// company.
+func (a *ApprovalRepository) ListSLAApprovalRequests(ctx context.Context, companyID *uuid.UUID) ([]*models.ApprovalRequest, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	rows, err := a.pool.Query(ctx, `
+		SELECT `+approvalRequestColumns+`
+		FROM approval_requests r
+		WHERE r.status = 'pending' AND ($1::uuid IS NULL OR r.company_id = $1)
+			AND EXISTS (
+				SELECT 1 FROM jsonb_array_elements(r.steps) s
+				WHERE (s->>'step')::int = r.current_step AND COALESCE((s->>'sla_hours')::int, 0) > 0
+			)
+		ORDER BY r.step_started_at
+	`, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*models.ApprovalRequest
+	for rows.Next() {
+		var r models.ApprovalRequest
+		if err := scanApprovalRequest(rows, &r); err != nil {
+			return nil, err
+		}
+		requests = append(requests, &r)
+	}
+
+	return requests, rows.Err()
+}
+
+// MarkApprovalReminded records that the approvers of step were reminded. It
+// reports false when the request has moved on or was already reminded.
+func (a *ApprovalRepository) MarkApprovalReminded(ctx context.Context, requestID uuid.UUID, step int) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	result, err := a.pool.Exec(ctx, `
+		UPDATE approval_requests
+		SET reminded_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending' AND current_step = $2 AND reminded_at IS NULL
+	`, requestID, step)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// EscalateApprovalRequest raises step to escalation level, adding
+// approverIDs to those who may decide it. It reports false when the request
+// has moved on or was already escalated that far.
+func (a *ApprovalRepository) EscalateApprovalRequest(ctx context.Context, requestID uuid.UUID, step, level int, approverIDs []uuid.UUID) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	result, err := a.pool.Exec(ctx, `
+		UPDATE approval_requests
+		SET escalation_level = $3,
+			escalated_to = ARRAY(SELECT DISTINCT unnest(escalated_to || $4::uuid[])),
+			escalated_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending' AND current_step = $2 AND escalation_level < $3
+	`, requestID, step, level, approverIDs)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+const approvalRevisionColumns = `
+	r.id, r.entity_type, r.entity_id, r.revision, r.data, r.submitted_by, r.created_at`
+
+func scanApprovalRevision(row pgx.Row, r *models.ApprovalRevision, extra ...any) error {
+	dest := []any{&r.ID, &r.EntityType, &r.EntityID, &r.Revision, &r.Data, &r.SubmittedBy, &r.CreatedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// insertApprovalRevision records r as the entity's next revision through db,
+// unless its data is the same as the latest revision's. It reports whether
+// a revision was recorded, numbering r when it was.
+func insertApprovalRevision(ctx context.Context, db dbExecutor, r *models.ApprovalRevision) (bool, error) {
+	err := db.QueryRow(ctx, `
+		INSERT INTO approval_revisions AS r (entity_type, entity_id, revision, data, submitted_by)
+		SELECT $1, $2, COALESCE(MAX(revision), 0) + 1, $3, $4
+		FROM approval_revisions
+		WHERE entity_type = $1 AND entity_id = $2
+		HAVING COALESCE((array_agg(data ORDER BY revision DESC))[1] <> $3::jsonb, true)
+		RETURNING r.id, r.revision, r.created_at
+	`, r.EntityType, r.EntityID, r.Data, r.SubmittedBy).Scan(&r.ID, &r.Revision, &r.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// RecordRevision records r as the entity's next revision unless nothing
+// changed since the latest one.
+func (a *ApprovalRepository) RecordRevision(ctx context.Context, r *models.ApprovalRevision) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return insertApprovalRevision(ctx, a.pool, r)
+}
+
+// ListRevisions returns an entity's revisions, oldest first, with the names
+// of whoever submitted them.
+func (a *ApprovalRepository) ListRevisions(ctx context.Context, entityType string, entityID uuid.UUID) ([]*models.ApprovalRevision, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := a.pool.Query(ctx, `
+		SELECT `+approvalRevisionColumns+`, COALESCE(e.first_name || ' ' || e.last_name, '')
+		FROM approval_revisions r
+		LEFT JOIN employees e ON e.id = r.submitted_by
+		WHERE r.entity_type = $1 AND r.entity_id = $2
+		ORDER BY r.revision
+	`, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*models.ApprovalRevision
+	for rows.Next() {
+		var r models.ApprovalRevision
+		if err := scanApprovalRevision(rows, &r, &r.SubmittedByName); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &r)
+	}
+
+	return revisions, rows.Err()
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type AuditLogRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditLogRepository(pool *pgxpool.Pool) *AuditLogRepository {
+	return &AuditLogRepository{
+		pool: pool,
+	}
+}
+
+func (a *AuditLogRepository) CreateAuditLog(ctx context.Context, log *models.AuditLog) (*models.AuditLog, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	if err := insertAuditLog(ctx, a.pool, log); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// insertAuditLog writes log through db so that repositories can record audit
+// entries in the same transaction as the change being audited.
+func insertAuditLog(ctx context.Context, db dbExecutor, log *models.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (
+			company_id, user_id, target_employee_id, action, entity_type, entity_id,
+			old_values, new_values, ip_address, user_agent, metadata
+		)
+		VALUES (
+			$1,$2,$3,$4,$5,$6,$7,$8,NULLIF($9, '')::inet,$10,COALESCE($11, '{}'::jsonb)
+		)
+		RETURNING id, created_at
+	`
+
+	return db.QueryRow(ctx, query,
+		log.CompanyID,
+		log.UserID,
+		log.TargetEmployeeID,
+		log.Action,
+		log.EntityType,
+		log.EntityID,
+		log.OldValues,
+		log.NewValues,
+		log.IPAddress,
+		log.UserAgent,
+		log.Metadata,
+	).Scan(&log.ID, &log.CreatedAt)
+}
@@ -0,0 +1,306 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// UpdateManager sets or clears (nil) the manager of an employee.
+func (e *EmployeeRepository) UpdateManager(ctx context.Context, employeeID uuid.UUID, managerID *uuid.UUID) (*models.Employee, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE employees e
+		SET manager_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE e.id = $2
+		RETURNING %s
+	`, employeeColumns)
+
+	var employee models.Employee
+	if err := scanEmployee(e.pool.QueryRow(ctx, query, managerID, employeeID), &employee); err != nil {
+		return nil, err
+	}
+
+	return &employee, nil
+}
+
+// IsInManagementChain reports whether candidateID is managerID itself or sits
+// anywhere above it in the reporting line. Assigning managerID as the manager
+// of candidateID would then create a cycle.
+func (e *EmployeeRepository) IsInManagementChain(ctx context.Context, managerID, candidateID uuid.UUID) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT id, manager_id FROM employees WHERE id = $1
+			UNION
+			SELECT e.id, e.manager_id FROM employees e JOIN chain c ON e.id = c.manager_id
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE id = $2)
+	`
+
+	var found bool
+	if err := e.pool.QueryRow(ctx, query, managerID, candidateID).Scan(&found); err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+// GetDirectReports returns the employees whose manager is managerID, excluding
+// terminated employees.
+func (e *EmployeeRepository) GetDirectReports(ctx context.Context, managerID uuid.UUID) ([]*models.Employee, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM employees e
+		WHERE e.manager_id = $1 AND e.status <> 'terminated'
+		ORDER BY e.last_name, e.first_name
+	`, employeeColumns)
+
+	rows, err := e.pool.Query(ctx, query, managerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var employees []*models.Employee
+	for rows.Next() {
+		var emp models.Employee
+		if err := scanEmployee(rows, &emp); err != nil {
+			return nil, err
+		}
+		employees = append(employees, &emp)
+	}
+
+	return employees, rows.Err()
+}
+
+// GetAllReports walks the reporting line below managerID and returns every
+// direct and indirect report with its depth. A maxDepth of 0 means unlimited.
+func (e *EmployeeRepository) GetAllReports(ctx context.Context, managerID uuid.UUID, maxDepth int) ([]*models.EmployeeReport, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE reports AS (
+			SELECT id, 1 AS depth, ARRAY[id] AS path
+			FROM employees
+			WHERE manager_id = $1 AND status <> 'terminated'
+			UNION ALL
+			SELECT emp.id, r.depth + 1, r.path || emp.id
+			FROM employees emp
+			JOIN reports r ON emp.manager_id = r.id
+			WHERE emp.status <> 'terminated'
+				AND NOT emp.id = ANY(r.path)
+				AND ($2::int = 0 OR r.depth < $2::int)
+		)
+		SELECT %s, r.depth
+		FROM reports r
+		JOIN employees e ON e.id = r.id
+		ORDER BY r.depth, e.last_name, e.first_name
+	`, employeeColumns)
+
+	rows, err := e.pool.Query(ctx, query, managerID, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []*models.EmployeeReport
+	for rows.Next() {
+		var report models.EmployeeReport
+		if err := scanEmployee(rows, &report.Employee, &report.Depth); err != nil {
+			return nil, err
+		}
+		reports = append(reports, &report)
+	}
+
+	return reports, rows.Err()
+}
+
+// GetManagementChain returns the managers above employeeID, starting with the
+// direct manager at depth 1 and ending at the top of the organisation.
+func (e *EmployeeRepository) GetManagementChain(ctx context.Context, employeeID uuid.UUID) ([]*models.EmployeeReport, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE chain AS (
+			SELECT manager_id AS id, 1 AS depth, ARRAY[id, manager_id] AS path
+			FROM employees
+			WHERE id = $1 AND manager_id IS NOT NULL
+			UNION ALL
+			SELECT emp.manager_id, c.depth + 1, c.path || emp.manager_id
+			FROM chain c
+			JOIN employees emp ON emp.id = c.id
+			WHERE emp.manager_id IS NOT NULL AND NOT emp.manager_id = ANY(c.path)
+		)
+		SELECT %s, c.depth
+		FROM chain c
+		JOIN employees e ON e.id = c.id
+		ORDER BY c.depth
+	`, employeeColumns)
+
+	rows, err := e.pool.Query(ctx, query, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chain []*models.EmployeeReport
+	for rows.Next() {
+		var manager models.EmployeeReport
+		if err := scanEmployee(rows, &manager.Employee, &manager.Depth); err != nil {
+			return nil, err
+		}
+		chain = append(chain, &manager)
+	}
+
+	return chain, rows.Err()
+}
+
+// GetSpanOfControl returns reporting statistics for a single manager.
+func (e *EmployeeRepository) GetSpanOfControl(ctx context.Context, managerID uuid.UUID) (*models.SpanOfControl, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		WITH RECURSIVE reports AS (
+			SELECT id, 1 AS depth, ARRAY[id] AS path
+			FROM employees
+			WHERE manager_id = $1 AND status <> 'terminated'
+			UNION ALL
+			SELECT emp.id, r.depth + 1, r.path || emp.id
+			FROM employees emp
+			JOIN reports r ON emp.manager_id = r.id
+			WHERE emp.status <> 'terminated' AND NOT emp.id = ANY(r.path)
+		)
+		SELECT
+			COUNT(*) FILTER (WHERE depth = 1),
+			COUNT(*),
+			COALESCE(MAX(depth), 0)
+		FROM reports
+	`
+
+	span := models.SpanOfControl{ManagerID: managerID}
+	if err := e.pool.QueryRow(ctx, query, managerID).Scan(
+		&span.DirectReports,
+		&span.TotalReports,
+		&span.Depth,
+	); err != nil {
+		return nil, err
+	}
+
+	return &span, nil
+}
+
+// GetCompanySpanOfControl returns reporting statistics for every employee in
+// the company who has at least one report, widest span first.
+func (e *EmployeeRepository) GetCompanySpanOfControl(ctx context.Context, companyID uuid.UUID) ([]*models.SpanOfControl, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		WITH RECURSIVE reports AS (
+			SELECT manager_id AS root_id, id, 1 AS depth, ARRAY[id] AS path
+			FROM employees
+			WHERE company_id = $1 AND manager_id IS NOT NULL AND status <> 'terminated'
+			UNION ALL
+			SELECT r.root_id, emp.id, r.depth + 1, r.path || emp.id
+			FROM employees emp
+			JOIN reports r ON emp.manager_id = r.id
+			WHERE emp.status <> 'terminated' AND NOT emp.id = ANY(r.path)
+		)
+		SELECT
+			root_id,
+			COUNT(*) FILTER (WHERE depth = 1) AS direct_reports,
+			COUNT(*) AS total_reports,
+			MAX(depth)
+		FROM reports
+		GROUP BY root_id
+		ORDER BY direct_reports DESC, total_reports DESC
+	`
+
+	rows, err := e.pool.Query(ctx, query, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var spans []*models.SpanOfControl
+	for rows.Next() {
+		var span models.SpanOfControl
+		if err := rows.Scan(&span.ManagerID, &span.DirectReports, &span.TotalReports, &span.Depth); err != nil {
+			return nil, err
+		}
+		spans = append(spans, &span)
+	}
+
+	return spans, rows.Err()
+}
+
+// GetEmployeesByRoleName returns the non-terminated employees of a company
+// holding the named role, whether it is a company role or a system role.
+func (e *EmployeeRepository) GetEmployeesByRoleName(ctx context.Context, companyID uuid.UUID, roleName string) ([]*models.Employee, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM employees e
+		JOIN roles r ON r.id = e.role_id
+		WHERE e.company_id = $1 AND r.name = $2 AND e.status <> 'terminated'
+		ORDER BY e.last_name, e.first_name
+	`, employeeColumns)
+
+	rows, err := e.pool.Query(ctx, query, companyID, roleName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var employees []*models.Employee
+	for rows.Next() {
+		var emp models.Employee
+		if err := scanEmployee(rows, &emp); err != nil {
+			return nil, err
+		}
+		employees = append(employees, &emp)
+	}
+
+	return employees, rows.Err()
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// createHierarchyEmployee creates an active employee reporting to managerID.
+func createHierarchyEmployee(t *testing.T, repo *EmployeeRepository, name string, managerID *uuid.UUID) *models.Employee {
+	t.Helper()
+
+	employee, err := repo.CreateEmployee(context.Background(), &models.Employee{
+		CompanyID:      uuid.MustParse(testCompanyID),
+		Email:          fmt.Sprintf("%s.%d@example.com", name, time.Now().UnixNano()),
+		PasswordHash:   ptrString("hashed"),
+		Phone:          "+1234567890",
+		FirstName:      name,
+		LastName:       "Hierarchy",
+		EmployeeCode:   fmt.Sprintf("%s%d", name, time.Now().UnixNano()),
+		ManagerID:      managerID,
+		RoleID:         uuid.MustParse(testRoleID),
+		Status:         "active",
+		EmploymentType: "full_time",
+		HireDate:       time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	return employee
+}
+
+func TestEmployeeRepository_ReportingLine(t *testing.T) {
+	repo := setupEmployeeRepository(t)
+	pool := setupTestDB(t)
+	ctx := context.Background()
+
+	if err := cleanupEmployeeTestData(ctx, pool, testCompanyID); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+
+	ceo := createHierarchyEmployee(t, repo, "ceo", nil)
+	cto := createHierarchyEmployee(t, repo, "cto", &ceo.ID)
+	engineer := createHierarchyEmployee(t, repo, "engineer", &cto.ID)
+
+	direct, err := repo.GetDirectReports(ctx, ceo.ID)
+	if err != nil {
+		t.Fatalf("GetDirectReports failed: %v", err)
+	}
+	if len(direct) != 1 || direct[0].ID != cto.ID {
+		t.Errorf("expected cto as only direct report, got %d reports", len(direct))
+	}
+
+	all, err := repo.GetAllReports(ctx, ceo.ID, 0)
+	if err != nil {
+		t.Fatalf("GetAllReports failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(all))
+	}
+	if all[1].ID != engineer.ID || all[1].Depth != 2 {
+		t.Errorf("expected engineer at depth 2, got %v at depth %d", all[1].ID, all[1].Depth)
+	}
+
+	limited, err := repo.GetAllReports(ctx, ceo.ID, 1)
+	if err != nil {
+		t.Fatalf("GetAllReports with depth failed: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("expected 1 report at max depth 1, got %d", len(limited))
+	}
+
+	chain, err := repo.GetManagementChain(ctx, engineer.ID)
+	if err != nil {
+		t.Fatalf("GetManagementChain failed: %v", err)
+	}
+	if len(chain) != 2 || chain[0].ID != cto.ID || chain[1].ID != ceo.ID {
+		t.Errorf("expected chain cto -> ceo, got %d entries", len(chain))
+	}
+
+	span, err := repo.GetSpanOfControl(ctx, ceo.ID)
+	if err != nil {
+		t.Fatalf("GetSpanOfControl failed: %v", err)
+	}
+	if span.DirectReports != 1 || span.TotalReports != 2 || span.Depth != 2 {
+		t.Errorf("unexpected span %+v", span)
+	}
+}
+
+func TestEmployeeRepository_UpdateManager_RejectsCycles(t *testing.T) {
+	repo := setupEmployeeRepository(t)
+	pool := setupTestDB(t)
+	ctx := context.Background()
+
+	if err := cleanupEmployeeTestData(ctx, pool, testCompanyID); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+
+	manager := createHierarchyEmployee(t, repo, "manager", nil)
+	report := createHierarchyEmployee(t, repo, "report", &manager.ID)
+
+	cycle, err := repo.IsInManagementChain(ctx, report.ID, manager.ID)
+	if err != nil {
+		t.Fatalf("IsInManagementChain failed: %v", err)
+	}
+	if !cycle {
+		t.Error("expected manager to be in the report's management chain")
+	}
+
+	if _, err := repo.UpdateManager(ctx, manager.ID, &report.ID); err == nil {
+		t.Error("expected cycle to be rejected")
+	}
+
+	if _, err := repo.UpdateManager(ctx, manager.ID, &manager.ID); err == nil {
+		t.Error("expected self-management to be rejected")
+	}
+}
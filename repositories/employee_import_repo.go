@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type EmployeeImportRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewEmployeeImportRepository(pool *pgxpool.Pool) *EmployeeImportRepository {
+	return &EmployeeImportRepository{
+		pool: pool,
+	}
+}
+
+// GetReferenceData loads the departments, designations, levels, roles and
+// employees of a company that import rows are resolved against.
+func (i *EmployeeImportRepository) GetReferenceData(ctx context.Context, companyID uuid.UUID) (*models.ImportReferenceData, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var data models.ImportReferenceData
+	var err error
+
+	if data.Departments, err = i.queryReferences(ctx,
+		"SELECT id, name, COALESCE(code, '') FROM departments WHERE company_id = $1 AND status = 'active'",
+		companyID,
+	); err != nil {
+		return nil, err
+	}
+	if data.Designations, err = i.queryReferences(ctx,
+		"SELECT id, name, '' FROM designations WHERE company_id = $1 AND status = 'active'",
+		companyID,
+	); err != nil {
+		return nil, err
+	}
+	if data.Levels, err = i.queryReferences(ctx,
+		"SELECT id, name, '' FROM levels WHERE company_id = $1",
+		companyID,
+	); err != nil {
+		return nil, err
+	}
+	if data.Roles, err = i.queryReferences(ctx,
+		"SELECT id, name, '' FROM roles WHERE company_id = $1 OR company_id IS NULL ORDER BY company_id NULLS LAST",
+		companyID,
+	); err != nil {
+		return nil, err
+	}
+
+	rows, err := i.pool.Query(ctx,
+		"SELECT id, email, COALESCE(employee_code, ''), status FROM employees WHERE company_id = $1",
+		companyID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ref models.ImportEmployeeRef
+		if err := rows.Scan(&ref.ID, &ref.Email, &ref.EmployeeCode, &ref.Status); err != nil {
+			return nil, err
+		}
+		data.Employees = append(data.Employees, ref)
+	}
+
+	return &data, rows.Err()
+}
+
+// ImportEmployees inserts every employee in one transaction. managerRows maps
+// the index of an employee to the index of its manager within the same
+// slice; those links are set once all rows exist, so a manager may appear
+// after their reports. Nothing is written if any row fails.
+func (i *EmployeeImportRepository) ImportEmployees(
+	ctx context.Context,
+	employees []*models.Employee,
+	managerRows map[int]int,
+	audit *models.AuditLog,
+) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 2*time.Minute)
+		defer cancel()
+	}
+
+	tx, err := i.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, employee := range employees {
+		if err := insertEmployee(ctx, tx, employee); err != nil {
+			return err
+		}
+	}
+
+	for employeeRow, managerRow := range managerRows {
+		employee := employees[employeeRow]
+		employee.ManagerID = &employees[managerRow].ID
+		if _, err := tx.Exec(ctx,
+			"UPDATE employees SET manager_id = $1 WHERE id = $2",
+			employee.ManagerID, employee.ID,
+		); err != nil {
+			return err
+		}
+	}
+
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (i *EmployeeImportRepository) queryReferences(ctx context.Context, query string, args ...any) ([]models.ImportReference, error) {
+	rows, err := i.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []models.ImportReference
+	for rows.Next() {
+		var ref models.ImportReference
+		if err := rows.Scan(&ref.ID, &ref.Name, &ref.Code); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
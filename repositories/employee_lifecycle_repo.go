@@ -0,0 +1,318 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// ErrEmployeeStatusChanged is returned when an employee's status no longer
+// matches the from_status a transition was validated against.
+var ErrEmployeeStatusChanged = errors.New("employee status changed since the transition was requested")
+
+type EmployeeLifecycleRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewEmployeeLifecycleRepository(pool *pgxpool.Pool) *EmployeeLifecycleRepository {
+	return &EmployeeLifecycleRepository{
+		pool: pool,
+	}
+}
+
+const transitionColumns = `
+	id, company_id, employee_id, from_status, to_status, effective_date,
+	termination_date, COALESCE(reason, ''), status, requested_by, applied_at,
+	COALESCE(failure_reason, ''), created_at, updated_at`
+
+func scanTransition(row pgx.Row, t *models.EmployeeStatusTransition) error {
+	return row.Scan(
+		&t.ID, &t.CompanyID, &t.EmployeeID, &t.FromStatus, &t.ToStatus, &t.EffectiveDate,
+		&t.TerminationDate, &t.Reason, &t.Status, &t.RequestedBy, &t.AppliedAt,
+		&t.FailureReason, &t.CreatedAt, &t.UpdatedAt,
+	)
+}
+
+// CreateTransition stores a transition with status 'scheduled' to be applied
+// on its effective date.
+func (l *EmployeeLifecycleRepository) CreateTransition(ctx context.Context, t *models.EmployeeStatusTransition) (*models.EmployeeStatusTransition, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return insertScheduledTransition(ctx, l.pool, t)
+}
+
+// insertScheduledTransition stores t through db with status 'scheduled'.
+func insertScheduledTransition(ctx context.Context, db dbExecutor, t *models.EmployeeStatusTransition) (*models.EmployeeStatusTransition, error) {
+	query := `
+		INSERT INTO employee_status_transitions (
+			company_id, employee_id, from_status, to_status, effective_date,
+			termination_date, reason, status, requested_by
+		)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,'scheduled',$8)
+		RETURNING ` + transitionColumns
+
+	var created models.EmployeeStatusTransition
+	err := scanTransition(db.QueryRow(ctx, query,
+		t.CompanyID,
+		t.EmployeeID,
+		t.FromStatus,
+		t.ToStatus,
+		t.EffectiveDate,
+		t.TerminationDate,
+		t.Reason,
+		t.RequestedBy,
+	), &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// ApplyTransition changes the employee's status, records the transition as
+// applied and writes the audit entry in one transaction. A transition without
+// an ID (an immediate change) is inserted; a scheduled one is updated.
+//
+// Leaving 'terminated' is a rehire: termination_date is cleared and hire_date
+// moves to the effective date.
+func (l *EmployeeLifecycleRepository) ApplyTransition(ctx context.Context, t *models.EmployeeStatusTransition, audit *models.AuditLog) (*models.EmployeeStatusTransition, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	applied, err := applyTransition(ctx, tx, t, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// applyTransition is ApplyTransition within tx.
+func applyTransition(ctx context.Context, tx pgx.Tx, t *models.EmployeeStatusTransition, audit *models.AuditLog) (*models.EmployeeStatusTransition, error) {
+	var current string
+	if err := tx.QueryRow(ctx,
+		"SELECT status FROM employees WHERE id = $1 FOR UPDATE",
+		t.EmployeeID,
+	).Scan(&current); err != nil {
+		return nil, err
+	}
+	if current != t.FromStatus {
+		return nil, ErrEmployeeStatusChanged
+	}
+
+	_, err := tx.Exec(ctx, `
+		UPDATE employees
+		SET
+			status = $1,
+			termination_date = CASE
+				WHEN $1 = 'terminated' THEN $2::date
+				WHEN status = 'terminated' THEN NULL
+				ELSE termination_date
+			END,
+			hire_date = CASE WHEN status = 'terminated' THEN $3::date ELSE hire_date END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+	`, t.ToStatus, t.TerminationDate, t.EffectiveDate, t.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied models.EmployeeStatusTransition
+	if t.ID == uuid.Nil {
+		err = scanTransition(tx.QueryRow(ctx, `
+			INSERT INTO employee_status_transitions (
+				company_id, employee_id, from_status, to_status, effective_date,
+				termination_date, reason, status, requested_by, applied_at
+			)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,'applied',$8,CURRENT_TIMESTAMP)
+			RETURNING `+transitionColumns,
+			t.CompanyID, t.EmployeeID, t.FromStatus, t.ToStatus, t.EffectiveDate,
+			t.TerminationDate, t.Reason, t.RequestedBy,
+		), &applied)
+	} else {
+		err = scanTransition(tx.QueryRow(ctx, `
+			UPDATE employee_status_transitions
+			SET status = 'applied', applied_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND status = 'scheduled'
+			RETURNING `+transitionColumns,
+			t.ID,
+		), &applied)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	audit.EntityID = &applied.ID
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return nil, err
+	}
+
+	return &applied, nil
+}
+
+func (l *EmployeeLifecycleRepository) GetTransitionByID(ctx context.Context, transitionID uuid.UUID) (*models.EmployeeStatusTransition, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := "SELECT " + transitionColumns + " FROM employee_status_transitions WHERE id = $1"
+
+	var t models.EmployeeStatusTransition
+	if err := scanTransition(l.pool.QueryRow(ctx, query, transitionID), &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// ListTransitions returns the full transition history of an employee, newest
+// effective date first.
+func (l *EmployeeLifecycleRepository) ListTransitions(ctx context.Context, employeeID uuid.UUID) ([]*models.EmployeeStatusTransition, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT ` + transitionColumns + `
+		FROM employee_status_transitions
+		WHERE employee_id = $1
+		ORDER BY effective_date DESC, created_at DESC
+	`
+
+	return l.queryTransitions(ctx, query, employeeID)
+}
+
+// GetDueTransitions returns scheduled transitions whose effective date is on
+// or before asOf, oldest first.
+func (l *EmployeeLifecycleRepository) GetDueTransitions(ctx context.Context, asOf time.Time) ([]*models.EmployeeStatusTransition, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT ` + transitionColumns + `
+		FROM employee_status_transitions
+		WHERE status = 'scheduled' AND effective_date <= $1
+		ORDER BY effective_date, created_at
+	`
+
+	return l.queryTransitions(ctx, query, asOf)
+}
+
+// HasScheduledTransition reports whether the employee already has a pending
+// future transition.
+func (l *EmployeeLifecycleRepository) HasScheduledTransition(ctx context.Context, employeeID uuid.UUID) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var exists bool
+	err := l.pool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM employee_status_transitions WHERE employee_id = $1 AND status = 'scheduled')",
+		employeeID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// CancelTransition cancels a transition that has not been applied yet. The
+// offboarding checklist opened with a scheduled termination is deleted in
+// the same transaction, so it never runs for an employee who is staying.
+func (l *EmployeeLifecycleRepository) CancelTransition(ctx context.Context, transitionID uuid.UUID) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
+		"UPDATE employee_status_transitions SET status = 'cancelled' WHERE id = $1 AND status = 'scheduled'",
+		transitionID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("scheduled transition not found")
+	}
+
+	if _, err := tx.Exec(ctx,
+		"DELETE FROM offboarding_checklists WHERE transition_id = $1 AND status = 'scheduled'",
+		transitionID,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// MarkTransitionFailed records why a scheduled transition could not be
+// applied so it is not retried on every run.
+func (l *EmployeeLifecycleRepository) MarkTransitionFailed(ctx context.Context, transitionID uuid.UUID, reason string) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	_, err := l.pool.Exec(ctx,
+		"UPDATE employee_status_transitions SET status = 'failed', failure_reason = $1 WHERE id = $2 AND status = 'scheduled'",
+		reason, transitionID,
+	)
+	return err
+}
+
+func (l *EmployeeLifecycleRepository) queryTransitions(ctx context.Context, query string, args ...any) ([]*models.EmployeeStatusTransition, error) {
+	rows, err := l.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transitions []*models.EmployeeStatusTransition
+	for rows.Next() {
+		var t models.EmployeeStatusTransition
+		if err := scanTransition(rows, &t); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, &t)
+	}
+
+	return transitions, rows.Err()
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/falasefemi2/companyflowlow/dto"
@@ -29,25 +31,35 @@ func (e *EmployeeRepository) CreateEmployee(ctx context.Context, employee *model
 		defer cancel()
 	}
 
+	if err := insertEmployee(ctx, e.pool, employee); err != nil {
+		return nil, err
+	}
+
+	return employee, nil
+}
+
+// insertEmployee inserts employee and fills in the generated columns.
+func insertEmployee(ctx context.Context, db dbExecutor, employee *models.Employee) error {
 	query := `
 		INSERT INTO employees (
 			company_id, email, password_hash, phone, first_name, last_name,
 			employee_code, department_id, designation_id, level_id, manager_id,
 			role_id, status, employment_type, hire_date, date_of_birth, gender,
-			address, emergency_contact_name, emergency_contact_phone, profile_image_url
+			address, emergency_contact_name, emergency_contact_phone, profile_image_url,
+			probation_end_date
 		)
 		VALUES (
-			$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21
+			$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22
 		)
 		RETURNING id, company_id, email, password_hash, phone, first_name, last_name,
 				  employee_code, department_id, designation_id, level_id, manager_id,
-				  role_id, status, employment_type, hire_date, termination_date,
+				  role_id, status, employment_type, hire_date, termination_date, probation_end_date,
 				  date_of_birth, gender, address, emergency_contact_name,
 				  emergency_contact_phone, profile_image_url, last_login_at,
 				  created_at, updated_at
 	`
 
-	err := e.pool.QueryRow(ctx, query,
+	return db.QueryRow(ctx, query,
 		employee.CompanyID,
 		employee.Email,
 		employee.PasswordHash,
@@ -69,6 +81,7 @@ func (e *EmployeeRepository) CreateEmployee(ctx context.Context, employee *model
 		employee.EmergencyContactName,
 		employee.EmergencyContactPhone,
 		employee.ProfileImageURL,
+		employee.ProbationEndDate,
 	).Scan(
 		&employee.ID,
 		&employee.CompanyID,
@@ -87,6 +100,7 @@ func (e *EmployeeRepository) CreateEmployee(ctx context.Context, employee *model
 		&employee.EmploymentType,
 		&employee.HireDate,
 		&employee.TerminationDate,
+		&employee.ProbationEndDate,
 		&employee.DateOfBirth,
 		&employee.Gender,
 		&employee.Address,
@@ -97,12 +111,6 @@ func (e *EmployeeRepository) CreateEmployee(ctx context.Context, employee *model
 		&employee.CreatedAt,
 		&employee.UpdatedAt,
 	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return employee, nil
 }
 
 func (e *EmployeeRepository) GetEmployeeByID(ctx context.Context, employeeID uuid.UUID) (*models.Employee, error) {
@@ -116,7 +124,7 @@ func (e *EmployeeRepository) GetEmployeeByID(ctx context.Context, employeeID uui
 		SELECT
 			id, company_id, email, password_hash, phone, first_name, last_name,
 			employee_code, department_id, designation_id, level_id, manager_id,
-			role_id, status, employment_type, hire_date, termination_date,
+			role_id, status, employment_type, hire_date, termination_date, probation_end_date,
 			date_of_birth, gender, address, emergency_contact_name,
 			emergency_contact_phone, profile_image_url, last_login_at,
 			created_at, updated_at
@@ -144,6 +152,7 @@ func (e *EmployeeRepository) GetEmployeeByID(ctx context.Context, employeeID uui
 		&employee.EmploymentType,
 		&employee.HireDate,
 		&employee.TerminationDate,
+		&employee.ProbationEndDate,
 		&employee.DateOfBirth,
 		&employee.Gender,
 		&employee.Address,
@@ -206,7 +215,7 @@ func (e *EmployeeRepository) GetEmployeeList(
 		SELECT
 			id, company_id, email, password_hash, phone, first_name, last_name,
 			employee_code, department_id, designation_id, level_id, manager_id,
-			role_id, status, employment_type, hire_date, termination_date,
+			role_id, status, employment_type, hire_date, termination_date, probation_end_date,
 			date_of_birth, gender, address, emergency_contact_name,
 			emergency_contact_phone, profile_image_url, last_login_at,
 			created_at, updated_at
@@ -233,7 +242,7 @@ func (e *EmployeeRepository) GetEmployeeList(
 			&emp.Phone, &emp.FirstName, &emp.LastName, &emp.EmployeeCode,
 			&emp.DepartmentID, &emp.DesignationID, &emp.LevelID, &emp.ManagerID,
 			&emp.RoleID, &emp.Status, &emp.EmploymentType, &emp.HireDate,
-			&emp.TerminationDate, &emp.DateOfBirth, &emp.Gender, &emp.Address,
+			&emp.TerminationDate, &emp.ProbationEndDate, &emp.DateOfBirth, &emp.Gender, &emp.Address,
 			&emp.EmergencyContactName, &emp.EmergencyContactPhone,
 			&emp.ProfileImageURL, &emp.LastLoginAt, &emp.CreatedAt, &emp.UpdatedAt,
 		); err != nil {
@@ -255,6 +264,19 @@ func (e *EmployeeRepository) GetEmployeeList(
 	}, nil
 }
 
+// DeactivatableStatuses are the statuses the employee lifecycle allows to
+// move to 'inactive'. They must match the service's transition rules.
+var DeactivatableStatuses = []string{"probation", "active", "on_leave"}
+
+// ErrEmployeeNotDeactivatable is returned when a soft delete would move an
+// employee to 'inactive' from a status the lifecycle does not allow.
+var ErrEmployeeNotDeactivatable = errors.New("employee cannot be deactivated from their current status")
+
+// DeleteEmployee removes an employee, or with hardDelete false deactivates
+// them. Deactivation is an ordinary lifecycle transition: it is refused from
+// statuses outside DeactivatableStatuses, and recorded as an applied status
+// transition with an audit entry in the same transaction. Deactivating an
+// inactive employee changes nothing.
 func (e *EmployeeRepository) DeleteEmployee(ctx context.Context, employeeID string, hardDelete bool) error {
 	if _, ok := ctx.Deadline(); !ok {
 		var cancel context.CancelFunc
@@ -273,17 +295,82 @@ func (e *EmployeeRepository) DeleteEmployee(ctx context.Context, employeeID stri
 		return nil
 	}
 
-	result, err := e.pool.Exec(
-		ctx,
-		"UPDATE employees SET status = 'inactive', updated_at = CURRENT_TIMESTAMP WHERE id = $1",
-		employeeID,
-	)
+	tx, err := e.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	if result.RowsAffected() == 0 {
+	defer tx.Rollback(ctx)
+
+	var companyID, id uuid.UUID
+	var status string
+	err = tx.QueryRow(ctx,
+		"SELECT id, company_id, status FROM employees WHERE id = $1 FOR UPDATE",
+		employeeID,
+	).Scan(&id, &companyID, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
 		return errors.New("employee not found")
 	}
+	if err != nil {
+		return err
+	}
+	if status == "inactive" {
+		return nil
+	}
+	if !slices.Contains(DeactivatableStatuses, status) {
+		return ErrEmployeeNotDeactivatable
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE employees SET status = 'inactive', updated_at = CURRENT_TIMESTAMP WHERE id = $1",
+		id,
+	); err != nil {
+		return err
+	}
 
-	return nil
+	var transitionID uuid.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO employee_status_transitions (
+			company_id, employee_id, from_status, to_status, effective_date, reason, status, applied_at
+		)
+		VALUES ($1, $2, $3, 'inactive', CURRENT_DATE, 'employee deleted', 'applied', CURRENT_TIMESTAMP)
+		RETURNING id
+	`, companyID, id, status).Scan(&transitionID); err != nil {
+		return err
+	}
+	if err := insertAuditLog(ctx, tx, &models.AuditLog{
+		CompanyID:        companyID,
+		TargetEmployeeID: &id,
+		Action:           "employee_deactivated",
+		EntityType:       "employee_status_transition",
+		EntityID:         &transitionID,
+		OldValues:        map[string]any{"status": status},
+		NewValues:        map[string]any{"status": "inactive"},
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// employeeColumns selects every employee column from a table aliased as e,
+// in the order expected by scanEmployee.
+const employeeColumns = `
+	e.id, e.company_id, e.email, e.password_hash, e.phone, e.first_name, e.last_name,
+	e.employee_code, e.department_id, e.designation_id, e.level_id, e.manager_id,
+	e.role_id, e.status, e.employment_type, e.hire_date, e.termination_date, e.probation_end_date,
+	e.date_of_birth, e.gender, e.address, e.emergency_contact_name,
+	e.emergency_contact_phone, e.profile_image_url, e.last_login_at,
+	e.created_at, e.updated_at`
+
+func scanEmployee(row pgx.Row, employee *models.Employee, extra ...any) error {
+	dest := []any{
+		&employee.ID, &employee.CompanyID, &employee.Email, &employee.PasswordHash,
+		&employee.Phone, &employee.FirstName, &employee.LastName, &employee.EmployeeCode,
+		&employee.DepartmentID, &employee.DesignationID, &employee.LevelID, &employee.ManagerID,
+		&employee.RoleID, &employee.Status, &employee.EmploymentType, &employee.HireDate,
+		&employee.TerminationDate, &employee.ProbationEndDate, &employee.DateOfBirth, &employee.Gender, &employee.Address,
+		&employee.EmergencyContactName, &employee.EmergencyContactPhone,
+		&employee.ProfileImageURL, &employee.LastLoginAt, &employee.CreatedAt, &employee.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
 }
@@ -34,7 +34,7 @@ func TestEmployeeRepository_CreateEmployee(t *testing.T) {
 	employee := &models.Employee{
 		CompanyID:             companyID,
 		Email:                 uniqueEmail,
-		PasswordHash:          "hashed_password",
+		PasswordHash:          ptrString("hashed_password"),
 		Phone:                 "+1234567890",
 		FirstName:             "Test",
 		LastName:              "Employee",
@@ -81,7 +81,7 @@ func TestEmployeeRepository_GetEmployeeByID(t *testing.T) {
 	employee := &models.Employee{
 		CompanyID:      companyID,
 		Email:          fmt.Sprintf("retrieve.%d@example.com", time.Now().UnixNano()),
-		PasswordHash:   "hashed",
+		PasswordHash:   ptrString("hashed"),
 		Phone:          "+1234567890",
 		FirstName:      "Retrieve",
 		LastName:       "Test",
@@ -127,7 +127,7 @@ func TestEmployeeRepository_GetEmployeeList(t *testing.T) {
 		_, err := repo.CreateEmployee(ctx, &models.Employee{
 			CompanyID:      companyID,
 			Email:          fmt.Sprintf("list%d.%d@example.com", i, time.Now().UnixNano()),
-			PasswordHash:   "hashed",
+			PasswordHash:   ptrString("hashed"),
 			Phone:          "+1234567890",
 			FirstName:      "Employee",
 			LastName:       fmt.Sprintf("Test%d", i),
@@ -182,7 +182,7 @@ func TestEmployeeRepository_GetEmployeeList_FilterStatus(t *testing.T) {
 	_, err := repo.CreateEmployee(ctx, &models.Employee{
 		CompanyID:      companyID,
 		Email:          fmt.Sprintf("active.%d@example.com", time.Now().UnixNano()),
-		PasswordHash:   "hashed",
+		PasswordHash:   ptrString("hashed"),
 		Phone:          "+1234567890",
 		FirstName:      "Active",
 		LastName:       "User",
@@ -230,7 +230,7 @@ func TestEmployeeRepository_DeleteEmployee(t *testing.T) {
 	employee, err := repo.CreateEmployee(ctx, &models.Employee{
 		CompanyID:      companyID,
 		Email:          fmt.Sprintf("delete.%d@example.com", time.Now().UnixNano()),
-		PasswordHash:   "hashed",
+		PasswordHash:   ptrString("hashed"),
 		Phone:          "+1234567890",
 		FirstName:      "Delete",
 		LastName:       "Test",
@@ -268,3 +268,7 @@ func TestEmployeeRepository_DeleteEmployee(t *testing.T) {
 		t.Error("expected error after hard delete")
 	}
 }
+
+func ptrString(s string) *string {
+	return &s
+}
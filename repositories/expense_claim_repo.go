@@ -0,0 +1,686 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// ErrExpenseClaimStatusChanged is returned when a claim is no longer in the
+// status a change expected, typically because a concurrent action got
+// there first.
+var ErrExpenseClaimStatusChanged = errors.New("expense claim status has changed")
+
+const expenseClaimColumns = `
+	id, company_id, employee_id, title, COALESCE(description, ''), currency, total_amount, status,
+	current_step, submitted_at, approved_by, approved_at, COALESCE(rejection_reason, ''),
+	payout_batch_id, reimbursed_at, created_at, updated_at`
+
+func scanExpenseClaim(row pgx.Row, c *models.ExpenseClaim, extra ...any) error {
+	dest := []any{
+		&c.ID, &c.CompanyID, &c.EmployeeID, &c.Title, &c.Description, &c.Currency, &c.TotalAmount, &c.Status,
+		&c.CurrentStep, &c.SubmittedAt, &c.ApprovedBy, &c.ApprovedAt, &c.RejectionReason,
+		&c.PayoutBatchID, &c.ReimbursedAt, &c.CreatedAt, &c.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+const expenseClaimItemColumns = `
+	id, claim_id, category_id, expense_date, description, COALESCE(merchant, ''), amount,
+	COALESCE(receipt_url, ''), position, created_at`
+
+func scanExpenseClaimItem(row pgx.Row, i *models.ExpenseClaimItem) error {
+	return row.Scan(
+		&i.ID, &i.ClaimID, &i.CategoryID, &i.ExpenseDate, &i.Description, &i.Merchant, &i.Amount,
+		&i.ReceiptURL, &i.Position, &i.CreatedAt,
+	)
+}
+
+// CreateExpenseClaim stores claim and its items as a draft in the company's
+// currency, totalling the items.
+func (x *ExpenseRepository) CreateExpenseClaim(ctx context.Context, claim *models.ExpenseClaim) (*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := x.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var created models.ExpenseClaim
+	if err := scanExpenseClaim(tx.QueryRow(ctx, `
+		INSERT INTO expense_claims (company_id, employee_id, title, description, currency, status)
+		SELECT $1, $2, $3, NULLIF($4, ''), COALESCE(currency, 'USD'), 'draft'
+		FROM companies WHERE id = $1
+		RETURNING `+expenseClaimColumns,
+		claim.CompanyID, claim.EmployeeID, claim.Title, claim.Description,
+	), &created); err != nil {
+		return nil, err
+	}
+
+	if err := replaceExpenseClaimItems(ctx, tx, &created, claim.Items); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// replaceExpenseClaimItems replaces the items of claim with items through
+// tx, in the order given, and brings its total in line with them.
+func replaceExpenseClaimItems(ctx context.Context, tx pgx.Tx, claim *models.ExpenseClaim, items []*models.ExpenseClaimItem) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM expense_claim_items WHERE claim_id = $1", claim.ID); err != nil {
+		return err
+	}
+
+	claim.Items = make([]*models.ExpenseClaimItem, 0, len(items))
+	for position, item := range items {
+		var created models.ExpenseClaimItem
+		if err := scanExpenseClaimItem(tx.QueryRow(ctx, `
+			INSERT INTO expense_claim_items (claim_id, category_id, expense_date, description, merchant, amount, receipt_url, position)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, NULLIF($7, ''), $8)
+			RETURNING `+expenseClaimItemColumns,
+			claim.ID, item.CategoryID, item.ExpenseDate, item.Description, item.Merchant, item.Amount, item.ReceiptURL, position,
+		), &created); err != nil {
+			return err
+		}
+		claim.Items = append(claim.Items, &created)
+	}
+
+	return tx.QueryRow(ctx, `
+		UPDATE expense_claims
+		SET total_amount = (SELECT COALESCE(SUM(amount), 0) FROM expense_claim_items WHERE claim_id = $1)
+		WHERE id = $1
+		RETURNING total_amount
+	`, claim.ID).Scan(&claim.TotalAmount)
+}
+
+// loadExpenseClaimItems fills in the items of claims through db.
+func loadExpenseClaimItems(ctx context.Context, db dbExecutor, claims ...*models.ExpenseClaim) error {
+	if len(claims) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.ExpenseClaim, len(claims))
+	ids := make([]uuid.UUID, 0, len(claims))
+	for _, c := range claims {
+		c.Items = []*models.ExpenseClaimItem{}
+		byID[c.ID] = c
+		ids = append(ids, c.ID)
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT `+expenseClaimItemColumns+`
+		FROM expense_claim_items
+		WHERE claim_id = ANY($1)
+		ORDER BY claim_id, position
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.ExpenseClaimItem
+		if err := scanExpenseClaimItem(rows, &item); err != nil {
+			return err
+		}
+		claim := byID[item.ClaimID]
+		claim.Items = append(claim.Items, &item)
+	}
+
+	return rows.Err()
+}
+
+// GetExpenseClaimByID returns a claim with its items.
+func (x *ExpenseRepository) GetExpenseClaimByID(ctx context.Context, claimID uuid.UUID) (*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var claim models.ExpenseClaim
+	if err := scanExpenseClaim(x.pool.QueryRow(ctx,
+		"SELECT "+expenseClaimColumns+" FROM expense_claims WHERE id = $1",
+		claimID,
+	), &claim); err != nil {
+		return nil, err
+	}
+	if err := loadExpenseClaimItems(ctx, x.pool, &claim); err != nil {
+		return nil, err
+	}
+
+	return &claim, nil
+}
+
+// ListExpenseClaims returns a company's claims with their items, newest
+// first, narrowed to one employee when employeeID is set. An empty status
+// returns every claim.
+func (x *ExpenseRepository) ListExpenseClaims(ctx context.Context, companyID uuid.UUID, employeeID *uuid.UUID, status string) ([]*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	rows, err := x.pool.Query(ctx, `
+		SELECT `+expenseClaimColumns+`
+		FROM expense_claims
+		WHERE company_id = $1 AND ($2::uuid IS NULL OR employee_id = $2) AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC
+	`, companyID, employeeID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claims []*models.ExpenseClaim
+	for rows.Next() {
+		var claim models.ExpenseClaim
+		if err := scanExpenseClaim(rows, &claim); err != nil {
+			return nil, err
+		}
+		claims = append(claims, &claim)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadExpenseClaimItems(ctx, x.pool, claims...); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// EditExpenseClaim replaces the title, description and items of a draft or
+// a claim awaiting changes with those of edited. It returns
+// ErrExpenseClaimStatusChanged when the claim can no longer be edited.
+func (x *ExpenseRepository) EditExpenseClaim(ctx context.Context, edited *models.ExpenseClaim) (*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := x.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.ExpenseClaim
+	err = scanExpenseClaim(tx.QueryRow(ctx, `
+		UPDATE expense_claims
+		SET title = $2, description = NULLIF($3, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status IN ('draft', 'changes_requested')
+		RETURNING `+expenseClaimColumns,
+		edited.ID, edited.Title, edited.Description,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrExpenseClaimStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := replaceExpenseClaimItems(ctx, tx, &updated, edited.Items); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// AttachExpenseReceipt sets the receipt of one item of a draft or a claim
+// awaiting changes. It returns ErrExpenseClaimStatusChanged when the claim
+// can no longer be edited and pgx.ErrNoRows when the item is not the
+// claim's.
+func (x *ExpenseRepository) AttachExpenseReceipt(ctx context.Context, claimID, itemID uuid.UUID, receiptURL string) (*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := x.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.ExpenseClaim
+	err = scanExpenseClaim(tx.QueryRow(ctx, `
+		UPDATE expense_claims
+		SET updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status IN ('draft', 'changes_requested')
+		RETURNING `+expenseClaimColumns,
+		claimID,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrExpenseClaimStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(ctx,
+		"UPDATE expense_claim_items SET receipt_url = $3 WHERE id = $2 AND claim_id = $1",
+		claimID, itemID, receiptURL,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	if err := loadExpenseClaimItems(ctx, tx, &updated); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// ExpenseSubmissionCheck vets a claim as it goes before approvers, with the
+// items it holds inside the submitting transaction. spend reads what the
+// employee has claimed on their other claims through that transaction.
+type ExpenseSubmissionCheck func(claim *models.ExpenseClaim, spend ExpenseSpendReader) error
+
+// ExpenseSpendReader totals per month and category what the employee has
+// claimed between from and to, inclusive, on their other claims that still
+// count: pending, awaiting changes, approved or reimbursed.
+type ExpenseSpendReader func(from, to time.Time) ([]*models.ExpenseSpend, error)
+
+// SubmitExpenseClaim puts a draft before the first step of its approvers
+// once it passes check. The employee row is locked so concurrent
+// submissions cannot both pass their spending limits. It returns
+// ErrExpenseClaimStatusChanged when the claim is no longer a draft, and
+// the error from check when it fails.
+func (x *ExpenseRepository) SubmitExpenseClaim(ctx context.Context, claimID uuid.UUID, check ExpenseSubmissionCheck) (*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := x.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockClaimEmployee(ctx, tx, claimID); err != nil {
+		return nil, err
+	}
+
+	var updated models.ExpenseClaim
+	err = scanExpenseClaim(tx.QueryRow(ctx, `
+		UPDATE expense_claims
+		SET status = 'pending', current_step = 1, submitted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'draft'
+		RETURNING `+expenseClaimColumns,
+		claimID,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrExpenseClaimStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpenseSubmission(ctx, tx, &updated, check); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// lockClaimEmployee locks the row of the employee who owns claimID.
+func lockClaimEmployee(ctx context.Context, tx pgx.Tx, claimID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		SELECT 1 FROM employees
+		WHERE id = (SELECT employee_id FROM expense_claims WHERE id = $1)
+		FOR UPDATE
+	`, claimID)
+	return err
+}
+
+// checkExpenseSubmission loads the items of claim through tx and runs check
+// against them.
+func checkExpenseSubmission(ctx context.Context, tx pgx.Tx, claim *models.ExpenseClaim, check ExpenseSubmissionCheck) error {
+	if err := loadExpenseClaimItems(ctx, tx, claim); err != nil {
+		return err
+	}
+	return check(claim, func(from, to time.Time) ([]*models.ExpenseSpend, error) {
+		return listExpenseSpend(ctx, tx, claim.EmployeeID, claim.ID, from, to)
+	})
+}
+
+// TransitionExpenseClaim moves a claim from fromStatus to history.Action
+// (approved, rejected or withdrawn), recording history and closing its
+// approval request in one transaction. It returns
+// ErrExpenseClaimStatusChanged when the claim is no longer in fromStatus.
+func (x *ExpenseRepository) TransitionExpenseClaim(
+	ctx context.Context,
+	claimID uuid.UUID,
+	fromStatus string,
+	history *models.ApprovalHistory,
+) (*models.ExpenseClaim, error) {
+	return x.transitionExpenseClaim(ctx, claimID, fromStatus, 0, history)
+}
+
+// FinalizeExpenseClaim applies the final approval or rejection of a claim
+// pending at step, as TransitionExpenseClaim does. It returns
+// ErrExpenseClaimStatusChanged when the claim is no longer pending at step.
+func (x *ExpenseRepository) FinalizeExpenseClaim(
+	ctx context.Context,
+	claimID uuid.UUID,
+	step int,
+	history *models.ApprovalHistory,
+) (*models.ExpenseClaim, error) {
+	return x.transitionExpenseClaim(ctx, claimID, "pending", step, history)
+}
+
+// transitionExpenseClaim moves a claim in fromStatus, and at step unless
+// step is 0, to history.Action.
+func (x *ExpenseRepository) transitionExpenseClaim(
+	ctx context.Context,
+	claimID uuid.UUID,
+	fromStatus string,
+	step int,
+	history *models.ApprovalHistory,
+) (*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := x.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	toStatus := history.Action
+	var updated models.ExpenseClaim
+	err = scanExpenseClaim(tx.QueryRow(ctx, `
+		UPDATE expense_claims
+		SET status = $3,
+			approved_by = CASE WHEN $3 = 'approved' THEN $4 ELSE approved_by END,
+			approved_at = CASE WHEN $3 = 'approved' THEN CURRENT_TIMESTAMP ELSE approved_at END,
+			rejection_reason = CASE WHEN $3 = 'rejected' THEN NULLIF($5, '') ELSE rejection_reason END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $2 AND ($6 = 0 OR current_step = $6)
+		RETURNING `+expenseClaimColumns,
+		claimID, fromStatus, toStatus, history.ApproverID, history.Comments, step,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrExpenseClaimStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "expense_claim"
+	history.EntityID = claimID
+	history.StepNumber = updated.CurrentStep
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+	if err := closeApprovalRequest(ctx, tx, "expense_claim", claimID, toStatus); err != nil {
+		return nil, err
+	}
+	if err := loadExpenseClaimItems(ctx, tx, &updated); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// AdvanceExpenseClaim records an intermediate approval: the pending claim
+// moves from fromStep to toStep along with its approval request. It returns
+// ErrExpenseClaimStatusChanged when the claim is no longer pending at
+// fromStep.
+func (x *ExpenseRepository) AdvanceExpenseClaim(
+	ctx context.Context,
+	claimID uuid.UUID,
+	fromStep, toStep int,
+	history *models.ApprovalHistory,
+) (*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := x.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.ExpenseClaim
+	err = scanExpenseClaim(tx.QueryRow(ctx, `
+		UPDATE expense_claims
+		SET current_step = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending' AND current_step = $2
+		RETURNING `+expenseClaimColumns,
+		claimID, fromStep, toStep,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrExpenseClaimStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = advanceApprovalRequest(ctx, tx, "expense_claim", claimID, fromStep, toStep)
+	if errors.Is(err, ErrApprovalRequestStatusChanged) {
+		return nil, ErrExpenseClaimStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "expense_claim"
+	history.EntityID = claimID
+	history.StepNumber = fromStep
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// ReturnExpenseClaim sends a pending claim at step back to its requester for
+// changes, along with its approval request. It returns
+// ErrExpenseClaimStatusChanged when the claim is no longer pending at step.
+func (x *ExpenseRepository) ReturnExpenseClaim(
+	ctx context.Context,
+	claimID uuid.UUID,
+	step int,
+	history *models.ApprovalHistory,
+) (*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := x.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.ExpenseClaim
+	err = scanExpenseClaim(tx.QueryRow(ctx, `
+		UPDATE expense_claims
+		SET status = 'changes_requested', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending' AND current_step = $2
+		RETURNING `+expenseClaimColumns,
+		claimID, step,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrExpenseClaimStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = returnApprovalRequest(ctx, tx, "expense_claim", claimID, step)
+	if errors.Is(err, ErrApprovalRequestStatusChanged) {
+		return nil, ErrExpenseClaimStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "expense_claim"
+	history.EntityID = claimID
+	history.StepNumber = step
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// ResubmitExpenseClaim puts a claim awaiting changes back before the
+// approvers of toStep once it passes check, along with its approval
+// request, recording revision, when not nil, as its latest revision. As in
+// SubmitExpenseClaim the employee row is locked first. It returns
+// ErrExpenseClaimStatusChanged when the claim is no longer awaiting changes.
+func (x *ExpenseRepository) ResubmitExpenseClaim(
+	ctx context.Context,
+	claimID uuid.UUID,
+	toStep int,
+	history *models.ApprovalHistory,
+	revision *models.ApprovalRevision,
+	check ExpenseSubmissionCheck,
+) (*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := x.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockClaimEmployee(ctx, tx, claimID); err != nil {
+		return nil, err
+	}
+
+	var updated models.ExpenseClaim
+	err = scanExpenseClaim(tx.QueryRow(ctx, `
+		UPDATE expense_claims
+		SET status = 'pending', current_step = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'changes_requested'
+		RETURNING `+expenseClaimColumns,
+		claimID, toStep,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrExpenseClaimStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = resubmitApprovalRequest(ctx, tx, "expense_claim", claimID, toStep)
+	if errors.Is(err, ErrApprovalRequestStatusChanged) {
+		return nil, ErrExpenseClaimStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "expense_claim"
+	history.EntityID = claimID
+	history.StepNumber = toStep
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+	if revision != nil {
+		if _, err := insertApprovalRevision(ctx, tx, revision); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkExpenseSubmission(ctx, tx, &updated, check); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// listExpenseSpend totals what an employee has claimed per month and
+// category between from and to, inclusive, across their submitted claims
+// that still count: pending, awaiting changes, approved or reimbursed.
+// excludeClaimID leaves out the claim being checked.
+func listExpenseSpend(ctx context.Context, db dbExecutor, employeeID, excludeClaimID uuid.UUID, from, to time.Time) ([]*models.ExpenseSpend, error) {
+	rows, err := db.Query(ctx, `
+		SELECT date_trunc('month', i.expense_date)::date, i.category_id, SUM(i.amount)
+		FROM expense_claim_items i
+		JOIN expense_claims c ON c.id = i.claim_id
+		WHERE c.employee_id = $1 AND c.id <> $2
+			AND c.status IN ('pending', 'changes_requested', 'approved', 'reimbursed')
+			AND i.expense_date BETWEEN $3 AND $4
+		GROUP BY 1, 2
+	`, employeeID, excludeClaimID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var spend []*models.ExpenseSpend
+	for rows.Next() {
+		var s models.ExpenseSpend
+		if err := rows.Scan(&s.Month, &s.CategoryID, &s.Amount); err != nil {
+			return nil, err
+		}
+		spend = append(spend, &s)
+	}
+
+	return spend, rows.Err()
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// ErrNoExpenseClaimsToPay is returned when a payout batch would contain no
+// claims.
+var ErrNoExpenseClaimsToPay = errors.New("no approved expense claims are waiting to be paid")
+
+const expensePayoutBatchColumns = `
+	id, company_id, reference, currency, total_amount, claim_count, created_by, created_at`
+
+func scanExpensePayoutBatch(row pgx.Row, b *models.ExpensePayoutBatch) error {
+	return row.Scan(&b.ID, &b.CompanyID, &b.Reference, &b.Currency, &b.TotalAmount, &b.ClaimCount, &b.CreatedBy, &b.CreatedAt)
+}
+
+// CreateExpensePayoutBatches gathers the company's approved claims that
+// have not been paid yet, only those in claimIDs when it is not empty, into
+// one new batch per currency and marks them reimbursed, in one transaction.
+// The batches are numbered PAY/<year>/<sequence> within the company, in
+// currency order. It returns the batches with the claims each pays, or
+// ErrNoExpenseClaimsToPay when there are none.
+func (x *ExpenseRepository) CreateExpensePayoutBatches(
+	ctx context.Context,
+	companyID uuid.UUID,
+	claimIDs []uuid.UUID,
+	createdBy *uuid.UUID,
+) ([]*models.ExpensePayoutBatch, map[uuid.UUID][]*models.ExpenseClaim, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	tx, err := x.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the company serialises batch numbering.
+	if _, err := tx.Exec(ctx, "SELECT 1 FROM companies WHERE id = $1 FOR UPDATE", companyID); err != nil {
+		return nil, nil, err
+	}
+
+	var year, sequence int
+	if err := tx.QueryRow(ctx, `
+		SELECT EXTRACT(YEAR FROM CURRENT_DATE)::int, COUNT(*)
+		FROM expense_payout_batches
+		WHERE company_id = $1 AND created_at >= date_trunc('year', CURRENT_DATE)
+	`, companyID).Scan(&year, &sequence); err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT DISTINCT currency
+		FROM expense_claims
+		WHERE company_id = $1 AND status = 'approved' AND payout_batch_id IS NULL
+			AND (COALESCE(cardinality($2::uuid[]), 0) = 0 OR id = ANY($2))
+		ORDER BY currency
+	`, companyID, claimIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	currencies, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var batches []*models.ExpensePayoutBatch
+	claimsByBatch := make(map[uuid.UUID][]*models.ExpenseClaim, len(currencies))
+	for _, currency := range currencies {
+		sequence++
+		batch, claims, err := createExpensePayoutBatch(ctx, tx, companyID, claimIDs, createdBy, currency, fmt.Sprintf("PAY/%d/%04d", year, sequence))
+		if err != nil {
+			return nil, nil, err
+		}
+		batches = append(batches, batch)
+		claimsByBatch[batch.ID] = claims
+	}
+	if len(batches) == 0 {
+		return nil, nil, ErrNoExpenseClaimsToPay
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return batches, claimsByBatch, nil
+}
+
+// createExpensePayoutBatch pays the claims in currency that
+// CreateExpensePayoutBatches selects into a new batch numbered reference.
+func createExpensePayoutBatch(
+	ctx context.Context,
+	tx pgx.Tx,
+	companyID uuid.UUID,
+	claimIDs []uuid.UUID,
+	createdBy *uuid.UUID,
+	currency, reference string,
+) (*models.ExpensePayoutBatch, []*models.ExpenseClaim, error) {
+	batch := &models.ExpensePayoutBatch{}
+	if err := scanExpensePayoutBatch(tx.QueryRow(ctx, `
+		INSERT INTO expense_payout_batches (company_id, reference, currency, total_amount, claim_count, created_by)
+		VALUES ($1, $2, $3, 0, 0, $4)
+		RETURNING `+expensePayoutBatchColumns,
+		companyID, reference, currency, createdBy,
+	), batch); err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE expense_claims
+		SET status = 'reimbursed', payout_batch_id = $2, reimbursed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE company_id = $1 AND status = 'approved' AND payout_batch_id IS NULL AND currency = $3
+			AND (COALESCE(cardinality($4::uuid[]), 0) = 0 OR id = ANY($4))
+		RETURNING `+expenseClaimColumns,
+		companyID, batch.ID, currency, claimIDs,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	var claims []*models.ExpenseClaim
+	for rows.Next() {
+		var claim models.ExpenseClaim
+		if err := scanExpenseClaim(rows, &claim); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		claims = append(claims, &claim)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := scanExpensePayoutBatch(tx.QueryRow(ctx, `
+		UPDATE expense_payout_batches
+		SET claim_count = (SELECT COUNT(*) FROM expense_claims WHERE payout_batch_id = $1),
+			total_amount = (SELECT COALESCE(SUM(total_amount), 0) FROM expense_claims WHERE payout_batch_id = $1)
+		WHERE id = $1
+		RETURNING `+expensePayoutBatchColumns,
+		batch.ID,
+	), batch); err != nil {
+		return nil, nil, err
+	}
+
+	return batch, claims, nil
+}
+
+func (x *ExpenseRepository) GetExpensePayoutBatchByID(ctx context.Context, batchID uuid.UUID) (*models.ExpensePayoutBatch, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var batch models.ExpensePayoutBatch
+	if err := scanExpensePayoutBatch(x.pool.QueryRow(ctx,
+		"SELECT "+expensePayoutBatchColumns+" FROM expense_payout_batches WHERE id = $1",
+		batchID,
+	), &batch); err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+// ListExpensePayoutBatches returns the company's batches, newest first.
+func (x *ExpenseRepository) ListExpensePayoutBatches(ctx context.Context, companyID uuid.UUID) ([]*models.ExpensePayoutBatch, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := x.pool.Query(ctx, `
+		SELECT `+expensePayoutBatchColumns+`
+		FROM expense_payout_batches
+		WHERE company_id = $1
+		ORDER BY created_at DESC
+	`, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []*models.ExpensePayoutBatch
+	for rows.Next() {
+		var batch models.ExpensePayoutBatch
+		if err := scanExpensePayoutBatch(rows, &batch); err != nil {
+			return nil, err
+		}
+		batches = append(batches, &batch)
+	}
+
+	return batches, rows.Err()
+}
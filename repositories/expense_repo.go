@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type ExpenseRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewExpenseRepository(pool *pgxpool.Pool) *ExpenseRepository {
+	return &ExpenseRepository{
+		pool: pool,
+	}
+}
+
+const expenseCategoryColumns = `
+	id, company_id, name, COALESCE(description, ''), requires_receipt, is_active, created_at, updated_at`
+
+func scanExpenseCategory(row pgx.Row, c *models.ExpenseCategory) error {
+	return row.Scan(&c.ID, &c.CompanyID, &c.Name, &c.Description, &c.RequiresReceipt, &c.IsActive, &c.CreatedAt, &c.UpdatedAt)
+}
+
+const expenseLimitColumns = `id, company_id, level_id, category_id, period, amount, created_at`
+
+func scanExpenseLimit(row pgx.Row, l *models.ExpenseLimit) error {
+	return row.Scan(&l.ID, &l.CompanyID, &l.LevelID, &l.CategoryID, &l.Period, &l.Amount, &l.CreatedAt)
+}
+
+func (x *ExpenseRepository) CreateExpenseCategory(ctx context.Context, c *models.ExpenseCategory) (*models.ExpenseCategory, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var created models.ExpenseCategory
+	if err := scanExpenseCategory(x.pool.QueryRow(ctx, `
+		INSERT INTO expense_categories (company_id, name, description, requires_receipt, is_active)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING `+expenseCategoryColumns,
+		c.CompanyID, c.Name, c.Description, c.RequiresReceipt, c.IsActive,
+	), &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (x *ExpenseRepository) GetExpenseCategoryByID(ctx context.Context, categoryID uuid.UUID) (*models.ExpenseCategory, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var c models.ExpenseCategory
+	if err := scanExpenseCategory(x.pool.QueryRow(ctx,
+		"SELECT "+expenseCategoryColumns+" FROM expense_categories WHERE id = $1",
+		categoryID,
+	), &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// ListExpenseCategories returns the company's categories by name, only the
+// active ones when activeOnly is set.
+func (x *ExpenseRepository) ListExpenseCategories(ctx context.Context, companyID uuid.UUID, activeOnly bool) ([]*models.ExpenseCategory, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := x.pool.Query(ctx, `
+		SELECT `+expenseCategoryColumns+`
+		FROM expense_categories
+		WHERE company_id = $1 AND (NOT $2 OR is_active)
+		ORDER BY name
+	`, companyID, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*models.ExpenseCategory
+	for rows.Next() {
+		var c models.ExpenseCategory
+		if err := scanExpenseCategory(rows, &c); err != nil {
+			return nil, err
+		}
+		categories = append(categories, &c)
+	}
+
+	return categories, rows.Err()
+}
+
+func (x *ExpenseRepository) UpdateExpenseCategory(ctx context.Context, c *models.ExpenseCategory) (*models.ExpenseCategory, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var updated models.ExpenseCategory
+	if err := scanExpenseCategory(x.pool.QueryRow(ctx, `
+		UPDATE expense_categories
+		SET name = $2, description = NULLIF($3, ''), requires_receipt = $4, is_active = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+expenseCategoryColumns,
+		c.ID, c.Name, c.Description, c.RequiresReceipt, c.IsActive,
+	), &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// LevelExists reports whether levelID is one of the company's levels.
+func (x *ExpenseRepository) LevelExists(ctx context.Context, companyID, levelID uuid.UUID) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var exists bool
+	err := x.pool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM levels WHERE id = $1 AND company_id = $2)",
+		levelID, companyID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// ListExpenseLimits returns the limits of a level, whole-claim limits first.
+func (x *ExpenseRepository) ListExpenseLimits(ctx context.Context, levelID uuid.UUID) ([]*models.ExpenseLimit, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := x.pool.Query(ctx, `
+		SELECT `+expenseLimitColumns+`
+		FROM expense_limits
+		WHERE level_id = $1
+		ORDER BY category_id NULLS FIRST, period
+	`, levelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var limits []*models.ExpenseLimit
+	for rows.Next() {
+		var l models.ExpenseLimit
+		if err := scanExpenseLimit(rows, &l); err != nil {
+			return nil, err
+		}
+		limits = append(limits, &l)
+	}
+
+	return limits, rows.Err()
+}
+
+// ReplaceExpenseLimits replaces every limit of a level with limits in one
+// transaction.
+func (x *ExpenseRepository) ReplaceExpenseLimits(ctx context.Context, companyID, levelID uuid.UUID, limits []*models.ExpenseLimit) ([]*models.ExpenseLimit, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := x.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM expense_limits WHERE level_id = $1", levelID); err != nil {
+		return nil, err
+	}
+
+	created := make([]*models.ExpenseLimit, 0, len(limits))
+	for _, l := range limits {
+		var c models.ExpenseLimit
+		if err := scanExpenseLimit(tx.QueryRow(ctx, `
+			INSERT INTO expense_limits (company_id, level_id, category_id, period, amount)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING `+expenseLimitColumns,
+			companyID, levelID, l.CategoryID, l.Period, l.Amount,
+		), &c); err != nil {
+			return nil, err
+		}
+		created = append(created, &c)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
@@ -0,0 +1,254 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// exportTimeout bounds a whole streamed export rather than a single query.
+const exportTimeout = 5 * time.Minute
+
+type ExportRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewExportRepository(pool *pgxpool.Pool) *ExportRepository {
+	return &ExportRepository{
+		pool: pool,
+	}
+}
+
+// StreamEmployees calls fn for every employee matching the list filters, in
+// employee code order, without loading the result set into memory.
+// Pagination fields of listRequest are ignored.
+func (x *ExportRepository) StreamEmployees(
+	ctx context.Context,
+	companyID uuid.UUID,
+	listRequest *dto.EmployeeListRequest,
+	fn func(row *models.EmployeeExportRow) error,
+) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, exportTimeout)
+		defer cancel()
+	}
+
+	where := "WHERE e.company_id = $1"
+	args := []any{companyID}
+	i := 2
+
+	if listRequest.Status != "" {
+		where += fmt.Sprintf(" AND e.status = $%d", i)
+		args = append(args, listRequest.Status)
+		i++
+	}
+	if listRequest.DepartmentID != "" {
+		where += fmt.Sprintf(" AND e.department_id = $%d", i)
+		id, err := uuid.Parse(listRequest.DepartmentID)
+		if err != nil {
+			return err
+		}
+		args = append(args, id)
+		i++
+	}
+	if listRequest.ManagerID != "" {
+		where += fmt.Sprintf(" AND e.manager_id = $%d", i)
+		id, err := uuid.Parse(listRequest.ManagerID)
+		if err != nil {
+			return err
+		}
+		args = append(args, id)
+		i++
+	}
+	if listRequest.EmploymentType != "" {
+		where += fmt.Sprintf(" AND e.employment_type = $%d", i)
+		args = append(args, listRequest.EmploymentType)
+		i++
+	}
+	if listRequest.Search != "" {
+		where += fmt.Sprintf(" AND (e.first_name ILIKE $%d OR e.last_name ILIKE $%d OR e.email ILIKE $%d)", i, i, i)
+		args = append(args, "%"+listRequest.Search+"%")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			e.id, COALESCE(e.employee_code, ''), e.email, COALESCE(e.phone, ''),
+			e.first_name, e.last_name,
+			COALESCE(d.name, ''), COALESCE(des.name, ''), COALESCE(l.name, ''), COALESCE(r.name, ''),
+			COALESCE(m.first_name || ' ' || m.last_name, ''), COALESCE(m.employee_code, ''),
+			e.status, e.employment_type, e.hire_date, e.termination_date, e.probation_end_date,
+			e.date_of_birth, COALESCE(e.gender, ''), COALESCE(e.address, ''),
+			COALESCE(e.emergency_contact_name, ''), COALESCE(e.emergency_contact_phone, ''),
+			e.created_at
+		FROM employees e
+		LEFT JOIN departments d ON d.id = e.department_id
+		LEFT JOIN designations des ON des.id = e.designation_id
+		LEFT JOIN levels l ON l.id = e.level_id
+		LEFT JOIN roles r ON r.id = e.role_id
+		LEFT JOIN employees m ON m.id = e.manager_id
+		%s
+		ORDER BY e.employee_code, e.last_name, e.first_name
+	`, where)
+
+	rows, err := x.pool.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.EmployeeExportRow
+		if err := rows.Scan(
+			&row.ID, &row.EmployeeCode, &row.Email, &row.Phone,
+			&row.FirstName, &row.LastName,
+			&row.DepartmentName, &row.DesignationName, &row.LevelName, &row.RoleName,
+			&row.ManagerName, &row.ManagerCode,
+			&row.Status, &row.EmploymentType, &row.HireDate, &row.TerminationDate, &row.ProbationEndDate,
+			&row.DateOfBirth, &row.Gender, &row.Address,
+			&row.EmergencyContactName, &row.EmergencyContactPhone,
+			&row.CreatedAt,
+		); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// StreamDepartments calls fn for every department matching the list filters,
+// in name order. Pagination fields of listRequest are ignored.
+func (x *ExportRepository) StreamDepartments(
+	ctx context.Context,
+	companyID uuid.UUID,
+	listRequest *dto.DepartmentListRequest,
+	fn func(row *models.DepartmentExportRow) error,
+) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, exportTimeout)
+		defer cancel()
+	}
+
+	where := "WHERE d.company_id = $1"
+	args := []any{companyID}
+	i := 2
+
+	if listRequest.Status != "" {
+		where += fmt.Sprintf(" AND d.status = $%d", i)
+		args = append(args, listRequest.Status)
+		i++
+	}
+	if listRequest.Search != "" {
+		where += fmt.Sprintf(" AND (d.name ILIKE $%d OR d.code ILIKE $%d)", i, i)
+		args = append(args, "%"+listRequest.Search+"%")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			d.id, d.name, COALESCE(d.code, ''), COALESCE(d.description, ''),
+			COALESCE(p.name, ''), COALESCE(h.first_name || ' ' || h.last_name, ''),
+			COALESCE(d.cost_center, ''), d.status,
+			(SELECT COUNT(*) FROM employees e WHERE e.department_id = d.id AND e.status <> 'terminated'),
+			d.created_at
+		FROM departments d
+		LEFT JOIN departments p ON p.id = d.parent_department_id
+		LEFT JOIN employees h ON h.id = d.hod_id
+		%s
+		ORDER BY d.name
+	`, where)
+
+	rows, err := x.pool.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.DepartmentExportRow
+		if err := rows.Scan(
+			&row.ID, &row.Name, &row.Code, &row.Description,
+			&row.ParentDepartmentName, &row.HODName,
+			&row.CostCenter, &row.Status,
+			&row.EmployeeCount,
+			&row.CreatedAt,
+		); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ExpensePayoutBatchExists reports whether batchID is one of the company's
+// payout batches.
+func (x *ExportRepository) ExpensePayoutBatchExists(ctx context.Context, companyID, batchID uuid.UUID) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var exists bool
+	err := x.pool.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM expense_payout_batches WHERE id = $1 AND company_id = $2)",
+		batchID, companyID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// StreamExpensePayouts calls fn for every claim of a payout batch, by
+// employee name.
+func (x *ExportRepository) StreamExpensePayouts(
+	ctx context.Context,
+	companyID, batchID uuid.UUID,
+	fn func(row *models.ExpensePayoutExportRow) error,
+) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, exportTimeout)
+		defer cancel()
+	}
+
+	rows, err := x.pool.Query(ctx, `
+		SELECT
+			b.reference, c.id, COALESCE(e.employee_code, ''), e.first_name || ' ' || e.last_name, e.email,
+			c.title, c.currency, c.total_amount, c.approved_at, c.reimbursed_at
+		FROM expense_claims c
+		JOIN expense_payout_batches b ON b.id = c.payout_batch_id
+		JOIN employees e ON e.id = c.employee_id
+		WHERE b.id = $1 AND b.company_id = $2
+		ORDER BY e.last_name, e.first_name, c.created_at
+	`, batchID, companyID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.ExpensePayoutExportRow
+		if err := rows.Scan(
+			&row.BatchReference, &row.ClaimID, &row.EmployeeCode, &row.EmployeeName, &row.Email,
+			&row.Title, &row.Currency, &row.Amount, &row.ApprovedAt, &row.ReimbursedAt,
+		); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
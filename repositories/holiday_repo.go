@@ -0,0 +1,434 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type HolidayRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewHolidayRepository(pool *pgxpool.Pool) *HolidayRepository {
+	return &HolidayRepository{
+		pool: pool,
+	}
+}
+
+const holidayCalendarColumns = `
+	hc.id, hc.company_id, hc.name, COALESCE(hc.country, ''), hc.work_days, hc.is_default,
+	ARRAY(SELECT d.id FROM departments d WHERE d.holiday_calendar_id = hc.id ORDER BY d.name),
+	hc.created_at, hc.updated_at`
+
+func scanHolidayCalendar(row pgx.Row, c *models.HolidayCalendar) error {
+	return row.Scan(
+		&c.ID, &c.CompanyID, &c.Name, &c.Country, &c.WorkDays, &c.IsDefault,
+		&c.DepartmentIDs, &c.CreatedAt, &c.UpdatedAt,
+	)
+}
+
+const holidayColumns = `
+	id, company_id, calendar_id, holiday_date, name, recurring, created_at, updated_at`
+
+func scanHoliday(row pgx.Row, h *models.CompanyHoliday) error {
+	return row.Scan(
+		&h.ID, &h.CompanyID, &h.CalendarID, &h.HolidayDate, &h.Name, &h.Recurring,
+		&h.CreatedAt, &h.UpdatedAt,
+	)
+}
+
+// CreateCalendar inserts a calendar and assigns it to departmentIDs. Making
+// it the default takes the flag from the company's previous default.
+func (h *HolidayRepository) CreateCalendar(ctx context.Context, calendar *models.HolidayCalendar) (*models.HolidayCalendar, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if calendar.IsDefault {
+		if err := clearDefaultCalendar(ctx, tx, calendar.CompanyID); err != nil {
+			return nil, err
+		}
+	}
+
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO holiday_calendars (company_id, name, country, work_days, is_default)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING id
+	`, calendar.CompanyID, calendar.Name, calendar.Country, calendar.WorkDays, calendar.IsDefault).Scan(&id); err != nil {
+		return nil, err
+	}
+
+	if err := assignCalendarDepartments(ctx, tx, id, calendar.DepartmentIDs); err != nil {
+		return nil, err
+	}
+
+	created, err := getCalendar(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// UpdateCalendar overwrites the calendar's editable columns and replaces its
+// department assignments.
+func (h *HolidayRepository) UpdateCalendar(ctx context.Context, calendar *models.HolidayCalendar) (*models.HolidayCalendar, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if calendar.IsDefault {
+		if err := clearDefaultCalendar(ctx, tx, calendar.CompanyID); err != nil {
+			return nil, err
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE holiday_calendars
+		SET name = $1, country = NULLIF($2, ''), work_days = $3, is_default = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5
+	`, calendar.Name, calendar.Country, calendar.WorkDays, calendar.IsDefault, calendar.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, errors.New("holiday calendar not found")
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE departments SET holiday_calendar_id = NULL WHERE holiday_calendar_id = $1",
+		calendar.ID,
+	); err != nil {
+		return nil, err
+	}
+	if err := assignCalendarDepartments(ctx, tx, calendar.ID, calendar.DepartmentIDs); err != nil {
+		return nil, err
+	}
+
+	updated, err := getCalendar(ctx, tx, calendar.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func clearDefaultCalendar(ctx context.Context, tx pgx.Tx, companyID uuid.UUID) error {
+	_, err := tx.Exec(ctx,
+		"UPDATE holiday_calendars SET is_default = false, updated_at = CURRENT_TIMESTAMP WHERE company_id = $1 AND is_default",
+		companyID,
+	)
+	return err
+}
+
+func assignCalendarDepartments(ctx context.Context, tx pgx.Tx, calendarID uuid.UUID, departmentIDs []uuid.UUID) error {
+	if len(departmentIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx,
+		"UPDATE departments SET holiday_calendar_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = ANY($2)",
+		calendarID, departmentIDs,
+	)
+	return err
+}
+
+func getCalendar(ctx context.Context, db dbExecutor, calendarID uuid.UUID) (*models.HolidayCalendar, error) {
+	var calendar models.HolidayCalendar
+	if err := scanHolidayCalendar(db.QueryRow(ctx,
+		"SELECT "+holidayCalendarColumns+" FROM holiday_calendars hc WHERE hc.id = $1",
+		calendarID,
+	), &calendar); err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (h *HolidayRepository) GetCalendarByID(ctx context.Context, calendarID uuid.UUID) (*models.HolidayCalendar, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return getCalendar(ctx, h.pool, calendarID)
+}
+
+// ListCalendars returns a company's calendars, default first.
+func (h *HolidayRepository) ListCalendars(ctx context.Context, companyID uuid.UUID) ([]*models.HolidayCalendar, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := h.pool.Query(ctx, `
+		SELECT `+holidayCalendarColumns+`
+		FROM holiday_calendars hc
+		WHERE hc.company_id = $1
+		ORDER BY hc.is_default DESC, hc.name
+	`, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var calendars []*models.HolidayCalendar
+	for rows.Next() {
+		var calendar models.HolidayCalendar
+		if err := scanHolidayCalendar(rows, &calendar); err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, &calendar)
+	}
+
+	return calendars, rows.Err()
+}
+
+func (h *HolidayRepository) DeleteCalendar(ctx context.Context, calendarID uuid.UUID) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tag, err := h.pool.Exec(ctx, "DELETE FROM holiday_calendars WHERE id = $1", calendarID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("holiday calendar not found")
+	}
+	return nil
+}
+
+// GetEmployeeCalendar returns the calendar that applies to an employee: the
+// one assigned to the nearest department up their department tree, or else
+// the company default. It returns pgx.ErrNoRows when the company has no
+// calendar at all.
+func (h *HolidayRepository) GetEmployeeCalendar(ctx context.Context, employeeID uuid.UUID) (*models.HolidayCalendar, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var companyID uuid.UUID
+	var departmentID *uuid.UUID
+	if err := h.pool.QueryRow(ctx,
+		"SELECT company_id, department_id FROM employees WHERE id = $1",
+		employeeID,
+	).Scan(&companyID, &departmentID); err != nil {
+		return nil, err
+	}
+
+	return resolveCalendar(ctx, h.pool, companyID, departmentID)
+}
+
+// GetDepartmentCalendar returns the calendar that applies to the members of
+// a department, resolved the same way as for an employee.
+func (h *HolidayRepository) GetDepartmentCalendar(ctx context.Context, companyID, departmentID uuid.UUID) (*models.HolidayCalendar, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return resolveCalendar(ctx, h.pool, companyID, &departmentID)
+}
+
+// resolveCalendar walks up from departmentID to the nearest department with
+// an assigned calendar, falling back to the company default.
+func resolveCalendar(ctx context.Context, db dbExecutor, companyID uuid.UUID, departmentID *uuid.UUID) (*models.HolidayCalendar, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_department_id, holiday_calendar_id, 0 AS depth
+			FROM departments
+			WHERE id = $2
+			UNION ALL
+			SELECT d.id, d.parent_department_id, d.holiday_calendar_id, c.depth + 1
+			FROM departments d
+			JOIN chain c ON d.id = c.parent_department_id
+			WHERE c.depth < 50
+		), assigned AS (
+			SELECT holiday_calendar_id AS id
+			FROM chain
+			WHERE holiday_calendar_id IS NOT NULL
+			ORDER BY depth
+			LIMIT 1
+		)
+		SELECT ` + holidayCalendarColumns + `
+		FROM holiday_calendars hc
+		WHERE hc.company_id = $1
+			AND (hc.id IN (SELECT id FROM assigned) OR hc.is_default)
+		ORDER BY hc.id IN (SELECT id FROM assigned) DESC
+		LIMIT 1
+	`
+
+	var calendar models.HolidayCalendar
+	if err := scanHolidayCalendar(db.QueryRow(ctx, query, companyID, departmentID), &calendar); err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (h *HolidayRepository) CreateHoliday(ctx context.Context, holiday *models.CompanyHoliday) (*models.CompanyHoliday, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var created models.CompanyHoliday
+	if err := scanHoliday(h.pool.QueryRow(ctx, `
+		INSERT INTO company_holidays (company_id, calendar_id, holiday_date, name, recurring)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+holidayColumns,
+		holiday.CompanyID, holiday.CalendarID, holiday.HolidayDate, holiday.Name, holiday.Recurring,
+	), &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// ImportHolidays inserts holidays into a calendar in one transaction,
+// skipping any already present with the same date and name. It returns the
+// holidays actually inserted.
+func (h *HolidayRepository) ImportHolidays(ctx context.Context, holidays []*models.CompanyHoliday) ([]*models.CompanyHoliday, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var inserted []*models.CompanyHoliday
+	for _, holiday := range holidays {
+		var created models.CompanyHoliday
+		err := scanHoliday(tx.QueryRow(ctx, `
+			INSERT INTO company_holidays (company_id, calendar_id, holiday_date, name, recurring)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (calendar_id, holiday_date, name) DO NOTHING
+			RETURNING `+holidayColumns,
+			holiday.CompanyID, holiday.CalendarID, holiday.HolidayDate, holiday.Name, holiday.Recurring,
+		), &created)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		inserted = append(inserted, &created)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
+
+func (h *HolidayRepository) GetHolidayByID(ctx context.Context, holidayID uuid.UUID) (*models.CompanyHoliday, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var holiday models.CompanyHoliday
+	if err := scanHoliday(h.pool.QueryRow(ctx,
+		"SELECT "+holidayColumns+" FROM company_holidays WHERE id = $1",
+		holidayID,
+	), &holiday); err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+func (h *HolidayRepository) DeleteHoliday(ctx context.Context, holidayID uuid.UUID) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tag, err := h.pool.Exec(ctx, "DELETE FROM company_holidays WHERE id = $1", holidayID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("holiday not found")
+	}
+	return nil
+}
+
+// ListHolidays returns a calendar's holidays that can fall between from and
+// to: one-off holidays in the range and recurring holidays that started on
+// or before to. Callers expand the recurring ones.
+func (h *HolidayRepository) ListHolidays(ctx context.Context, calendarID uuid.UUID, from, to time.Time) ([]*models.CompanyHoliday, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := h.pool.Query(ctx, `
+		SELECT `+holidayColumns+`
+		FROM company_holidays
+		WHERE calendar_id = $1
+			AND ((NOT recurring AND holiday_date BETWEEN $2 AND $3) OR (recurring AND holiday_date <= $3))
+		ORDER BY holiday_date, name
+	`, calendarID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holidays []*models.CompanyHoliday
+	for rows.Next() {
+		var holiday models.CompanyHoliday
+		if err := scanHoliday(rows, &holiday); err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, &holiday)
+	}
+
+	return holidays, rows.Err()
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type LeaveAccrualRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLeaveAccrualRepository(pool *pgxpool.Pool) *LeaveAccrualRepository {
+	return &LeaveAccrualRepository{
+		pool: pool,
+	}
+}
+
+// ListActiveLeaveTypes returns the active leave types of every company,
+// grouped by company.
+func (l *LeaveAccrualRepository) ListActiveLeaveTypes(ctx context.Context) ([]*models.LeaveType, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := l.pool.Query(ctx, `
+		SELECT `+leaveTypeColumns+`
+		FROM leave_types
+		WHERE status = 'active'
+		ORDER BY company_id, name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leaveTypes []*models.LeaveType
+	for rows.Next() {
+		var lt models.LeaveType
+		if err := scanLeaveType(rows, &lt); err != nil {
+			return nil, err
+		}
+		leaveTypes = append(leaveTypes, &lt)
+	}
+
+	return leaveTypes, rows.Err()
+}
+
+// ListAccrualEmployees returns the employees of a company who are employed
+// at some point during year.
+func (l *LeaveAccrualRepository) ListAccrualEmployees(ctx context.Context, companyID uuid.UUID, year int) ([]*models.Employee, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	rows, err := l.pool.Query(ctx, `
+		SELECT `+employeeColumns+`
+		FROM employees e
+		WHERE e.company_id = $1
+			AND e.hire_date <= $3
+			AND (e.termination_date IS NULL OR e.termination_date >= $2)
+			AND (e.status <> 'terminated' OR e.termination_date IS NOT NULL)
+	`, companyID, yearStart, yearEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var employees []*models.Employee
+	for rows.Next() {
+		var employee models.Employee
+		if err := scanEmployee(rows, &employee); err != nil {
+			return nil, err
+		}
+		employees = append(employees, &employee)
+	}
+
+	return employees, rows.Err()
+}
+
+// ListCompanyLeaveBalances returns every balance of a company's employees
+// for year.
+func (l *LeaveAccrualRepository) ListCompanyLeaveBalances(ctx context.Context, companyID uuid.UUID, year int) ([]*models.LeaveBalance, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := l.pool.Query(ctx, `
+		SELECT `+leaveBalanceColumns+`
+		FROM leave_balances
+		WHERE employee_id IN (SELECT id FROM employees WHERE company_id = $1) AND year = $2
+	`, companyID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []*models.LeaveBalance
+	for rows.Next() {
+		var balance models.LeaveBalance
+		if err := scanLeaveBalance(rows, &balance); err != nil {
+			return nil, err
+		}
+		balances = append(balances, &balance)
+	}
+
+	return balances, rows.Err()
+}
+
+// ApplyLeaveAccruals brings each balance up to date in one transaction and
+// returns how many balances changed. Missing balances are opened with the
+// accrued days; existing ones are credited only the accrual they have not
+// received yet and get their carry-forward replaced, so applying the same
+// accruals twice changes nothing.
+func (l *LeaveAccrualRepository) ApplyLeaveAccruals(ctx context.Context, accruals []*models.LeaveAccrual) (int, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	changed := 0
+	for _, a := range accruals {
+		balance, err := lockLeaveBalance(ctx, tx, a.EmployeeID, a.LeaveTypeID, a.Year, 0)
+		if err != nil {
+			return 0, err
+		}
+
+		// Only the accrual beyond what was already credited is added, so
+		// manual adjustments to total_days survive reruns.
+		accrued := max(a.AccruedDays-balance.AccruedDays, 0)
+		carried := a.CarriedForwardDays - balance.CarriedForwardDays
+		if accrued == 0 && carried == 0 {
+			continue
+		}
+
+		if accrued > 0 {
+			if _, err := tx.Exec(ctx,
+				"UPDATE leave_balances SET accrued_days = $2 WHERE id = $1",
+				balance.ID, a.AccruedDays,
+			); err != nil {
+				return 0, err
+			}
+			if balance, err = changeLeaveBalance(ctx, tx, balance, &models.LeaveLedgerEntry{
+				EntryType:  "accrual",
+				TotalDelta: accrued,
+				Reason:     fmt.Sprintf("accrued %.1f days for %d", a.AccruedDays, a.Year),
+			}); err != nil {
+				return 0, err
+			}
+		}
+		if carried != 0 {
+			if _, err := changeLeaveBalance(ctx, tx, balance, &models.LeaveLedgerEntry{
+				EntryType:           "carry_forward",
+				CarriedForwardDelta: carried,
+				Reason:              fmt.Sprintf("carried forward from %d", a.Year-1),
+			}); err != nil {
+				return 0, err
+			}
+		}
+		changed++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
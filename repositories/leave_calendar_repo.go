@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type LeaveCalendarRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLeaveCalendarRepository(pool *pgxpool.Pool) *LeaveCalendarRepository {
+	return &LeaveCalendarRepository{
+		pool: pool,
+	}
+}
+
+// ListDepartmentMembers returns the non-terminated members of a department,
+// and of its sub-departments when includeSubDepartments is set.
+func (l *LeaveCalendarRepository) ListDepartmentMembers(ctx context.Context, departmentID uuid.UUID, includeSubDepartments bool) ([]*models.Employee, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, 0 AS depth FROM departments WHERE id = $1
+			UNION ALL
+			SELECT d.id, t.depth + 1
+			FROM departments d
+			JOIN tree t ON d.parent_department_id = t.id
+			WHERE $2 AND t.depth < 50
+		)
+		SELECT ` + employeeColumns + `
+		FROM employees e
+		WHERE e.department_id IN (SELECT id FROM tree) AND e.status <> 'terminated'
+		ORDER BY e.last_name, e.first_name
+	`
+
+	rows, err := l.pool.Query(ctx, query, departmentID, includeSubDepartments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var employees []*models.Employee
+	for rows.Next() {
+		var employee models.Employee
+		if err := scanEmployee(rows, &employee); err != nil {
+			return nil, err
+		}
+		employees = append(employees, &employee)
+	}
+
+	return employees, rows.Err()
+}
+
+// ListTeamLeave returns the leave requests in statuses of the given
+// employees that overlap from..to, in start date order.
+func (l *LeaveCalendarRepository) ListTeamLeave(ctx context.Context, employeeIDs []uuid.UUID, statuses []string, from, to time.Time) ([]*models.TeamLeaveEntry, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT lr.id, lr.employee_id, e.first_name || ' ' || e.last_name, lr.leave_type_id,
+			lt.name, lt.color_code, lr.start_date, lr.end_date, lr.start_half_day, lr.end_half_day,
+			lr.days_requested, lr.status, lr.updated_at
+		FROM leave_requests lr
+		JOIN employees e ON e.id = lr.employee_id
+		JOIN leave_types lt ON lt.id = lr.leave_type_id
+		WHERE lr.employee_id = ANY($1) AND lr.status = ANY($2)
+			AND lr.start_date <= $4 AND lr.end_date >= $3
+		ORDER BY lr.start_date, e.last_name, e.first_name
+	`
+
+	rows, err := l.pool.Query(ctx, query, employeeIDs, statuses, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.TeamLeaveEntry
+	for rows.Next() {
+		var entry models.TeamLeaveEntry
+		if err := rows.Scan(
+			&entry.RequestID, &entry.EmployeeID, &entry.EmployeeName, &entry.LeaveTypeID,
+			&entry.LeaveTypeName, &entry.ColorCode, &entry.StartDate, &entry.EndDate,
+			&entry.StartHalfDay, &entry.EndHalfDay, &entry.DaysRequested, &entry.Status, &entry.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// SetCalendarFeedToken stores the hash of an employee's feed token,
+// replacing (and so revoking) any previous one.
+func (l *LeaveCalendarRepository) SetCalendarFeedToken(ctx context.Context, employeeID uuid.UUID, tokenHash string) (time.Time, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var createdAt time.Time
+	err := l.pool.QueryRow(ctx, `
+		INSERT INTO calendar_feed_tokens (employee_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (employee_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash, created_at = CURRENT_TIMESTAMP, last_accessed_at = NULL
+		RETURNING created_at
+	`, employeeID, tokenHash).Scan(&createdAt)
+	return createdAt, err
+}
+
+// DeleteCalendarFeedToken revokes an employee's feed token. It returns
+// pgx.ErrNoRows when they have none.
+func (l *LeaveCalendarRepository) DeleteCalendarFeedToken(ctx context.Context, employeeID uuid.UUID) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tag, err := l.pool.Exec(ctx, "DELETE FROM calendar_feed_tokens WHERE employee_id = $1", employeeID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetCalendarFeedEmployee returns the non-terminated employee owning the feed
+// token with tokenHash and records the access.
+func (l *LeaveCalendarRepository) GetCalendarFeedEmployee(ctx context.Context, tokenHash string) (*models.Employee, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		WITH feed AS (
+			UPDATE calendar_feed_tokens
+			SET last_accessed_at = CURRENT_TIMESTAMP
+			WHERE token_hash = $1
+			RETURNING employee_id
+		)
+		SELECT ` + employeeColumns + `
+		FROM employees e
+		JOIN feed f ON f.employee_id = e.id
+		WHERE e.status <> 'terminated'
+	`
+
+	var employee models.Employee
+	if err := scanEmployee(l.pool.QueryRow(ctx, query, tokenHash), &employee); err != nil {
+		return nil, err
+	}
+	return &employee, nil
+}
@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// ErrNegativeLeaveBalance is returned when an adjustment would leave fewer
+// days than are already used or reserved.
+var ErrNegativeLeaveBalance = errors.New("adjustment would leave a negative available balance")
+
+type LeaveLedgerRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLeaveLedgerRepository(pool *pgxpool.Pool) *LeaveLedgerRepository {
+	return &LeaveLedgerRepository{
+		pool: pool,
+	}
+}
+
+const leaveLedgerColumns = `
+	id, balance_id, entry_type, total_delta, used_delta, pending_delta, carried_forward_delta,
+	leave_request_id, actor_id, COALESCE(reason, ''), created_at`
+
+func scanLeaveLedgerEntry(row pgx.Row, e *models.LeaveLedgerEntry) error {
+	return row.Scan(
+		&e.ID, &e.BalanceID, &e.EntryType, &e.TotalDelta, &e.UsedDelta, &e.PendingDelta, &e.CarriedForwardDelta,
+		&e.LeaveRequestID, &e.ActorID, &e.Reason, &e.CreatedAt,
+	)
+}
+
+// changeLeaveBalance adds the deltas of entry to the balance before, which
+// the caller must have locked, and records entry with the change actually
+// made: used and pending days never go below zero. Nothing is recorded when
+// nothing changed.
+func changeLeaveBalance(ctx context.Context, tx pgx.Tx, before *models.LeaveBalance, entry *models.LeaveLedgerEntry) (*models.LeaveBalance, error) {
+	var after models.LeaveBalance
+	if err := scanLeaveBalance(tx.QueryRow(ctx, `
+		UPDATE leave_balances
+		SET total_days = total_days + $2,
+			used_days = GREATEST(used_days + $3, 0),
+			pending_days = GREATEST(pending_days + $4, 0),
+			carried_forward_days = carried_forward_days + $5,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+leaveBalanceColumns,
+		before.ID, entry.TotalDelta, entry.UsedDelta, entry.PendingDelta, entry.CarriedForwardDelta,
+	), &after); err != nil {
+		return nil, err
+	}
+
+	entry.BalanceID = before.ID
+	entry.TotalDelta = after.TotalDays - before.TotalDays
+	entry.UsedDelta = after.UsedDays - before.UsedDays
+	entry.PendingDelta = after.PendingDays - before.PendingDays
+	entry.CarriedForwardDelta = after.CarriedForwardDays - before.CarriedForwardDays
+	if err := insertLeaveLedgerEntry(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// insertLeaveLedgerEntry appends entry to the ledger unless all its deltas
+// are zero.
+func insertLeaveLedgerEntry(ctx context.Context, db dbExecutor, e *models.LeaveLedgerEntry) error {
+	if e.TotalDelta == 0 && e.UsedDelta == 0 && e.PendingDelta == 0 && e.CarriedForwardDelta == 0 {
+		return nil
+	}
+	return db.QueryRow(ctx, `
+		INSERT INTO leave_ledger_entries (
+			balance_id, entry_type, total_delta, used_delta, pending_delta, carried_forward_delta,
+			leave_request_id, actor_id, reason
+		)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,NULLIF($9, ''))
+		RETURNING id, created_at
+	`, e.BalanceID, e.EntryType, e.TotalDelta, e.UsedDelta, e.PendingDelta, e.CarriedForwardDelta,
+		e.LeaveRequestID, e.ActorID, e.Reason,
+	).Scan(&e.ID, &e.CreatedAt)
+}
+
+// AdjustLeaveBalance adds days (which may be negative) to a balance's total
+// as a manual adjustment, opening the balance with openingDays accrued if
+// it does not exist yet.
+func (l *LeaveLedgerRepository) AdjustLeaveBalance(
+	ctx context.Context,
+	employeeID, leaveTypeID uuid.UUID,
+	year int,
+	openingDays float64,
+	entry *models.LeaveLedgerEntry,
+) (*models.LeaveBalance, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	balance, err := lockLeaveBalance(ctx, tx, employeeID, leaveTypeID, year, openingDays)
+	if err != nil {
+		return nil, err
+	}
+	available := balance.TotalDays + balance.CarriedForwardDays - balance.UsedDays - balance.PendingDays
+	if available+entry.TotalDelta < 0 {
+		return nil, ErrNegativeLeaveBalance
+	}
+
+	entry.EntryType = "adjustment"
+	if balance, err = changeLeaveBalance(ctx, tx, balance, entry); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
+
+// ListLedgerEntries returns the entries of an employee's balances for a
+// year, oldest first.
+func (l *LeaveLedgerRepository) ListLedgerEntries(ctx context.Context, employeeID uuid.UUID, year int) ([]*models.LeaveLedgerEntry, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT ` + leaveLedgerColumns + `
+		FROM leave_ledger_entries
+		WHERE balance_id IN (SELECT id FROM leave_balances WHERE employee_id = $1 AND year = $2)
+		ORDER BY created_at, id
+	`
+
+	rows, err := l.pool.Query(ctx, query, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.LeaveLedgerEntry
+	for rows.Next() {
+		var entry models.LeaveLedgerEntry
+		if err := scanLeaveLedgerEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// ListDiscrepancies recomputes every balance of a company for a year from
+// its ledger and returns those whose stored totals differ.
+func (l *LeaveLedgerRepository) ListDiscrepancies(ctx context.Context, companyID uuid.UUID, year int) ([]*models.LeaveBalanceDiscrepancy, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT ` + leaveBalanceColumns + `, ledger_total, ledger_used, ledger_pending, ledger_carried_forward
+		FROM (
+			SELECT b.*,
+				COALESCE(SUM(l.total_delta), 0) AS ledger_total,
+				COALESCE(SUM(l.used_delta), 0) AS ledger_used,
+				COALESCE(SUM(l.pending_delta), 0) AS ledger_pending,
+				COALESCE(SUM(l.carried_forward_delta), 0) AS ledger_carried_forward
+			FROM leave_balances b
+			JOIN employees e ON e.id = b.employee_id
+			LEFT JOIN leave_ledger_entries l ON l.balance_id = b.id
+			WHERE e.company_id = $1 AND b.year = $2
+			GROUP BY b.id
+		) recomputed
+		WHERE total_days <> ledger_total
+			OR used_days <> ledger_used
+			OR pending_days <> ledger_pending
+			OR carried_forward_days <> ledger_carried_forward
+		ORDER BY employee_id, leave_type_id
+	`
+
+	rows, err := l.pool.Query(ctx, query, companyID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var discrepancies []*models.LeaveBalanceDiscrepancy
+	for rows.Next() {
+		var d models.LeaveBalanceDiscrepancy
+		if err := scanLeaveBalance(rows, &d.Balance, &d.LedgerTotal, &d.LedgerUsed, &d.LedgerPending, &d.LedgerCarriedForward); err != nil {
+			return nil, err
+		}
+		discrepancies = append(discrepancies, &d)
+	}
+
+	return discrepancies, rows.Err()
+}
@@ -0,0 +1,816 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+var (
+	// ErrLeaveOverlap is returned when a request overlaps one of the
+	// employee's pending, returned or approved requests.
+	ErrLeaveOverlap = errors.New("leave request overlaps another pending or approved request")
+
+	// ErrInsufficientLeaveBalance is returned when the balance cannot cover
+	// the requested days.
+	ErrInsufficientLeaveBalance = errors.New("insufficient leave balance")
+
+	// ErrLeaveRequestStatusChanged is returned when a request is no longer in
+	// the status a transition expected, typically because a concurrent
+	// action got there first.
+	ErrLeaveRequestStatusChanged = errors.New("leave request status has changed")
+)
+
+type LeaveRequestRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLeaveRequestRepository(pool *pgxpool.Pool) *LeaveRequestRepository {
+	return &LeaveRequestRepository{
+		pool: pool,
+	}
+}
+
+const leaveRequestColumns = `
+	id, employee_id, leave_type_id, start_date, end_date, start_half_day, end_half_day,
+	days_requested, COALESCE(reason, ''), COALESCE(attachment_url, ''), documentation_status,
+	documentation_due_date, status, current_step, approved_by, approved_at,
+	COALESCE(rejection_reason, ''), created_at, updated_at`
+
+func scanLeaveRequest(row pgx.Row, r *models.LeaveRequest, extra ...any) error {
+	dest := []any{
+		&r.ID, &r.EmployeeID, &r.LeaveTypeID, &r.StartDate, &r.EndDate, &r.StartHalfDay, &r.EndHalfDay,
+		&r.DaysRequested, &r.Reason, &r.AttachmentURL, &r.DocumentationStatus,
+		&r.DocumentationDueDate, &r.Status, &r.CurrentStep, &r.ApprovedBy,
+		&r.ApprovedAt, &r.RejectionReason, &r.CreatedAt, &r.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+const leaveBalanceColumns = `
+	id, employee_id, leave_type_id, year, total_days, used_days, pending_days,
+	carried_forward_days, accrued_days, created_at, updated_at`
+
+func scanLeaveBalance(row pgx.Row, b *models.LeaveBalance, extra ...any) error {
+	dest := []any{
+		&b.ID, &b.EmployeeID, &b.LeaveTypeID, &b.Year, &b.TotalDays, &b.UsedDays, &b.PendingDays,
+		&b.CarriedForwardDays, &b.AccruedDays, &b.CreatedAt, &b.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// SubmitLeaveRequest stores a pending request, reserves its days against
+// the balance for its year and starts its approval request, with revision
+// when not nil, in one transaction. The employee row is locked so
+// concurrent submissions cannot both pass the overlap and balance checks.
+// A balance that does not exist yet is opened with openingDays accrued.
+//
+// On ErrInsufficientLeaveBalance the current balance is returned alongside
+// the error.
+func (l *LeaveRequestRepository) SubmitLeaveRequest(
+	ctx context.Context,
+	request *models.LeaveRequest,
+	openingDays float64,
+	approval *models.ApprovalRequest,
+	revision *models.ApprovalRevision,
+) (*models.LeaveRequest, *models.LeaveBalance, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT 1 FROM employees WHERE id = $1 FOR UPDATE", request.EmployeeID); err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkLeaveOverlap(ctx, tx, request); err != nil {
+		return nil, nil, err
+	}
+
+	balance, err := lockLeaveBalance(ctx, tx, request.EmployeeID, request.LeaveTypeID, request.StartDate.Year(), openingDays)
+	if err != nil {
+		return nil, nil, err
+	}
+	available := balance.TotalDays + balance.CarriedForwardDays - balance.UsedDays - balance.PendingDays
+	if available < request.DaysRequested {
+		return nil, balance, ErrInsufficientLeaveBalance
+	}
+
+	var created models.LeaveRequest
+	err = scanLeaveRequest(tx.QueryRow(ctx, `
+		INSERT INTO leave_requests (
+			employee_id, leave_type_id, start_date, end_date, start_half_day, end_half_day,
+			days_requested, reason, attachment_url, documentation_status, documentation_due_date, status
+		)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,NULLIF($8, ''),NULLIF($9, ''),$10,$11,'pending')
+		RETURNING `+leaveRequestColumns,
+		request.EmployeeID, request.LeaveTypeID, request.StartDate, request.EndDate,
+		request.StartHalfDay, request.EndHalfDay, request.DaysRequested, request.Reason, request.AttachmentURL,
+		request.DocumentationStatus, request.DocumentationDueDate,
+	), &created)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	balance, err = changeLeaveBalance(ctx, tx, balance, &models.LeaveLedgerEntry{
+		EntryType:      "reservation",
+		PendingDelta:   created.DaysRequested,
+		LeaveRequestID: &created.ID,
+		ActorID:        &created.EmployeeID,
+		Reason:         "leave requested",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	approval.EntityID = created.ID
+	if _, err := insertApprovalRequest(ctx, tx, approval); err != nil {
+		return nil, nil, err
+	}
+	if revision != nil {
+		revision.EntityID = created.ID
+		if _, err := insertApprovalRevision(ctx, tx, revision); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return &created, balance, nil
+}
+
+// checkLeaveOverlap returns ErrLeaveOverlap when request overlaps another of
+// the employee's live requests: pending, awaiting changes or approved.
+func checkLeaveOverlap(ctx context.Context, tx pgx.Tx, request *models.LeaveRequest) error {
+	var overlaps bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM leave_requests
+			WHERE employee_id = $1 AND id <> $4 AND status IN ('pending', 'changes_requested', 'approved')
+				AND start_date <= $3 AND end_date >= $2
+		)
+	`, request.EmployeeID, request.StartDate, request.EndDate, request.ID).Scan(&overlaps); err != nil {
+		return err
+	}
+	if overlaps {
+		return ErrLeaveOverlap
+	}
+	return nil
+}
+
+// TransitionLeaveRequest moves a request from fromStatus to the status named
+// by history.Action, adjusts the balance and records history in one
+// transaction. The request row is locked first, so of two concurrent
+// actions only one sees the expected status; the other gets
+// ErrLeaveRequestStatusChanged.
+//
+// Approving moves the days from pending to used. Rejecting, withdrawing or
+// cancelling a request that is pending or awaiting changes releases its
+// pending days, and cancelling or revoking an approved request gives back
+// its used days.
+func (l *LeaveRequestRepository) TransitionLeaveRequest(
+	ctx context.Context,
+	requestID uuid.UUID,
+	fromStatus string,
+	history *models.ApprovalHistory,
+) (*models.LeaveRequest, *models.LeaveBalance, error) {
+	return l.transition(ctx, requestID, fromStatus, 0, history)
+}
+
+// FinalizeLeaveRequest applies the final approval or rejection of a request
+// pending at step, as TransitionLeaveRequest does. It returns
+// ErrLeaveRequestStatusChanged when the request is no longer pending at
+// step.
+func (l *LeaveRequestRepository) FinalizeLeaveRequest(
+	ctx context.Context,
+	requestID uuid.UUID,
+	step int,
+	history *models.ApprovalHistory,
+) (*models.LeaveRequest, *models.LeaveBalance, error) {
+	return l.transition(ctx, requestID, "pending", step, history)
+}
+
+// transition moves a request in fromStatus, and at step unless step is 0,
+// to the status named by history.Action.
+func (l *LeaveRequestRepository) transition(
+	ctx context.Context,
+	requestID uuid.UUID,
+	fromStatus string,
+	step int,
+	history *models.ApprovalHistory,
+) (*models.LeaveRequest, *models.LeaveBalance, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current models.LeaveRequest
+	if err := scanLeaveRequest(tx.QueryRow(ctx,
+		"SELECT "+leaveRequestColumns+" FROM leave_requests WHERE id = $1 FOR UPDATE",
+		requestID,
+	), &current); err != nil {
+		return nil, nil, err
+	}
+	if current.Status != fromStatus || (step != 0 && current.CurrentStep != step) {
+		return nil, nil, ErrLeaveRequestStatusChanged
+	}
+
+	updated, balance, err := transitionLeaveRequest(ctx, tx, &current, history)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return updated, balance, nil
+}
+
+// transitionLeaveRequest is TransitionLeaveRequest within tx for current,
+// a request already locked and checked to be in the expected status.
+func transitionLeaveRequest(
+	ctx context.Context,
+	tx pgx.Tx,
+	current *models.LeaveRequest,
+	history *models.ApprovalHistory,
+) (*models.LeaveRequest, *models.LeaveBalance, error) {
+	fromStatus := current.Status
+	toStatus := history.Action
+	entry := &models.LeaveLedgerEntry{
+		EntryType:      "release",
+		LeaveRequestID: &current.ID,
+		ActorID:        history.ApproverID,
+		Reason:         "leave " + toStatus,
+	}
+	switch {
+	case fromStatus == "pending" && toStatus == "approved":
+		entry.EntryType = "consumption"
+		entry.PendingDelta, entry.UsedDelta = -current.DaysRequested, current.DaysRequested
+	case fromStatus == "pending", fromStatus == "changes_requested":
+		entry.PendingDelta = -current.DaysRequested
+	case fromStatus == "approved":
+		entry.UsedDelta = -current.DaysRequested
+	}
+
+	var updated models.LeaveRequest
+	if err := scanLeaveRequest(tx.QueryRow(ctx, `
+		UPDATE leave_requests
+		SET status = $2,
+			approved_by = CASE WHEN $2 = 'approved' THEN $3 ELSE approved_by END,
+			approved_at = CASE WHEN $2 = 'approved' THEN CURRENT_TIMESTAMP ELSE approved_at END,
+			rejection_reason = CASE WHEN $2 = 'rejected' THEN NULLIF($4, '') ELSE rejection_reason END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+leaveRequestColumns,
+		current.ID, toStatus, history.ApproverID, history.Comments,
+	), &updated); err != nil {
+		return nil, nil, err
+	}
+
+	// changeLeaveBalance keeps requests that predate balance reservation
+	// from driving the totals negative.
+	balance, err := lockLeaveBalance(ctx, tx, current.EmployeeID, current.LeaveTypeID, current.StartDate.Year(), 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if balance, err = changeLeaveBalance(ctx, tx, balance, entry); err != nil {
+		return nil, nil, err
+	}
+
+	history.EntityType = "leave_request"
+	history.EntityID = current.ID
+	history.StepNumber = current.CurrentStep
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, nil, err
+	}
+	if err := closeApprovalRequest(ctx, tx, "leave_request", current.ID, toStatus); err != nil {
+		return nil, nil, err
+	}
+
+	return &updated, balance, nil
+}
+
+// AdvanceLeaveRequest records an intermediate approval: the pending request
+// moves from fromStep to toStep of its workflow along with its approval
+// request, and history is recorded against fromStep. It returns
+// ErrLeaveRequestStatusChanged when the request is no longer pending at
+// fromStep.
+func (l *LeaveRequestRepository) AdvanceLeaveRequest(
+	ctx context.Context,
+	requestID uuid.UUID,
+	fromStep, toStep int,
+	history *models.ApprovalHistory,
+) (*models.LeaveRequest, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.LeaveRequest
+	err = scanLeaveRequest(tx.QueryRow(ctx, `
+		UPDATE leave_requests
+		SET current_step = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending' AND current_step = $2
+		RETURNING `+leaveRequestColumns,
+		requestID, fromStep, toStep,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLeaveRequestStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = advanceApprovalRequest(ctx, tx, "leave_request", requestID, fromStep, toStep)
+	if errors.Is(err, ErrApprovalRequestStatusChanged) {
+		return nil, ErrLeaveRequestStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "leave_request"
+	history.EntityID = requestID
+	history.StepNumber = fromStep
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// ReturnLeaveRequest sends a pending request at step back to its requester
+// for changes, along with its approval request, and records history against
+// step. The request keeps its reserved days while it is away. It returns
+// ErrLeaveRequestStatusChanged when the request is no longer pending at step.
+func (l *LeaveRequestRepository) ReturnLeaveRequest(
+	ctx context.Context,
+	requestID uuid.UUID,
+	step int,
+	history *models.ApprovalHistory,
+) (*models.LeaveRequest, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.LeaveRequest
+	err = scanLeaveRequest(tx.QueryRow(ctx, `
+		UPDATE leave_requests
+		SET status = 'changes_requested', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending' AND current_step = $2
+		RETURNING `+leaveRequestColumns,
+		requestID, step,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLeaveRequestStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = returnApprovalRequest(ctx, tx, "leave_request", requestID, step)
+	if errors.Is(err, ErrApprovalRequestStatusChanged) {
+		return nil, ErrLeaveRequestStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "leave_request"
+	history.EntityID = requestID
+	history.StepNumber = step
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// EditLeaveRequest replaces the dates, days, reason and documentation of a
+// request awaiting changes with those of edited, moving its reservation to
+// the new days in one transaction. A balance that does not exist yet is
+// opened with openingDays accrued. It returns ErrLeaveRequestStatusChanged
+// when the request is no longer awaiting changes, and on
+// ErrInsufficientLeaveBalance the balance the new days would draw on.
+func (l *LeaveRequestRepository) EditLeaveRequest(
+	ctx context.Context,
+	edited *models.LeaveRequest,
+	openingDays float64,
+	actorID *uuid.UUID,
+) (*models.LeaveRequest, *models.LeaveBalance, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT 1 FROM employees WHERE id = $1 FOR UPDATE", edited.EmployeeID); err != nil {
+		return nil, nil, err
+	}
+
+	var current models.LeaveRequest
+	if err := scanLeaveRequest(tx.QueryRow(ctx,
+		"SELECT "+leaveRequestColumns+" FROM leave_requests WHERE id = $1 FOR UPDATE",
+		edited.ID,
+	), &current); err != nil {
+		return nil, nil, err
+	}
+	if current.Status != "changes_requested" {
+		return nil, nil, ErrLeaveRequestStatusChanged
+	}
+	if err := checkLeaveOverlap(ctx, tx, edited); err != nil {
+		return nil, nil, err
+	}
+
+	balance, err := lockLeaveBalance(ctx, tx, current.EmployeeID, current.LeaveTypeID, current.StartDate.Year(), 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := changeLeaveBalance(ctx, tx, balance, &models.LeaveLedgerEntry{
+		EntryType:      "release",
+		PendingDelta:   -current.DaysRequested,
+		LeaveRequestID: &current.ID,
+		ActorID:        actorID,
+		Reason:         "leave edited",
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	// The new dates may fall in another year, and so draw on another
+	// balance.
+	balance, err = lockLeaveBalance(ctx, tx, current.EmployeeID, current.LeaveTypeID, edited.StartDate.Year(), openingDays)
+	if err != nil {
+		return nil, nil, err
+	}
+	available := balance.TotalDays + balance.CarriedForwardDays - balance.UsedDays - balance.PendingDays
+	if available < edited.DaysRequested {
+		return nil, balance, ErrInsufficientLeaveBalance
+	}
+
+	var updated models.LeaveRequest
+	if err := scanLeaveRequest(tx.QueryRow(ctx, `
+		UPDATE leave_requests
+		SET start_date = $2, end_date = $3, start_half_day = $4, end_half_day = $5, days_requested = $6,
+			reason = NULLIF($7, ''), attachment_url = NULLIF($8, ''),
+			documentation_status = $9, documentation_due_date = $10, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+leaveRequestColumns,
+		current.ID, edited.StartDate, edited.EndDate, edited.StartHalfDay, edited.EndHalfDay, edited.DaysRequested,
+		edited.Reason, edited.AttachmentURL, edited.DocumentationStatus, edited.DocumentationDueDate,
+	), &updated); err != nil {
+		return nil, nil, err
+	}
+
+	balance, err = changeLeaveBalance(ctx, tx, balance, &models.LeaveLedgerEntry{
+		EntryType:      "reservation",
+		PendingDelta:   updated.DaysRequested,
+		LeaveRequestID: &updated.ID,
+		ActorID:        actorID,
+		Reason:         "leave edited",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return &updated, balance, nil
+}
+
+// ResubmitLeaveRequest puts a request awaiting changes back before the
+// approvers of toStep, along with its approval request, recording history
+// against toStep and revision, when not nil, as its latest revision. It
+// returns ErrLeaveRequestStatusChanged when the request is no longer
+// awaiting changes.
+func (l *LeaveRequestRepository) ResubmitLeaveRequest(
+	ctx context.Context,
+	requestID uuid.UUID,
+	toStep int,
+	history *models.ApprovalHistory,
+	revision *models.ApprovalRevision,
+) (*models.LeaveRequest, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.LeaveRequest
+	err = scanLeaveRequest(tx.QueryRow(ctx, `
+		UPDATE leave_requests
+		SET status = 'pending', current_step = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'changes_requested'
+		RETURNING `+leaveRequestColumns,
+		requestID, toStep,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLeaveRequestStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = resubmitApprovalRequest(ctx, tx, "leave_request", requestID, toStep)
+	if errors.Is(err, ErrApprovalRequestStatusChanged) {
+		return nil, ErrLeaveRequestStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "leave_request"
+	history.EntityID = requestID
+	history.StepNumber = toStep
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+	if revision != nil {
+		if _, err := insertApprovalRevision(ctx, tx, revision); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// lockLeaveBalance returns the balance row for the year, opening it with
+// openingDays accrued if needed, locked for the rest of the transaction.
+func lockLeaveBalance(ctx context.Context, tx pgx.Tx, employeeID, leaveTypeID uuid.UUID, year int, openingDays float64) (*models.LeaveBalance, error) {
+	var openedID uuid.UUID
+	err := tx.QueryRow(ctx, `
+		INSERT INTO leave_balances (employee_id, leave_type_id, year, total_days, accrued_days)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (employee_id, leave_type_id, year) DO NOTHING
+		RETURNING id
+	`, employeeID, leaveTypeID, year, openingDays).Scan(&openedID)
+	switch {
+	case err == nil:
+		if err := insertLeaveLedgerEntry(ctx, tx, &models.LeaveLedgerEntry{
+			BalanceID:  openedID,
+			EntryType:  "accrual",
+			TotalDelta: openingDays,
+			Reason:     "balance opened",
+		}); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	var balance models.LeaveBalance
+	if err := scanLeaveBalance(tx.QueryRow(ctx, `
+		SELECT `+leaveBalanceColumns+`
+		FROM leave_balances
+		WHERE employee_id = $1 AND leave_type_id = $2 AND year = $3
+		FOR UPDATE
+	`, employeeID, leaveTypeID, year), &balance); err != nil {
+		return nil, err
+	}
+
+	return &balance, nil
+}
+
+func (l *LeaveRequestRepository) GetLeaveRequestByID(ctx context.Context, requestID uuid.UUID) (*models.LeaveRequest, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := "SELECT " + leaveRequestColumns + " FROM leave_requests WHERE id = $1"
+
+	var request models.LeaveRequest
+	if err := scanLeaveRequest(l.pool.QueryRow(ctx, query, requestID), &request); err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// ListLeaveRequests returns an employee's requests, newest start date first.
+// An empty status returns every request.
+func (l *LeaveRequestRepository) ListLeaveRequests(ctx context.Context, employeeID uuid.UUID, status string) ([]*models.LeaveRequest, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT ` + leaveRequestColumns + `
+		FROM leave_requests
+		WHERE employee_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY start_date DESC, created_at DESC
+	`
+
+	rows, err := l.pool.Query(ctx, query, employeeID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*models.LeaveRequest
+	for rows.Next() {
+		var request models.LeaveRequest
+		if err := scanLeaveRequest(rows, &request); err != nil {
+			return nil, err
+		}
+		requests = append(requests, &request)
+	}
+
+	return requests, rows.Err()
+}
+
+// ListLeaveBalances returns an employee's balances for a year.
+func (l *LeaveRequestRepository) ListLeaveBalances(ctx context.Context, employeeID uuid.UUID, year int) ([]*models.LeaveBalance, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT ` + leaveBalanceColumns + `
+		FROM leave_balances
+		WHERE employee_id = $1 AND year = $2
+	`
+
+	rows, err := l.pool.Query(ctx, query, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []*models.LeaveBalance
+	for rows.Next() {
+		var balance models.LeaveBalance
+		if err := scanLeaveBalance(rows, &balance); err != nil {
+			return nil, err
+		}
+		balances = append(balances, &balance)
+	}
+
+	return balances, rows.Err()
+}
+
+// AttachLeaveDocument stores the supporting document of a pending or
+// approved request. A request awaiting (or overdue on) its documentation
+// becomes provided.
+func (l *LeaveRequestRepository) AttachLeaveDocument(ctx context.Context, requestID uuid.UUID, attachmentURL string) (*models.LeaveRequest, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		UPDATE leave_requests
+		SET attachment_url = $2,
+			documentation_status = CASE
+				WHEN documentation_status IN ('awaiting', 'overdue') THEN 'provided'
+				ELSE documentation_status
+			END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status IN ('pending', 'changes_requested', 'approved')
+		RETURNING ` + leaveRequestColumns
+
+	var request models.LeaveRequest
+	err := scanLeaveRequest(l.pool.QueryRow(ctx, query, requestID, attachmentURL), &request)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLeaveRequestStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// ListOverdueLeaveDocumentation returns the pending and approved requests
+// whose documentation was due before asOf and has not arrived: those still
+// awaiting it, and those already flagged overdue whose leave type reverts
+// them (so an interrupted revert is retried).
+func (l *LeaveRequestRepository) ListOverdueLeaveDocumentation(ctx context.Context, asOf time.Time) ([]*models.OverdueLeaveDocumentation, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT ` + leaveRequestColumns + `, company_id, leave_type_name, documentation_overdue_action
+		FROM (
+			SELECT lr.*, e.company_id, lt.name AS leave_type_name, lt.documentation_overdue_action
+			FROM leave_requests lr
+			JOIN employees e ON e.id = lr.employee_id
+			JOIN leave_types lt ON lt.id = lr.leave_type_id
+			WHERE lr.documentation_due_date < $1
+				AND lr.status IN ('pending', 'approved')
+				AND (lr.documentation_status = 'awaiting'
+					OR (lr.documentation_status = 'overdue' AND lt.documentation_overdue_action = 'revert'))
+		) overdue
+		ORDER BY documentation_due_date
+	`
+
+	rows, err := l.pool.Query(ctx, query, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var overdue []*models.OverdueLeaveDocumentation
+	for rows.Next() {
+		var o models.OverdueLeaveDocumentation
+		if err := scanLeaveRequest(rows, &o.LeaveRequest, &o.CompanyID, &o.LeaveTypeName, &o.OverdueAction); err != nil {
+			return nil, err
+		}
+		overdue = append(overdue, &o)
+	}
+
+	return overdue, rows.Err()
+}
+
+// MarkLeaveDocumentationOverdue flags a request still awaiting its
+// documentation. It reports whether the request was flagged, which is false
+// when the document arrived in the meantime.
+func (l *LeaveRequestRepository) MarkLeaveDocumentationOverdue(ctx context.Context, requestID uuid.UUID) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tag, err := l.pool.Exec(ctx, `
+		UPDATE leave_requests
+		SET documentation_status = 'overdue', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND documentation_status = 'awaiting'
+	`, requestID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type LeaveTypeRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLeaveTypeRepository(pool *pgxpool.Pool) *LeaveTypeRepository {
+	return &LeaveTypeRepository{
+		pool: pool,
+	}
+}
+
+const leaveTypeColumns = `
+	id, company_id, name, COALESCE(code, ''), COALESCE(description, ''), days_allowed,
+	is_paid, requires_documentation, carry_forward_allowed, max_carry_forward_days,
+	color_code, status, eligible_employment_types, eligible_genders, min_tenure_days,
+	allowed_during_probation, accrual_policy, documentation_after_days, documentation_grace_days,
+	documentation_overdue_action, created_at, updated_at`
+
+func scanLeaveType(row pgx.Row, lt *models.LeaveType) error {
+	return row.Scan(
+		&lt.ID, &lt.CompanyID, &lt.Name, &lt.Code, &lt.Description, &lt.DaysAllowed,
+		&lt.IsPaid, &lt.RequiresDocumentation, &lt.CarryForwardAllowed, &lt.MaxCarryForwardDays,
+		&lt.ColorCode, &lt.Status, &lt.EligibleEmploymentTypes, &lt.EligibleGenders, &lt.MinTenureDays,
+		&lt.AllowedDuringProbation, &lt.AccrualPolicy, &lt.DocumentationAfterDays, &lt.DocumentationGraceDays,
+		&lt.DocumentationOverdueAction, &lt.CreatedAt, &lt.UpdatedAt,
+	)
+}
+
+func (l *LeaveTypeRepository) CreateLeaveType(ctx context.Context, lt *models.LeaveType) (*models.LeaveType, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		INSERT INTO leave_types (
+			company_id, name, code, description, days_allowed, is_paid,
+			requires_documentation, carry_forward_allowed, max_carry_forward_days,
+			color_code, status, eligible_employment_types, eligible_genders,
+			min_tenure_days, allowed_during_probation, accrual_policy,
+			documentation_after_days, documentation_grace_days, documentation_overdue_action
+		)
+		VALUES ($1,$2,NULLIF($3, ''),$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19)
+		RETURNING ` + leaveTypeColumns
+
+	var created models.LeaveType
+	err := scanLeaveType(l.pool.QueryRow(ctx, query,
+		lt.CompanyID,
+		lt.Name,
+		lt.Code,
+		lt.Description,
+		lt.DaysAllowed,
+		lt.IsPaid,
+		lt.RequiresDocumentation,
+		lt.CarryForwardAllowed,
+		lt.MaxCarryForwardDays,
+		lt.ColorCode,
+		lt.Status,
+		lt.EligibleEmploymentTypes,
+		lt.EligibleGenders,
+		lt.MinTenureDays,
+		lt.AllowedDuringProbation,
+		lt.AccrualPolicy,
+		lt.DocumentationAfterDays,
+		lt.DocumentationGraceDays,
+		lt.DocumentationOverdueAction,
+	), &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (l *LeaveTypeRepository) GetLeaveTypeByID(ctx context.Context, leaveTypeID uuid.UUID) (*models.LeaveType, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := "SELECT " + leaveTypeColumns + " FROM leave_types WHERE id = $1"
+
+	var lt models.LeaveType
+	if err := scanLeaveType(l.pool.QueryRow(ctx, query, leaveTypeID), &lt); err != nil {
+		return nil, err
+	}
+
+	return &lt, nil
+}
+
+// ListLeaveTypes returns a company's leave types by name. An empty status
+// returns both active and inactive types.
+func (l *LeaveTypeRepository) ListLeaveTypes(ctx context.Context, companyID uuid.UUID, status string) ([]*models.LeaveType, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT ` + leaveTypeColumns + `
+		FROM leave_types
+		WHERE company_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY name
+	`
+
+	rows, err := l.pool.Query(ctx, query, companyID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leaveTypes []*models.LeaveType
+	for rows.Next() {
+		var lt models.LeaveType
+		if err := scanLeaveType(rows, &lt); err != nil {
+			return nil, err
+		}
+		leaveTypes = append(leaveTypes, &lt)
+	}
+
+	return leaveTypes, rows.Err()
+}
+
+// UpdateLeaveType overwrites every editable column of the leave type.
+func (l *LeaveTypeRepository) UpdateLeaveType(ctx context.Context, lt *models.LeaveType) (*models.LeaveType, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		UPDATE leave_types
+		SET
+			name = $1,
+			code = NULLIF($2, ''),
+			description = $3,
+			days_allowed = $4,
+			is_paid = $5,
+			requires_documentation = $6,
+			carry_forward_allowed = $7,
+			max_carry_forward_days = $8,
+			color_code = $9,
+			status = $10,
+			eligible_employment_types = $11,
+			eligible_genders = $12,
+			min_tenure_days = $13,
+			allowed_during_probation = $14,
+			accrual_policy = $15,
+			documentation_after_days = $16,
+			documentation_grace_days = $17,
+			documentation_overdue_action = $18,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $19
+		RETURNING ` + leaveTypeColumns
+
+	var updated models.LeaveType
+	err := scanLeaveType(l.pool.QueryRow(ctx, query,
+		lt.Name,
+		lt.Code,
+		lt.Description,
+		lt.DaysAllowed,
+		lt.IsPaid,
+		lt.RequiresDocumentation,
+		lt.CarryForwardAllowed,
+		lt.MaxCarryForwardDays,
+		lt.ColorCode,
+		lt.Status,
+		lt.EligibleEmploymentTypes,
+		lt.EligibleGenders,
+		lt.MinTenureDays,
+		lt.AllowedDuringProbation,
+		lt.AccrualPolicy,
+		lt.DocumentationAfterDays,
+		lt.DocumentationGraceDays,
+		lt.DocumentationOverdueAction,
+		lt.ID,
+	), &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// IsLeaveTypeInUse reports whether any balance or request refers to the leave
+// type, in which case deleting it would cascade into leave history.
+func (l *LeaveTypeRepository) IsLeaveTypeInUse(ctx context.Context, leaveTypeID uuid.UUID) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var inUse bool
+	err := l.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM leave_requests WHERE leave_type_id = $1)
+			OR EXISTS (SELECT 1 FROM leave_balances WHERE leave_type_id = $1)
+	`, leaveTypeID).Scan(&inUse)
+	return inUse, err
+}
+
+func (l *LeaveTypeRepository) DeleteLeaveType(ctx context.Context, leaveTypeID uuid.UUID, softDelete bool) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := "DELETE FROM leave_types WHERE id = $1"
+	if softDelete {
+		query = "UPDATE leave_types SET status = 'inactive', updated_at = CURRENT_TIMESTAMP WHERE id = $1"
+	}
+
+	result, err := l.pool.Exec(ctx, query, leaveTypeID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("leave type not found")
+	}
+	return nil
+}
@@ -28,13 +28,13 @@ func (l *LevelRepository) CreateLevel(ctx context.Context, level *models.Level)
 
 	query := `
 	INSERT INTO levels (
-		company_id, name, hierarchy_level, min_salary, max_salary,description
+		company_id, name, hierarchy_level, min_salary, max_salary,description, probation_days
 	)
 	VALUES (
-		$1,$2,$3,$4,$5,$6
+		$1,$2,$3,$4,$5,$6,$7
 	)
 	RETURNING id, 
-		company_id, name, hierarchy_level, min_salary, max_salary,description, created_at, updated_at
+		company_id, name, hierarchy_level, min_salary, max_salary,description, probation_days, created_at, updated_at
 	`
 
 	err := l.pool.QueryRow(ctx, query,
@@ -44,6 +44,7 @@ func (l *LevelRepository) CreateLevel(ctx context.Context, level *models.Level)
 		level.MinSalary,
 		level.MaxSalary,
 		level.Description,
+		level.ProbationDays,
 	).Scan(
 		&level.ID,
 		&level.CompanyID,
@@ -52,6 +53,7 @@ func (l *LevelRepository) CreateLevel(ctx context.Context, level *models.Level)
 		&level.MinSalary,
 		&level.MaxSalary,
 		&level.Description,
+		&level.ProbationDays,
 		&level.CreatedAt,
 		&level.UpdatedAt,
 	)
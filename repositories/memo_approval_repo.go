@@ -0,0 +1,278 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// SubmitMemo puts a draft before the first step of its approvers. It
+// returns ErrMemoStatusChanged when the memo is no longer a draft.
+func (m *MemoRepository) SubmitMemo(ctx context.Context, memoID uuid.UUID) (*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var updated models.Memo
+	err := scanMemo(m.pool.QueryRow(ctx, `
+		UPDATE memos
+		SET status = 'pending', current_step = 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'draft'
+		RETURNING `+memoColumns,
+		memoID,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemoStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := loadMemoAudience(ctx, m.pool, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// FinalizeMemo moves a memo pending at step to history.Action (approved or
+// rejected), recording history and closing its approval request in one
+// transaction. It returns ErrMemoStatusChanged when the memo is no longer
+// pending at step.
+func (m *MemoRepository) FinalizeMemo(ctx context.Context, memoID uuid.UUID, step int, history *models.ApprovalHistory) (*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.Memo
+	err = scanMemo(tx.QueryRow(ctx, `
+		UPDATE memos
+		SET status = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending' AND current_step = $3
+		RETURNING `+memoColumns,
+		memoID, history.Action, step,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemoStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "memo"
+	history.EntityID = memoID
+	history.StepNumber = updated.CurrentStep
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+	if err := closeApprovalRequest(ctx, tx, "memo", memoID, history.Action); err != nil {
+		return nil, err
+	}
+	if err := loadMemoAudience(ctx, tx, &updated); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// AdvanceMemo records an intermediate approval: the pending memo moves from
+// fromStep to toStep along with its approval request. It returns
+// ErrMemoStatusChanged when the memo is no longer pending at fromStep.
+func (m *MemoRepository) AdvanceMemo(
+	ctx context.Context,
+	memoID uuid.UUID,
+	fromStep, toStep int,
+	history *models.ApprovalHistory,
+) (*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.Memo
+	err = scanMemo(tx.QueryRow(ctx, `
+		UPDATE memos
+		SET current_step = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending' AND current_step = $2
+		RETURNING `+memoColumns,
+		memoID, fromStep, toStep,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemoStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = advanceApprovalRequest(ctx, tx, "memo", memoID, fromStep, toStep)
+	if errors.Is(err, ErrApprovalRequestStatusChanged) {
+		return nil, ErrMemoStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "memo"
+	history.EntityID = memoID
+	history.StepNumber = fromStep
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// ReturnMemo sends a pending memo at step back to its sender for changes,
+// along with its approval request. It returns ErrMemoStatusChanged when the
+// memo is no longer pending at step.
+func (m *MemoRepository) ReturnMemo(
+	ctx context.Context,
+	memoID uuid.UUID,
+	step int,
+	history *models.ApprovalHistory,
+) (*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.Memo
+	err = scanMemo(tx.QueryRow(ctx, `
+		UPDATE memos
+		SET status = 'changes_requested', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending' AND current_step = $2
+		RETURNING `+memoColumns,
+		memoID, step,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemoStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = returnApprovalRequest(ctx, tx, "memo", memoID, step)
+	if errors.Is(err, ErrApprovalRequestStatusChanged) {
+		return nil, ErrMemoStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "memo"
+	history.EntityID = memoID
+	history.StepNumber = step
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// ResubmitMemo puts a memo awaiting changes back before the approvers of
+// toStep, along with its approval request, recording revision, when not
+// nil, as its latest revision. It returns ErrMemoStatusChanged when the
+// memo is no longer awaiting changes.
+func (m *MemoRepository) ResubmitMemo(
+	ctx context.Context,
+	memoID uuid.UUID,
+	toStep int,
+	history *models.ApprovalHistory,
+	revision *models.ApprovalRevision,
+) (*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.Memo
+	err = scanMemo(tx.QueryRow(ctx, `
+		UPDATE memos
+		SET status = 'pending', current_step = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'changes_requested'
+		RETURNING `+memoColumns,
+		memoID, toStep,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemoStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = resubmitApprovalRequest(ctx, tx, "memo", memoID, toStep)
+	if errors.Is(err, ErrApprovalRequestStatusChanged) {
+		return nil, ErrMemoStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history.EntityType = "memo"
+	history.EntityID = memoID
+	history.StepNumber = toStep
+	if err := insertApprovalHistory(ctx, tx, history); err != nil {
+		return nil, err
+	}
+	if revision != nil {
+		if _, err := insertApprovalRevision(ctx, tx, revision); err != nil {
+			return nil, err
+		}
+	}
+	if err := loadMemoAudience(ctx, tx, &updated); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
@@ -0,0 +1,249 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// ErrDisciplinaryCaseClosed is returned when a change needs an open case.
+var ErrDisciplinaryCaseClosed = errors.New("disciplinary case is closed")
+
+const disciplinaryCaseColumns = `
+	id, company_id, employee_id, title, COALESCE(description, ''), status, COALESCE(outcome, ''),
+	COALESCE(outcome_notes, ''), opened_by, closed_by, closed_at, created_at, updated_at`
+
+func scanDisciplinaryCase(row pgx.Row, c *models.DisciplinaryCase) error {
+	return row.Scan(
+		&c.ID, &c.CompanyID, &c.EmployeeID, &c.Title, &c.Description, &c.Status, &c.Outcome,
+		&c.OutcomeNotes, &c.OpenedBy, &c.ClosedBy, &c.ClosedAt, &c.CreatedAt, &c.UpdatedAt,
+	)
+}
+
+const memoThreadColumns = `
+	r.id, r.memo_id, r.author_id, COALESCE(e.first_name || ' ' || e.last_name, ''), r.kind, r.content, r.created_at`
+
+func scanMemoThreadEntry(row pgx.Row, r *models.MemoThreadEntry) error {
+	return row.Scan(&r.ID, &r.MemoID, &r.AuthorID, &r.AuthorName, &r.Kind, &r.Content, &r.CreatedAt)
+}
+
+// ListDisciplinaryMemos returns the disciplinary memos about subjectID,
+// newest first. Drafts are only returned to their sender, viewerID.
+func (m *MemoRepository) ListDisciplinaryMemos(ctx context.Context, subjectID, viewerID uuid.UUID) ([]*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	return m.listMemos(ctx, `
+		SELECT `+memoColumns+`
+		FROM memos
+		WHERE memo_type = 'disciplinary' AND subject_employee_id = $1 AND (status <> 'draft' OR employee_id = $2)
+		ORDER BY COALESCE(published_at, created_at) DESC
+	`, subjectID, viewerID)
+}
+
+// ListCaseMemos returns the memos linked to a case, oldest first.
+func (m *MemoRepository) ListCaseMemos(ctx context.Context, caseID uuid.UUID) ([]*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	return m.listMemos(ctx, `
+		SELECT `+memoColumns+`
+		FROM memos
+		WHERE case_id = $1
+		ORDER BY COALESCE(published_at, created_at)
+	`, caseID)
+}
+
+func (m *MemoRepository) CreateMemoThreadEntry(ctx context.Context, entry *models.MemoThreadEntry) (*models.MemoThreadEntry, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var created models.MemoThreadEntry
+	if err := scanMemoThreadEntry(m.pool.QueryRow(ctx, `
+		WITH r AS (
+			INSERT INTO memo_responses (memo_id, author_id, kind, content)
+			VALUES ($1, $2, $3, $4)
+			RETURNING *
+		)
+		SELECT `+memoThreadColumns+`
+		FROM r
+		LEFT JOIN employees e ON e.id = r.author_id
+	`, entry.MemoID, entry.AuthorID, entry.Kind, entry.Content), &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// ListMemoThread returns the thread under a memo, oldest first.
+func (m *MemoRepository) ListMemoThread(ctx context.Context, memoID uuid.UUID) ([]*models.MemoThreadEntry, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := m.pool.Query(ctx, `
+		SELECT `+memoThreadColumns+`
+		FROM memo_responses r
+		LEFT JOIN employees e ON e.id = r.author_id
+		WHERE r.memo_id = $1
+		ORDER BY r.created_at, r.id
+	`, memoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var thread []*models.MemoThreadEntry
+	for rows.Next() {
+		var entry models.MemoThreadEntry
+		if err := scanMemoThreadEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+		thread = append(thread, &entry)
+	}
+
+	return thread, rows.Err()
+}
+
+func (m *MemoRepository) CreateDisciplinaryCase(ctx context.Context, c *models.DisciplinaryCase) (*models.DisciplinaryCase, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var created models.DisciplinaryCase
+	if err := scanDisciplinaryCase(m.pool.QueryRow(ctx, `
+		INSERT INTO disciplinary_cases (company_id, employee_id, title, description, opened_by)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		RETURNING `+disciplinaryCaseColumns,
+		c.CompanyID, c.EmployeeID, c.Title, c.Description, c.OpenedBy,
+	), &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (m *MemoRepository) GetDisciplinaryCaseByID(ctx context.Context, caseID uuid.UUID) (*models.DisciplinaryCase, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var c models.DisciplinaryCase
+	if err := scanDisciplinaryCase(m.pool.QueryRow(ctx,
+		"SELECT "+disciplinaryCaseColumns+" FROM disciplinary_cases WHERE id = $1",
+		caseID,
+	), &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// ListDisciplinaryCases returns a company's cases, newest first, narrowed
+// to one employee when employeeID is set. An empty status returns every
+// case.
+func (m *MemoRepository) ListDisciplinaryCases(ctx context.Context, companyID uuid.UUID, employeeID *uuid.UUID, status string) ([]*models.DisciplinaryCase, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	rows, err := m.pool.Query(ctx, `
+		SELECT `+disciplinaryCaseColumns+`
+		FROM disciplinary_cases
+		WHERE company_id = $1 AND ($2::uuid IS NULL OR employee_id = $2) AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC
+	`, companyID, employeeID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cases []*models.DisciplinaryCase
+	for rows.Next() {
+		var c models.DisciplinaryCase
+		if err := scanDisciplinaryCase(rows, &c); err != nil {
+			return nil, err
+		}
+		cases = append(cases, &c)
+	}
+
+	return cases, rows.Err()
+}
+
+// CloseDisciplinaryCase records the outcome of an open case. It returns
+// ErrDisciplinaryCaseClosed when the case is already closed.
+func (m *MemoRepository) CloseDisciplinaryCase(ctx context.Context, caseID uuid.UUID, outcome, notes string, closedBy *uuid.UUID) (*models.DisciplinaryCase, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var closed models.DisciplinaryCase
+	err := scanDisciplinaryCase(m.pool.QueryRow(ctx, `
+		UPDATE disciplinary_cases
+		SET status = 'closed', outcome = $2, outcome_notes = NULLIF($3, ''), closed_by = $4,
+			closed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'open'
+		RETURNING `+disciplinaryCaseColumns,
+		caseID, outcome, notes, closedBy,
+	), &closed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDisciplinaryCaseClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &closed, nil
+}
+
+// SetMemoCase links a memo to a case, or unlinks it when caseID is nil,
+// provided the case it is linked to or from is open. It returns
+// ErrDisciplinaryCaseClosed otherwise.
+func (m *MemoRepository) SetMemoCase(ctx context.Context, memoID uuid.UUID, caseID *uuid.UUID) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	result, err := m.pool.Exec(ctx, `
+		UPDATE memos
+		SET case_id = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM disciplinary_cases
+				WHERE id IN ($2, memos.case_id) AND status <> 'open'
+			)
+	`, memoID, caseID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrDisciplinaryCaseClosed
+	}
+	return nil
+}
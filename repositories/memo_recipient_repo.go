@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+const memoRecipientColumns = `
+	r.id, r.memo_id, r.employee_id, e.first_name || ' ' || e.last_name, r.is_read, r.read_at,
+	r.acknowledged_at, r.reminder_count, r.last_reminded_at, r.created_at`
+
+func memoRecipientDest(r *models.MemoRecipient) []any {
+	return []any{
+		&r.ID, &r.MemoID, &r.EmployeeID, &r.EmployeeName, &r.IsRead, &r.ReadAt,
+		&r.AcknowledgedAt, &r.ReminderCount, &r.LastRemindedAt, &r.CreatedAt,
+	}
+}
+
+func scanMemoRecipient(row pgx.Row, r *models.MemoRecipient) error {
+	return row.Scan(memoRecipientDest(r)...)
+}
+
+// receivedMemoColumns selects a memo, as m, followed by one of its
+// recipients, as r with their employee e.
+const receivedMemoColumns = `
+	m.id, m.company_id, m.employee_id, m.memo_type, m.title, m.content, COALESCE(m.reference_number, ''),
+	m.status, COALESCE(m.current_step, 1), COALESCE(m.priority, 'normal'), m.requires_acknowledgement,
+	m.subject_employee_id, m.case_id, m.published_at, m.created_at, m.updated_at, ` + memoRecipientColumns
+
+func scanReceivedMemo(row pgx.Row, memo *models.ReceivedMemo) error {
+	return scanMemo(row, &memo.Memo, memoRecipientDest(&memo.Receipt)...)
+}
+
+// ListReceivedMemos returns the published memos sent to employeeID with
+// their receipts, newest first, only the unread ones when unreadOnly is
+// set.
+func (m *MemoRepository) ListReceivedMemos(ctx context.Context, employeeID uuid.UUID, unreadOnly bool) ([]*models.ReceivedMemo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	rows, err := m.pool.Query(ctx, `
+		SELECT `+receivedMemoColumns+`
+		FROM memo_recipients r
+		JOIN employees e ON e.id = r.employee_id
+		JOIN memos m ON m.id = r.memo_id
+		WHERE r.employee_id = $1 AND m.status = 'published' AND (NOT $2 OR NOT r.is_read)
+		ORDER BY m.published_at DESC
+	`, employeeID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var received []*models.ReceivedMemo
+	for rows.Next() {
+		var memo models.ReceivedMemo
+		if err := scanReceivedMemo(rows, &memo); err != nil {
+			return nil, err
+		}
+		received = append(received, &memo)
+	}
+
+	return received, rows.Err()
+}
+
+// ListMemoRecipients returns everyone a memo was published to, by name.
+func (m *MemoRepository) ListMemoRecipients(ctx context.Context, memoID uuid.UUID) ([]*models.MemoRecipient, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	rows, err := m.pool.Query(ctx, `
+		SELECT `+memoRecipientColumns+`
+		FROM memo_recipients r
+		JOIN employees e ON e.id = r.employee_id
+		WHERE r.memo_id = $1
+		ORDER BY e.last_name, e.first_name
+	`, memoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []*models.MemoRecipient
+	for rows.Next() {
+		var recipient models.MemoRecipient
+		if err := scanMemoRecipient(rows, &recipient); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, &recipient)
+	}
+
+	return recipients, rows.Err()
+}
+
+// MarkMemoRead records that employeeID has read a memo, keeping the time
+// they first read it. acknowledge also records their acknowledgement. It
+// returns pgx.ErrNoRows when the memo was not sent to them.
+func (m *MemoRepository) MarkMemoRead(ctx context.Context, memoID, employeeID uuid.UUID, acknowledge bool) (*models.MemoRecipient, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var recipient models.MemoRecipient
+	if err := scanMemoRecipient(m.pool.QueryRow(ctx, `
+		UPDATE memo_recipients r
+		SET is_read = true,
+			read_at = COALESCE(r.read_at, CURRENT_TIMESTAMP),
+			acknowledged_at = CASE WHEN $3 THEN COALESCE(r.acknowledged_at, CURRENT_TIMESTAMP) ELSE r.acknowledged_at END
+		FROM employees e
+		WHERE e.id = r.employee_id AND r.memo_id = $1 AND r.employee_id = $2
+		RETURNING `+memoRecipientColumns,
+		memoID, employeeID, acknowledge,
+	), &recipient); err != nil {
+		return nil, err
+	}
+
+	return &recipient, nil
+}
+
+// ListDueMemoReminders returns the recipients of published urgent memos who
+// have not yet read them, or not acknowledged those requiring it, at least
+// interval after publication and after their last reminder, and who have
+// had fewer than maxReminders reminders.
+func (m *MemoRepository) ListDueMemoReminders(ctx context.Context, now time.Time, interval time.Duration, maxReminders int) ([]*models.ReceivedMemo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	cutoff := now.Add(-interval)
+	rows, err := m.pool.Query(ctx, `
+		SELECT `+receivedMemoColumns+`
+		FROM memo_recipients r
+		JOIN employees e ON e.id = r.employee_id
+		JOIN memos m ON m.id = r.memo_id
+		WHERE m.status = 'published' AND m.priority = 'urgent'
+			AND (NOT r.is_read OR (m.requires_acknowledgement AND r.acknowledged_at IS NULL))
+			AND m.published_at <= $1 AND (r.last_reminded_at IS NULL OR r.last_reminded_at <= $1)
+			AND r.reminder_count < $2
+			AND e.status NOT IN ('inactive', 'terminated')
+		ORDER BY m.published_at, r.id
+	`, cutoff, maxReminders)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []*models.ReceivedMemo
+	for rows.Next() {
+		var memo models.ReceivedMemo
+		if err := scanReceivedMemo(rows, &memo); err != nil {
+			return nil, err
+		}
+		due = append(due, &memo)
+	}
+
+	return due, rows.Err()
+}
+
+// MarkMemoReminded counts a reminder sent to a recipient whose count was
+// still reminderCount. It reports false when another run reminded them
+// first.
+func (m *MemoRepository) MarkMemoReminded(ctx context.Context, recipientID uuid.UUID, reminderCount int) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	result, err := m.pool.Exec(ctx, `
+		UPDATE memo_recipients
+		SET reminder_count = reminder_count + 1, last_reminded_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND reminder_count = $2
+	`, recipientID, reminderCount)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
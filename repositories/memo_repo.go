@@ -0,0 +1,486 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+// ErrMemoStatusChanged is returned when a memo is no longer in the status a
+// change expected, typically because a concurrent action got there first.
+var ErrMemoStatusChanged = errors.New("memo status has changed")
+
+// ErrMemoHasNoRecipients is returned when a memo's targets match no active
+// employees at publish time.
+var ErrMemoHasNoRecipients = errors.New("the memo's targets match no active employees")
+
+type MemoRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewMemoRepository(pool *pgxpool.Pool) *MemoRepository {
+	return &MemoRepository{
+		pool: pool,
+	}
+}
+
+const memoColumns = `
+	id, company_id, employee_id, memo_type, title, content, COALESCE(reference_number, ''),
+	status, COALESCE(current_step, 1), COALESCE(priority, 'normal'), requires_acknowledgement,
+	subject_employee_id, case_id, published_at, created_at, updated_at`
+
+func scanMemo(row pgx.Row, m *models.Memo, extra ...any) error {
+	dest := []any{
+		&m.ID, &m.CompanyID, &m.EmployeeID, &m.MemoType, &m.Title, &m.Content, &m.ReferenceNumber,
+		&m.Status, &m.CurrentStep, &m.Priority, &m.RequiresAcknowledgement,
+		&m.SubjectEmployeeID, &m.CaseID, &m.PublishedAt, &m.CreatedAt, &m.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// CreateMemo stores memo and its targets as a draft.
+func (m *MemoRepository) CreateMemo(ctx context.Context, memo *models.Memo) (*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var created models.Memo
+	if err := scanMemo(tx.QueryRow(ctx, `
+		INSERT INTO memos (
+			company_id, employee_id, memo_type, title, content, priority, requires_acknowledgement, subject_employee_id, status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'draft')
+		RETURNING `+memoColumns,
+		memo.CompanyID, memo.EmployeeID, memo.MemoType, memo.Title, memo.Content, memo.Priority,
+		memo.RequiresAcknowledgement, memo.SubjectEmployeeID,
+	), &created); err != nil {
+		return nil, err
+	}
+
+	if err := replaceMemoTargets(ctx, tx, &created, memo.Targets); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// replaceMemoTargets replaces the targets of memo with targets through tx.
+func replaceMemoTargets(ctx context.Context, tx pgx.Tx, memo *models.Memo, targets []*models.MemoTarget) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM memo_targets WHERE memo_id = $1", memo.ID); err != nil {
+		return err
+	}
+
+	memo.Targets = make([]*models.MemoTarget, 0, len(targets))
+	for _, target := range targets {
+		created := models.MemoTarget{MemoID: memo.ID, TargetType: target.TargetType, TargetID: target.TargetID}
+		if err := tx.QueryRow(ctx,
+			"INSERT INTO memo_targets (memo_id, target_type, target_id) VALUES ($1, $2, $3) RETURNING id",
+			memo.ID, target.TargetType, target.TargetID,
+		).Scan(&created.ID); err != nil {
+			return err
+		}
+		memo.Targets = append(memo.Targets, &created)
+	}
+	return nil
+}
+
+// loadMemoAudience fills in the targets and recipient counts of memos
+// through db.
+func loadMemoAudience(ctx context.Context, db dbExecutor, memos ...*models.Memo) error {
+	if len(memos) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Memo, len(memos))
+	ids := make([]uuid.UUID, 0, len(memos))
+	for _, memo := range memos {
+		memo.Targets = []*models.MemoTarget{}
+		byID[memo.ID] = memo
+		ids = append(ids, memo.ID)
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT id, memo_id, target_type, target_id
+		FROM memo_targets
+		WHERE memo_id = ANY($1)
+		ORDER BY memo_id, created_at, id
+	`, ids)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var target models.MemoTarget
+		if err := rows.Scan(&target.ID, &target.MemoID, &target.TargetType, &target.TargetID); err != nil {
+			rows.Close()
+			return err
+		}
+		memo := byID[target.MemoID]
+		memo.Targets = append(memo.Targets, &target)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = db.Query(ctx, `
+		SELECT memo_id, COUNT(*)
+		FROM memo_recipients
+		WHERE memo_id = ANY($1)
+		GROUP BY memo_id
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var memoID uuid.UUID
+		var count int
+		if err := rows.Scan(&memoID, &count); err != nil {
+			return err
+		}
+		byID[memoID].RecipientCount = count
+	}
+
+	return rows.Err()
+}
+
+// GetMemoByID returns a memo with its targets and recipient count.
+func (m *MemoRepository) GetMemoByID(ctx context.Context, memoID uuid.UUID) (*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var memo models.Memo
+	if err := scanMemo(m.pool.QueryRow(ctx,
+		"SELECT "+memoColumns+" FROM memos WHERE id = $1",
+		memoID,
+	), &memo); err != nil {
+		return nil, err
+	}
+	if err := loadMemoAudience(ctx, m.pool, &memo); err != nil {
+		return nil, err
+	}
+
+	return &memo, nil
+}
+
+// ListMemos returns a company's memos, newest first, leaving out
+// disciplinary memos, which are only listed per subject. Drafts are only
+// returned to their sender, viewerID. senderID, status and memoType narrow
+// the list when set.
+func (m *MemoRepository) ListMemos(
+	ctx context.Context,
+	companyID, viewerID uuid.UUID,
+	senderID *uuid.UUID,
+	status, memoType string,
+) ([]*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	return m.listMemos(ctx, `
+		SELECT `+memoColumns+`
+		FROM memos
+		WHERE company_id = $1 AND memo_type <> 'disciplinary' AND (status <> 'draft' OR employee_id = $2)
+			AND ($3::uuid IS NULL OR employee_id = $3) AND ($4 = '' OR status = $4) AND ($5 = '' OR memo_type = $5)
+		ORDER BY COALESCE(published_at, created_at) DESC
+	`, companyID, viewerID, senderID, status, memoType)
+}
+
+// listMemos runs a query selecting memoColumns and loads the audience of
+// the memos it returns.
+func (m *MemoRepository) listMemos(ctx context.Context, query string, args ...any) ([]*models.Memo, error) {
+	rows, err := m.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memos []*models.Memo
+	for rows.Next() {
+		var memo models.Memo
+		if err := scanMemo(rows, &memo); err != nil {
+			return nil, err
+		}
+		memos = append(memos, &memo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadMemoAudience(ctx, m.pool, memos...); err != nil {
+		return nil, err
+	}
+	return memos, nil
+}
+
+// EditMemo replaces the type, title, content, priority, acknowledgement
+// requirement, subject and targets of a draft, or of a memo sent back for
+// changes, with those of edited. It returns ErrMemoStatusChanged when the
+// memo is no longer editable.
+func (m *MemoRepository) EditMemo(ctx context.Context, edited *models.Memo) (*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var updated models.Memo
+	err = scanMemo(tx.QueryRow(ctx, `
+		UPDATE memos
+		SET memo_type = $2, title = $3, content = $4, priority = $5, requires_acknowledgement = $6,
+			subject_employee_id = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status IN ('draft', 'changes_requested')
+		RETURNING `+memoColumns,
+		edited.ID, edited.MemoType, edited.Title, edited.Content, edited.Priority, edited.RequiresAcknowledgement,
+		edited.SubjectEmployeeID,
+	), &updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemoStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := replaceMemoTargets(ctx, tx, &updated, edited.Targets); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// PublishMemo publishes a memo in fromStatus, draft or approved, in one
+// transaction. It numbers the memo with reference, given the company's
+// pattern, the publish time and the next value of the company's counter for
+// the year. It then expands the memo's targets into recipients: department
+// targets take in their sub-departments, while inactive and terminated
+// employees and the sender are left out.
+//
+// It returns ErrMemoStatusChanged when the memo is no longer in fromStatus
+// and ErrMemoHasNoRecipients when nobody would receive it.
+func (m *MemoRepository) PublishMemo(
+	ctx context.Context,
+	memoID uuid.UUID,
+	fromStatus string,
+	reference func(pattern string, publishedAt time.Time, sequence int) string,
+) (*models.Memo, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var companyID uuid.UUID
+	if err := tx.QueryRow(ctx, "SELECT company_id FROM memos WHERE id = $1", memoID).Scan(&companyID); err != nil {
+		return nil, err
+	}
+
+	var pattern string
+	var now time.Time
+	if err := tx.QueryRow(ctx,
+		"SELECT memo_reference_pattern, CURRENT_TIMESTAMP FROM companies WHERE id = $1",
+		companyID,
+	).Scan(&pattern, &now); err != nil {
+		return nil, err
+	}
+
+	// The counter row is locked until commit, which serialises numbering
+	// within the company's year.
+	var sequence int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO memo_reference_counters (company_id, year, last_value)
+		VALUES ($1, EXTRACT(YEAR FROM $2::timestamptz)::INT, 1)
+		ON CONFLICT (company_id, year)
+		DO UPDATE SET last_value = memo_reference_counters.last_value + 1
+		RETURNING last_value
+	`, companyID, now).Scan(&sequence); err != nil {
+		return nil, err
+	}
+
+	var published models.Memo
+	err = scanMemo(tx.QueryRow(ctx, `
+		UPDATE memos
+		SET status = 'published', reference_number = $2, published_at = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $4
+		RETURNING `+memoColumns,
+		memoID, reference(pattern, now, sequence), now, fromStatus,
+	), &published)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemoStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		WITH RECURSIVE tree AS (
+			SELECT d.id, 0 AS depth
+			FROM departments d
+			JOIN memo_targets t ON t.target_id = d.id AND t.target_type = 'department'
+			WHERE t.memo_id = $1 AND d.company_id = $2
+			UNION ALL
+			SELECT d.id, tree.depth + 1
+			FROM departments d
+			JOIN tree ON d.parent_department_id = tree.id
+			WHERE tree.depth < 50
+		),
+		targets AS (
+			SELECT target_type, target_id FROM memo_targets WHERE memo_id = $1
+		)
+		INSERT INTO memo_recipients (memo_id, employee_id)
+		SELECT $1, e.id
+		FROM employees e
+		WHERE e.company_id = $2 AND e.id <> $3 AND e.status NOT IN ('inactive', 'terminated')
+			AND (
+				EXISTS (SELECT 1 FROM targets WHERE target_type = 'company')
+				OR e.id IN (SELECT target_id FROM targets WHERE target_type = 'individual')
+				OR e.department_id IN (SELECT id FROM tree)
+				OR e.role_id IN (SELECT target_id FROM targets WHERE target_type = 'role')
+				OR e.level_id IN (SELECT target_id FROM targets WHERE target_type = 'level')
+			)
+		ON CONFLICT (memo_id, employee_id) DO NOTHING
+	`, memoID, companyID, published.EmployeeID); err != nil {
+		return nil, err
+	}
+
+	if err := loadMemoAudience(ctx, tx, &published); err != nil {
+		return nil, err
+	}
+	if published.RecipientCount == 0 {
+		return nil, ErrMemoHasNoRecipients
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &published, nil
+}
+
+// ListMemoRecipientIDs returns the employees a memo was published to.
+func (m *MemoRepository) ListMemoRecipientIDs(ctx context.Context, memoID uuid.UUID) ([]uuid.UUID, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := m.pool.Query(ctx, "SELECT employee_id FROM memo_recipients WHERE memo_id = $1", memoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// memoTargetTables maps target types to the table their target_id refers to.
+var memoTargetTables = map[string]string{
+	"individual": "employees",
+	"department": "departments",
+	"level":      "levels",
+}
+
+// MemoTargetExists reports whether targetID is an employee, department,
+// role or level of the company, per targetType. Roles include the system
+// roles shared by every company.
+func (m *MemoRepository) MemoTargetExists(ctx context.Context, companyID uuid.UUID, targetType string, targetID uuid.UUID) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := "SELECT EXISTS (SELECT 1 FROM roles WHERE id = $1 AND (company_id = $2 OR company_id IS NULL))"
+	if targetType != "role" {
+		table, ok := memoTargetTables[targetType]
+		if !ok {
+			return false, fmt.Errorf("unknown memo target type %q", targetType)
+		}
+		query = "SELECT EXISTS (SELECT 1 FROM " + table + " WHERE id = $1 AND company_id = $2)"
+	}
+
+	var exists bool
+	err := m.pool.QueryRow(ctx, query, targetID, companyID).Scan(&exists)
+	return exists, err
+}
+
+func (m *MemoRepository) GetMemoReferencePattern(ctx context.Context, companyID uuid.UUID) (string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var pattern string
+	err := m.pool.QueryRow(ctx, "SELECT memo_reference_pattern FROM companies WHERE id = $1", companyID).Scan(&pattern)
+	return pattern, err
+}
+
+func (m *MemoRepository) SetMemoReferencePattern(ctx context.Context, companyID uuid.UUID, pattern string) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	result, err := m.pool.Exec(ctx,
+		"UPDATE companies SET memo_reference_pattern = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1",
+		companyID, pattern,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
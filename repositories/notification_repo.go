@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type NotificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationRepository(pool *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{
+		pool: pool,
+	}
+}
+
+// CreateNotification stores a notification. When a notification with the same
+// recipient and dedupe key already exists nothing is written and false is
+// returned, which lets scheduled jobs re-run without spamming recipients.
+func (n *NotificationRepository) CreateNotification(ctx context.Context, notification *models.Notification) (bool, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		INSERT INTO notifications (
+			company_id, recipient_id, type, title, body, entity_type, entity_id, dedupe_key
+		)
+		VALUES ($1,$2,$3,$4,$5,NULLIF($6, ''),$7,NULLIF($8, ''))
+		ON CONFLICT (recipient_id, dedupe_key) DO NOTHING
+		RETURNING id, is_read, created_at
+	`
+
+	err := n.pool.QueryRow(ctx, query,
+		notification.CompanyID,
+		notification.RecipientID,
+		notification.Type,
+		notification.Title,
+		notification.Body,
+		notification.EntityType,
+		notification.EntityID,
+		notification.DedupeKey,
+	).Scan(&notification.ID, &notification.IsRead, &notification.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ListNotifications returns the newest notifications of a recipient.
+func (n *NotificationRepository) ListNotifications(ctx context.Context, recipientID uuid.UUID, unreadOnly bool, limit int) ([]*models.Notification, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT
+			id, company_id, recipient_id, type, title, COALESCE(body, ''),
+			COALESCE(entity_type, ''), entity_id, COALESCE(dedupe_key, ''),
+			is_read, read_at, created_at
+		FROM notifications
+		WHERE recipient_id = $1 AND (NOT $2 OR NOT is_read)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := n.pool.Query(ctx, query, recipientID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		var notification models.Notification
+		if err := rows.Scan(
+			&notification.ID, &notification.CompanyID, &notification.RecipientID,
+			&notification.Type, &notification.Title, &notification.Body,
+			&notification.EntityType, &notification.EntityID, &notification.DedupeKey,
+			&notification.IsRead, &notification.ReadAt, &notification.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, &notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (n *NotificationRepository) MarkRead(ctx context.Context, notificationID, recipientID uuid.UUID) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	result, err := n.pool.Exec(ctx, `
+		UPDATE notifications
+		SET is_read = true, read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE id = $1 AND recipient_id = $2
+	`, notificationID, recipientID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("notification not found")
+	}
+	return nil
+}
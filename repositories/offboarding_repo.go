@@ -0,0 +1,527 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type OffboardingRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOffboardingRepository(pool *pgxpool.Pool) *OffboardingRepository {
+	return &OffboardingRepository{
+		pool: pool,
+	}
+}
+
+const checklistColumns = `
+	id, company_id, employee_id, transition_id, termination_date, new_manager_id,
+	hod_successor_id, approver_successor_id, status, initiated_by,
+	automation_completed_at, completed_at, created_at, updated_at`
+
+func scanChecklist(row pgx.Row, c *models.OffboardingChecklist) error {
+	return row.Scan(
+		&c.ID, &c.CompanyID, &c.EmployeeID, &c.TransitionID, &c.TerminationDate, &c.NewManagerID,
+		&c.HODSuccessorID, &c.ApproverSuccessorID, &c.Status, &c.InitiatedBy,
+		&c.AutomationCompletedAt, &c.CompletedAt, &c.CreatedAt, &c.UpdatedAt,
+	)
+}
+
+// CreateChecklist stores a checklist together with its items.
+func (o *OffboardingRepository) CreateChecklist(ctx context.Context, checklist *models.OffboardingChecklist) (*models.OffboardingChecklist, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := o.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	created, err := insertChecklist(ctx, tx, checklist)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// CreateChecklistWithTransition terminates an employee and opens their
+// checklist in one transaction, so neither exists without the other. With
+// audit set the transition is applied now; without it the transition is
+// scheduled. The stored transition is linked to the checklist. It returns
+// ErrEmployeeStatusChanged when the employee's status no longer matches
+// t.FromStatus.
+func (o *OffboardingRepository) CreateChecklistWithTransition(
+	ctx context.Context,
+	t *models.EmployeeStatusTransition,
+	audit *models.AuditLog,
+	checklist *models.OffboardingChecklist,
+) (*models.EmployeeStatusTransition, *models.OffboardingChecklist, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := o.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	stored, created, err := createChecklistWithTransition(ctx, tx, t, audit, checklist)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return stored, created, nil
+}
+
+// createChecklistWithTransition is CreateChecklistWithTransition within tx.
+func createChecklistWithTransition(
+	ctx context.Context,
+	tx pgx.Tx,
+	t *models.EmployeeStatusTransition,
+	audit *models.AuditLog,
+	checklist *models.OffboardingChecklist,
+) (*models.EmployeeStatusTransition, *models.OffboardingChecklist, error) {
+	var stored *models.EmployeeStatusTransition
+	var err error
+	if audit != nil {
+		stored, err = applyTransition(ctx, tx, t, audit)
+	} else {
+		stored, err = insertScheduledTransition(ctx, tx, t)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	checklist.TransitionID = &stored.ID
+	created, err := insertChecklist(ctx, tx, checklist)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stored, created, nil
+}
+
+// insertChecklist stores a checklist and its items within tx.
+func insertChecklist(ctx context.Context, tx pgx.Tx, checklist *models.OffboardingChecklist) (*models.OffboardingChecklist, error) {
+	var created models.OffboardingChecklist
+	err := scanChecklist(tx.QueryRow(ctx, `
+		INSERT INTO offboarding_checklists (
+			company_id, employee_id, transition_id, termination_date, new_manager_id,
+			hod_successor_id, approver_successor_id, status, initiated_by
+		)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,'scheduled',$8)
+		RETURNING `+checklistColumns,
+		checklist.CompanyID,
+		checklist.EmployeeID,
+		checklist.TransitionID,
+		checklist.TerminationDate,
+		checklist.NewManagerID,
+		checklist.HODSuccessorID,
+		checklist.ApproverSuccessorID,
+		checklist.InitiatedBy,
+	), &created)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range checklist.Items {
+		var createdItem models.OffboardingChecklistItem
+		err := tx.QueryRow(ctx, `
+			INSERT INTO offboarding_checklist_items (checklist_id, code, title, is_automated)
+			VALUES ($1,$2,$3,$4)
+			RETURNING id, checklist_id, code, title, is_automated, status, COALESCE(notes, ''),
+				completed_by, completed_at, created_at
+		`, created.ID, item.Code, item.Title, item.IsAutomated).Scan(
+			&createdItem.ID, &createdItem.ChecklistID, &createdItem.Code, &createdItem.Title,
+			&createdItem.IsAutomated, &createdItem.Status, &createdItem.Notes,
+			&createdItem.CompletedBy, &createdItem.CompletedAt, &createdItem.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		created.Items = append(created.Items, &createdItem)
+	}
+
+	return &created, nil
+}
+
+func (o *OffboardingRepository) GetChecklistByID(ctx context.Context, checklistID uuid.UUID) (*models.OffboardingChecklist, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var checklist models.OffboardingChecklist
+	err := scanChecklist(o.pool.QueryRow(ctx,
+		"SELECT "+checklistColumns+" FROM offboarding_checklists WHERE id = $1",
+		checklistID,
+	), &checklist)
+	if err != nil {
+		return nil, err
+	}
+
+	if checklist.Items, err = o.getItems(ctx, checklist.ID); err != nil {
+		return nil, err
+	}
+	return &checklist, nil
+}
+
+// GetLatestChecklistForEmployee returns the most recent checklist of an
+// employee; a rehired and re-terminated employee has several.
+func (o *OffboardingRepository) GetLatestChecklistForEmployee(ctx context.Context, employeeID uuid.UUID) (*models.OffboardingChecklist, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	var checklist models.OffboardingChecklist
+	err := scanChecklist(o.pool.QueryRow(ctx, `
+		SELECT `+checklistColumns+`
+		FROM offboarding_checklists
+		WHERE employee_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, employeeID), &checklist)
+	if err != nil {
+		return nil, err
+	}
+
+	if checklist.Items, err = o.getItems(ctx, checklist.ID); err != nil {
+		return nil, err
+	}
+	return &checklist, nil
+}
+
+// GetDueChecklists returns scheduled checklists whose employee has been
+// terminated, i.e. whose automated steps are ready to run.
+func (o *OffboardingRepository) GetDueChecklists(ctx context.Context, asOf time.Time) ([]*models.OffboardingChecklist, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	rows, err := o.pool.Query(ctx, `
+		SELECT `+checklistColumns+`
+		FROM offboarding_checklists
+		WHERE status = 'scheduled'
+			AND termination_date <= $1
+			AND employee_id IN (SELECT id FROM employees WHERE status = 'terminated')
+		ORDER BY termination_date
+	`, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checklists []*models.OffboardingChecklist
+	for rows.Next() {
+		var checklist models.OffboardingChecklist
+		if err := scanChecklist(rows, &checklist); err != nil {
+			return nil, err
+		}
+		checklists = append(checklists, &checklist)
+	}
+
+	return checklists, rows.Err()
+}
+
+// ExecuteOffboarding runs the automated offboarding steps in one transaction:
+// revoking calendar feed tokens, reassigning direct reports, transferring HOD
+// posts, cancelling future pending leave and reassigning approval workflow
+// steps.
+// The automated checklist items are completed with a summary of what changed.
+// Running it twice is a no-op because only scheduled checklists are picked up.
+func (o *OffboardingRepository) ExecuteOffboarding(ctx context.Context, checklistID uuid.UUID, audit *models.AuditLog) (*models.OffboardingResult, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	tx, err := o.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var c models.OffboardingChecklist
+	err = scanChecklist(tx.QueryRow(ctx,
+		"SELECT "+checklistColumns+" FROM offboarding_checklists WHERE id = $1 AND status = 'scheduled' FOR UPDATE",
+		checklistID,
+	), &c)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("offboarding already executed")
+		}
+		return nil, err
+	}
+
+	var result models.OffboardingResult
+	notes := map[string]string{}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM calendar_feed_tokens WHERE employee_id = $1", c.EmployeeID); err != nil {
+		return nil, err
+	}
+	notes["revoke_feed_tokens"] = "calendar feed tokens revoked"
+
+	if c.NewManagerID != nil {
+		// A direct report promoted into the leaver's place moves up to the
+		// leaver's own manager before taking over their team.
+		if _, err := tx.Exec(ctx, `
+			UPDATE employees
+			SET manager_id = (SELECT manager_id FROM employees WHERE id = $1), updated_at = CURRENT_TIMESTAMP
+			WHERE id = $2 AND manager_id = $1
+		`, c.EmployeeID, *c.NewManagerID); err != nil {
+			return nil, err
+		}
+
+		tag, err := tx.Exec(ctx, `
+			UPDATE employees
+			SET manager_id = $1, updated_at = CURRENT_TIMESTAMP
+			WHERE manager_id = $2 AND id <> $1
+		`, *c.NewManagerID, c.EmployeeID)
+		if err != nil {
+			return nil, err
+		}
+		result.ReassignedReports = int(tag.RowsAffected())
+	}
+	notes["reassign_direct_reports"] = fmt.Sprintf("%d direct reports reassigned", result.ReassignedReports)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE departments
+		SET hod_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE hod_id = $2
+	`, c.HODSuccessorID, c.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	result.TransferredHODPosts = int(tag.RowsAffected())
+	if c.HODSuccessorID != nil {
+		notes["transfer_hod_posts"] = fmt.Sprintf("%d HOD posts transferred", result.TransferredHODPosts)
+	} else {
+		notes["transfer_hod_posts"] = fmt.Sprintf("%d HOD posts cleared", result.TransferredHODPosts)
+	}
+
+	// Future requests still awaiting a decision are cancelled the way any
+	// cancellation is: their reserved days are released, and the decision is
+	// recorded in approval_history and closes their approval requests.
+	rows, err := tx.Query(ctx, `
+		SELECT `+leaveRequestColumns+`
+		FROM leave_requests
+		WHERE employee_id = $1 AND status IN ('pending', 'changes_requested') AND start_date > $2
+		ORDER BY start_date
+		FOR UPDATE
+	`, c.EmployeeID, c.TerminationDate)
+	if err != nil {
+		return nil, err
+	}
+	cancelled, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.LeaveRequest, error) {
+		var request models.LeaveRequest
+		err := scanLeaveRequest(row, &request)
+		return &request, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range cancelled {
+		if _, _, err := transitionLeaveRequest(ctx, tx, request, &models.ApprovalHistory{
+			ApproverID: audit.UserID,
+			Action:     "cancelled",
+			Comments:   "cancelled by offboarding",
+		}); err != nil {
+			return nil, err
+		}
+	}
+	result.CancelledLeaveRequests = len(cancelled)
+	notes["cancel_pending_leave"] = fmt.Sprintf("%d pending leave requests cancelled", result.CancelledLeaveRequests)
+
+	// Steps naming the leaver as approver are pointed at the successor, or
+	// fall back to their role when there is none.
+	var successor *string
+	if c.ApproverSuccessorID != nil {
+		id := c.ApproverSuccessorID.String()
+		successor = &id
+	}
+	tag, err = tx.Exec(ctx, `
+		UPDATE approval_workflows
+		SET steps = (
+			SELECT jsonb_agg(
+				CASE WHEN step->>'approver_id' = $1
+					THEN jsonb_set(step, '{approver_id}', COALESCE(to_jsonb($2::text), 'null'::jsonb))
+					ELSE step
+				END
+				ORDER BY ord
+			)
+			FROM jsonb_array_elements(steps) WITH ORDINALITY AS s(step, ord)
+		)
+		WHERE company_id = $3
+			AND steps @> jsonb_build_array(jsonb_build_object('approver_id', $1::text))
+	`, c.EmployeeID.String(), successor, c.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	result.ReassignedWorkflows = int(tag.RowsAffected())
+
+	// Requests already under way carry their own copy of the steps.
+	if _, err := tx.Exec(ctx, `
+		UPDATE approval_requests
+		SET steps = (
+			SELECT jsonb_agg(
+				CASE WHEN step->>'approver_id' = $1
+					THEN jsonb_set(step, '{approver_id}', COALESCE(to_jsonb($2::text), 'null'::jsonb))
+					ELSE step
+				END
+				ORDER BY ord
+			)
+			FROM jsonb_array_elements(steps) WITH ORDINALITY AS s(step, ord)
+		)
+		WHERE company_id = $3 AND status IN ('pending', 'changes_requested')
+			AND steps @> jsonb_build_array(jsonb_build_object('approver_id', $1::text))
+	`, c.EmployeeID.String(), successor, c.CompanyID); err != nil {
+		return nil, err
+	}
+	notes["reassign_approval_steps"] = fmt.Sprintf("%d approval workflows updated", result.ReassignedWorkflows)
+
+	for code, note := range notes {
+		if _, err := tx.Exec(ctx, `
+			UPDATE offboarding_checklist_items
+			SET status = 'completed', notes = $1, completed_at = CURRENT_TIMESTAMP
+			WHERE checklist_id = $2 AND code = $3 AND is_automated
+		`, note, c.ID, code); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE offboarding_checklists SET automation_completed_at = CURRENT_TIMESTAMP WHERE id = $1",
+		c.ID,
+	); err != nil {
+		return nil, err
+	}
+	if err := refreshChecklistStatus(ctx, tx, c.ID); err != nil {
+		return nil, err
+	}
+
+	audit.EntityID = &c.ID
+	audit.NewValues = map[string]any{
+		"reassigned_reports":       result.ReassignedReports,
+		"transferred_hod_posts":    result.TransferredHODPosts,
+		"cancelled_leave_requests": result.CancelledLeaveRequests,
+		"reassigned_workflows":     result.ReassignedWorkflows,
+	}
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UpdateItemStatus completes or skips a pending checklist item and closes the
+// checklist once nothing is pending.
+func (o *OffboardingRepository) UpdateItemStatus(ctx context.Context, checklistID, itemID uuid.UUID, status, notes string, completedBy *uuid.UUID) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := o.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE offboarding_checklist_items
+		SET status = $1, notes = NULLIF($2, ''), completed_by = $3, completed_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND checklist_id = $5 AND status = 'pending'
+	`, status, notes, completedBy, itemID, checklistID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("pending checklist item not found")
+	}
+
+	if err := refreshChecklistStatus(ctx, tx, checklistID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// refreshChecklistStatus marks a checklist whose automated steps have run as
+// completed when no items are pending, or in_progress otherwise.
+func refreshChecklistStatus(ctx context.Context, db dbExecutor, checklistID uuid.UUID) error {
+	_, err := db.Exec(ctx, `
+		UPDATE offboarding_checklists
+		SET
+			status = CASE WHEN pending.n = 0 THEN 'completed' ELSE 'in_progress' END,
+			completed_at = CASE WHEN pending.n = 0 THEN CURRENT_TIMESTAMP ELSE NULL END
+		FROM (
+			SELECT COUNT(*) AS n FROM offboarding_checklist_items
+			WHERE checklist_id = $1 AND status = 'pending'
+		) pending
+		WHERE id = $1 AND automation_completed_at IS NOT NULL
+	`, checklistID)
+	return err
+}
+
+func (o *OffboardingRepository) getItems(ctx context.Context, checklistID uuid.UUID) ([]*models.OffboardingChecklistItem, error) {
+	rows, err := o.pool.Query(ctx, `
+		SELECT id, checklist_id, code, title, is_automated, status, COALESCE(notes, ''),
+			completed_by, completed_at, created_at
+		FROM offboarding_checklist_items
+		WHERE checklist_id = $1
+		ORDER BY is_automated DESC, created_at, code
+	`, checklistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*models.OffboardingChecklistItem
+	for rows.Next() {
+		var item models.OffboardingChecklistItem
+		if err := rows.Scan(
+			&item.ID, &item.ChecklistID, &item.Code, &item.Title, &item.IsAutomated,
+			&item.Status, &item.Notes, &item.CompletedBy, &item.CompletedAt, &item.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestOffboardingRepository_ExecuteOffboarding(t *testing.T) {
+	employeeRepo := setupEmployeeRepository(t)
+	pool := setupTestDB(t)
+	repo := NewOffboardingRepository(pool)
+	ctx := context.Background()
+
+	if err := cleanupEmployeeTestData(ctx, pool, testCompanyID); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+
+	leaver := createHierarchyEmployee(t, employeeRepo, "leaver", nil)
+	successor := createHierarchyEmployee(t, employeeRepo, "successor", &leaver.ID)
+	report := createHierarchyEmployee(t, employeeRepo, "report", &leaver.ID)
+
+	checklist, err := repo.CreateChecklist(ctx, &models.OffboardingChecklist{
+		CompanyID:       uuid.MustParse(testCompanyID),
+		EmployeeID:      leaver.ID,
+		TerminationDate: time.Now(),
+		NewManagerID:    &successor.ID,
+		Items: []*models.OffboardingChecklistItem{
+			{Code: "reassign_direct_reports", Title: "Reassign direct reports", IsAutomated: true},
+			{Code: "exit_interview", Title: "Conduct exit interview"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateChecklist failed: %v", err)
+	}
+
+	result, err := repo.ExecuteOffboarding(ctx, checklist.ID, &models.AuditLog{
+		CompanyID:  uuid.MustParse(testCompanyID),
+		Action:     "employee_offboarded",
+		EntityType: "offboarding_checklist",
+	})
+	if err != nil {
+		t.Fatalf("ExecuteOffboarding failed: %v", err)
+	}
+	if result.ReassignedReports != 1 {
+		t.Errorf("expected 1 reassigned report, got %d", result.ReassignedReports)
+	}
+
+	updated, err := employeeRepo.GetEmployeeByID(ctx, report.ID)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if updated.ManagerID == nil || *updated.ManagerID != successor.ID {
+		t.Error("expected report to move to the successor")
+	}
+
+	stored, err := repo.GetChecklistByID(ctx, checklist.ID)
+	if err != nil {
+		t.Fatalf("GetChecklistByID failed: %v", err)
+	}
+	if stored.Status != "in_progress" {
+		t.Errorf("expected in_progress while manual items remain, got %s", stored.Status)
+	}
+
+	if _, err := repo.ExecuteOffboarding(ctx, checklist.ID, &models.AuditLog{}); err == nil {
+		t.Error("expected second execution to be rejected")
+	}
+}
+
+func TestOffboardingRepository_CancelScheduledTermination(t *testing.T) {
+	employeeRepo := setupEmployeeRepository(t)
+	pool := setupTestDB(t)
+	repo := NewOffboardingRepository(pool)
+	lifecycleRepo := NewEmployeeLifecycleRepository(pool)
+	ctx := context.Background()
+
+	if err := cleanupEmployeeTestData(ctx, pool, testCompanyID); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+
+	leaver := createHierarchyEmployee(t, employeeRepo, "leaver", nil)
+	terminationDate := time.Now().AddDate(0, 1, 0)
+
+	transition, checklist, err := repo.CreateChecklistWithTransition(ctx, &models.EmployeeStatusTransition{
+		CompanyID:       uuid.MustParse(testCompanyID),
+		EmployeeID:      leaver.ID,
+		FromStatus:      leaver.Status,
+		ToStatus:        "terminated",
+		EffectiveDate:   terminationDate,
+		TerminationDate: &terminationDate,
+		Reason:          "resigned",
+	}, nil, &models.OffboardingChecklist{
+		CompanyID:       uuid.MustParse(testCompanyID),
+		EmployeeID:      leaver.ID,
+		TerminationDate: terminationDate,
+		Items: []*models.OffboardingChecklistItem{
+			{Code: "exit_interview", Title: "Conduct exit interview"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateChecklistWithTransition failed: %v", err)
+	}
+
+	if err := lifecycleRepo.CancelTransition(ctx, transition.ID); err != nil {
+		t.Fatalf("CancelTransition failed: %v", err)
+	}
+
+	if _, err := repo.GetChecklistByID(ctx, checklist.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected the checklist to be deleted with the transition, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type OrgChartRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOrgChartRepository(pool *pgxpool.Pool) *OrgChartRepository {
+	return &OrgChartRepository{
+		pool: pool,
+	}
+}
+
+// GetPeopleTree returns the reporting tree below rootID, or below every
+// employee without a manager when rootID is nil. Terminated employees are
+// left out. A maxDepth of 0 means unlimited.
+func (o *OrgChartRepository) GetPeopleTree(ctx context.Context, companyID uuid.UUID, rootID *uuid.UUID, maxDepth int) ([]*models.OrgChartNode, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, manager_id AS parent_id, 0 AS depth, ARRAY[id] AS path
+			FROM employees
+			WHERE company_id = $1
+				AND status <> 'terminated'
+				AND (($2::uuid IS NULL AND manager_id IS NULL) OR id = $2::uuid)
+			UNION ALL
+			SELECT e.id, e.manager_id, t.depth + 1, t.path || e.id
+			FROM employees e
+			JOIN tree t ON e.manager_id = t.id
+			WHERE e.status <> 'terminated'
+				AND NOT e.id = ANY(t.path)
+				AND ($3::int = 0 OR t.depth < $3::int)
+		)
+		SELECT
+			t.id, t.parent_id, t.depth,
+			e.first_name || ' ' || e.last_name,
+			COALESCE(des.name, ''),
+			COALESCE(e.profile_image_url, ''),
+			''
+		FROM tree t
+		JOIN employees e ON e.id = t.id
+		LEFT JOIN designations des ON des.id = e.designation_id
+		ORDER BY t.depth, e.last_name, e.first_name
+	`
+
+	rows, err := o.pool.Query(ctx, query, companyID, rootID, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	return scanOrgChartNodes(rows)
+}
+
+// GetDepartmentTree returns the department tree below rootID, or below every
+// top-level department when rootID is nil, with each department's HOD.
+func (o *OrgChartRepository) GetDepartmentTree(ctx context.Context, companyID uuid.UUID, rootID *uuid.UUID, maxDepth int) ([]*models.OrgChartNode, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, parent_department_id AS parent_id, 0 AS depth, ARRAY[id] AS path
+			FROM departments
+			WHERE company_id = $1
+				AND (($2::uuid IS NULL AND parent_department_id IS NULL) OR id = $2::uuid)
+			UNION ALL
+			SELECT d.id, d.parent_department_id, t.depth + 1, t.path || d.id
+			FROM departments d
+			JOIN tree t ON d.parent_department_id = t.id
+			WHERE NOT d.id = ANY(t.path)
+				AND ($3::int = 0 OR t.depth < $3::int)
+		)
+		SELECT
+			t.id, t.parent_id, t.depth,
+			d.name,
+			COALESCE(des.name, ''),
+			COALESCE(h.profile_image_url, ''),
+			COALESCE(h.first_name || ' ' || h.last_name, '')
+		FROM tree t
+		JOIN departments d ON d.id = t.id
+		LEFT JOIN employees h ON h.id = d.hod_id
+		LEFT JOIN designations des ON des.id = h.designation_id
+		ORDER BY t.depth, d.name
+	`
+
+	rows, err := o.pool.Query(ctx, query, companyID, rootID, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	return scanOrgChartNodes(rows)
+}
+
+func scanOrgChartNodes(rows pgx.Rows) ([]*models.OrgChartNode, error) {
+	defer rows.Close()
+
+	var nodes []*models.OrgChartNode
+	for rows.Next() {
+		var node models.OrgChartNode
+		if err := rows.Scan(
+			&node.ID, &node.ParentID, &node.Depth,
+			&node.Name, &node.Designation, &node.PhotoURL, &node.HeadName,
+		); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, &node)
+	}
+
+	return nodes, rows.Err()
+}
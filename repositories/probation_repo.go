@@ -0,0 +1,262 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+type ProbationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewProbationRepository(pool *pgxpool.Pool) *ProbationRepository {
+	return &ProbationRepository{
+		pool: pool,
+	}
+}
+
+const probationReviewColumns = `
+	id, company_id, employee_id, decision, previous_end_date, new_end_date,
+	comments, decided_by, created_at`
+
+func scanProbationReview(row pgx.Row, r *models.ProbationReview) error {
+	return row.Scan(
+		&r.ID, &r.CompanyID, &r.EmployeeID, &r.Decision, &r.PreviousEndDate, &r.NewEndDate,
+		&r.Comments, &r.DecidedBy, &r.CreatedAt,
+	)
+}
+
+// GetProbationsEndingBy returns employees still on probation whose probation
+// ends on or before date, soonest first. A nil companyID covers every company.
+func (p *ProbationRepository) GetProbationsEndingBy(ctx context.Context, companyID *uuid.UUID, date time.Time) ([]*models.Employee, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM employees e
+		WHERE e.status = 'probation'
+			AND e.probation_end_date <= $1
+			AND ($2::uuid IS NULL OR e.company_id = $2)
+		ORDER BY e.probation_end_date, e.last_name, e.first_name
+	`, employeeColumns)
+
+	rows, err := p.pool.Query(ctx, query, date, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var employees []*models.Employee
+	for rows.Next() {
+		var emp models.Employee
+		if err := scanEmployee(rows, &emp); err != nil {
+			return nil, err
+		}
+		employees = append(employees, &emp)
+	}
+
+	return employees, rows.Err()
+}
+
+// ExtendProbation moves the probation end date of an employee still on
+// probation and records the review and audit entry in one transaction.
+func (p *ProbationRepository) ExtendProbation(ctx context.Context, review *models.ProbationReview, audit *models.AuditLog) (*models.ProbationReview, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE employees
+		SET probation_end_date = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status = 'probation'
+	`, review.NewEndDate, review.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrEmployeeStatusChanged
+	}
+
+	created, err := recordProbationReview(ctx, tx, review, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ConfirmProbation applies the employee's move out of probation and records
+// the review and its audit entry in one transaction. It returns
+// ErrEmployeeStatusChanged when the employee is no longer on probation.
+func (p *ProbationRepository) ConfirmProbation(
+	ctx context.Context,
+	t *models.EmployeeStatusTransition,
+	transitionAudit *models.AuditLog,
+	review *models.ProbationReview,
+	audit *models.AuditLog,
+) (*models.ProbationReview, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := applyTransition(ctx, tx, t, transitionAudit); err != nil {
+		return nil, err
+	}
+
+	created, err := recordProbationReview(ctx, tx, review, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// TerminateProbation terminates the employee, opens their offboarding
+// checklist and records the review and its audit entry in one transaction.
+// As with CreateChecklistWithTransition, the termination is applied now when
+// transitionAudit is set and scheduled otherwise.
+func (p *ProbationRepository) TerminateProbation(
+	ctx context.Context,
+	t *models.EmployeeStatusTransition,
+	transitionAudit *models.AuditLog,
+	checklist *models.OffboardingChecklist,
+	review *models.ProbationReview,
+	audit *models.AuditLog,
+) (*models.EmployeeStatusTransition, *models.OffboardingChecklist, *models.ProbationReview, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	stored, created, err := createChecklistWithTransition(ctx, tx, t, transitionAudit, checklist)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	recorded, err := recordProbationReview(ctx, tx, review, audit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return stored, created, recorded, nil
+}
+
+// ListReviews returns the probation decisions recorded for an employee,
+// newest first.
+func (p *ProbationRepository) ListReviews(ctx context.Context, employeeID uuid.UUID) ([]*models.ProbationReview, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	query := `
+		SELECT ` + probationReviewColumns + `
+		FROM probation_reviews
+		WHERE employee_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := p.pool.Query(ctx, query, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []*models.ProbationReview
+	for rows.Next() {
+		var review models.ProbationReview
+		if err := scanProbationReview(rows, &review); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, &review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// recordProbationReview stores review and its audit entry within tx.
+func recordProbationReview(ctx context.Context, tx pgx.Tx, review *models.ProbationReview, audit *models.AuditLog) (*models.ProbationReview, error) {
+	created, err := insertProbationReview(ctx, tx, review)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.EntityID = &created.ID
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func insertProbationReview(ctx context.Context, db dbExecutor, r *models.ProbationReview) (*models.ProbationReview, error) {
+	query := `
+		INSERT INTO probation_reviews (
+			company_id, employee_id, decision, previous_end_date, new_end_date,
+			comments, decided_by
+		)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+		RETURNING ` + probationReviewColumns
+
+	var created models.ProbationReview
+	err := scanProbationReview(db.QueryRow(ctx, query,
+		r.CompanyID,
+		r.EmployeeID,
+		r.Decision,
+		r.PreviousEndDate,
+		r.NewEndDate,
+		r.Comments,
+		r.DecidedBy,
+	), &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// approvalResources maps workflow types onto the permission resource whose
+// approve right a delegate needs.
+var approvalResources = map[string]string{
+	"leave":   "leaves",
+	"memo":    "memos",
+	"expense": "expenses",
+}
+
+// CreateDelegation hands delegatorID's approval authority to another
+// employee. The delegator or an HR manager may set it up, and the delegate
+// must be an active colleague whose role can approve every workflow type
+// the delegation covers.
+func (s *ApprovalService) CreateDelegation(
+	ctx context.Context,
+	companyID, delegatorID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.CreateApprovalDelegationRequest,
+) (*dto.ApprovalDelegationResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, delegatorID); err != nil {
+		return nil, err
+	}
+	if err := s.checkDelegationActor(ctx, companyID, delegatorID, actorID); err != nil {
+		return nil, err
+	}
+
+	delegation, err := buildApprovalDelegation(companyID, delegatorID, req, utils.Today())
+	if err != nil {
+		return nil, err
+	}
+	delegation.CreatedBy = actorID
+
+	delegate, err := s.activeEmployee(ctx, companyID, delegation.DelegateID)
+	if err != nil {
+		return nil, err
+	}
+	if delegate == nil || delegate.Status == "inactive" {
+		return nil, &utils.ValidationError{Field: "delegate_id", Message: "delegate must be an active employee of the company"}
+	}
+
+	types := workflowTypes
+	if delegation.WorkflowType != "" {
+		types = []string{delegation.WorkflowType}
+	}
+	for _, workflowType := range types {
+		allowed, err := s.approvalRepo.CanApprove(ctx, delegate.ID, approvalResources[workflowType])
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, &utils.ValidationError{Field: "delegate_id", Message: "the delegate's role cannot approve " + workflowType + " requests"}
+		}
+	}
+
+	overlaps, err := s.approvalRepo.HasOverlappingDelegation(ctx, delegation)
+	if err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, &utils.ValidationError{Field: "start_date", Message: "an existing delegation already covers part of this period; revoke it first"}
+	}
+
+	created, err := s.approvalRepo.CreateDelegation(ctx, delegation)
+	if err != nil {
+		return nil, err
+	}
+	return toApprovalDelegationResponse(created), nil
+}
+
+// ListDelegations returns the delegations an employee has given or
+// received.
+func (s *ApprovalService) ListDelegations(ctx context.Context, companyID, employeeID uuid.UUID) ([]*dto.ApprovalDelegationResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+
+	delegations, err := s.approvalRepo.ListDelegations(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ApprovalDelegationResponse, 0, len(delegations))
+	for _, d := range delegations {
+		responses = append(responses, toApprovalDelegationResponse(d))
+	}
+	return responses, nil
+}
+
+// RevokeDelegation ends one of delegatorID's delegations immediately.
+func (s *ApprovalService) RevokeDelegation(ctx context.Context, companyID, delegatorID, delegationID uuid.UUID, actorID *uuid.UUID) (*dto.ApprovalDelegationResponse, error) {
+	delegation, err := s.approvalRepo.GetDelegationByID(ctx, delegationID)
+	if err != nil || delegation.CompanyID != companyID || delegation.DelegatorID != delegatorID {
+		return nil, ErrApprovalDelegationNotFound
+	}
+	if err := s.checkDelegationActor(ctx, companyID, delegatorID, actorID); err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.approvalRepo.RevokeDelegation(ctx, delegationID)
+	if err != nil {
+		return nil, err
+	}
+	return toApprovalDelegationResponse(revoked), nil
+}
+
+// checkDelegationActor allows the delegator and HR managers to manage a
+// delegator's delegations.
+func (s *ApprovalService) checkDelegationActor(ctx context.Context, companyID, delegatorID uuid.UUID, actorID *uuid.UUID) error {
+	if actorID == nil {
+		return &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	if *actorID == delegatorID {
+		return nil
+	}
+
+	hr, err := s.employeeRepo.GetEmployeesByRoleName(ctx, companyID, hrRoleName)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(hr, func(e *models.Employee) bool { return e.ID == *actorID }) {
+		return forbidden("only the approver or HR can manage their delegations")
+	}
+	return nil
+}
+
+// buildApprovalDelegation parses and checks a delegation request. It may
+// start in the past but must not have ended before today.
+func buildApprovalDelegation(companyID, delegatorID uuid.UUID, req *dto.CreateApprovalDelegationRequest, today time.Time) (*models.ApprovalDelegation, error) {
+	delegateID, err := uuid.Parse(req.DelegateID)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "delegate_id", Message: "invalid delegate_id"}
+	}
+	if delegateID == delegatorID {
+		return nil, &utils.ValidationError{Field: "delegate_id", Message: "approvers cannot delegate to themselves"}
+	}
+	if req.WorkflowType != "" && !slices.Contains(workflowTypes, req.WorkflowType) {
+		return nil, &utils.ValidationError{Field: "workflow_type", Message: "workflow_type must be one of leave, memo, expense"}
+	}
+
+	startDate, err := utils.ParseDate(req.StartDate)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "start_date", Message: "start_date must be YYYY-MM-DD"}
+	}
+	var endDate *time.Time
+	if req.EndDate != "" {
+		end, err := utils.ParseDate(req.EndDate)
+		if err != nil {
+			return nil, &utils.ValidationError{Field: "end_date", Message: "end_date must be YYYY-MM-DD"}
+		}
+		if end.Before(startDate) {
+			return nil, &utils.ValidationError{Field: "end_date", Message: "end_date cannot be before start_date"}
+		}
+		if end.Before(today) {
+			return nil, &utils.ValidationError{Field: "end_date", Message: "end_date cannot be in the past"}
+		}
+		endDate = &end
+	}
+
+	return &models.ApprovalDelegation{
+		CompanyID:    companyID,
+		DelegatorID:  delegatorID,
+		DelegateID:   delegateID,
+		WorkflowType: req.WorkflowType,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		OnLeaveOnly:  req.OnLeaveOnly,
+		Reason:       strings.TrimSpace(req.Reason),
+	}, nil
+}
+
+func toApprovalDelegationResponse(d *models.ApprovalDelegation) *dto.ApprovalDelegationResponse {
+	return &dto.ApprovalDelegationResponse{
+		ID:           d.ID.String(),
+		DelegatorID:  d.DelegatorID.String(),
+		DelegateID:   d.DelegateID.String(),
+		WorkflowType: d.WorkflowType,
+		StartDate:    d.StartDate,
+		EndDate:      d.EndDate,
+		OnLeaveOnly:  d.OnLeaveOnly,
+		Reason:       d.Reason,
+		CreatedBy:    uuidStringPtr(d.CreatedBy),
+		RevokedAt:    d.RevokedAt,
+		CreatedAt:    d.CreatedAt,
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestBuildApprovalDelegation(t *testing.T) {
+	today := time.Date(2026, time.June, 10, 0, 0, 0, 0, time.UTC)
+	delegator := uuid.New()
+	delegate := uuid.NewString()
+
+	tests := []struct {
+		name    string
+		req     dto.CreateApprovalDelegationRequest
+		wantErr bool
+	}{
+		{"date range", dto.CreateApprovalDelegationRequest{DelegateID: delegate, StartDate: "2026-06-15", EndDate: "2026-06-26"}, false},
+		{"open-ended on leave only", dto.CreateApprovalDelegationRequest{DelegateID: delegate, StartDate: "2026-06-01", OnLeaveOnly: true}, false},
+		{"one workflow type", dto.CreateApprovalDelegationRequest{DelegateID: delegate, WorkflowType: "leave", StartDate: "2026-06-15"}, false},
+		{"to themselves", dto.CreateApprovalDelegationRequest{DelegateID: delegator.String(), StartDate: "2026-06-15"}, true},
+		{"bad delegate", dto.CreateApprovalDelegationRequest{DelegateID: "nope", StartDate: "2026-06-15"}, true},
+		{"unknown workflow type", dto.CreateApprovalDelegationRequest{DelegateID: delegate, WorkflowType: "travel", StartDate: "2026-06-15"}, true},
+		{"end before start", dto.CreateApprovalDelegationRequest{DelegateID: delegate, StartDate: "2026-06-15", EndDate: "2026-06-12"}, true},
+		{"already ended", dto.CreateApprovalDelegationRequest{DelegateID: delegate, StartDate: "2026-06-01", EndDate: "2026-06-09"}, true},
+		{"bad start date", dto.CreateApprovalDelegationRequest{DelegateID: delegate, StartDate: "15/06/2026"}, true},
+	}
+
+	for _, tt := range tests {
+		delegation, err := buildApprovalDelegation(uuid.New(), delegator, &tt.req, today)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if err == nil && (delegation.DelegatorID != delegator || delegation.OnLeaveOnly != tt.req.OnLeaveOnly) {
+			t.Errorf("%s: unexpected delegation %+v", tt.name, delegation)
+		}
+	}
+}
+
+func TestApprovalHistorySummary(t *testing.T) {
+	approver, principal := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		history models.ApprovalHistory
+		want    string
+	}{
+		{"automatic", models.ApprovalHistory{Action: "rejected"}, "rejected automatically"},
+		{"approver", models.ApprovalHistory{Action: "approved", ApproverID: &approver, ApproverName: "Ada Obi"}, "approved by Ada Obi"},
+		{"changes requested", models.ApprovalHistory{Action: "requested_changes", ApproverID: &approver, ApproverName: "Ada Obi"}, "changes requested by Ada Obi"},
+		{"delegate", models.ApprovalHistory{
+			Action: "approved", ApproverID: &approver, ApproverName: "Ada Obi",
+			OnBehalfOf: &principal, OnBehalfOfName: "Tunde Bello",
+		}, "approved by Ada Obi on behalf of Tunde Bello"},
+	}
+
+	for _, tt := range tests {
+		if got := approvalHistorySummary(&tt.history); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// bulkApprovalActions maps the verbs accepted for bulk decisions onto
+// engine actions.
+var bulkApprovalActions = map[string]string{
+	"approve": "approved",
+	"reject":  "rejected",
+}
+
+// maxBulkApprovalItems bounds how many items one bulk decision covers.
+const maxBulkApprovalItems = 100
+
+// defaultInboxPageSize is the inbox page size when the caller names none.
+const defaultInboxPageSize = 20
+
+// Inbox lists one page of the pending items whose current step approverID
+// may decide: as a step approver, through a delegation or an escalation, or
+// as HR when nobody else can. Counts cover every type, whatever req.Type is.
+func (s *ApprovalService) Inbox(
+	ctx context.Context,
+	companyID uuid.UUID,
+	approverID *uuid.UUID,
+	req *dto.ApprovalInboxRequest,
+	now time.Time,
+) (*dto.ApprovalInboxResponse, error) {
+	if approverID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	if req.Type != "" && !slices.Contains(workflowTypes, req.Type) {
+		return nil, &utils.ValidationError{Field: "type", Message: "type must be one of " + strings.Join(workflowTypes, ", ")}
+	}
+	requesterID, err := parseOptionalUUID(req.RequesterID)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "requester_id", Message: "invalid requester_id"}
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = defaultInboxPageSize
+	}
+	if req.Page < 1 {
+		return nil, &utils.ValidationError{Field: "page", Message: "page must be at least 1"}
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		return nil, &utils.ValidationError{Field: "page_size", Message: "page_size must be between 1 and 100"}
+	}
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, *approverID); err != nil {
+		return nil, err
+	}
+	asHR, err := isHR(ctx, s.employeeRepo, companyID, *approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	page, counts, err := s.approvalRepo.ListInboxApprovalRequests(ctx, companyID, *approverID, asHR, requesterID, req, now)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ApprovalInboxResponse{
+		Counts:     map[string]int{},
+		Total:      page.Total,
+		Page:       page.Page,
+		PageSize:   page.PageSize,
+		TotalPages: page.TotalPages,
+		HasNext:    page.HasNext,
+		HasPrev:    page.HasPrev,
+		Items:      []*dto.ApprovalInboxItemResponse{},
+	}
+	for _, t := range workflowTypes {
+		response.Counts[t] = counts[t]
+	}
+	// The query has already picked this page's items as ones the caller may
+	// decide, so every one is listed; loading them only fills in their
+	// details and whose behalf the caller acts on.
+	for _, request := range page.Data {
+		item, err := s.inboxItem(ctx, companyID, request, *approverID, now)
+		if err != nil {
+			return nil, err
+		}
+		response.Items = append(response.Items, item)
+	}
+
+	return response, nil
+}
+
+// inboxItem describes request as an inbox item of approverID. An item whose
+// entity can no longer be loaded is listed with what its approval request
+// says alone.
+func (s *ApprovalService) inboxItem(ctx context.Context, companyID uuid.UUID, request *models.ApprovalRequest, approverID uuid.UUID, now time.Time) (*dto.ApprovalInboxItemResponse, error) {
+	step := approvalStepAt(request.Steps, request.CurrentStep)
+	deadline, hasSLA := stepDeadline(request, step)
+	item := &dto.ApprovalInboxItemResponse{
+		EntityType:   request.EntityType,
+		EntityID:     request.EntityID.String(),
+		RequesterID:  request.RequesterID.String(),
+		Step:         request.CurrentStep,
+		TotalSteps:   len(request.Steps),
+		WaitingSince: request.StepStartedAt,
+		Overdue:      hasSLA && !now.Before(deadline),
+	}
+	if step != nil {
+		item.StepName = step.Name
+	}
+	if hasSLA {
+		item.DueAt = &deadline
+	}
+
+	target, ok := s.targets[request.EntityType]
+	if !ok {
+		return item, nil
+	}
+	subject, err := target.ApprovalSubject(ctx, companyID, request.EntityID)
+	if errors.Is(err, ErrNotFound) {
+		return item, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	item.Type = subject.WorkflowType
+	item.Title = subject.Title
+	item.RequesterName = subject.Requester.FirstName + " " + subject.Requester.LastName
+
+	approvers, err := s.currentApprovers(ctx, request, subject)
+	if err != nil {
+		return nil, err
+	}
+	if approver := inboxApprover(approvers, approverID); approver != nil {
+		item.OnBehalfOf = uuidStringPtr(approver.OnBehalfOf)
+	}
+	return item, nil
+}
+
+// inboxApprover returns how approverID stands among a step's approvers,
+// preferring their own right to decide over a delegation.
+func inboxApprover(approvers []stepApprover, approverID uuid.UUID) *stepApprover {
+	var found *stepApprover
+	for i := range approvers {
+		if approvers[i].ID != approverID {
+			continue
+		}
+		if approvers[i].OnBehalfOf == nil {
+			return &approvers[i]
+		}
+		if found == nil {
+			found = &approvers[i]
+		}
+	}
+	return found
+}
+
+// BulkDecide approves or rejects several items on behalf of actorID. Each
+// item is decided in its own transaction, so one failing leaves the others
+// decided; the result of each is reported in the order given.
+func (s *ApprovalService) BulkDecide(
+	ctx context.Context,
+	companyID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.BulkApprovalDecisionRequest,
+) (*dto.BulkApprovalDecisionResponse, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	action, ok := bulkApprovalActions[req.Action]
+	if !ok {
+		return nil, &utils.ValidationError{Field: "action", Message: "action must be approve or reject"}
+	}
+	comments := strings.TrimSpace(req.Comments)
+	if action == "rejected" && comments == "" {
+		return nil, &utils.ValidationError{Field: "comments", Message: "a reason is required when rejecting"}
+	}
+	if len(req.Items) == 0 || len(req.Items) > maxBulkApprovalItems {
+		return nil, &utils.ValidationError{Field: "items", Message: "between 1 and 100 items can be decided at once"}
+	}
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, *actorID); err != nil {
+		return nil, err
+	}
+
+	response := &dto.BulkApprovalDecisionResponse{Results: make([]*dto.BulkApprovalResultResponse, 0, len(req.Items))}
+	seen := make(map[string]bool, len(req.Items))
+	for _, item := range req.Items {
+		result := &dto.BulkApprovalResultResponse{EntityType: item.EntityType, EntityID: item.EntityID}
+		response.Results = append(response.Results, result)
+
+		key := item.EntityType + ":" + item.EntityID
+		entityID, err := uuid.Parse(item.EntityID)
+		_, known := s.targets[item.EntityType]
+		switch {
+		case !known:
+			result.Error = "unknown entity_type"
+		case err != nil:
+			result.Error = "invalid entity_id"
+		case seen[key]:
+			result.Error = "listed more than once"
+		default:
+			seen[key] = true
+			var decision *ApprovalDecision
+			if decision, err = s.Decide(ctx, companyID, item.EntityType, entityID, *actorID, action, comments); err == nil {
+				result.Success = true
+				result.Status, result.NextStep = action, decision.NextStep
+				if decision.NextStep != 0 {
+					result.Status = "pending"
+				}
+			} else {
+				result.Error = bulkDecisionError(err, item.EntityType, entityID)
+			}
+		}
+
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+	return response, nil
+}
+
+// bulkDecisionError explains why one item of a bulk decision failed without
+// exposing internal errors, which are logged instead.
+func bulkDecisionError(err error, entityType string, entityID uuid.UUID) string {
+	var validationErr *utils.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return validationErr.Error()
+	case errors.Is(err, ErrNotFound):
+		return err.Error()
+	}
+	log.Printf("bulk decide %s %s: %v", entityType, entityID, err)
+	return "could not be decided; try again"
+}
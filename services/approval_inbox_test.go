@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+)
+
+func TestInboxApprover(t *testing.T) {
+	me, principal, other := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name          string
+		approvers     []stepApprover
+		wantFound     bool
+		wantDelegated bool
+	}{
+		{"not an approver", []stepApprover{{ID: other}}, false, false},
+		{"direct", []stepApprover{{ID: other}, {ID: me}}, true, false},
+		{"delegate", []stepApprover{{ID: principal}, {ID: me, OnBehalfOf: &principal}}, true, true},
+		{"direct beats delegate", []stepApprover{{ID: me, OnBehalfOf: &principal}, {ID: me}}, true, false},
+	}
+
+	for _, tt := range tests {
+		got := inboxApprover(tt.approvers, me)
+		if (got != nil) != tt.wantFound {
+			t.Errorf("%s: expected found %v, got %+v", tt.name, tt.wantFound, got)
+			continue
+		}
+		if got != nil && (got.OnBehalfOf != nil) != tt.wantDelegated {
+			t.Errorf("%s: expected delegated %v, got %+v", tt.name, tt.wantDelegated, got)
+		}
+	}
+}
+
+func TestBulkDecideValidation(t *testing.T) {
+	actor := uuid.New()
+	item := dto.BulkApprovalItemRequest{EntityType: leaveApprovalEntity, EntityID: uuid.NewString()}
+	tooMany := make([]dto.BulkApprovalItemRequest, maxBulkApprovalItems+1)
+
+	tests := []struct {
+		name  string
+		actor *uuid.UUID
+		req   dto.BulkApprovalDecisionRequest
+	}{
+		{"no actor", nil, dto.BulkApprovalDecisionRequest{Action: "approve", Items: []dto.BulkApprovalItemRequest{item}}},
+		{"unknown action", &actor, dto.BulkApprovalDecisionRequest{Action: "escalate", Items: []dto.BulkApprovalItemRequest{item}}},
+		{"reject without reason", &actor, dto.BulkApprovalDecisionRequest{Action: "reject", Items: []dto.BulkApprovalItemRequest{item}}},
+		{"no items", &actor, dto.BulkApprovalDecisionRequest{Action: "approve"}},
+		{"too many items", &actor, dto.BulkApprovalDecisionRequest{Action: "approve", Items: tooMany}},
+	}
+
+	s := &ApprovalService{}
+	for _, tt := range tests {
+		if _, err := s.BulkDecide(context.Background(), uuid.New(), tt.actor, &tt.req); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// Resubmission is how an entity sent back for changes goes back before its
+// approvers.
+type Resubmission struct {
+	Step     int                      // The step approval restarts at
+	Revision *models.ApprovalRevision // The entity as resubmitted; recorded only if it changed
+	Changes  []*dto.RevisionChangeResponse
+}
+
+// recordRevision records the subject's reviewable fields as its next
+// revision, unless they have not changed since the last one.
+func (s *ApprovalService) recordRevision(ctx context.Context, subject *ApprovalSubject) error {
+	if subject.Revision == nil {
+		return nil
+	}
+	_, err := s.approvalRepo.RecordRevision(ctx, &models.ApprovalRevision{
+		EntityType:  subject.EntityType,
+		EntityID:    subject.EntityID,
+		Data:        subject.Revision,
+		SubmittedBy: &subject.Requester.ID,
+	})
+	return err
+}
+
+// PrepareResubmission works out where an entity sent back for changes
+// restarts once its requester resubmits it. The target then applies the
+// returned Resubmission in its own transaction and calls Resubmitted.
+func (s *ApprovalService) PrepareResubmission(ctx context.Context, subject *ApprovalSubject) (*Resubmission, error) {
+	request, err := s.approvalRepo.GetApprovalRequest(ctx, subject.EntityType, subject.EntityID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrApprovalRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != "changes_requested" {
+		return nil, &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only items sent back for changes can be resubmitted; this one is %s", request.Status)}
+	}
+
+	revisions, err := s.approvalRepo.ListRevisions(ctx, subject.EntityType, subject.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	var previous map[string]any
+	if len(revisions) > 0 {
+		previous = revisions[len(revisions)-1].Data
+	}
+
+	changes := revisionChanges(previous, subject.Revision)
+	return &Resubmission{
+		Step: resubmitStep(request.CurrentStep, len(changes) > 0),
+		Revision: &models.ApprovalRevision{
+			EntityType:  subject.EntityType,
+			EntityID:    subject.EntityID,
+			Data:        subject.Revision,
+			SubmittedBy: &subject.Requester.ID,
+		},
+		Changes: changes,
+	}, nil
+}
+
+// Resubmitted tells the approvers of a resubmitted entity's step that it is
+// back with them. Failures are logged rather than undoing the resubmission.
+func (s *ApprovalService) Resubmitted(ctx context.Context, subject *ApprovalSubject) {
+	request, err := s.approvalRepo.GetApprovalRequest(ctx, subject.EntityType, subject.EntityID)
+	if err == nil {
+		var approvers []stepApprover
+		if approvers, err = s.currentApprovers(ctx, request, subject); err == nil && len(approvers) > 0 {
+			_, err = s.notificationService.Notify(ctx, models.Notification{
+				CompanyID:  request.CompanyID,
+				Type:       "approval_resubmitted",
+				Title:      "Approval required again",
+				Body:       fmt.Sprintf("%s was resubmitted after changes and is waiting for your approval (step %d of %d).", subject.Title, request.CurrentStep, len(request.Steps)),
+				EntityType: subject.EntityType,
+				EntityID:   &subject.EntityID,
+				DedupeKey:  fmt.Sprintf("approval_resubmitted:%s:%s:%d", subject.EntityType, subject.EntityID, request.StepStartedAt.Unix()),
+			}, approverIDs(approvers)...)
+		}
+	}
+	if err != nil {
+		log.Printf("notify approvers of resubmitted %s %s: %v", subject.EntityType, subject.EntityID, err)
+	}
+}
+
+// ListRevisions returns each version of an entity put before its approvers,
+// with what changed from the one before.
+func (s *ApprovalService) ListRevisions(ctx context.Context, companyID uuid.UUID, entityType string, entityID uuid.UUID) ([]*dto.ApprovalRevisionResponse, error) {
+	target, ok := s.targets[entityType]
+	if !ok {
+		return nil, ErrApprovalRequestNotFound
+	}
+	if _, err := target.ApprovalSubject(ctx, companyID, entityID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.approvalRepo.ListRevisions(ctx, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ApprovalRevisionResponse, 0, len(revisions))
+	var previous map[string]any
+	for _, r := range revisions {
+		response := &dto.ApprovalRevisionResponse{
+			Revision:        r.Revision,
+			Data:            r.Data,
+			Changes:         []*dto.RevisionChangeResponse{},
+			SubmittedBy:     uuidStringPtr(r.SubmittedBy),
+			SubmittedByName: r.SubmittedByName,
+			CreatedAt:       r.CreatedAt,
+		}
+		if previous != nil {
+			response.Changes = revisionChanges(previous, r.Data)
+		}
+		responses = append(responses, response)
+		previous = r.Data
+	}
+	return responses, nil
+}
+
+// resubmitStep is where approval restarts after changes requested at
+// returnedAt: changed entities go back to the first step, since earlier
+// approvers approved something else, while unchanged ones resume where they
+// were sent back.
+func resubmitStep(returnedAt int, changed bool) int {
+	if changed {
+		return 1
+	}
+	return max(returnedAt, 1)
+}
+
+// revisionChanges lists the fields that differ between two revisions, in
+// field order. Without a previous revision there is nothing to compare, so
+// nothing is reported as changed.
+func revisionChanges(before, after map[string]any) []*dto.RevisionChangeResponse {
+	changes := []*dto.RevisionChangeResponse{}
+	if before == nil {
+		return changes
+	}
+
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	for _, field := range fields {
+		if !reflect.DeepEqual(before[field], after[field]) {
+			changes = append(changes, &dto.RevisionChangeResponse{Field: field, From: before[field], To: after[field]})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestRevisionChanges(t *testing.T) {
+	original := map[string]any{"start_date": "2026-03-02", "days_requested": 5.0, "reason": "family visit"}
+
+	tests := []struct {
+		name   string
+		before map[string]any
+		after  map[string]any
+		want   []string
+	}{
+		{"first revision", nil, original, nil},
+		{"unchanged", original, map[string]any{"start_date": "2026-03-02", "days_requested": 5.0, "reason": "family visit"}, nil},
+		{"changed fields", original, map[string]any{"start_date": "2026-03-09", "days_requested": 4.5, "reason": "family visit"}, []string{"days_requested", "start_date"}},
+		{"added and removed", original, map[string]any{"start_date": "2026-03-02", "days_requested": 5.0, "attachment_url": "https://files/x.pdf"}, []string{"attachment_url", "reason"}},
+	}
+
+	for _, tt := range tests {
+		changes := revisionChanges(tt.before, tt.after)
+		var got []string
+		for _, c := range changes {
+			got = append(got, c.Field)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected changes to %v, got %v", tt.name, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: expected changes to %v, got %v", tt.name, tt.want, got)
+				break
+			}
+		}
+	}
+}
+
+func TestResubmitStep(t *testing.T) {
+	tests := []struct {
+		name       string
+		returnedAt int
+		changed    bool
+		want       int
+	}{
+		{"unchanged resumes at returning step", 3, false, 3},
+		{"changed restarts", 3, true, 1},
+		{"returned at first step", 1, true, 1},
+	}
+
+	for _, tt := range tests {
+		if got := resubmitStep(tt.returnedAt, tt.changed); got != tt.want {
+			t.Errorf("%s: expected step %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestBuildApprovalDecisionRequestChanges(t *testing.T) {
+	request := &models.ApprovalRequest{Steps: []models.ApprovalStep{{Step: 1}, {Step: 2}}, CurrentStep: 1}
+	actor := uuid.New()
+
+	if _, err := buildApprovalDecision(request, &actor, actionRequestChanges, ""); err == nil {
+		t.Errorf("expected requesting changes without comments to fail")
+	}
+
+	decision, err := buildApprovalDecision(request, &actor, actionRequestChanges, "attach the doctor's note")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !decision.Returned() || decision.Final() || decision.NextStep != 0 {
+		t.Errorf("expected a returned, non-final decision, got %+v", decision)
+	}
+}
+
+func TestMergeLeaveUpdate(t *testing.T) {
+	request := &models.LeaveRequest{
+		LeaveTypeID: uuid.New(),
+		StartDate:   time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2026, time.March, 6, 0, 0, 0, 0, time.UTC),
+		Reason:      "family visit",
+	}
+
+	merged := mergeLeaveUpdate(request, &dto.UpdateLeaveRequest{EndDate: ptr("2026-03-04"), EndHalfDay: ptr(true)})
+	if merged.StartDate != "2026-03-02" || merged.EndDate != "2026-03-04" || !merged.EndHalfDay || merged.Reason != "family visit" {
+		t.Errorf("unexpected merge %+v", merged)
+	}
+	if merged.LeaveTypeID != request.LeaveTypeID.String() {
+		t.Errorf("expected leave type %s, got %s", request.LeaveTypeID, merged.LeaveTypeID)
+	}
+}
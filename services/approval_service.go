@@ -0,0 +1,799 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// Approver types of a workflow step.
+const (
+	approverEmployee = "employee" // A named employee
+	approverRole     = "role"     // Every employee holding a role
+	approverManager  = "manager"  // The requester's manager
+	approverHOD      = "hod"      // The head of the requester's department
+)
+
+// actionRequestChanges is the decision that returns an entity to its
+// requester instead of approving or rejecting it.
+const actionRequestChanges = "requested_changes"
+
+var workflowTypes = []string{"leave", "memo", "expense"}
+
+// defaultApprovalSteps apply when a company has no workflow for an entity:
+// the requester's manager decides, as before workflows existed.
+var defaultApprovalSteps = []models.ApprovalStep{{Step: 1, ApproverType: approverManager}}
+
+// ApprovalSubject describes an entity to the approval engine.
+type ApprovalSubject struct {
+	EntityType   string // leave_request, memo, expense_claim
+	EntityID     uuid.UUID
+	WorkflowType string // leave, memo, expense
+	Requester    *models.Employee
+	Title        string // Shown to approvers, e.g. "Annual Leave for Ada Obi (2026-03-02 to 2026-03-06)"
+	Status       string // The entity's own status; only pending entities can be decided
+	// Revision holds the fields approvers review, as JSON-compatible values
+	// (strings, float64s and bools), recorded each time the entity is put
+	// before them so they can see what changed between rounds.
+	Revision map[string]any
+}
+
+// ApprovalDecision is what the engine asks an ApprovalTarget to apply.
+type ApprovalDecision struct {
+	Action   string     // approved, rejected, requested_changes
+	Step     int        // The step decided
+	NextStep int        // The step the entity moves on to; 0 when the decision is final or returns it
+	ActorID  *uuid.UUID // Nil when taken automatically on timeout
+	// OnBehalfOf is the approver a delegate decided for, recorded in
+	// approval_history.
+	OnBehalfOf *uuid.UUID
+	Comments   string
+}
+
+// Final reports whether the decision ends the workflow.
+func (d *ApprovalDecision) Final() bool {
+	return d.NextStep == 0 && !d.Returned()
+}
+
+// Returned reports whether the decision sends the entity back to its
+// requester for changes.
+func (d *ApprovalDecision) Returned() bool {
+	return d.Action == actionRequestChanges
+}
+
+// ApprovalTarget is implemented by each kind of entity that goes through
+// approval workflows.
+type ApprovalTarget interface {
+	// ApprovalSubject loads the entity, returning a not found error when it
+	// does not belong to companyID.
+	ApprovalSubject(ctx context.Context, companyID, entityID uuid.UUID) (*ApprovalSubject, error)
+	// ApplyApprovalDecision moves the entity on to decision.NextStep, to its
+	// final status, or back to its requester for changes, recording the
+	// decision in approval_history.
+	ApplyApprovalDecision(ctx context.Context, subject *ApprovalSubject, decision *ApprovalDecision) error
+}
+
+type ApprovalService struct {
+	approvalRepo        *repositories.ApprovalRepository
+	employeeRepo        *repositories.EmployeeRepository
+	departmentRepo      *repositories.DepartmentRepository
+	notificationService *NotificationService
+	targets             map[string]ApprovalTarget
+}
+
+func NewApprovalService(
+	approvalRepo *repositories.ApprovalRepository,
+	employeeRepo *repositories.EmployeeRepository,
+	departmentRepo *repositories.DepartmentRepository,
+	notificationService *NotificationService,
+) *ApprovalService {
+	return &ApprovalService{
+		approvalRepo:        approvalRepo,
+		employeeRepo:        employeeRepo,
+		departmentRepo:      departmentRepo,
+		notificationService: notificationService,
+		targets:             make(map[string]ApprovalTarget),
+	}
+}
+
+// RegisterTarget makes entities of entityType decidable through the engine.
+func (s *ApprovalService) RegisterTarget(entityType string, target ApprovalTarget) {
+	s.targets[entityType] = target
+}
+
+// Start puts a newly submitted entity through the workflow that applies to
+// its requester and notifies the approvers of the first step. Starting an
+// entity twice returns the approval request already under way.
+func (s *ApprovalService) Start(ctx context.Context, subject *ApprovalSubject) (*models.ApprovalRequest, error) {
+	request, err := s.start(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyApprovers(ctx, request, subject)
+	return request, nil
+}
+
+func (s *ApprovalService) start(ctx context.Context, subject *ApprovalSubject) (*models.ApprovalRequest, error) {
+	start, err := s.PrepareStart(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.approvalRepo.CreateApprovalRequest(ctx, start.Request)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordRevision(ctx, subject); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// ApprovalStart is how a newly submitted entity goes before its approvers.
+type ApprovalStart struct {
+	Request  *models.ApprovalRequest  // Under the workflow that applies to the requester
+	Revision *models.ApprovalRevision // The entity as submitted; nil when it has nothing to review
+}
+
+// PrepareStart works out the approval request a newly submitted entity
+// starts with. A target submitting an entity stores it, setting the entity
+// ID on both parts, in the transaction that submits the entity and then
+// calls Started.
+func (s *ApprovalService) PrepareStart(ctx context.Context, subject *ApprovalSubject) (*ApprovalStart, error) {
+	request := &models.ApprovalRequest{
+		CompanyID:   subject.Requester.CompanyID,
+		EntityType:  subject.EntityType,
+		EntityID:    subject.EntityID,
+		RequesterID: subject.Requester.ID,
+		Steps:       defaultApprovalSteps,
+		CurrentStep: 1,
+	}
+
+	workflow, err := s.approvalRepo.FindWorkflow(ctx, subject.Requester.CompanyID, subject.WorkflowType, subject.Requester.DepartmentID)
+	switch {
+	case err == nil:
+		request.WorkflowID = &workflow.ID
+		request.Steps = workflow.Steps
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	start := &ApprovalStart{Request: request}
+	if subject.Revision != nil {
+		start.Revision = &models.ApprovalRevision{
+			EntityType:  subject.EntityType,
+			EntityID:    subject.EntityID,
+			Data:        subject.Revision,
+			SubmittedBy: &subject.Requester.ID,
+		}
+	}
+	return start, nil
+}
+
+// Started tells the approvers of the first step of a stored approval
+// request that the entity awaits them.
+func (s *ApprovalService) Started(ctx context.Context, request *models.ApprovalRequest, subject *ApprovalSubject) {
+	s.notifyApprovers(ctx, request, subject)
+}
+
+// Decide approves, rejects or requests changes to the current step of an
+// entity on behalf of actorID. Entities submitted before the engine tracked
+// them are started on first use. An approval at the last step, or any
+// rejection, is final; a request for changes returns the entity to its
+// requester; otherwise the entity moves to the next step and its approvers
+// are notified.
+func (s *ApprovalService) Decide(
+	ctx context.Context,
+	companyID uuid.UUID,
+	entityType string,
+	entityID, actorID uuid.UUID,
+	action, comments string,
+) (*ApprovalDecision, error) {
+	target, ok := s.targets[entityType]
+	if !ok {
+		return nil, fmt.Errorf("no approval target registered for %s", entityType)
+	}
+
+	subject, err := target.ApprovalSubject(ctx, companyID, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if subject.Status != "pending" {
+		return nil, &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only pending items can be decided; this one is %s", subject.Status)}
+	}
+
+	request, err := s.approvalRequest(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != "pending" {
+		return nil, &utils.ValidationError{Field: "status", Message: fmt.Sprintf("the approval is already %s", request.Status)}
+	}
+
+	approver, err := s.canDecide(ctx, request, subject, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if approver == nil {
+		return nil, forbidden(fmt.Sprintf("you are not an approver for step %d of this item", request.CurrentStep))
+	}
+
+	decision, err := buildApprovalDecision(request, &actorID, action, comments)
+	if err != nil {
+		return nil, err
+	}
+	decision.OnBehalfOf = approver.OnBehalfOf
+	if decision.Returned() {
+		// Keep what the approver saw, for comparison with the resubmission.
+		if err := s.recordRevision(ctx, subject); err != nil {
+			return nil, err
+		}
+	}
+	if err := target.ApplyApprovalDecision(ctx, subject, decision); err != nil {
+		return nil, err
+	}
+
+	if decision.NextStep != 0 {
+		request.CurrentStep = decision.NextStep
+		s.notifyApprovers(ctx, request, subject)
+	}
+	return decision, nil
+}
+
+// approvalRequest returns the subject's approval request, starting one
+// without notifications for entities that predate the engine.
+func (s *ApprovalService) approvalRequest(ctx context.Context, subject *ApprovalSubject) (*models.ApprovalRequest, error) {
+	request, err := s.approvalRepo.GetApprovalRequest(ctx, subject.EntityType, subject.EntityID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s.start(ctx, subject)
+	}
+	return request, err
+}
+
+// GetApprovalStatus returns an entity's progress through its workflow.
+func (s *ApprovalService) GetApprovalStatus(ctx context.Context, companyID uuid.UUID, entityType string, entityID uuid.UUID) (*dto.ApprovalStatusResponse, error) {
+	target, ok := s.targets[entityType]
+	if !ok {
+		return nil, ErrApprovalRequestNotFound
+	}
+	subject, err := target.ApprovalSubject(ctx, companyID, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := s.approvalRepo.GetApprovalRequest(ctx, entityType, entityID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrApprovalRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ApprovalStatusResponse{
+		EntityType:       request.EntityType,
+		EntityID:         request.EntityID.String(),
+		WorkflowID:       uuidStringPtr(request.WorkflowID),
+		Status:           request.Status,
+		CurrentStep:      request.CurrentStep,
+		Steps:            toApprovalStepResponses(request.Steps),
+		CurrentApprovers: []string{},
+		StepStartedAt:    request.StepStartedAt,
+	}
+	if request.Status == "pending" {
+		approvers, err := s.currentApprovers(ctx, request, subject)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range approverIDs(approvers) {
+			response.CurrentApprovers = append(response.CurrentApprovers, id.String())
+		}
+	}
+	return response, nil
+}
+
+// stepApprover is someone who may decide a step, standing in for
+// OnBehalfOf when they hold a delegation.
+type stepApprover struct {
+	ID         uuid.UUID
+	OnBehalfOf *uuid.UUID
+}
+
+// canDecide returns how actorID may decide the current step: as one of its
+// approvers, as a delegate of one, or as an HR manager at any step. It
+// returns nil when they may not; nobody decides on their own request.
+func (s *ApprovalService) canDecide(ctx context.Context, request *models.ApprovalRequest, subject *ApprovalSubject, actorID uuid.UUID) (*stepApprover, error) {
+	if actorID == subject.Requester.ID {
+		return nil, nil
+	}
+
+	approvers, err := s.currentApprovers(ctx, request, subject)
+	if err != nil {
+		return nil, err
+	}
+	// Approvers acting for themselves come before delegations they gave.
+	var delegated *stepApprover
+	for i := range approvers {
+		if approvers[i].ID != actorID {
+			continue
+		}
+		if approvers[i].OnBehalfOf == nil {
+			return &approvers[i], nil
+		}
+		if delegated == nil {
+			delegated = &approvers[i]
+		}
+	}
+	if delegated != nil {
+		return delegated, nil
+	}
+
+	hr, err := s.employeeRepo.GetEmployeesByRoleName(ctx, subject.Requester.CompanyID, hrRoleName)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(hr, func(e *models.Employee) bool { return e.ID == actorID }) {
+		return &stepApprover{ID: actorID}, nil
+	}
+	return nil, nil
+}
+
+// currentApprovers resolves who decides the request's current step,
+// together with the delegates of approvers who have handed over their
+// authority today. A step that resolves to nobody but the requester falls
+// to HR so that no request is stuck.
+func (s *ApprovalService) currentApprovers(ctx context.Context, request *models.ApprovalRequest, subject *ApprovalSubject) ([]stepApprover, error) {
+	requester := subject.Requester
+
+	var approvers []stepApprover
+	if step := approvalStepAt(request.Steps, request.CurrentStep); step != nil {
+		ids, err := s.approvalRepo.StepApprovers(ctx, step, requester.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		delegates := map[uuid.UUID]uuid.UUID{}
+		if len(ids) > 0 {
+			if delegates, err = s.approvalRepo.ActiveDelegates(ctx, ids, subject.WorkflowType, utils.Today()); err != nil {
+				return nil, err
+			}
+		}
+		for _, id := range ids {
+			approvers = append(approvers, stepApprover{ID: id})
+			if delegate, ok := delegates[id]; ok && delegate != requester.ID {
+				approvers = append(approvers, stepApprover{ID: delegate, OnBehalfOf: &id})
+			}
+		}
+	}
+	// Overdue steps are escalated to further approvers.
+	for _, id := range request.EscalatedTo {
+		if id != requester.ID {
+			approvers = append(approvers, stepApprover{ID: id})
+		}
+	}
+	if len(approvers) > 0 {
+		return approvers, nil
+	}
+
+	hr, err := s.employeeRepo.GetEmployeesByRoleName(ctx, requester.CompanyID, hrRoleName)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range hr {
+		if e.ID != requester.ID {
+			approvers = append(approvers, stepApprover{ID: e.ID})
+		}
+	}
+	return approvers, nil
+}
+
+// approverIDs returns each approver and delegate once, in order.
+func approverIDs(approvers []stepApprover) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(approvers))
+	for _, a := range approvers {
+		if !slices.Contains(ids, a.ID) {
+			ids = append(ids, a.ID)
+		}
+	}
+	return ids
+}
+
+// activeEmployee returns the company's employee, or nil when they have left
+// or belong elsewhere.
+func (s *ApprovalService) activeEmployee(ctx context.Context, companyID, employeeID uuid.UUID) (*models.Employee, error) {
+	employee, err := s.employeeRepo.GetEmployeeByID(ctx, employeeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if employee.CompanyID != companyID || employee.Status == "terminated" {
+		return nil, nil
+	}
+	return employee, nil
+}
+
+// notifyApprovers tells the approvers of the request's current step that an
+// item awaits them. Failures are logged rather than undoing the submission
+// or decision.
+func (s *ApprovalService) notifyApprovers(ctx context.Context, request *models.ApprovalRequest, subject *ApprovalSubject) {
+	approvers, err := s.currentApprovers(ctx, request, subject)
+	if err == nil && len(approvers) > 0 {
+		_, err = s.notificationService.Notify(ctx, models.Notification{
+			CompanyID:  request.CompanyID,
+			Type:       "approval_required",
+			Title:      "Approval required",
+			Body:       fmt.Sprintf("%s is waiting for your approval (step %d of %d).", subject.Title, request.CurrentStep, len(request.Steps)),
+			EntityType: subject.EntityType,
+			EntityID:   &subject.EntityID,
+			DedupeKey:  fmt.Sprintf("approval:%s:%s:%d", subject.EntityType, subject.EntityID, request.CurrentStep),
+		}, approverIDs(approvers)...)
+	}
+	if err != nil {
+		log.Printf("notify approvers of %s %s: %v", subject.EntityType, subject.EntityID, err)
+	}
+}
+
+func (s *ApprovalService) CreateWorkflow(ctx context.Context, companyID uuid.UUID, req *dto.CreateApprovalWorkflowRequest) (*dto.ApprovalWorkflowResponse, error) {
+	workflow := &models.ApprovalWorkflow{
+		CompanyID:    companyID,
+		Name:         strings.TrimSpace(req.Name),
+		WorkflowType: req.WorkflowType,
+		IsActive:     req.IsActive == nil || *req.IsActive,
+	}
+	if !slices.Contains(workflowTypes, workflow.WorkflowType) {
+		return nil, &utils.ValidationError{Field: "workflow_type", Message: "workflow_type must be one of leave, memo, expense"}
+	}
+	if err := s.applyWorkflowDepartment(ctx, workflow, req.DepartmentID); err != nil {
+		return nil, err
+	}
+	steps, err := parseApprovalSteps(req.Steps)
+	if err != nil {
+		return nil, err
+	}
+	workflow.Steps = steps
+	if err := checkWorkflowName(workflow.Name); err != nil {
+		return nil, err
+	}
+	if err := s.checkApprovalSteps(ctx, companyID, workflow.Steps); err != nil {
+		return nil, err
+	}
+
+	created, err := s.approvalRepo.CreateWorkflow(ctx, workflow)
+	if isUniqueViolation(err) {
+		return nil, errActiveWorkflowExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toApprovalWorkflowResponse(created), nil
+}
+
+func (s *ApprovalService) GetWorkflow(ctx context.Context, companyID, workflowID uuid.UUID) (*dto.ApprovalWorkflowResponse, error) {
+	workflow, err := s.getCompanyWorkflow(ctx, companyID, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	return toApprovalWorkflowResponse(workflow), nil
+}
+
+func (s *ApprovalService) ListWorkflows(ctx context.Context, companyID uuid.UUID, workflowType string) ([]*dto.ApprovalWorkflowResponse, error) {
+	if workflowType != "" && !slices.Contains(workflowTypes, workflowType) {
+		return nil, &utils.ValidationError{Field: "workflow_type", Message: "workflow_type must be one of leave, memo, expense"}
+	}
+
+	workflows, err := s.approvalRepo.ListWorkflows(ctx, companyID, workflowType)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ApprovalWorkflowResponse, 0, len(workflows))
+	for _, w := range workflows {
+		responses = append(responses, toApprovalWorkflowResponse(w))
+	}
+	return responses, nil
+}
+
+// UpdateWorkflow changes a workflow for items submitted from now on; items
+// already under way keep the steps they started with.
+func (s *ApprovalService) UpdateWorkflow(ctx context.Context, companyID, workflowID uuid.UUID, req *dto.UpdateApprovalWorkflowRequest) (*dto.ApprovalWorkflowResponse, error) {
+	workflow, err := s.getCompanyWorkflow(ctx, companyID, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		workflow.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.DepartmentID != nil {
+		if err := s.applyWorkflowDepartment(ctx, workflow, *req.DepartmentID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Steps != nil {
+		if workflow.Steps, err = parseApprovalSteps(*req.Steps); err != nil {
+			return nil, err
+		}
+		if err := s.checkApprovalSteps(ctx, companyID, workflow.Steps); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsActive != nil {
+		workflow.IsActive = *req.IsActive
+	}
+	if err := checkWorkflowName(workflow.Name); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.approvalRepo.UpdateWorkflow(ctx, workflow)
+	if isUniqueViolation(err) {
+		return nil, errActiveWorkflowExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toApprovalWorkflowResponse(updated), nil
+}
+
+// DeleteWorkflow removes a workflow. Items already under way keep their
+// steps.
+func (s *ApprovalService) DeleteWorkflow(ctx context.Context, companyID, workflowID uuid.UUID) error {
+	if _, err := s.getCompanyWorkflow(ctx, companyID, workflowID); err != nil {
+		return err
+	}
+	return s.approvalRepo.DeleteWorkflow(ctx, workflowID)
+}
+
+var errActiveWorkflowExists = &utils.ValidationError{
+	Field:   "is_active",
+	Message: "an active workflow of this type already exists for this department; deactivate it first",
+}
+
+func (s *ApprovalService) getCompanyWorkflow(ctx context.Context, companyID, workflowID uuid.UUID) (*models.ApprovalWorkflow, error) {
+	workflow, err := s.approvalRepo.GetWorkflowByID(ctx, workflowID)
+	if err != nil || workflow.CompanyID != companyID {
+		return nil, ErrApprovalWorkflowNotFound
+	}
+	return workflow, nil
+}
+
+// applyWorkflowDepartment scopes the workflow to one of the company's
+// departments, or to the whole company when value is empty.
+func (s *ApprovalService) applyWorkflowDepartment(ctx context.Context, workflow *models.ApprovalWorkflow, value string) error {
+	if value == "" {
+		workflow.DepartmentID = nil
+		return nil
+	}
+	departmentID, err := uuid.Parse(value)
+	if err != nil {
+		return &utils.ValidationError{Field: "department_id", Message: "invalid department_id"}
+	}
+	department, err := s.departmentRepo.GetDepartmentByID(ctx, departmentID)
+	if err != nil || department.CompanyID != workflow.CompanyID {
+		return &utils.ValidationError{Field: "department_id", Message: "department not found"}
+	}
+	workflow.DepartmentID = &departmentID
+	return nil
+}
+
+func checkWorkflowName(name string) error {
+	if name == "" || len(name) > 100 {
+		return &utils.ValidationError{Field: "name", Message: "name is required and must be at most 100 characters"}
+	}
+	return nil
+}
+
+// checkApprovalSteps checks that every employee and role named by the steps
+// is available to the company.
+func (s *ApprovalService) checkApprovalSteps(ctx context.Context, companyID uuid.UUID, steps []models.ApprovalStep) error {
+	for _, step := range steps {
+		field := fmt.Sprintf("steps[%d]", step.Step-1)
+		if step.ApproverID != nil {
+			approver, err := s.activeEmployee(ctx, companyID, *step.ApproverID)
+			if err != nil {
+				return err
+			}
+			if approver == nil {
+				return &utils.ValidationError{Field: field + ".approver_id", Message: "approver not found"}
+			}
+		}
+		if step.RoleID != nil {
+			ok, err := s.approvalRepo.RoleAvailable(ctx, companyID, *step.RoleID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return &utils.ValidationError{Field: field + ".role_id", Message: "role not found"}
+			}
+		}
+	}
+	return nil
+}
+
+// parseApprovalSteps turns the requested steps into workflow steps numbered
+// from 1 in the order given.
+func parseApprovalSteps(reqs []dto.ApprovalStepRequest) ([]models.ApprovalStep, error) {
+	if len(reqs) == 0 {
+		return nil, &utils.ValidationError{Field: "steps", Message: "a workflow needs at least one step"}
+	}
+
+	steps := make([]models.ApprovalStep, 0, len(reqs))
+	for i, req := range reqs {
+		field := fmt.Sprintf("steps[%d]", i)
+		step := models.ApprovalStep{
+			Step:              i + 1,
+			Name:              strings.TrimSpace(req.Name),
+			ApproverType:      req.ApproverType,
+			SLAHours:          req.SLAHours,
+			RemindBeforeHours: req.RemindBeforeHours,
+			TimeoutAction:     req.TimeoutAction,
+		}
+		if err := checkStepSLA(field, &step); err != nil {
+			return nil, err
+		}
+
+		var err error
+		if step.ApproverID, err = parseOptionalUUID(req.ApproverID); err != nil {
+			return nil, &utils.ValidationError{Field: field + ".approver_id", Message: "invalid approver_id"}
+		}
+		if step.RoleID, err = parseOptionalUUID(req.RoleID); err != nil {
+			return nil, &utils.ValidationError{Field: field + ".role_id", Message: "invalid role_id"}
+		}
+
+		switch step.ApproverType {
+		case approverEmployee:
+			if step.ApproverID == nil {
+				return nil, &utils.ValidationError{Field: field + ".approver_id", Message: "employee steps need an approver_id"}
+			}
+		case approverRole:
+			if step.RoleID == nil {
+				return nil, &utils.ValidationError{Field: field + ".role_id", Message: "role steps need a role_id"}
+			}
+			if step.ApproverID != nil {
+				return nil, &utils.ValidationError{Field: field + ".approver_id", Message: "role steps cannot name an approver"}
+			}
+		case approverManager, approverHOD:
+			if step.ApproverID != nil || step.RoleID != nil {
+				return nil, &utils.ValidationError{Field: field, Message: step.ApproverType + " steps cannot name an approver or role"}
+			}
+		default:
+			return nil, &utils.ValidationError{Field: field + ".approver_type", Message: "approver_type must be one of employee, role, manager, hod"}
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// checkStepSLA validates a step's deadline settings.
+func checkStepSLA(field string, step *models.ApprovalStep) error {
+	if step.SLAHours < 0 || step.RemindBeforeHours < 0 {
+		return &utils.ValidationError{Field: field + ".sla_hours", Message: "sla_hours and remind_before_hours cannot be negative"}
+	}
+	if step.RemindBeforeHours > 0 && step.RemindBeforeHours >= step.SLAHours {
+		return &utils.ValidationError{Field: field + ".remind_before_hours", Message: "remind_before_hours must be less than sla_hours"}
+	}
+	switch step.TimeoutAction {
+	case "", timeoutEscalate:
+	case timeoutApprove, timeoutReject:
+		if step.SLAHours == 0 {
+			return &utils.ValidationError{Field: field + ".timeout_action", Message: "timeout_action needs sla_hours"}
+		}
+	default:
+		return &utils.ValidationError{Field: field + ".timeout_action", Message: "timeout_action must be one of escalate, approve, reject"}
+	}
+	return nil
+}
+
+func parseOptionalUUID(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// approverType returns the step's approver type, inferring it for steps
+// written before approver_type existed.
+func approverType(step *models.ApprovalStep) string {
+	switch {
+	case step.ApproverType != "":
+		return step.ApproverType
+	case step.ApproverID != nil:
+		return approverEmployee
+	case step.RoleID != nil:
+		return approverRole
+	default:
+		return approverManager
+	}
+}
+
+// approvalStepAt returns the step numbered n, or nil.
+func approvalStepAt(steps []models.ApprovalStep, n int) *models.ApprovalStep {
+	for i := range steps {
+		if steps[i].Step == n {
+			return &steps[i]
+		}
+	}
+	return nil
+}
+
+// nextApprovalStep returns the number of the step after current, or 0 when
+// current is the last.
+func nextApprovalStep(steps []models.ApprovalStep, current int) int {
+	next := 0
+	for _, step := range steps {
+		if step.Step > current && (next == 0 || step.Step < next) {
+			next = step.Step
+		}
+	}
+	return next
+}
+
+// buildApprovalDecision works out what approving or rejecting the request's
+// current step leads to.
+func buildApprovalDecision(request *models.ApprovalRequest, actorID *uuid.UUID, action, comments string) (*ApprovalDecision, error) {
+	decision := &ApprovalDecision{
+		Action:   action,
+		Step:     request.CurrentStep,
+		ActorID:  actorID,
+		Comments: comments,
+	}
+	switch action {
+	case "approved":
+		decision.NextStep = nextApprovalStep(request.Steps, request.CurrentStep)
+	case "rejected":
+	case actionRequestChanges:
+		if comments == "" {
+			return nil, &utils.ValidationError{Field: "comments", Message: "say what needs to change when requesting changes"}
+		}
+	default:
+		return nil, &utils.ValidationError{Field: "action", Message: "action must be approve, reject or request changes"}
+	}
+	return decision, nil
+}
+
+func toApprovalStepResponses(steps []models.ApprovalStep) []*dto.ApprovalStepResponse {
+	responses := make([]*dto.ApprovalStepResponse, 0, len(steps))
+	for i := range steps {
+		responses = append(responses, &dto.ApprovalStepResponse{
+			Step:         steps[i].Step,
+			Name:         steps[i].Name,
+			ApproverType: approverType(&steps[i]),
+			ApproverID:   uuidStringPtr(steps[i].ApproverID),
+			RoleID:       uuidStringPtr(steps[i].RoleID),
+
+			SLAHours:          steps[i].SLAHours,
+			RemindBeforeHours: steps[i].RemindBeforeHours,
+			TimeoutAction:     stepTimeoutAction(&steps[i]),
+		})
+	}
+	return responses
+}
+
+func toApprovalWorkflowResponse(w *models.ApprovalWorkflow) *dto.ApprovalWorkflowResponse {
+	return &dto.ApprovalWorkflowResponse{
+		ID:           w.ID.String(),
+		CompanyID:    w.CompanyID.String(),
+		Name:         w.Name,
+		WorkflowType: w.WorkflowType,
+		DepartmentID: uuidStringPtr(w.DepartmentID),
+		Steps:        toApprovalStepResponses(w.Steps),
+		IsActive:     w.IsActive,
+		CreatedAt:    w.CreatedAt,
+		UpdatedAt:    w.UpdatedAt,
+	}
+}
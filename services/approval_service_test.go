@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestParseApprovalSteps(t *testing.T) {
+	id := uuid.NewString()
+
+	tests := []struct {
+		name    string
+		steps   []dto.ApprovalStepRequest
+		wantErr bool
+	}{
+		{"no steps", nil, true},
+		{"manager then hod", []dto.ApprovalStepRequest{{ApproverType: "manager"}, {ApproverType: "hod"}}, false},
+		{"employee with role fallback", []dto.ApprovalStepRequest{{ApproverType: "employee", ApproverID: id, RoleID: id}}, false},
+		{"employee without approver", []dto.ApprovalStepRequest{{ApproverType: "employee"}}, true},
+		{"role without role", []dto.ApprovalStepRequest{{ApproverType: "role"}}, true},
+		{"role naming approver", []dto.ApprovalStepRequest{{ApproverType: "role", RoleID: id, ApproverID: id}}, true},
+		{"manager naming role", []dto.ApprovalStepRequest{{ApproverType: "manager", RoleID: id}}, true},
+		{"bad approver id", []dto.ApprovalStepRequest{{ApproverType: "employee", ApproverID: "nope"}}, true},
+		{"unknown type", []dto.ApprovalStepRequest{{ApproverType: "board"}}, true},
+	}
+
+	for _, tt := range tests {
+		steps, err := parseApprovalSteps(tt.steps)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		for i, step := range steps {
+			if step.Step != i+1 {
+				t.Errorf("%s: expected step %d to be numbered %d, got %d", tt.name, i, i+1, step.Step)
+			}
+		}
+	}
+}
+
+func TestApproverType(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name string
+		step models.ApprovalStep
+		want string
+	}{
+		{"explicit", models.ApprovalStep{ApproverType: "hod"}, "hod"},
+		{"legacy approver", models.ApprovalStep{ApproverID: &id, RoleID: &id}, "employee"},
+		{"legacy role", models.ApprovalStep{RoleID: &id}, "role"},
+		{"legacy empty", models.ApprovalStep{}, "manager"},
+	}
+
+	for _, tt := range tests {
+		if got := approverType(&tt.step); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestBuildApprovalDecision(t *testing.T) {
+	steps := []models.ApprovalStep{{Step: 1}, {Step: 2}, {Step: 3}}
+	actor := uuid.New()
+
+	tests := []struct {
+		name     string
+		current  int
+		action   string
+		wantNext int
+		wantErr  bool
+	}{
+		{"approve first step", 1, "approved", 2, false},
+		{"approve last step", 3, "approved", 0, false},
+		{"reject middle step", 2, "rejected", 0, false},
+		{"unknown action", 1, "cancelled", 0, true},
+	}
+
+	for _, tt := range tests {
+		request := &models.ApprovalRequest{Steps: steps, CurrentStep: tt.current}
+		decision, err := buildApprovalDecision(request, &actor, tt.action, "")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if decision.Step != tt.current || decision.NextStep != tt.wantNext {
+			t.Errorf("%s: expected step %d -> %d, got %d -> %d", tt.name, tt.current, tt.wantNext, decision.Step, decision.NextStep)
+		}
+		if decision.Final() != (tt.wantNext == 0) {
+			t.Errorf("%s: expected final %v", tt.name, tt.wantNext == 0)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// What happens to a step once its SLA expires.
+const (
+	timeoutEscalate = "escalate"
+	timeoutApprove  = "approve"
+	timeoutReject   = "reject"
+)
+
+// SLA actions due on an approval request.
+const (
+	slaRemind   = "remind"
+	slaEscalate = "escalate"
+	slaApprove  = "approved"
+	slaReject   = "rejected"
+)
+
+// maxEscalationLevel is HR; level 1 is the approvers' own managers.
+const maxEscalationLevel = 2
+
+func stepTimeoutAction(step *models.ApprovalStep) string {
+	if step.TimeoutAction == "" {
+		return timeoutEscalate
+	}
+	return step.TimeoutAction
+}
+
+// stepDeadline returns when the current step of request is due, and false
+// when its step has no SLA.
+func stepDeadline(request *models.ApprovalRequest, step *models.ApprovalStep) (time.Time, bool) {
+	if step == nil || step.SLAHours <= 0 {
+		return time.Time{}, false
+	}
+	return request.StepStartedAt.Add(time.Duration(step.SLAHours) * time.Hour), true
+}
+
+// dueSLAAction returns the SLA action due on request at now, if any: a
+// reminder ahead of the deadline, then the step's timeout action. Escalated
+// steps escalate once more, to HR, a further SLA period later.
+func dueSLAAction(request *models.ApprovalRequest, step *models.ApprovalStep, now time.Time) string {
+	deadline, ok := stepDeadline(request, step)
+	if !ok {
+		return ""
+	}
+
+	if now.Before(deadline) {
+		remindBefore := step.RemindBeforeHours
+		if remindBefore == 0 {
+			remindBefore = max(step.SLAHours/4, 1)
+		}
+		if request.RemindedAt == nil && !now.Before(deadline.Add(-time.Duration(remindBefore)*time.Hour)) {
+			return slaRemind
+		}
+		return ""
+	}
+
+	switch stepTimeoutAction(step) {
+	case timeoutApprove:
+		return slaApprove
+	case timeoutReject:
+		return slaReject
+	}
+	switch request.EscalationLevel {
+	case 0:
+		return slaEscalate
+	case maxEscalationLevel - 1:
+		if !now.Before(deadline.Add(time.Duration(step.SLAHours) * time.Hour)) {
+			return slaEscalate
+		}
+	}
+	return ""
+}
+
+// EnforceApprovalSLAs reminds, escalates or decides every pending item whose
+// step SLA calls for it at now, and returns how many it acted on.
+func (s *ApprovalService) EnforceApprovalSLAs(ctx context.Context, now time.Time) (int, error) {
+	requests, err := s.approvalRepo.ListSLAApprovalRequests(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	for _, request := range requests {
+		step := approvalStepAt(request.Steps, request.CurrentStep)
+		action := dueSLAAction(request, step, now)
+		if action == "" {
+			continue
+		}
+		target, ok := s.targets[request.EntityType]
+		if !ok {
+			continue
+		}
+		subject, err := target.ApprovalSubject(ctx, request.CompanyID, request.EntityID)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return handled, err
+		}
+		if subject.Status != "pending" {
+			continue
+		}
+
+		var acted bool
+		switch action {
+		case slaRemind:
+			acted, err = s.remindApprovers(ctx, request, subject, step)
+		case slaEscalate:
+			acted, err = s.escalate(ctx, request, subject, step)
+		default:
+			acted, err = s.decideOnTimeout(ctx, target, request, subject, step, action)
+		}
+		if err != nil {
+			return handled, err
+		}
+		if acted {
+			handled++
+		}
+	}
+
+	if handled > 0 {
+		log.Printf("applied approval SLAs to %d items", handled)
+	}
+	return handled, nil
+}
+
+func (s *ApprovalService) remindApprovers(ctx context.Context, request *models.ApprovalRequest, subject *ApprovalSubject, step *models.ApprovalStep) (bool, error) {
+	reminded, err := s.approvalRepo.MarkApprovalReminded(ctx, request.ID, request.CurrentStep)
+	if err != nil || !reminded {
+		return false, err
+	}
+
+	approvers, err := s.currentApprovers(ctx, request, subject)
+	if err != nil {
+		return false, err
+	}
+	deadline, _ := stepDeadline(request, step)
+	if _, err := s.notificationService.Notify(ctx, models.Notification{
+		CompanyID:  request.CompanyID,
+		Type:       "approval_reminder",
+		Title:      "Approval due soon",
+		Body:       fmt.Sprintf("%s needs your decision by %s.", subject.Title, deadline.Format(time.RFC3339)),
+		EntityType: subject.EntityType,
+		EntityID:   &subject.EntityID,
+		DedupeKey:  fmt.Sprintf("approval_reminder:%s:%s:%d:%d", subject.EntityType, subject.EntityID, request.CurrentStep, request.StepStartedAt.Unix()),
+	}, approverIDs(approvers)...); err != nil {
+		log.Printf("remind approvers of %s %s: %v", subject.EntityType, subject.EntityID, err)
+	}
+	return true, nil
+}
+
+// escalate adds the next level of approvers to an overdue step: first the
+// managers of its approvers, then HR.
+func (s *ApprovalService) escalate(ctx context.Context, request *models.ApprovalRequest, subject *ApprovalSubject, step *models.ApprovalStep) (bool, error) {
+	level := request.EscalationLevel + 1
+	current, err := s.currentApprovers(ctx, request, subject)
+	if err != nil {
+		return false, err
+	}
+	currentIDs := approverIDs(current)
+
+	var escalateTo []uuid.UUID
+	if level < maxEscalationLevel {
+		for _, a := range current {
+			if a.OnBehalfOf != nil {
+				continue
+			}
+			approver, err := s.activeEmployee(ctx, request.CompanyID, a.ID)
+			if err != nil {
+				return false, err
+			}
+			if approver == nil || approver.ManagerID == nil {
+				continue
+			}
+			manager, err := s.activeEmployee(ctx, request.CompanyID, *approver.ManagerID)
+			if err != nil {
+				return false, err
+			}
+			if manager != nil && manager.ID != subject.Requester.ID && !slices.Contains(currentIDs, manager.ID) && !slices.Contains(escalateTo, manager.ID) {
+				escalateTo = append(escalateTo, manager.ID)
+			}
+		}
+	}
+	if len(escalateTo) == 0 {
+		level = maxEscalationLevel
+		hr, err := s.employeeRepo.GetEmployeesByRoleName(ctx, request.CompanyID, hrRoleName)
+		if err != nil {
+			return false, err
+		}
+		for _, e := range hr {
+			if e.ID != subject.Requester.ID {
+				escalateTo = append(escalateTo, e.ID)
+			}
+		}
+	}
+
+	escalated, err := s.approvalRepo.EscalateApprovalRequest(ctx, request.ID, request.CurrentStep, level, escalateTo)
+	if err != nil || !escalated || len(escalateTo) == 0 {
+		return escalated, err
+	}
+
+	if _, err := s.notificationService.Notify(ctx, models.Notification{
+		CompanyID:  request.CompanyID,
+		Type:       "approval_escalated",
+		Title:      "Overdue approval escalated to you",
+		Body:       fmt.Sprintf("%s has waited more than %d hours for a decision at step %d and now needs yours.", subject.Title, step.SLAHours, request.CurrentStep),
+		EntityType: subject.EntityType,
+		EntityID:   &subject.EntityID,
+		DedupeKey: fmt.Sprintf("approval_escalated:%s:%s:%d:%d:%d",
+			subject.EntityType, subject.EntityID, request.CurrentStep, request.StepStartedAt.Unix(), level),
+	}, escalateTo...); err != nil {
+		log.Printf("notify escalation of %s %s: %v", subject.EntityType, subject.EntityID, err)
+	}
+	return true, nil
+}
+
+// decideOnTimeout approves or rejects an overdue step without an actor.
+func (s *ApprovalService) decideOnTimeout(
+	ctx context.Context,
+	target ApprovalTarget,
+	request *models.ApprovalRequest,
+	subject *ApprovalSubject,
+	step *models.ApprovalStep,
+	action string,
+) (bool, error) {
+	comments := fmt.Sprintf("Automatically %s: no decision within %d hours", action, step.SLAHours)
+	decision, err := buildApprovalDecision(request, nil, action, comments)
+	if err != nil {
+		return false, err
+	}
+
+	var validationErr *utils.ValidationError
+	err = target.ApplyApprovalDecision(ctx, subject, decision)
+	if errors.As(err, &validationErr) {
+		// Someone decided the step after the list was read.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if decision.NextStep != 0 {
+		request.CurrentStep = decision.NextStep
+		request.EscalatedTo = nil
+		s.notifyApprovers(ctx, request, subject)
+	}
+	return true, nil
+}
+
+// OverdueApprovals lists the company's items that are past their step SLA,
+// grouped by each approver who could decide them, most overdue approvers
+// first.
+func (s *ApprovalService) OverdueApprovals(ctx context.Context, companyID uuid.UUID, now time.Time) ([]*dto.ApproverOverdueResponse, error) {
+	requests, err := s.approvalRepo.ListSLAApprovalRequests(ctx, &companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	byApprover := make(map[uuid.UUID]*dto.ApproverOverdueResponse)
+	var responses []*dto.ApproverOverdueResponse
+	for _, request := range requests {
+		step := approvalStepAt(request.Steps, request.CurrentStep)
+		deadline, ok := stepDeadline(request, step)
+		if !ok || now.Before(deadline) {
+			continue
+		}
+		target, ok := s.targets[request.EntityType]
+		if !ok {
+			continue
+		}
+		subject, err := target.ApprovalSubject(ctx, companyID, request.EntityID)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		approvers, err := s.currentApprovers(ctx, request, subject)
+		if err != nil {
+			return nil, err
+		}
+		item := &dto.OverdueApprovalResponse{
+			EntityType:      request.EntityType,
+			EntityID:        request.EntityID.String(),
+			Title:           subject.Title,
+			Step:            request.CurrentStep,
+			StepName:        step.Name,
+			DueAt:           deadline,
+			HoursOverdue:    int(now.Sub(deadline).Hours()),
+			EscalationLevel: request.EscalationLevel,
+		}
+		for _, a := range approvers {
+			if a.OnBehalfOf != nil {
+				continue
+			}
+			entry, ok := byApprover[a.ID]
+			if !ok {
+				entry = &dto.ApproverOverdueResponse{ApproverID: a.ID.String()}
+				if approver, err := s.employeeRepo.GetEmployeeByID(ctx, a.ID); err == nil {
+					entry.ApproverName = approver.FirstName + " " + approver.LastName
+				}
+				byApprover[a.ID] = entry
+				responses = append(responses, entry)
+			}
+			entry.Items = append(entry.Items, item)
+			entry.OverdueCount++
+		}
+	}
+
+	slices.SortStableFunc(responses, func(a, b *dto.ApproverOverdueResponse) int {
+		return b.OverdueCount - a.OverdueCount
+	})
+	if responses == nil {
+		responses = []*dto.ApproverOverdueResponse{}
+	}
+	return responses, nil
+}
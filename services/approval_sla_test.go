@@ -0,0 +1,74 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestDueSLAAction(t *testing.T) {
+	started := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return started.Add(time.Duration(hours) * time.Hour) }
+	reminded := at(40)
+
+	escalate := &models.ApprovalStep{Step: 1, SLAHours: 48}
+	approve := &models.ApprovalStep{Step: 1, SLAHours: 48, RemindBeforeHours: 4, TimeoutAction: "approve"}
+	reject := &models.ApprovalStep{Step: 1, SLAHours: 48, TimeoutAction: "reject"}
+
+	tests := []struct {
+		name     string
+		step     *models.ApprovalStep
+		reminded *time.Time
+		level    int
+		now      time.Time
+		want     string
+	}{
+		{"no SLA", &models.ApprovalStep{Step: 1}, nil, 0, at(500), ""},
+		{"well before deadline", escalate, nil, 0, at(10), ""},
+		{"default reminder window", escalate, nil, 0, at(36), slaRemind},
+		{"already reminded", escalate, &reminded, 0, at(40), ""},
+		{"custom reminder window not reached", approve, nil, 0, at(43), ""},
+		{"custom reminder window", approve, nil, 0, at(44), slaRemind},
+		{"expired escalates", escalate, &reminded, 0, at(48), slaEscalate},
+		{"escalated waits another period", escalate, &reminded, 1, at(90), ""},
+		{"escalated to HR after another period", escalate, &reminded, 1, at(96), slaEscalate},
+		{"fully escalated", escalate, &reminded, 2, at(500), ""},
+		{"expired approves", approve, &reminded, 0, at(49), slaApprove},
+		{"expired rejects", reject, nil, 0, at(48), slaReject},
+	}
+
+	for _, tt := range tests {
+		request := &models.ApprovalRequest{
+			CurrentStep:     1,
+			StepStartedAt:   started,
+			RemindedAt:      tt.reminded,
+			EscalationLevel: tt.level,
+		}
+		if got := dueSLAAction(request, tt.step, tt.now); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestParseApprovalStepSLAs(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    dto.ApprovalStepRequest
+		wantErr bool
+	}{
+		{"no SLA", dto.ApprovalStepRequest{ApproverType: "manager"}, false},
+		{"SLA with auto approve", dto.ApprovalStepRequest{ApproverType: "manager", SLAHours: 24, TimeoutAction: "approve"}, false},
+		{"negative SLA", dto.ApprovalStepRequest{ApproverType: "manager", SLAHours: -1}, true},
+		{"reminder after deadline", dto.ApprovalStepRequest{ApproverType: "manager", SLAHours: 24, RemindBeforeHours: 24}, true},
+		{"timeout without SLA", dto.ApprovalStepRequest{ApproverType: "manager", TimeoutAction: "reject"}, true},
+		{"unknown timeout action", dto.ApprovalStepRequest{ApproverType: "manager", SLAHours: 24, TimeoutAction: "ignore"}, true},
+	}
+
+	for _, tt := range tests {
+		if _, err := parseApprovalSteps([]dto.ApprovalStepRequest{tt.step}); (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
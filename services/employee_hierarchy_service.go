@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// ValidateManager checks that managerID may become the manager of employee:
+// it must be a different, non-terminated employee of the same company who
+// does not already report (directly or indirectly) to employee.
+func (es *EmployeeService) ValidateManager(ctx context.Context, employee *models.Employee, managerID uuid.UUID) error {
+	if managerID == employee.ID {
+		return &utils.ValidationError{Field: "manager_id", Message: "an employee cannot be their own manager"}
+	}
+
+	manager, err := es.getCompanyEmployee(ctx, employee.CompanyID, managerID)
+	if err != nil {
+		if errors.Is(err, ErrEmployeeNotFound) {
+			return &utils.ValidationError{Field: "manager_id", Message: "manager not found in this company"}
+		}
+		return err
+	}
+	if manager.Status == "terminated" {
+		return &utils.ValidationError{Field: "manager_id", Message: "a terminated employee cannot be assigned as manager"}
+	}
+
+	// A freshly created employee has no reports yet, so there is nothing to
+	// loop back to.
+	if employee.ID == uuid.Nil {
+		return nil
+	}
+
+	cycle, err := es.employeeRepo.IsInManagementChain(ctx, managerID, employee.ID)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return &utils.ValidationError{Field: "manager_id", Message: "manager assignment would create a reporting cycle"}
+	}
+
+	return nil
+}
+
+// ChangeManager validates and applies a manager change. A nil managerID
+// removes the employee's manager.
+func (es *EmployeeService) ChangeManager(ctx context.Context, companyID, employeeID uuid.UUID, managerID *uuid.UUID) (*dto.EmployeeResponse, error) {
+	employee, err := es.getCompanyEmployee(ctx, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if managerID != nil {
+		if err := es.ValidateManager(ctx, employee, *managerID); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := es.employeeRepo.UpdateManager(ctx, employeeID, managerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toEmployeeResponse(updated), nil
+}
+
+func (es *EmployeeService) GetDirectReports(ctx context.Context, companyID, managerID uuid.UUID) ([]*dto.EmployeeResponse, error) {
+	if _, err := es.getCompanyEmployee(ctx, companyID, managerID); err != nil {
+		return nil, err
+	}
+
+	reports, err := es.employeeRepo.GetDirectReports(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.EmployeeResponse, 0, len(reports))
+	for _, report := range reports {
+		responses = append(responses, toEmployeeResponse(report))
+	}
+	return responses, nil
+}
+
+// GetAllReports returns every direct and indirect report of managerID down to
+// maxDepth levels (0 for the whole subtree).
+func (es *EmployeeService) GetAllReports(ctx context.Context, companyID, managerID uuid.UUID, maxDepth int) ([]*dto.ReportingLineResponse, error) {
+	if maxDepth < 0 {
+		return nil, &utils.ValidationError{Field: "max_depth", Message: "max_depth cannot be negative"}
+	}
+	if _, err := es.getCompanyEmployee(ctx, companyID, managerID); err != nil {
+		return nil, err
+	}
+
+	reports, err := es.employeeRepo.GetAllReports(ctx, managerID, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	return toReportingLineResponses(reports), nil
+}
+
+// GetManagementChain returns the managers above employeeID, nearest first.
+func (es *EmployeeService) GetManagementChain(ctx context.Context, companyID, employeeID uuid.UUID) ([]*dto.ReportingLineResponse, error) {
+	if _, err := es.getCompanyEmployee(ctx, companyID, employeeID); err != nil {
+		return nil, err
+	}
+
+	chain, err := es.employeeRepo.GetManagementChain(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	return toReportingLineResponses(chain), nil
+}
+
+// GetSkipLevelManager returns the manager's manager of employeeID, or nil when
+// the employee is fewer than two levels from the top.
+func (es *EmployeeService) GetSkipLevelManager(ctx context.Context, companyID, employeeID uuid.UUID) (*dto.EmployeeResponse, error) {
+	chain, err := es.GetManagementChain(ctx, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) < 2 {
+		return nil, nil
+	}
+	return &chain[1].EmployeeResponse, nil
+}
+
+func (es *EmployeeService) GetSpanOfControl(ctx context.Context, companyID, managerID uuid.UUID) (*dto.SpanOfControlResponse, error) {
+	if _, err := es.getCompanyEmployee(ctx, companyID, managerID); err != nil {
+		return nil, err
+	}
+
+	span, err := es.employeeRepo.GetSpanOfControl(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+	return toSpanOfControlResponse(span), nil
+}
+
+func (es *EmployeeService) GetCompanySpanOfControl(ctx context.Context, companyID uuid.UUID) ([]*dto.SpanOfControlResponse, error) {
+	spans, err := es.employeeRepo.GetCompanySpanOfControl(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.SpanOfControlResponse, 0, len(spans))
+	for _, span := range spans {
+		responses = append(responses, toSpanOfControlResponse(span))
+	}
+	return responses, nil
+}
+
+func toReportingLineResponses(reports []*models.EmployeeReport) []*dto.ReportingLineResponse {
+	responses := make([]*dto.ReportingLineResponse, 0, len(reports))
+	for _, report := range reports {
+		responses = append(responses, &dto.ReportingLineResponse{
+			EmployeeResponse: *toEmployeeResponse(&report.Employee),
+			Depth:            report.Depth,
+		})
+	}
+	return responses
+}
+
+func toSpanOfControlResponse(span *models.SpanOfControl) *dto.SpanOfControlResponse {
+	return &dto.SpanOfControlResponse{
+		ManagerID:     span.ManagerID.String(),
+		DirectReports: span.DirectReports,
+		TotalReports:  span.TotalReports,
+		Depth:         span.Depth,
+	}
+}
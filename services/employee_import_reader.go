@@ -0,0 +1,293 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxXLSXColumns is the widest sheet Excel allows (column XFD).
+	maxXLSXColumns = 16384
+	// maxXLSXEntrySize caps how much any one part of a workbook may
+	// decompress to, whatever its header claims.
+	maxXLSXEntrySize = 64 << 20
+	// maxXLSXCells caps the cells, blanks included, read from a sheet.
+	maxXLSXCells = 5_000_000
+)
+
+// errXLSXTooLarge is returned for workbooks that decompress past the caps.
+var errXLSXTooLarge = errors.New("invalid xlsx: file is too large once decompressed")
+
+// importRecord is one non-blank row of an import file. Line is the row
+// number the user sees in their spreadsheet, header included.
+type importRecord struct {
+	Line   int
+	Values []string
+}
+
+// readImportTable returns the header and data rows of a CSV or XLSX file.
+// Only the first worksheet of a workbook is read.
+func readImportTable(data []byte, format string) ([]string, []importRecord, error) {
+	var records []importRecord
+	var err error
+
+	switch format {
+	case "csv":
+		records, err = readCSVRecords(data)
+	case "xlsx":
+		records, err = readXLSXRecords(data)
+	default:
+		return nil, nil, fmt.Errorf("unsupported format %q: use csv or xlsx", format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("file is empty")
+	}
+
+	return records[0].Values, records[1:], nil
+}
+
+func readCSVRecords(data []byte) ([]importRecord, error) {
+	// Spreadsheet tools often prepend a UTF-8 byte order mark.
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records []importRecord
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if !isBlankRecord(values) {
+			records = append(records, importRecord{Line: line, Values: values})
+		}
+	}
+	return records, nil
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSXRecords(data []byte) ([]importRecord, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("invalid xlsx: not a zip archive")
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+	strs := make([]string, len(shared.Items))
+	for i, item := range shared.Items {
+		if len(item.Runs) == 0 {
+			strs[i] = item.Text
+			continue
+		}
+		var sb strings.Builder
+		for _, run := range item.Runs {
+			sb.WriteString(run.Text)
+		}
+		strs[i] = sb.String()
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("invalid xlsx: missing %s", sheetPath)
+	}
+	var sheet xlsxWorksheet
+	if err := decodeZipXML(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	var records []importRecord
+	cells := 0
+	for i, row := range sheet.Rows {
+		line := row.Number
+		if line == 0 {
+			line = i + 1
+		}
+
+		var values []string
+		for j, cell := range row.Cells {
+			col := j
+			if cell.Ref != "" {
+				if col, err = xlsxColumnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+			if col >= len(values) {
+				if cells += col + 1 - len(values); cells > maxXLSXCells {
+					return nil, errXLSXTooLarge
+				}
+				values = append(values, make([]string, col+1-len(values))...)
+			}
+
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(strs) {
+					return nil, fmt.Errorf("invalid xlsx: bad shared string in cell %s", cell.Ref)
+				}
+				values[col] = strs[idx]
+			case "inlineStr":
+				values[col] = cell.Inline
+			case "b":
+				values[col] = map[string]string{"1": "TRUE", "0": "FALSE"}[cell.Value]
+			default:
+				values[col] = cell.Value
+			}
+		}
+
+		if !isBlankRecord(values) {
+			records = append(records, importRecord{Line: line, Values: values})
+		}
+	}
+	return records, nil
+}
+
+// firstSheetPath follows the workbook relationships to the first worksheet.
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	wbFile, ok := files["xl/workbook.xml"]
+	relsFile, relsOK := files["xl/_rels/workbook.xml.rels"]
+	if !ok || !relsOK {
+		return fallback, nil
+	}
+
+	var wb xlsxWorkbook
+	if err := decodeZipXML(wbFile, &wb); err != nil {
+		return "", err
+	}
+	var rels xlsxRelationships
+	if err := decodeZipXML(relsFile, &rels); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", errors.New("invalid xlsx: workbook has no sheets")
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+func decodeZipXML(f *zip.File, v any) error {
+	if f.UncompressedSize64 > maxXLSXEntrySize {
+		return errXLSXTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// The size in the header is only a claim, so the reader enforces the cap
+	// too; one byte over it marks the entry as too large.
+	lr := io.LimitReader(rc, maxXLSXEntrySize+1).(*io.LimitedReader)
+	err = xml.NewDecoder(lr).Decode(v)
+	if lr.N == 0 {
+		return errXLSXTooLarge
+	}
+	if err != nil {
+		return fmt.Errorf("invalid xlsx: %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// xlsxColumnIndex converts a cell reference such as "AB12" into a zero-based
+// column index.
+func xlsxColumnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+		if col > maxXLSXColumns {
+			return 0, fmt.Errorf("invalid xlsx: cell reference %q is beyond column XFD", ref)
+		}
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("invalid xlsx: bad cell reference %q", ref)
+	}
+	return col - 1, nil
+}
+
+func isBlankRecord(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// importColumns maps normalised header names onto dto.CreateEmployeeRequest.
+// Reference columns accept an ID, a name or (for departments) a code; the
+// manager column accepts an employee code or email, including employees
+// defined further down the same file.
+var importColumns = map[string]func(req *dto.CreateEmployeeRequest, value string){
+	"email":                   func(r *dto.CreateEmployeeRequest, v string) { r.Email = v },
+	"phone":                   func(r *dto.CreateEmployeeRequest, v string) { r.Phone = v },
+	"first_name":              func(r *dto.CreateEmployeeRequest, v string) { r.FirstName = v },
+	"last_name":               func(r *dto.CreateEmployeeRequest, v string) { r.LastName = v },
+	"date_of_birth":           func(r *dto.CreateEmployeeRequest, v string) { r.DateOfBirth = v },
+	"employee_code":           func(r *dto.CreateEmployeeRequest, v string) { r.EmployeeCode = v },
+	"department":              func(r *dto.CreateEmployeeRequest, v string) { r.DepartmentID = v },
+	"designation":             func(r *dto.CreateEmployeeRequest, v string) { r.DesignationID = v },
+	"level":                   func(r *dto.CreateEmployeeRequest, v string) { r.LevelID = v },
+	"role":                    func(r *dto.CreateEmployeeRequest, v string) { r.RoleID = v },
+	"manager":                 func(r *dto.CreateEmployeeRequest, v string) { r.ManagerID = v },
+	"status":                  func(r *dto.CreateEmployeeRequest, v string) { r.Status = v },
+	"employment_type":         func(r *dto.CreateEmployeeRequest, v string) { r.EmploymentType = v },
+	"hire_date":               func(r *dto.CreateEmployeeRequest, v string) { r.HireDate = v },
+	"gender":                  func(r *dto.CreateEmployeeRequest, v string) { r.Gender = v },
+	"address":                 func(r *dto.CreateEmployeeRequest, v string) { r.Address = v },
+	"emergency_contact_name":  func(r *dto.CreateEmployeeRequest, v string) { r.EmergencyContactName = v },
+	"emergency_contact_phone": func(r *dto.CreateEmployeeRequest, v string) { r.EmergencyContactPhone = v },
+	"profile_image_url":       func(r *dto.CreateEmployeeRequest, v string) { r.ProfileImageUrl = v },
+}
+
+// importColumnAliases lets files use the JSON field names as headers too.
+var importColumnAliases = map[string]string{
+	"department_id":  "department",
+	"designation_id": "designation",
+	"level_id":       "level",
+	"role_id":        "role",
+	"manager_id":     "manager",
+	"manager_code":   "manager",
+	"manager_email":  "manager",
+}
+
+var requiredImportColumns = []string{
+	"email", "phone", "first_name", "last_name", "date_of_birth",
+	"employee_code", "department", "designation", "level", "role", "status",
+	"employment_type", "hire_date",
+}
+
+var (
+	employeeStatuses = []string{"active", "inactive", "on_leave", "terminated", "probation"}
+	employmentTypes  = []string{"full_time", "part_time", "contract", "intern"}
+)
+
+type EmployeeImportService struct {
+	importRepo *repositories.EmployeeImportRepository
+}
+
+func NewEmployeeImportService(importRepo *repositories.EmployeeImportRepository) *EmployeeImportService {
+	return &EmployeeImportService{
+		importRepo: importRepo,
+	}
+}
+
+// Import creates the employees described by a CSV or XLSX file. Every row is
+// validated first; if any row fails, nothing is written and the report lists
+// each problem by row. Otherwise all rows are created in one transaction,
+// unless dryRun is set. Imported employees have no password until they set
+// one up.
+func (s *EmployeeImportService) Import(
+	ctx context.Context,
+	companyID uuid.UUID,
+	actorID *uuid.UUID,
+	format string,
+	data []byte,
+	dryRun bool,
+) (*dto.EmployeeImportReport, error) {
+	header, records, err := readImportTable(data, strings.ToLower(format))
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "file", Message: err.Error()}
+	}
+	requests, lines, err := mapImportRecords(header, records)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := s.importRepo.GetReferenceData(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	employees, managerRows, rowErrors := planEmployeeImport(companyID, requests, lines, refs)
+	report := &dto.EmployeeImportReport{
+		TotalRows: len(requests),
+		DryRun:    dryRun,
+		Employees: []*dto.ImportedEmployee{},
+		Errors:    rowErrors,
+	}
+	if len(rowErrors) > 0 || dryRun {
+		if len(rowErrors) == 0 {
+			for i, employee := range employees {
+				report.Employees = append(report.Employees, &dto.ImportedEmployee{Row: lines[i], EmployeeCode: employee.EmployeeCode, Email: employee.Email})
+			}
+		}
+		return report, nil
+	}
+
+	err = s.importRepo.ImportEmployees(ctx, employees, managerRows, &models.AuditLog{
+		CompanyID:  companyID,
+		UserID:     actorID,
+		Action:     "employees_imported",
+		EntityType: "employee",
+		Metadata:   map[string]any{"rows": len(employees), "format": format},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Committed = true
+	for i, employee := range employees {
+		report.Employees = append(report.Employees, &dto.ImportedEmployee{
+			Row:          lines[i],
+			ID:           employee.ID.String(),
+			EmployeeCode: employee.EmployeeCode,
+			Email:        employee.Email,
+		})
+	}
+	return report, nil
+}
+
+// mapImportRecords turns data rows into create requests using the header row.
+// Unknown or missing columns reject the whole file, since a misspelt header
+// would otherwise silently drop data.
+func mapImportRecords(header []string, records []importRecord) ([]*dto.CreateEmployeeRequest, []int, error) {
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, h := range header {
+		name := normalizeImportColumn(h)
+		if alias, ok := importColumnAliases[name]; ok {
+			name = alias
+		}
+		if _, ok := importColumns[name]; !ok {
+			return nil, nil, &utils.ValidationError{Field: "file", Message: fmt.Sprintf("unknown column %q", strings.TrimSpace(h))}
+		}
+		if seen[name] {
+			return nil, nil, &utils.ValidationError{Field: "file", Message: fmt.Sprintf("duplicate column %q", strings.TrimSpace(h))}
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+	for _, name := range requiredImportColumns {
+		if !seen[name] {
+			return nil, nil, &utils.ValidationError{Field: "file", Message: fmt.Sprintf("missing required column %q", name)}
+		}
+	}
+
+	requests := make([]*dto.CreateEmployeeRequest, 0, len(records))
+	lines := make([]int, 0, len(records))
+	for _, record := range records {
+		var req dto.CreateEmployeeRequest
+		for i, value := range record.Values {
+			if i < len(columns) {
+				importColumns[columns[i]](&req, strings.TrimSpace(value))
+			}
+		}
+		requests = append(requests, &req)
+		lines = append(lines, record.Line)
+	}
+	return requests, lines, nil
+}
+
+func normalizeImportColumn(h string) string {
+	h = strings.ToLower(strings.TrimSpace(h))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(h)
+}
+
+// planEmployeeImport validates every request and resolves its references.
+// It returns the employees to insert, without a login credential, the
+// in-file manager links by index, and every row error found.
+func planEmployeeImport(
+	companyID uuid.UUID,
+	requests []*dto.CreateEmployeeRequest,
+	lines []int,
+	refs *models.ImportReferenceData,
+) ([]*models.Employee, map[int]int, []*dto.ImportRowError) {
+	var rowErrors []*dto.ImportRowError
+	fail := func(i int, field, value, message string) {
+		rowErrors = append(rowErrors, &dto.ImportRowError{Row: lines[i], Field: field, Value: value, Message: message})
+	}
+
+	existingByKey := make(map[string]models.ImportEmployeeRef, len(refs.Employees)*2)
+	for _, e := range refs.Employees {
+		existingByKey["email:"+strings.ToLower(e.Email)] = e
+		if e.EmployeeCode != "" {
+			existingByKey["code:"+strings.ToLower(e.EmployeeCode)] = e
+		}
+	}
+
+	// Rows are indexed by email and code up front so managers further down
+	// the file resolve; the first row to claim a key owns it.
+	rowByKey := make(map[string]int, len(requests)*2)
+	for i, req := range requests {
+		for _, key := range []string{"email:" + strings.ToLower(req.Email), "code:" + strings.ToLower(req.EmployeeCode)} {
+			if strings.HasSuffix(key, ":") {
+				continue
+			}
+			if _, taken := rowByKey[key]; !taken {
+				rowByKey[key] = i
+			}
+		}
+	}
+
+	employees := make([]*models.Employee, len(requests))
+	managerRows := make(map[int]int)
+	for i, req := range requests {
+		employee := &models.Employee{
+			CompanyID:             companyID,
+			Email:                 strings.ToLower(req.Email),
+			Phone:                 req.Phone,
+			FirstName:             req.FirstName,
+			LastName:              req.LastName,
+			EmployeeCode:          req.EmployeeCode,
+			Status:                req.Status,
+			EmploymentType:        req.EmploymentType,
+			Gender:                req.Gender,
+			Address:               req.Address,
+			EmergencyContactName:  req.EmergencyContactName,
+			EmergencyContactPhone: req.EmergencyContactPhone,
+			ProfileImageURL:       req.ProfileImageUrl,
+		}
+		employees[i] = employee
+
+		for _, required := range [][2]string{
+			{"phone", req.Phone},
+			{"first_name", req.FirstName},
+			{"last_name", req.LastName},
+			{"employee_code", req.EmployeeCode},
+		} {
+			if required[1] == "" {
+				fail(i, required[0], "", required[0]+" is required")
+			}
+		}
+
+		switch {
+		case req.Email == "":
+			fail(i, "email", "", "email is required")
+		case !utils.IsValidEmail(req.Email):
+			fail(i, "email", req.Email, "invalid email")
+		case rowByKey["email:"+employee.Email] != i:
+			fail(i, "email", req.Email, fmt.Sprintf("email also used on row %d", lines[rowByKey["email:"+employee.Email]]))
+		default:
+			if _, ok := existingByKey["email:"+employee.Email]; ok {
+				fail(i, "email", req.Email, "an employee with this email already exists")
+			}
+		}
+		if req.EmployeeCode != "" {
+			key := "code:" + strings.ToLower(req.EmployeeCode)
+			if rowByKey[key] != i {
+				fail(i, "employee_code", req.EmployeeCode, fmt.Sprintf("employee_code also used on row %d", lines[rowByKey[key]]))
+			} else if _, ok := existingByKey[key]; ok {
+				fail(i, "employee_code", req.EmployeeCode, "an employee with this employee_code already exists")
+			}
+		}
+
+		if !slices.Contains(employeeStatuses, req.Status) {
+			fail(i, "status", req.Status, "status must be one of "+strings.Join(employeeStatuses, ", "))
+		} else if req.Status == "terminated" {
+			fail(i, "status", req.Status, "terminated employees cannot be imported")
+		}
+		if !slices.Contains(employmentTypes, req.EmploymentType) {
+			fail(i, "employment_type", req.EmploymentType, "employment_type must be one of "+strings.Join(employmentTypes, ", "))
+		}
+
+		if hireDate, err := parseImportDate(req.HireDate); err != nil {
+			fail(i, "hire_date", req.HireDate, "hire_date "+err.Error())
+		} else {
+			employee.HireDate = hireDate
+		}
+		if dob, err := parseImportDate(req.DateOfBirth); err != nil {
+			fail(i, "date_of_birth", req.DateOfBirth, "date_of_birth "+err.Error())
+		} else {
+			employee.DateOfBirth = &dob
+		}
+
+		references := []struct {
+			field  string
+			value  string
+			refs   []models.ImportReference
+			target **uuid.UUID
+		}{
+			{"department", req.DepartmentID, refs.Departments, &employee.DepartmentID},
+			{"designation", req.DesignationID, refs.Designations, &employee.DesignationID},
+			{"level", req.LevelID, refs.Levels, &employee.LevelID},
+		}
+		for _, ref := range references {
+			if ref.value == "" {
+				fail(i, ref.field, "", ref.field+" is required")
+				continue
+			}
+			id, ok := resolveImportReference(ref.refs, ref.value)
+			if !ok {
+				fail(i, ref.field, ref.value, ref.field+" not found")
+				continue
+			}
+			*ref.target = &id
+		}
+		if req.RoleID == "" {
+			fail(i, "role", "", "role is required")
+		} else if id, ok := resolveImportReference(refs.Roles, req.RoleID); !ok {
+			fail(i, "role", req.RoleID, "role not found")
+		} else {
+			employee.RoleID = id
+		}
+
+		if req.ManagerID == "" {
+			continue
+		}
+		managerKey := strings.ToLower(req.ManagerID)
+		if id, err := uuid.Parse(req.ManagerID); err == nil {
+			for _, e := range refs.Employees {
+				if e.ID == id {
+					managerKey = "code:" + strings.ToLower(e.EmployeeCode)
+					if e.EmployeeCode == "" {
+						managerKey = "email:" + strings.ToLower(e.Email)
+					}
+				}
+			}
+		} else if strings.Contains(managerKey, "@") {
+			managerKey = "email:" + managerKey
+		} else {
+			managerKey = "code:" + managerKey
+		}
+
+		if row, ok := rowByKey[managerKey]; ok {
+			if row == i {
+				fail(i, "manager", req.ManagerID, "an employee cannot manage themselves")
+			} else {
+				managerRows[i] = row
+			}
+		} else if existing, ok := existingByKey[managerKey]; ok {
+			if existing.Status == "terminated" {
+				fail(i, "manager", req.ManagerID, "manager is terminated")
+			} else {
+				employee.ManagerID = &existing.ID
+			}
+		} else {
+			fail(i, "manager", req.ManagerID, "manager not found in the company or this file")
+		}
+	}
+
+	// In-file manager links must not loop back on themselves.
+	for start := range managerRows {
+		visited := map[int]bool{start: true}
+		for row, ok := managerRows[start]; ok; row, ok = managerRows[row] {
+			if visited[row] {
+				fail(start, "manager", requests[start].ManagerID, "manager references in this file form a cycle")
+				break
+			}
+			visited[row] = true
+		}
+	}
+
+	slices.SortStableFunc(rowErrors, func(a, b *dto.ImportRowError) int { return a.Row - b.Row })
+	return employees, managerRows, rowErrors
+}
+
+// resolveImportReference matches value against an ID, then a name or code,
+// ignoring case.
+func resolveImportReference(refs []models.ImportReference, value string) (uuid.UUID, bool) {
+	if id, err := uuid.Parse(value); err == nil {
+		for _, ref := range refs {
+			if ref.ID == id {
+				return ref.ID, true
+			}
+		}
+		return uuid.Nil, false
+	}
+	for _, ref := range refs {
+		if strings.EqualFold(ref.Name, value) || (ref.Code != "" && strings.EqualFold(ref.Code, value)) {
+			return ref.ID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// parseImportDate accepts YYYY-MM-DD or a spreadsheet date serial, which is
+// how XLSX files store date cells.
+func parseImportDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("is required")
+	}
+	if date, err := utils.ParseDate(value); err == nil {
+		return date, nil
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)), nil
+	}
+	return time.Time{}, errors.New("must be YYYY-MM-DD")
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+const importHeader = "Email,Phone,First Name,Last Name,Date of Birth,Employee Code,Department,Designation,Level,Role,Manager,Status,Employment Type,Hire Date\n"
+
+func importTestReferences() *models.ImportReferenceData {
+	return &models.ImportReferenceData{
+		Departments:  []models.ImportReference{{ID: uuid.New(), Name: "Engineering", Code: "ENG"}},
+		Designations: []models.ImportReference{{ID: uuid.New(), Name: "Engineer"}},
+		Levels:       []models.ImportReference{{ID: uuid.New(), Name: "L2"}},
+		Roles:        []models.ImportReference{{ID: uuid.New(), Name: "Employee"}},
+		Employees: []models.ImportEmployeeRef{
+			{ID: uuid.New(), Email: "cto@example.com", EmployeeCode: "EMP001", Status: "active"},
+		},
+	}
+}
+
+func planFromCSV(t *testing.T, csv string) ([]*models.Employee, map[int]int, []*dto.ImportRowError) {
+	t.Helper()
+	header, records, err := readImportTable([]byte(csv), "csv")
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	requests, lines, err := mapImportRecords(header, records)
+	if err != nil {
+		t.Fatalf("map records: %v", err)
+	}
+	return planEmployeeImport(uuid.New(), requests, lines, importTestReferences())
+}
+
+func TestPlanEmployeeImport_ResolvesManagersLaterInFile(t *testing.T) {
+	_, managerRows, errs := planFromCSV(t, importHeader+
+		"ada@example.com,555-0100,Ada,Lovelace,1990-12-10,EMP100,eng,Engineer,L2,Employee,EMP101,probation,full_time,2026-01-05\n"+
+		"grace@example.com,555-0101,Grace,Hopper,1985-12-09,EMP101,Engineering,Engineer,L2,Employee,cto@example.com,active,full_time,2026-01-05\n",
+	)
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors, got %+v", errs[0])
+	}
+	if managerRows[0] != 1 {
+		t.Errorf("expected row 0 to be managed by row 1, got %v", managerRows)
+	}
+}
+
+func TestPlanEmployeeImport_ReportsRowErrors(t *testing.T) {
+	_, _, errs := planFromCSV(t, importHeader+
+		"ada@example.com,555-0100,Ada,Lovelace,1990-12-10,EMP100,Sales,Engineer,L2,Employee,,active,full_time,2026-01-05\n"+
+		"ada@example.com,555-0101,Grace,Hopper,1985-12-09,EMP001,Engineering,Engineer,L2,Employee,EMP999,active,full_time,05/01/2026\n",
+	)
+
+	want := map[int][]string{
+		2: {"department"},
+		3: {"email", "employee_code", "hire_date", "manager"},
+	}
+	got := map[int][]string{}
+	for _, e := range errs {
+		got[e.Row] = append(got[e.Row], e.Field)
+	}
+	for row, fields := range want {
+		for _, field := range fields {
+			found := false
+			for _, f := range got[row] {
+				found = found || f == field
+			}
+			if !found {
+				t.Errorf("row %d: expected an error for %s, got %v", row, field, got[row])
+			}
+		}
+	}
+}
+
+func TestPlanEmployeeImport_DetectsManagerCycle(t *testing.T) {
+	_, _, errs := planFromCSV(t, importHeader+
+		"ada@example.com,555-0100,Ada,Lovelace,1990-12-10,EMP100,ENG,Engineer,L2,Employee,EMP101,active,full_time,2026-01-05\n"+
+		"grace@example.com,555-0101,Grace,Hopper,1985-12-09,EMP101,ENG,Engineer,L2,Employee,ada@example.com,active,full_time,2026-01-05\n",
+	)
+	if len(errs) != 2 || errs[0].Field != "manager" || errs[1].Field != "manager" {
+		t.Fatalf("expected a manager cycle error on both rows, got %d errors", len(errs))
+	}
+}
+
+func TestMapImportRecords_RejectsUnknownColumn(t *testing.T) {
+	header, records, err := readImportTable([]byte(importHeader[:len(importHeader)-1]+",Salary\n"), "csv")
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if _, _, err := mapImportRecords(header, records); err == nil {
+		t.Error("expected unknown column to be rejected")
+	}
+}
+
+func TestReadImportTable_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>Email</t></si><si><r><t>Hire </t></r><r><t>Date</t></r></si><si><t>ada@example.com</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+			`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="C3" t="inlineStr"><is><t>x</t></is></c><c r="B3"><v>46027</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+
+	header, records, err := readImportTable(buf.Bytes(), "xlsx")
+	if err != nil {
+		t.Fatalf("read xlsx: %v", err)
+	}
+	if len(header) != 2 || header[1] != "Hire Date" {
+		t.Fatalf("unexpected header %v", header)
+	}
+	if len(records) != 1 || records[0].Line != 3 {
+		t.Fatalf("expected one record on line 3, got %+v", records)
+	}
+	if records[0].Values[0] != "ada@example.com" || records[0].Values[2] != "x" {
+		t.Errorf("unexpected values %v", records[0].Values)
+	}
+
+	hireDate, err := parseImportDate(records[0].Values[1])
+	if err != nil || hireDate.Format("2006-01-02") != "2026-01-05" {
+		t.Errorf("expected serial 46027 to be 2026-01-05, got %v (%v)", hireDate, err)
+	}
+}
+
+func TestXLSXColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    int
+		wantErr bool
+	}{
+		{"A1", 0, false},
+		{"AB12", 27, false},
+		{"XFD1", maxXLSXColumns - 1, false},
+		{"XFE1", 0, true},
+		{"ZZZZZZZ1", 0, true},
+		{"12", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := xlsxColumnIndex(tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.ref, err)
+		} else if got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.ref, tt.want, got)
+		}
+	}
+}
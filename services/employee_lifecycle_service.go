@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// employeeStatusTransitions lists the statuses each status may move to.
+// 'terminated' is final here; leaving it is only possible through Rehire.
+var employeeStatusTransitions = map[string][]string{
+	"probation":  {"active", "inactive", "terminated"},
+	"active":     {"on_leave", "inactive", "terminated"},
+	"on_leave":   {"active", "inactive", "terminated"},
+	"inactive":   {"active", "terminated"},
+	"terminated": {},
+}
+
+// rehireStatuses are the statuses a terminated employee may be rehired into.
+var rehireStatuses = []string{"active", "probation"}
+
+// CanTransitionEmployee reports whether an employee may move from one status
+// to another through a regular transition.
+func CanTransitionEmployee(from, to string) bool {
+	return slices.Contains(employeeStatusTransitions[from], to)
+}
+
+type EmployeeLifecycleService struct {
+	employeeRepo  *repositories.EmployeeRepository
+	lifecycleRepo *repositories.EmployeeLifecycleRepository
+}
+
+func NewEmployeeLifecycleService(
+	employeeRepo *repositories.EmployeeRepository,
+	lifecycleRepo *repositories.EmployeeLifecycleRepository,
+) *EmployeeLifecycleService {
+	return &EmployeeLifecycleService{
+		employeeRepo:  employeeRepo,
+		lifecycleRepo: lifecycleRepo,
+	}
+}
+
+// TransitionStatus moves an employee to req.ToStatus. Transitions effective
+// today or earlier are applied immediately; later ones are scheduled and
+// applied by ApplyDueTransitions on their effective date.
+func (s *EmployeeLifecycleService) TransitionStatus(
+	ctx context.Context,
+	companyID, employeeID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.StatusTransitionRequest,
+) (*dto.StatusTransitionResponse, error) {
+	transition, err := s.prepareStatusTransition(ctx, companyID, employeeID, actorID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.applyOrSchedule(ctx, transition, "employee_status_changed")
+}
+
+// prepareStatusTransition validates a move of the employee to req.ToStatus
+// without applying or scheduling it.
+func (s *EmployeeLifecycleService) prepareStatusTransition(
+	ctx context.Context,
+	companyID, employeeID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.StatusTransitionRequest,
+) (*models.EmployeeStatusTransition, error) {
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, err := buildStatusTransition(employee, req, utils.Today())
+	if err != nil {
+		return nil, err
+	}
+	transition.RequestedBy = actorID
+
+	if err := s.checkNoScheduledTransition(ctx, employeeID); err != nil {
+		return nil, err
+	}
+	return transition, nil
+}
+
+// Rehire brings a terminated employee back as active or on probation. The
+// effective date becomes the new hire date and termination_date is cleared.
+func (s *EmployeeLifecycleService) Rehire(
+	ctx context.Context,
+	companyID, employeeID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.RehireRequest,
+) (*dto.StatusTransitionResponse, error) {
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if employee.Status != "terminated" {
+		return nil, &utils.ValidationError{Field: "status", Message: "only terminated employees can be rehired"}
+	}
+	if !slices.Contains(rehireStatuses, req.ToStatus) {
+		return nil, &utils.ValidationError{Field: "to_status", Message: "rehired employees must start as active or probation"}
+	}
+	effectiveDate, err := utils.ParseDate(req.EffectiveDate)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "effective_date", Message: "effective_date must be YYYY-MM-DD"}
+	}
+
+	transition := &models.EmployeeStatusTransition{
+		CompanyID:     employee.CompanyID,
+		EmployeeID:    employee.ID,
+		FromStatus:    employee.Status,
+		ToStatus:      req.ToStatus,
+		EffectiveDate: effectiveDate,
+		Reason:        strings.TrimSpace(req.Reason),
+		RequestedBy:   actorID,
+	}
+
+	if err := s.checkNoScheduledTransition(ctx, employee.ID); err != nil {
+		return nil, err
+	}
+	return s.applyOrSchedule(ctx, transition, "employee_rehired")
+}
+
+func (s *EmployeeLifecycleService) checkNoScheduledTransition(ctx context.Context, employeeID uuid.UUID) error {
+	pending, err := s.lifecycleRepo.HasScheduledTransition(ctx, employeeID)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return &utils.ValidationError{Field: "status", Message: "employee already has a scheduled status transition; cancel it first"}
+	}
+	return nil
+}
+
+func (s *EmployeeLifecycleService) applyOrSchedule(ctx context.Context, transition *models.EmployeeStatusTransition, action string) (*dto.StatusTransitionResponse, error) {
+	if transition.EffectiveDate.After(utils.Today()) {
+		scheduled, err := s.lifecycleRepo.CreateTransition(ctx, transition)
+		if err != nil {
+			return nil, err
+		}
+		return toStatusTransitionResponse(scheduled), nil
+	}
+
+	applied, err := s.lifecycleRepo.ApplyTransition(ctx, transition, transitionAuditLog(transition, action, false))
+	if err != nil {
+		if errors.Is(err, repositories.ErrEmployeeStatusChanged) {
+			return nil, &utils.ValidationError{Field: "status", Message: err.Error()}
+		}
+		return nil, err
+	}
+	return toStatusTransitionResponse(applied), nil
+}
+
+func (s *EmployeeLifecycleService) ListTransitions(ctx context.Context, companyID, employeeID uuid.UUID) ([]*dto.StatusTransitionResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+
+	transitions, err := s.lifecycleRepo.ListTransitions(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.StatusTransitionResponse, 0, len(transitions))
+	for _, t := range transitions {
+		responses = append(responses, toStatusTransitionResponse(t))
+	}
+	return responses, nil
+}
+
+func (s *EmployeeLifecycleService) CancelScheduledTransition(ctx context.Context, companyID, employeeID, transitionID uuid.UUID) error {
+	transition, err := s.lifecycleRepo.GetTransitionByID(ctx, transitionID)
+	if err != nil || transition.CompanyID != companyID || transition.EmployeeID != employeeID {
+		return ErrTransitionNotFound
+	}
+	if transition.Status != "scheduled" {
+		return &utils.ValidationError{Field: "status", Message: "only scheduled transitions can be cancelled"}
+	}
+	return s.lifecycleRepo.CancelTransition(ctx, transitionID)
+}
+
+// ApplyDueTransitions applies every scheduled transition effective on or
+// before asOf. Transitions that are no longer valid (the employee's status
+// changed in the meantime) are marked failed rather than retried. It returns
+// the number of transitions applied.
+func (s *EmployeeLifecycleService) ApplyDueTransitions(ctx context.Context, asOf time.Time) (int, error) {
+	due, err := s.lifecycleRepo.GetDueTransitions(ctx, asOf)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, transition := range due {
+		employee, err := s.employeeRepo.GetEmployeeByID(ctx, transition.EmployeeID)
+		if err != nil {
+			return applied, err
+		}
+
+		if employee.Status != transition.FromStatus {
+			reason := fmt.Sprintf("employee status changed from %s to %s before the effective date", transition.FromStatus, employee.Status)
+			if err := s.lifecycleRepo.MarkTransitionFailed(ctx, transition.ID, reason); err != nil {
+				return applied, err
+			}
+			continue
+		}
+
+		action := "employee_status_changed"
+		if transition.FromStatus == "terminated" {
+			action = "employee_rehired"
+		}
+
+		_, err = s.lifecycleRepo.ApplyTransition(ctx, transition, transitionAuditLog(transition, action, true))
+		if errors.Is(err, repositories.ErrEmployeeStatusChanged) {
+			if err := s.lifecycleRepo.MarkTransitionFailed(ctx, transition.ID, err.Error()); err != nil {
+				return applied, err
+			}
+			continue
+		}
+		if err != nil {
+			return applied, err
+		}
+		applied++
+	}
+
+	if applied > 0 {
+		log.Printf("applied %d scheduled employee status transitions", applied)
+	}
+	return applied, nil
+}
+
+// buildStatusTransition validates req against the employee's current status
+// and the lifecycle rules.
+func buildStatusTransition(employee *models.Employee, req *dto.StatusTransitionRequest, today time.Time) (*models.EmployeeStatusTransition, error) {
+	if employee.Status == req.ToStatus {
+		return nil, &utils.ValidationError{Field: "to_status", Message: fmt.Sprintf("employee is already %s", req.ToStatus)}
+	}
+	if employee.Status == "terminated" {
+		return nil, &utils.ValidationError{Field: "to_status", Message: "terminated employees can only be rehired"}
+	}
+	if !CanTransitionEmployee(employee.Status, req.ToStatus) {
+		return nil, &utils.ValidationError{
+			Field:   "to_status",
+			Message: fmt.Sprintf("cannot move an employee from %s to %s", employee.Status, req.ToStatus),
+		}
+	}
+
+	transition := &models.EmployeeStatusTransition{
+		CompanyID:     employee.CompanyID,
+		EmployeeID:    employee.ID,
+		FromStatus:    employee.Status,
+		ToStatus:      req.ToStatus,
+		EffectiveDate: today,
+		Reason:        strings.TrimSpace(req.Reason),
+	}
+
+	if req.ToStatus == "terminated" {
+		if req.TerminationDate == "" {
+			return nil, &utils.ValidationError{Field: "termination_date", Message: "termination_date is required when terminating an employee"}
+		}
+		if transition.Reason == "" {
+			return nil, &utils.ValidationError{Field: "reason", Message: "reason is required when terminating an employee"}
+		}
+		terminationDate, err := utils.ParseDate(req.TerminationDate)
+		if err != nil {
+			return nil, &utils.ValidationError{Field: "termination_date", Message: "termination_date must be YYYY-MM-DD"}
+		}
+		if terminationDate.Before(employee.HireDate) {
+			return nil, &utils.ValidationError{Field: "termination_date", Message: "termination_date cannot be before hire_date"}
+		}
+		transition.TerminationDate = &terminationDate
+		// Without an explicit effective date the status flips on the
+		// termination date itself.
+		transition.EffectiveDate = terminationDate
+	}
+
+	if req.EffectiveDate != "" {
+		effectiveDate, err := utils.ParseDate(req.EffectiveDate)
+		if err != nil {
+			return nil, &utils.ValidationError{Field: "effective_date", Message: "effective_date must be YYYY-MM-DD"}
+		}
+		transition.EffectiveDate = effectiveDate
+	}
+
+	return transition, nil
+}
+
+func transitionAuditLog(t *models.EmployeeStatusTransition, action string, scheduled bool) *models.AuditLog {
+	newValues := map[string]any{"status": t.ToStatus}
+	if t.TerminationDate != nil {
+		newValues["termination_date"] = t.TerminationDate.Format(utils.DateLayout)
+	}
+	if t.FromStatus == "terminated" {
+		newValues["hire_date"] = t.EffectiveDate.Format(utils.DateLayout)
+		newValues["termination_date"] = nil
+	}
+
+	return &models.AuditLog{
+		CompanyID:        t.CompanyID,
+		UserID:           t.RequestedBy,
+		TargetEmployeeID: &t.EmployeeID,
+		Action:           action,
+		EntityType:       "employee_status_transition",
+		OldValues:        map[string]any{"status": t.FromStatus},
+		NewValues:        newValues,
+		Metadata: map[string]any{
+			"reason":         t.Reason,
+			"effective_date": t.EffectiveDate.Format(utils.DateLayout),
+			"scheduled":      scheduled,
+		},
+	}
+}
+
+func toStatusTransitionResponse(t *models.EmployeeStatusTransition) *dto.StatusTransitionResponse {
+	return &dto.StatusTransitionResponse{
+		ID:              t.ID.String(),
+		EmployeeID:      t.EmployeeID.String(),
+		FromStatus:      t.FromStatus,
+		ToStatus:        t.ToStatus,
+		EffectiveDate:   t.EffectiveDate,
+		TerminationDate: t.TerminationDate,
+		Reason:          t.Reason,
+		Status:          t.Status,
+		RequestedBy:     uuidStringPtr(t.RequestedBy),
+		AppliedAt:       t.AppliedAt,
+		FailureReason:   t.FailureReason,
+		CreatedAt:       t.CreatedAt,
+	}
+}
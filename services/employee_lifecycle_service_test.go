@@ -0,0 +1,77 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+)
+
+func TestCanTransitionEmployee(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{"probation", "active", true},
+		{"active", "terminated", true},
+		{"on_leave", "active", true},
+		{"active", "probation", false},
+		{"terminated", "active", false},
+		{"terminated", "probation", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionEmployee(tt.from, tt.to); got != tt.allowed {
+			t.Errorf("%s -> %s: expected %v, got %v", tt.from, tt.to, tt.allowed, got)
+		}
+	}
+}
+
+func TestBuildStatusTransition_Termination(t *testing.T) {
+	today := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	employee := &models.Employee{
+		ID:        uuid.New(),
+		CompanyID: uuid.New(),
+		Status:    "active",
+		HireDate:  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	if _, err := buildStatusTransition(employee, &dto.StatusTransitionRequest{ToStatus: "terminated", Reason: "resigned"}, today); err == nil {
+		t.Error("expected termination without termination_date to fail")
+	}
+
+	if _, err := buildStatusTransition(employee, &dto.StatusTransitionRequest{ToStatus: "terminated", TerminationDate: "2026-03-31"}, today); err == nil {
+		t.Error("expected termination without reason to fail")
+	}
+
+	transition, err := buildStatusTransition(employee, &dto.StatusTransitionRequest{
+		ToStatus:        "terminated",
+		TerminationDate: "2026-03-31",
+		Reason:          "resigned",
+	}, today)
+	if err != nil {
+		t.Fatalf("expected valid termination, got %v", err)
+	}
+	if !transition.EffectiveDate.Equal(*transition.TerminationDate) {
+		t.Errorf("expected termination to take effect on the termination date, got %v", transition.EffectiveDate)
+	}
+
+	employee.Status = "terminated"
+	if _, err := buildStatusTransition(employee, &dto.StatusTransitionRequest{ToStatus: "active"}, today); err == nil {
+		t.Error("expected terminated employees to require a rehire")
+	}
+}
+
+func TestDeactivatableStatusesMatchTransitions(t *testing.T) {
+	for from := range employeeStatusTransitions {
+		want := CanTransitionEmployee(from, "inactive")
+		if got := slices.Contains(repositories.DeactivatableStatuses, from); got != want {
+			t.Errorf("%s: soft delete allowed %v, lifecycle allows %v", from, got, want)
+		}
+	}
+}
@@ -2,8 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/falasefemi2/companyflowlow/dto"
 	"github.com/falasefemi2/companyflowlow/models"
@@ -31,3 +33,69 @@ func NewEmployeeService(employeeRepo *repositories.EmployeeRepository) *Employee
 func (es *EmployeeService) CreateEmployee(ctx context.Context, req *dto.CreateEmployeeRequest) (*dto.EmployeeResponse, error) {
 	return nil, nil
 }
+
+// getCompanyEmployee loads an employee and hides employees that belong to a
+// different company behind ErrEmployeeNotFound.
+func (es *EmployeeService) getCompanyEmployee(ctx context.Context, companyID, employeeID uuid.UUID) (*models.Employee, error) {
+	return findCompanyEmployee(ctx, es.employeeRepo, companyID, employeeID)
+}
+
+func findCompanyEmployee(ctx context.Context, employeeRepo *repositories.EmployeeRepository, companyID, employeeID uuid.UUID) (*models.Employee, error) {
+	employee, err := employeeRepo.GetEmployeeByID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, err
+	}
+	if employee.CompanyID != companyID {
+		return nil, ErrEmployeeNotFound
+	}
+	return employee, nil
+}
+
+func toEmployeeResponse(employee *models.Employee) *dto.EmployeeResponse {
+	return &dto.EmployeeResponse{
+		ID:                    employee.ID.String(),
+		CompanyID:             employee.CompanyID.String(),
+		Email:                 employee.Email,
+		Phone:                 employee.Phone,
+		FirstName:             employee.FirstName,
+		LastName:              employee.LastName,
+		EmployeeCode:          employee.EmployeeCode,
+		DepartmentID:          uuidString(employee.DepartmentID),
+		DesignationID:         uuidString(employee.DesignationID),
+		LevelID:               uuidString(employee.LevelID),
+		ManagerID:             uuidString(employee.ManagerID),
+		RoleID:                employee.RoleID.String(),
+		Status:                employee.Status,
+		EmploymentType:        employee.EmploymentType,
+		DateOfBirth:           employee.DateOfBirth,
+		HireDate:              employee.HireDate,
+		TerminationDate:       employee.TerminationDate,
+		ProbationEndDate:      employee.ProbationEndDate,
+		Gender:                employee.Gender,
+		Address:               employee.Address,
+		EmergencyContactName:  employee.EmergencyContactName,
+		EmergencyContactPhone: employee.EmergencyContactPhone,
+		ProfileImageUrl:       employee.ProfileImageURL,
+		LastLoginAt:           employee.LastLoginAt,
+		CreatedAt:             employee.CreatedAt,
+		UpdatedAt:             employee.UpdatedAt,
+	}
+}
+
+func uuidString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func uuidStringPtr(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	value := id.String()
+	return &value
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrNotFound is wrapped by every "not found" error returned from services so
+// callers can match on it without knowing the entity involved.
+var ErrNotFound = errors.New("not found")
+
+// ErrForbidden is matched by every error returned when the actor may not
+// perform an action on the resource they named.
+var ErrForbidden = errors.New("forbidden")
+
+// forbiddenError explains why the actor was refused. It matches ErrForbidden
+// while reading as the explanation alone.
+type forbiddenError struct {
+	message string
+}
+
+func (e *forbiddenError) Error() string {
+	return e.message
+}
+
+func (e *forbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// forbidden returns an error matching ErrForbidden with message.
+func forbidden(message string) error {
+	return &forbiddenError{message: message}
+}
+
+var ErrCalendarFeedForbidden = forbidden("only the employee or HR can manage their calendar feed")
+
+var (
+	ErrEmployeeNotFound           = fmt.Errorf("employee %w", ErrNotFound)
+	ErrDepartmentNotFound         = fmt.Errorf("department %w", ErrNotFound)
+	ErrTransitionNotFound         = fmt.Errorf("status transition %w", ErrNotFound)
+	ErrChecklistNotFound          = fmt.Errorf("offboarding checklist %w", ErrNotFound)
+	ErrNotificationNotFound       = fmt.Errorf("notification %w", ErrNotFound)
+	ErrLeaveTypeNotFound          = fmt.Errorf("leave type %w", ErrNotFound)
+	ErrLeaveRequestNotFound       = fmt.Errorf("leave request %w", ErrNotFound)
+	ErrHolidayCalendarNotFound    = fmt.Errorf("holiday calendar %w", ErrNotFound)
+	ErrHolidayNotFound            = fmt.Errorf("holiday %w", ErrNotFound)
+	ErrCalendarFeedNotFound       = fmt.Errorf("calendar feed %w", ErrNotFound)
+	ErrApprovalWorkflowNotFound   = fmt.Errorf("approval workflow %w", ErrNotFound)
+	ErrApprovalRequestNotFound    = fmt.Errorf("approval request %w", ErrNotFound)
+	ErrApprovalDelegationNotFound = fmt.Errorf("approval delegation %w", ErrNotFound)
+	ErrLevelNotFound              = fmt.Errorf("level %w", ErrNotFound)
+	ErrExpenseCategoryNotFound    = fmt.Errorf("expense category %w", ErrNotFound)
+	ErrExpenseClaimNotFound       = fmt.Errorf("expense claim %w", ErrNotFound)
+	ErrExpensePayoutNotFound      = fmt.Errorf("expense payout batch %w", ErrNotFound)
+	ErrCompanyNotFound            = fmt.Errorf("company %w", ErrNotFound)
+	ErrMemoNotFound               = fmt.Errorf("memo %w", ErrNotFound)
+	ErrDisciplinaryCaseNotFound   = fmt.Errorf("disciplinary case %w", ErrNotFound)
+)
+
+// isUniqueViolation reports whether err is a Postgres unique constraint
+// violation, e.g. a duplicate name within a company.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
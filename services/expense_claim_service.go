@@ -0,0 +1,695 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// expenseActions are the action verbs accepted by the API for claims.
+var expenseActions = []string{"submit", "approve", "reject", "request-changes", "resubmit", "withdraw"}
+
+// expenseDecisions maps the deciding actions onto approval engine actions.
+var expenseDecisions = map[string]string{
+	"approve":         "approved",
+	"reject":          "rejected",
+	"request-changes": actionRequestChanges,
+}
+
+// CreateExpenseClaim stores a draft claim in the company's currency. Drafts
+// can be edited freely; limits and receipts are checked on submission.
+func (s *ExpenseService) CreateExpenseClaim(ctx context.Context, companyID, employeeID uuid.UUID, req *dto.CreateExpenseClaimRequest) (*dto.ExpenseClaimResponse, error) {
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if employee.Status == "terminated" || employee.Status == "inactive" {
+		return nil, &utils.ValidationError{Field: "employee", Message: fmt.Sprintf("%s employees cannot claim expenses", employee.Status)}
+	}
+
+	claim := &models.ExpenseClaim{
+		CompanyID:   companyID,
+		EmployeeID:  employeeID,
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+	}
+	if claim.Title == "" {
+		return nil, &utils.ValidationError{Field: "title", Message: "title is required"}
+	}
+	if claim.Items, err = s.buildExpenseItems(ctx, companyID, req.Items); err != nil {
+		return nil, err
+	}
+
+	created, err := s.expenseRepo.CreateExpenseClaim(ctx, claim)
+	if err != nil {
+		return nil, err
+	}
+	return toExpenseClaimResponse(created), nil
+}
+
+func (s *ExpenseService) GetExpenseClaim(ctx context.Context, companyID, employeeID, claimID uuid.UUID) (*dto.ExpenseClaimResponse, error) {
+	_, claim, err := s.employeeClaim(ctx, companyID, employeeID, claimID)
+	if err != nil {
+		return nil, err
+	}
+	return toExpenseClaimResponse(claim), nil
+}
+
+func (s *ExpenseService) ListEmployeeExpenseClaims(ctx context.Context, companyID, employeeID uuid.UUID, status string) ([]*dto.ExpenseClaimResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+	return s.listExpenseClaims(ctx, companyID, &employeeID, status)
+}
+
+// ListCompanyExpenseClaims returns the company's claims, narrowed to one
+// employee when employeeID is not empty.
+func (s *ExpenseService) ListCompanyExpenseClaims(ctx context.Context, companyID uuid.UUID, employeeID, status string) ([]*dto.ExpenseClaimResponse, error) {
+	id, err := parseOptionalUUID(employeeID)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "employee_id", Message: "invalid employee_id"}
+	}
+	return s.listExpenseClaims(ctx, companyID, id, status)
+}
+
+func (s *ExpenseService) listExpenseClaims(ctx context.Context, companyID uuid.UUID, employeeID *uuid.UUID, status string) ([]*dto.ExpenseClaimResponse, error) {
+	claims, err := s.expenseRepo.ListExpenseClaims(ctx, companyID, employeeID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ExpenseClaimResponse, 0, len(claims))
+	for _, c := range claims {
+		responses = append(responses, toExpenseClaimResponse(c))
+	}
+	return responses, nil
+}
+
+// UpdateExpenseClaim lets the claimant edit a draft, or a claim sent back
+// for changes, which stays with them until they resubmit it.
+func (s *ExpenseService) UpdateExpenseClaim(
+	ctx context.Context,
+	companyID, employeeID, claimID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.UpdateExpenseClaimRequest,
+) (*dto.ExpenseClaimResponse, error) {
+	employee, claim, err := s.employeeClaim(ctx, companyID, employeeID, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpenseEditable(claim, actorID, employee.ID); err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		claim.Title = strings.TrimSpace(*req.Title)
+		if claim.Title == "" {
+			return nil, &utils.ValidationError{Field: "title", Message: "title is required"}
+		}
+	}
+	if req.Description != nil {
+		claim.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Items != nil {
+		if claim.Items, err = s.buildExpenseItems(ctx, companyID, *req.Items); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.expenseRepo.EditExpenseClaim(ctx, claim)
+	if errors.Is(err, repositories.ErrExpenseClaimStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: "expense claim was changed by someone else; reload and try again"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toExpenseClaimResponse(updated), nil
+}
+
+// AttachExpenseReceipt sets the receipt of one item of a claim the claimant
+// can still edit.
+func (s *ExpenseService) AttachExpenseReceipt(
+	ctx context.Context,
+	companyID, employeeID, claimID, itemID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.AttachExpenseReceiptRequest,
+) (*dto.ExpenseClaimResponse, error) {
+	employee, claim, err := s.employeeClaim(ctx, companyID, employeeID, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpenseEditable(claim, actorID, employee.ID); err != nil {
+		return nil, err
+	}
+	receiptURL := strings.TrimSpace(req.ReceiptURL)
+	if receiptURL == "" {
+		return nil, &utils.ValidationError{Field: "receipt_url", Message: "receipt_url is required"}
+	}
+
+	updated, err := s.expenseRepo.AttachExpenseReceipt(ctx, claimID, itemID, receiptURL)
+	switch {
+	case errors.Is(err, repositories.ErrExpenseClaimStatusChanged):
+		return nil, &utils.ValidationError{Field: "status", Message: "expense claim was changed by someone else; reload and try again"}
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, &utils.ValidationError{Field: "item_id", Message: "the claim has no such item"}
+	case err != nil:
+		return nil, err
+	}
+	return toExpenseClaimResponse(updated), nil
+}
+
+// ActOnExpenseClaim applies one of the claim actions (submit, approve,
+// reject, request-changes, resubmit, withdraw) on behalf of actorID.
+// Approvals, rejections and requests for changes go through the claim's
+// approval workflow, so an approval may only move it on to the next step.
+func (s *ExpenseService) ActOnExpenseClaim(
+	ctx context.Context,
+	companyID, employeeID, claimID uuid.UUID,
+	actorID *uuid.UUID,
+	action string,
+	req *dto.ExpenseActionRequest,
+) (*dto.ExpenseClaimResponse, error) {
+	if !slices.Contains(expenseActions, action) {
+		return nil, &utils.ValidationError{Field: "action", Message: "action must be one of " + strings.Join(expenseActions, ", ")}
+	}
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+
+	employee, claim, err := s.employeeClaim(ctx, companyID, employeeID, claimID)
+	if err != nil {
+		return nil, err
+	}
+	isRequester := *actorID == employee.ID
+	comments := strings.TrimSpace(req.Comments)
+	if err := checkExpenseAction(claim, action, isRequester, comments); err != nil {
+		return nil, err
+	}
+
+	var updated *models.ExpenseClaim
+	switch action {
+	case "submit":
+		var check repositories.ExpenseSubmissionCheck
+		if check, err = s.expenseSubmissionCheck(ctx, employee); err != nil {
+			return nil, err
+		}
+		if updated, err = s.expenseRepo.SubmitExpenseClaim(ctx, claimID, check); err != nil {
+			break
+		}
+		// The claim stands even if its workflow cannot be started now; the
+		// first decision on it starts it instead.
+		if _, err := s.approvalService.Start(ctx, expenseApprovalSubject(employee, updated)); err != nil {
+			log.Printf("start approval of expense claim %s: %v", claimID, err)
+		}
+	case "approve", "reject", "request-changes":
+		if _, err := s.approvalService.Decide(ctx, companyID, expenseApprovalEntity, claimID, *actorID, expenseDecisions[action], comments); err != nil {
+			return nil, err
+		}
+		updated, err = s.expenseRepo.GetExpenseClaimByID(ctx, claimID)
+	case "resubmit":
+		return s.resubmitExpenseClaim(ctx, employee, claim, comments)
+	case "withdraw":
+		updated, err = s.expenseRepo.TransitionExpenseClaim(ctx, claimID, claim.Status, &models.ApprovalHistory{
+			ApproverID: actorID,
+			Action:     "withdrawn",
+			Comments:   comments,
+		})
+	}
+	if errors.Is(err, repositories.ErrExpenseClaimStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: "expense claim was changed by someone else; reload and try again"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toExpenseClaimResponse(updated), nil
+}
+
+// resubmitExpenseClaim puts a claim sent back for changes before its
+// approvers again, at the step the approval engine picks, once it passes
+// the submission checks afresh.
+func (s *ExpenseService) resubmitExpenseClaim(
+	ctx context.Context,
+	employee *models.Employee,
+	claim *models.ExpenseClaim,
+	comments string,
+) (*dto.ExpenseClaimResponse, error) {
+	check, err := s.expenseSubmissionCheck(ctx, employee)
+	if err != nil {
+		return nil, err
+	}
+	subject := expenseApprovalSubject(employee, claim)
+	resubmission, err := s.approvalService.PrepareResubmission(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.expenseRepo.ResubmitExpenseClaim(ctx, claim.ID, resubmission.Step, &models.ApprovalHistory{
+		ApproverID: &employee.ID,
+		Action:     "resubmitted",
+		Comments:   comments,
+	}, resubmission.Revision, check)
+	if errors.Is(err, repositories.ErrExpenseClaimStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: "expense claim was changed by someone else; reload and try again"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subject.Status = updated.Status
+	s.approvalService.Resubmitted(ctx, subject)
+	return toExpenseClaimResponse(updated), nil
+}
+
+// expenseSubmissionCheck returns the checks a claim of employee must pass
+// to go before approvers: it has items, each carries the receipt its
+// category requires, and together with the employee's other claims it stays
+// within their level's limits. The repository runs them inside the
+// submitting transaction, with the employee locked, so two claims submitted
+// at once cannot both slip under a limit.
+func (s *ExpenseService) expenseSubmissionCheck(ctx context.Context, employee *models.Employee) (repositories.ExpenseSubmissionCheck, error) {
+	categories, err := s.expenseCategoriesByID(ctx, employee.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	var limits []*models.ExpenseLimit
+	if employee.LevelID != nil {
+		if limits, err = s.expenseRepo.ListExpenseLimits(ctx, *employee.LevelID); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(claim *models.ExpenseClaim, spend repositories.ExpenseSpendReader) error {
+		if len(claim.Items) == 0 {
+			return &utils.ValidationError{Field: "items", Message: "add at least one item before submitting"}
+		}
+		for i, item := range claim.Items {
+			if category := categories[item.CategoryID]; category != nil && category.RequiresReceipt && item.ReceiptURL == "" {
+				return &utils.ValidationError{Field: fmt.Sprintf("items[%d].receipt_url", i), Message: category.Name + " expenses need a receipt"}
+			}
+		}
+
+		if len(limits) == 0 {
+			return nil
+		}
+		spent, err := spend(expenseMonths(claim.Items))
+		if err != nil {
+			return err
+		}
+		if violations := expenseLimitViolations(limits, claim.Items, spent, categories, claim.Currency); len(violations) > 0 {
+			return &utils.ValidationError{Field: "items", Message: "over the spending limit for your level: " + strings.Join(violations, "; ")}
+		}
+		return nil
+	}, nil
+}
+
+// employeeClaim loads one of an employee's claims.
+func (s *ExpenseService) employeeClaim(ctx context.Context, companyID, employeeID, claimID uuid.UUID) (*models.Employee, *models.ExpenseClaim, error) {
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	claim, err := s.expenseRepo.GetExpenseClaimByID(ctx, claimID)
+	if err != nil || claim.EmployeeID != employeeID {
+		return nil, nil, ErrExpenseClaimNotFound
+	}
+	return employee, claim, nil
+}
+
+func (s *ExpenseService) ListApprovalHistory(ctx context.Context, companyID, employeeID, claimID uuid.UUID) ([]*dto.ApprovalHistoryResponse, error) {
+	if _, _, err := s.employeeClaim(ctx, companyID, employeeID, claimID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.approvalHistoryRepo.ListApprovalHistory(ctx, expenseApprovalEntity, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ApprovalHistoryResponse, 0, len(history))
+	for _, h := range history {
+		responses = append(responses, toApprovalHistoryResponse(h))
+	}
+	return responses, nil
+}
+
+// ApprovalSubject implements ApprovalTarget for expense claims.
+func (s *ExpenseService) ApprovalSubject(ctx context.Context, companyID, claimID uuid.UUID) (*ApprovalSubject, error) {
+	claim, err := s.expenseRepo.GetExpenseClaimByID(ctx, claimID)
+	if err != nil || claim.CompanyID != companyID {
+		return nil, ErrExpenseClaimNotFound
+	}
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, claim.EmployeeID)
+	if err != nil {
+		return nil, ErrExpenseClaimNotFound
+	}
+	return expenseApprovalSubject(employee, claim), nil
+}
+
+// ApplyApprovalDecision implements ApprovalTarget for expense claims: an
+// intermediate approval moves the claim to the next step, a final decision
+// approves or rejects it and a request for changes sends it back, telling
+// the claimant of either.
+func (s *ExpenseService) ApplyApprovalDecision(ctx context.Context, subject *ApprovalSubject, decision *ApprovalDecision) error {
+	history := &models.ApprovalHistory{
+		ApproverID: decision.ActorID,
+		OnBehalfOf: decision.OnBehalfOf,
+		Action:     decision.Action,
+		Comments:   decision.Comments,
+	}
+
+	var err error
+	var updated *models.ExpenseClaim
+	switch {
+	case decision.Returned():
+		if updated, err = s.expenseRepo.ReturnExpenseClaim(ctx, subject.EntityID, decision.Step, history); err == nil {
+			s.notifyClaimant(ctx, subject.Requester, updated, decision.Comments)
+		}
+	case decision.Final():
+		if updated, err = s.expenseRepo.FinalizeExpenseClaim(ctx, subject.EntityID, decision.Step, history); err == nil {
+			s.notifyClaimant(ctx, subject.Requester, updated, decision.Comments)
+		}
+	default:
+		_, err = s.expenseRepo.AdvanceExpenseClaim(ctx, subject.EntityID, decision.Step, decision.NextStep, history)
+	}
+	if errors.Is(err, repositories.ErrExpenseClaimStatusChanged) {
+		return &utils.ValidationError{Field: "status", Message: "expense claim was changed by someone else; reload and try again"}
+	}
+	return err
+}
+
+// notifyClaimant tells the employee that someone else acted on their
+// claim. Failures are logged rather than undoing the change.
+func (s *ExpenseService) notifyClaimant(ctx context.Context, employee *models.Employee, claim *models.ExpenseClaim, comments string) {
+	title := "Expense claim " + claim.Status
+	body := fmt.Sprintf("Your expense claim %q for %s has been %s.", claim.Title, formatMoney(claim.Currency, claim.TotalAmount), claim.Status)
+	dedupeKey := fmt.Sprintf("expense_%s:%s", claim.Status, claim.ID)
+	switch claim.Status {
+	case "changes_requested":
+		title = "Changes requested to expense claim"
+		body = fmt.Sprintf("Your expense claim %q needs changes before it can be approved; edit and resubmit it.", claim.Title)
+		// A claim can be sent back more than once.
+		dedupeKey += fmt.Sprintf(":%d", claim.UpdatedAt.Unix())
+	case "reimbursed":
+		body = fmt.Sprintf("Your expense claim %q for %s has been paid out.", claim.Title, formatMoney(claim.Currency, claim.TotalAmount))
+	}
+	if comments != "" {
+		body += " Comments: " + comments
+	}
+
+	if _, err := s.notificationService.Notify(ctx, models.Notification{
+		CompanyID:  employee.CompanyID,
+		Type:       "expense_" + claim.Status,
+		Title:      title,
+		Body:       body,
+		EntityType: expenseApprovalEntity,
+		EntityID:   &claim.ID,
+		DedupeKey:  dedupeKey,
+	}, employee.ID); err != nil {
+		log.Printf("notify expense claim %s %s: %v", claim.ID, claim.Status, err)
+	}
+}
+
+func expenseApprovalSubject(employee *models.Employee, claim *models.ExpenseClaim) *ApprovalSubject {
+	return &ApprovalSubject{
+		EntityType:   expenseApprovalEntity,
+		EntityID:     claim.ID,
+		WorkflowType: "expense",
+		Requester:    employee,
+		Title: fmt.Sprintf("Expense claim %q for %s %s (%s)", claim.Title, employee.FirstName, employee.LastName,
+			formatMoney(claim.Currency, claim.TotalAmount)),
+		Status:   claim.Status,
+		Revision: expenseRevision(claim),
+	}
+}
+
+// expenseRevision is what approvers review of an expense claim.
+func expenseRevision(claim *models.ExpenseClaim) map[string]any {
+	items := make([]any, 0, len(claim.Items))
+	for _, item := range claim.Items {
+		items = append(items, map[string]any{
+			"category_id":  item.CategoryID.String(),
+			"expense_date": item.ExpenseDate.Format(utils.DateLayout),
+			"description":  item.Description,
+			"merchant":     item.Merchant,
+			"amount":       item.Amount,
+			"receipt_url":  item.ReceiptURL,
+		})
+	}
+	return map[string]any{
+		"title":        claim.Title,
+		"description":  claim.Description,
+		"total_amount": claim.TotalAmount,
+		"items":        items,
+	}
+}
+
+// checkExpenseEditable checks that actorID is the claimant and the claim is
+// a draft or was sent back for changes.
+func checkExpenseEditable(claim *models.ExpenseClaim, actorID *uuid.UUID, claimantID uuid.UUID) error {
+	if actorID == nil || *actorID != claimantID {
+		return forbidden("only the claimant can edit a claim")
+	}
+	if claim.Status != "draft" && claim.Status != "changes_requested" {
+		return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only draft claims or claims sent back for changes can be edited; this one is %s", claim.Status)}
+	}
+	return nil
+}
+
+// checkExpenseAction validates an action against the claim's status and the
+// actor's relationship to it. Only the claimant may submit, resubmit or
+// withdraw a claim, and never decide on it; the approval engine checks
+// deciders against the current step.
+func checkExpenseAction(claim *models.ExpenseClaim, action string, isRequester bool, comments string) error {
+	switch action {
+	case "submit", "resubmit", "withdraw":
+		if !isRequester {
+			return forbidden("only the claimant can " + action + " a claim")
+		}
+	default:
+		if isRequester {
+			return forbidden("claimants cannot decide on their own claims")
+		}
+	}
+
+	switch action {
+	case "submit":
+		if claim.Status != "draft" {
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only draft claims can be submitted; this one is %s", claim.Status)}
+		}
+	case "resubmit":
+		if claim.Status != "changes_requested" {
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only claims sent back for changes can be resubmitted; this one is %s", claim.Status)}
+		}
+	case "withdraw":
+		if claim.Status != "draft" && claim.Status != "pending" && claim.Status != "changes_requested" {
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("a %s claim cannot be withdrawn", claim.Status)}
+		}
+	default:
+		if claim.Status != "pending" {
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only pending claims can be decided; this one is %s", claim.Status)}
+		}
+		if action == "reject" && comments == "" {
+			return &utils.ValidationError{Field: "comments", Message: "a reason is required when rejecting"}
+		}
+		if action == "request-changes" && comments == "" {
+			return &utils.ValidationError{Field: "comments", Message: "say what needs to change when requesting changes"}
+		}
+	}
+	return nil
+}
+
+// buildExpenseItems checks the items of a claim, in the order given,
+// against the company's active categories.
+func (s *ExpenseService) buildExpenseItems(ctx context.Context, companyID uuid.UUID, reqs []dto.ExpenseClaimItemRequest) ([]*models.ExpenseClaimItem, error) {
+	categories, err := s.expenseCategoriesByID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := utils.Today()
+	items := make([]*models.ExpenseClaimItem, 0, len(reqs))
+	for i := range reqs {
+		item, err := buildExpenseItem(fmt.Sprintf("items[%d]", i), &reqs[i], today)
+		if err != nil {
+			return nil, err
+		}
+		if category := categories[item.CategoryID]; category == nil || !category.IsActive {
+			return nil, &utils.ValidationError{Field: fmt.Sprintf("items[%d].category_id", i), Message: "expense category not found"}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// buildExpenseItem parses and checks one item of a claim; field prefixes the
+// fields named in errors.
+func buildExpenseItem(field string, req *dto.ExpenseClaimItemRequest, today time.Time) (*models.ExpenseClaimItem, error) {
+	categoryID, err := uuid.Parse(req.CategoryID)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: field + ".category_id", Message: "invalid category_id"}
+	}
+	expenseDate, err := utils.ParseDate(req.ExpenseDate)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: field + ".expense_date", Message: "expense_date must be YYYY-MM-DD"}
+	}
+	if expenseDate.After(today) {
+		return nil, &utils.ValidationError{Field: field + ".expense_date", Message: "expense_date cannot be in the future"}
+	}
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, &utils.ValidationError{Field: field + ".description", Message: "description is required"}
+	}
+	if req.Amount <= 0 || !wholeCents(req.Amount) {
+		return nil, &utils.ValidationError{Field: field + ".amount", Message: "amount must be more than zero with at most two decimals"}
+	}
+
+	return &models.ExpenseClaimItem{
+		CategoryID:  categoryID,
+		ExpenseDate: expenseDate,
+		Description: description,
+		Merchant:    strings.TrimSpace(req.Merchant),
+		Amount:      req.Amount,
+		ReceiptURL:  strings.TrimSpace(req.ReceiptURL),
+	}, nil
+}
+
+// expenseMonths returns the first day of the earliest month and the last
+// day of the latest month the items fall in.
+func expenseMonths(items []*models.ExpenseClaimItem) (time.Time, time.Time) {
+	from, to := items[0].ExpenseDate, items[0].ExpenseDate
+	for _, item := range items[1:] {
+		if item.ExpenseDate.Before(from) {
+			from = item.ExpenseDate
+		}
+		if item.ExpenseDate.After(to) {
+			to = item.ExpenseDate
+		}
+	}
+	return startOfMonth(from), startOfMonth(to).AddDate(0, 1, -1)
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// expenseLimitViolations describes each limit the items of a claim break,
+// counting what spent says the employee has already claimed in the months
+// they fall in towards monthly limits.
+func expenseLimitViolations(
+	limits []*models.ExpenseLimit,
+	items []*models.ExpenseClaimItem,
+	spent []*models.ExpenseSpend,
+	categories map[uuid.UUID]*models.ExpenseCategory,
+	currency string,
+) []string {
+	var violations []string
+	for _, limit := range limits {
+		covers := func(categoryID uuid.UUID) bool {
+			return limit.CategoryID == nil || *limit.CategoryID == categoryID
+		}
+		what := "all expenses"
+		if limit.CategoryID != nil {
+			what = "category"
+			if category := categories[*limit.CategoryID]; category != nil {
+				what = category.Name
+			}
+		}
+		allowed := toCents(limit.Amount)
+
+		if limit.Period == "claim" {
+			var claimed int64
+			for _, item := range items {
+				if covers(item.CategoryID) {
+					claimed += toCents(item.Amount)
+				}
+			}
+			if claimed > allowed {
+				violations = append(violations, fmt.Sprintf("%s: %s claimed, limit %s per claim",
+					what, formatCents(currency, claimed), formatCents(currency, allowed)))
+			}
+			continue
+		}
+
+		claimedByMonth := make(map[time.Time]int64)
+		var months []time.Time
+		for _, item := range items {
+			if !covers(item.CategoryID) {
+				continue
+			}
+			month := startOfMonth(item.ExpenseDate)
+			if _, ok := claimedByMonth[month]; !ok {
+				months = append(months, month)
+			}
+			claimedByMonth[month] += toCents(item.Amount)
+		}
+		slices.SortFunc(months, func(a, b time.Time) int { return a.Compare(b) })
+
+		for _, month := range months {
+			var before int64
+			for _, sp := range spent {
+				if covers(sp.CategoryID) && sp.Month.Year() == month.Year() && sp.Month.Month() == month.Month() {
+					before += toCents(sp.Amount)
+				}
+			}
+			if claimed := claimedByMonth[month]; before+claimed > allowed {
+				violations = append(violations, fmt.Sprintf("%s in %s: %s claimed here and %s already, limit %s per month",
+					what, month.Format("January 2006"), formatCents(currency, claimed), formatCents(currency, before), formatCents(currency, allowed)))
+			}
+		}
+	}
+	return violations
+}
+
+func formatMoney(currency string, amount float64) string {
+	return formatCents(currency, toCents(amount))
+}
+
+func formatCents(currency string, cents int64) string {
+	return fmt.Sprintf("%s %d.%02d", currency, cents/100, cents%100)
+}
+
+func toExpenseClaimResponse(c *models.ExpenseClaim) *dto.ExpenseClaimResponse {
+	items := make([]*dto.ExpenseClaimItemResponse, 0, len(c.Items))
+	for _, item := range c.Items {
+		items = append(items, &dto.ExpenseClaimItemResponse{
+			ID:          item.ID.String(),
+			CategoryID:  item.CategoryID.String(),
+			ExpenseDate: item.ExpenseDate,
+			Description: item.Description,
+			Merchant:    item.Merchant,
+			Amount:      item.Amount,
+			ReceiptURL:  item.ReceiptURL,
+		})
+	}
+	return &dto.ExpenseClaimResponse{
+		ID:              c.ID.String(),
+		EmployeeID:      c.EmployeeID.String(),
+		Title:           c.Title,
+		Description:     c.Description,
+		Currency:        c.Currency,
+		TotalAmount:     c.TotalAmount,
+		Status:          c.Status,
+		CurrentStep:     c.CurrentStep,
+		SubmittedAt:     c.SubmittedAt,
+		ApprovedBy:      uuidStringPtr(c.ApprovedBy),
+		ApprovedAt:      c.ApprovedAt,
+		RejectionReason: c.RejectionReason,
+		PayoutBatchID:   uuidStringPtr(c.PayoutBatchID),
+		ReimbursedAt:    c.ReimbursedAt,
+		Items:           items,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+}
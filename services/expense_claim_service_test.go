@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+func TestExpenseLimitViolations(t *testing.T) {
+	travel, meals := uuid.New(), uuid.New()
+	categories := map[uuid.UUID]*models.ExpenseCategory{
+		travel: {ID: travel, Name: "Travel"},
+		meals:  {ID: meals, Name: "Meals"},
+	}
+	march := time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2026, time.April, 2, 0, 0, 0, 0, time.UTC)
+	item := func(category uuid.UUID, date time.Time, amount float64) *models.ExpenseClaimItem {
+		return &models.ExpenseClaimItem{CategoryID: category, ExpenseDate: date, Amount: amount}
+	}
+	items := []*models.ExpenseClaimItem{item(travel, march, 60.10), item(meals, march, 20), item(travel, april, 30)}
+
+	tests := []struct {
+		name   string
+		limit  *models.ExpenseLimit
+		spent  []*models.ExpenseSpend
+		want   int
+		inText string
+	}{
+		{"claim total within limit", &models.ExpenseLimit{Period: "claim", Amount: 110.10}, nil, 0, ""},
+		{"claim total over limit", &models.ExpenseLimit{Period: "claim", Amount: 110.09}, nil, 1, "all expenses: USD 110.10 claimed, limit USD 110.09 per claim"},
+		{"category limit ignores other categories", &models.ExpenseLimit{CategoryID: &meals, Period: "claim", Amount: 20}, nil, 0, ""},
+		{"category over limit", &models.ExpenseLimit{CategoryID: &travel, Period: "claim", Amount: 90}, nil, 1, "Travel: USD 90.10 claimed"},
+		{"monthly limit per month", &models.ExpenseLimit{CategoryID: &travel, Period: "month", Amount: 61}, nil, 0, ""},
+		{
+			"monthly limit counts earlier claims",
+			&models.ExpenseLimit{CategoryID: &travel, Period: "month", Amount: 100},
+			[]*models.ExpenseSpend{{Month: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), CategoryID: travel, Amount: 40}},
+			1, "Travel in March 2026: USD 60.10 claimed here and USD 40.00 already, limit USD 100.00 per month",
+		},
+		{
+			"monthly limit ignores other months and categories",
+			&models.ExpenseLimit{CategoryID: &travel, Period: "month", Amount: 100},
+			[]*models.ExpenseSpend{
+				{Month: time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC), CategoryID: travel, Amount: 90},
+				{Month: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), CategoryID: meals, Amount: 90},
+			},
+			0, "",
+		},
+		{
+			"overall monthly limit",
+			&models.ExpenseLimit{Period: "month", Amount: 100},
+			[]*models.ExpenseSpend{{Month: time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), CategoryID: meals, Amount: 80}},
+			1, "all expenses in April 2026",
+		},
+	}
+
+	for _, tt := range tests {
+		got := expenseLimitViolations([]*models.ExpenseLimit{tt.limit}, items, tt.spent, categories, "USD")
+		if len(got) != tt.want {
+			t.Errorf("%s: expected %d violations, got %v", tt.name, tt.want, got)
+			continue
+		}
+		if tt.inText != "" && !strings.Contains(got[0], tt.inText) {
+			t.Errorf("%s: expected %q in %q", tt.name, tt.inText, got[0])
+		}
+	}
+}
+
+func TestBuildExpenseItem(t *testing.T) {
+	today := time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)
+	valid := dto.ExpenseClaimItemRequest{
+		CategoryID:  uuid.NewString(),
+		ExpenseDate: "2026-03-14",
+		Description: " Taxi to client ",
+		Amount:      23.5,
+	}
+
+	tests := []struct {
+		name      string
+		edit      func(r *dto.ExpenseClaimItemRequest)
+		wantField string
+	}{
+		{"valid", func(r *dto.ExpenseClaimItemRequest) {}, ""},
+		{"bad category", func(r *dto.ExpenseClaimItemRequest) { r.CategoryID = "travel" }, "items[0].category_id"},
+		{"bad date", func(r *dto.ExpenseClaimItemRequest) { r.ExpenseDate = "14/03/2026" }, "items[0].expense_date"},
+		{"future date", func(r *dto.ExpenseClaimItemRequest) { r.ExpenseDate = "2026-03-16" }, "items[0].expense_date"},
+		{"no description", func(r *dto.ExpenseClaimItemRequest) { r.Description = "  " }, "items[0].description"},
+		{"zero amount", func(r *dto.ExpenseClaimItemRequest) { r.Amount = 0 }, "items[0].amount"},
+		{"fractional cents", func(r *dto.ExpenseClaimItemRequest) { r.Amount = 10.005 }, "items[0].amount"},
+	}
+
+	for _, tt := range tests {
+		req := valid
+		tt.edit(&req)
+		item, err := buildExpenseItem("items[0]", &req, today)
+		if tt.wantField == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			} else if item.Description != "Taxi to client" {
+				t.Errorf("%s: expected trimmed description, got %q", tt.name, item.Description)
+			}
+			continue
+		}
+		var validationErr *utils.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+			t.Errorf("%s: expected error on %s, got %v", tt.name, tt.wantField, err)
+		}
+	}
+}
+
+func TestCheckExpenseAction(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		action      string
+		isRequester bool
+		comments    string
+		wantErr     bool
+	}{
+		{"submit draft", "draft", "submit", true, "", false},
+		{"submit someone else's draft", "draft", "submit", false, "", true},
+		{"submit pending", "pending", "submit", true, "", true},
+		{"approve pending", "pending", "approve", false, "", false},
+		{"approve own claim", "pending", "approve", true, "", true},
+		{"approve draft", "draft", "approve", false, "", true},
+		{"reject without reason", "pending", "reject", false, "", true},
+		{"reject with reason", "pending", "reject", false, "no receipt", false},
+		{"request changes without comments", "pending", "request-changes", false, "", true},
+		{"resubmit returned claim", "changes_requested", "resubmit", true, "", false},
+		{"resubmit pending claim", "pending", "resubmit", true, "", true},
+		{"withdraw returned claim", "changes_requested", "withdraw", true, "", false},
+		{"withdraw approved claim", "approved", "withdraw", true, "", true},
+	}
+
+	for _, tt := range tests {
+		err := checkExpenseAction(&models.ExpenseClaim{Status: tt.status}, tt.action, tt.isRequester, tt.comments)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
+
+func TestExpenseMonths(t *testing.T) {
+	items := []*models.ExpenseClaimItem{
+		{ExpenseDate: time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		{ExpenseDate: time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)},
+		{ExpenseDate: time.Date(2026, time.February, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	from, to := expenseMonths(items)
+	if want := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC); !from.Equal(want) {
+		t.Errorf("expected from %s, got %s", want, from)
+	}
+	if want := time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC); !to.Equal(want) {
+		t.Errorf("expected to %s, got %s", want, to)
+	}
+}
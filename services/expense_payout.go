@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// CreateExpensePayout pays approved claims out in one batch per currency,
+// marking them reimbursed and telling each claimant. Only HR can pay claims
+// out.
+func (s *ExpenseService) CreateExpensePayout(
+	ctx context.Context,
+	companyID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.CreateExpensePayoutRequest,
+) ([]*dto.ExpensePayoutBatchResponse, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	if err := s.checkHR(ctx, companyID, *actorID); err != nil {
+		return nil, err
+	}
+
+	claimIDs := make([]uuid.UUID, 0, len(req.ClaimIDs))
+	for i, value := range req.ClaimIDs {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, &utils.ValidationError{Field: fmt.Sprintf("claim_ids[%d]", i), Message: "invalid claim id"}
+		}
+		claimIDs = append(claimIDs, id)
+	}
+
+	batches, claimsByBatch, err := s.expenseRepo.CreateExpensePayoutBatches(ctx, companyID, claimIDs, actorID)
+	if errors.Is(err, repositories.ErrNoExpenseClaimsToPay) {
+		return nil, &utils.ValidationError{Field: "claim_ids", Message: err.Error()}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ExpensePayoutBatchResponse, 0, len(batches))
+	for _, batch := range batches {
+		response := toExpensePayoutBatchResponse(batch)
+		response.Claims = make([]*dto.ExpenseClaimResponse, 0, len(claimsByBatch[batch.ID]))
+		for _, claim := range claimsByBatch[batch.ID] {
+			if employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, claim.EmployeeID); err == nil {
+				s.notifyClaimant(ctx, employee, claim, "")
+			}
+			response.Claims = append(response.Claims, toExpenseClaimResponse(claim))
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (s *ExpenseService) GetExpensePayout(ctx context.Context, companyID, batchID uuid.UUID) (*dto.ExpensePayoutBatchResponse, error) {
+	batch, err := s.expenseRepo.GetExpensePayoutBatchByID(ctx, batchID)
+	if err != nil || batch.CompanyID != companyID {
+		return nil, ErrExpensePayoutNotFound
+	}
+	return toExpensePayoutBatchResponse(batch), nil
+}
+
+func (s *ExpenseService) ListExpensePayouts(ctx context.Context, companyID uuid.UUID) ([]*dto.ExpensePayoutBatchResponse, error) {
+	batches, err := s.expenseRepo.ListExpensePayoutBatches(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ExpensePayoutBatchResponse, 0, len(batches))
+	for _, b := range batches {
+		responses = append(responses, toExpensePayoutBatchResponse(b))
+	}
+	return responses, nil
+}
+
+// checkHR checks that employeeID holds the HR role in the company.
+func (s *ExpenseService) checkHR(ctx context.Context, companyID, employeeID uuid.UUID) error {
+	ok, err := isHR(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil || ok {
+		return err
+	}
+	return forbidden("only HR can pay out expense claims")
+}
+
+func toExpensePayoutBatchResponse(b *models.ExpensePayoutBatch) *dto.ExpensePayoutBatchResponse {
+	return &dto.ExpensePayoutBatchResponse{
+		ID:          b.ID.String(),
+		Reference:   b.Reference,
+		Currency:    b.Currency,
+		TotalAmount: b.TotalAmount,
+		ClaimCount:  b.ClaimCount,
+		CreatedBy:   uuidStringPtr(b.CreatedBy),
+		CreatedAt:   b.CreatedAt,
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// expenseApprovalEntity is the approval entity type of expense claims.
+const expenseApprovalEntity = "expense_claim"
+
+type ExpenseService struct {
+	employeeRepo        *repositories.EmployeeRepository
+	expenseRepo         *repositories.ExpenseRepository
+	approvalHistoryRepo *repositories.ApprovalHistoryRepository
+	notificationService *NotificationService
+	approvalService     *ApprovalService
+}
+
+func NewExpenseService(
+	employeeRepo *repositories.EmployeeRepository,
+	expenseRepo *repositories.ExpenseRepository,
+	approvalHistoryRepo *repositories.ApprovalHistoryRepository,
+	notificationService *NotificationService,
+	approvalService *ApprovalService,
+) *ExpenseService {
+	s := &ExpenseService{
+		employeeRepo:        employeeRepo,
+		expenseRepo:         expenseRepo,
+		approvalHistoryRepo: approvalHistoryRepo,
+		notificationService: notificationService,
+		approvalService:     approvalService,
+	}
+	approvalService.RegisterTarget(expenseApprovalEntity, s)
+	return s
+}
+
+func (s *ExpenseService) CreateExpenseCategory(ctx context.Context, companyID uuid.UUID, req *dto.CreateExpenseCategoryRequest) (*dto.ExpenseCategoryResponse, error) {
+	category := &models.ExpenseCategory{
+		CompanyID:       companyID,
+		Name:            strings.TrimSpace(req.Name),
+		Description:     strings.TrimSpace(req.Description),
+		RequiresReceipt: req.RequiresReceipt == nil || *req.RequiresReceipt,
+		IsActive:        req.IsActive == nil || *req.IsActive,
+	}
+	if category.Name == "" {
+		return nil, &utils.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	created, err := s.expenseRepo.CreateExpenseCategory(ctx, category)
+	if isUniqueViolation(err) {
+		return nil, &utils.ValidationError{Field: "name", Message: "an expense category with this name already exists"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toExpenseCategoryResponse(created), nil
+}
+
+// ListExpenseCategories returns the company's categories, only the active
+// ones when activeOnly is set.
+func (s *ExpenseService) ListExpenseCategories(ctx context.Context, companyID uuid.UUID, activeOnly bool) ([]*dto.ExpenseCategoryResponse, error) {
+	categories, err := s.expenseRepo.ListExpenseCategories(ctx, companyID, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ExpenseCategoryResponse, 0, len(categories))
+	for _, c := range categories {
+		responses = append(responses, toExpenseCategoryResponse(c))
+	}
+	return responses, nil
+}
+
+func (s *ExpenseService) UpdateExpenseCategory(ctx context.Context, companyID, categoryID uuid.UUID, req *dto.UpdateExpenseCategoryRequest) (*dto.ExpenseCategoryResponse, error) {
+	category, err := s.getCompanyExpenseCategory(ctx, companyID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		category.Name = strings.TrimSpace(*req.Name)
+		if category.Name == "" {
+			return nil, &utils.ValidationError{Field: "name", Message: "name is required"}
+		}
+	}
+	if req.Description != nil {
+		category.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.RequiresReceipt != nil {
+		category.RequiresReceipt = *req.RequiresReceipt
+	}
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+
+	updated, err := s.expenseRepo.UpdateExpenseCategory(ctx, category)
+	if isUniqueViolation(err) {
+		return nil, &utils.ValidationError{Field: "name", Message: "an expense category with this name already exists"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toExpenseCategoryResponse(updated), nil
+}
+
+func (s *ExpenseService) getCompanyExpenseCategory(ctx context.Context, companyID, categoryID uuid.UUID) (*models.ExpenseCategory, error) {
+	category, err := s.expenseRepo.GetExpenseCategoryByID(ctx, categoryID)
+	if err != nil || category.CompanyID != companyID {
+		return nil, ErrExpenseCategoryNotFound
+	}
+	return category, nil
+}
+
+// expenseCategoriesByID returns every category of the company by ID.
+func (s *ExpenseService) expenseCategoriesByID(ctx context.Context, companyID uuid.UUID) (map[uuid.UUID]*models.ExpenseCategory, error) {
+	categories, err := s.expenseRepo.ListExpenseCategories(ctx, companyID, false)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*models.ExpenseCategory, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+	return byID, nil
+}
+
+func (s *ExpenseService) ListExpenseLimits(ctx context.Context, companyID, levelID uuid.UUID) ([]*dto.ExpenseLimitResponse, error) {
+	if err := s.checkCompanyLevel(ctx, companyID, levelID); err != nil {
+		return nil, err
+	}
+
+	limits, err := s.expenseRepo.ListExpenseLimits(ctx, levelID)
+	if err != nil {
+		return nil, err
+	}
+	return toExpenseLimitResponses(limits), nil
+}
+
+// SetExpenseLimits replaces the spending limits of a level. At most one
+// limit applies per category (or to all categories) and period.
+func (s *ExpenseService) SetExpenseLimits(ctx context.Context, companyID, levelID uuid.UUID, req *dto.SetExpenseLimitsRequest) ([]*dto.ExpenseLimitResponse, error) {
+	if err := s.checkCompanyLevel(ctx, companyID, levelID); err != nil {
+		return nil, err
+	}
+	categories, err := s.expenseCategoriesByID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make([]*models.ExpenseLimit, 0, len(req.Limits))
+	seen := make(map[string]bool, len(req.Limits))
+	for i, l := range req.Limits {
+		field := fmt.Sprintf("limits[%d]", i)
+		limit := &models.ExpenseLimit{Period: l.Period, Amount: l.Amount}
+		if l.Period != "claim" && l.Period != "month" {
+			return nil, &utils.ValidationError{Field: field + ".period", Message: "period must be claim or month"}
+		}
+		if l.Amount < 0 || !wholeCents(l.Amount) {
+			return nil, &utils.ValidationError{Field: field + ".amount", Message: "amount must be zero or more with at most two decimals"}
+		}
+		if l.CategoryID != "" {
+			categoryID, err := uuid.Parse(l.CategoryID)
+			if err != nil || categories[categoryID] == nil {
+				return nil, &utils.ValidationError{Field: field + ".category_id", Message: "expense category not found"}
+			}
+			limit.CategoryID = &categoryID
+		}
+
+		key := l.CategoryID + ":" + l.Period
+		if seen[key] {
+			return nil, &utils.ValidationError{Field: field, Message: "only one limit per category and period is allowed"}
+		}
+		seen[key] = true
+		limits = append(limits, limit)
+	}
+
+	created, err := s.expenseRepo.ReplaceExpenseLimits(ctx, companyID, levelID, limits)
+	if err != nil {
+		return nil, err
+	}
+	return toExpenseLimitResponses(created), nil
+}
+
+func (s *ExpenseService) checkCompanyLevel(ctx context.Context, companyID, levelID uuid.UUID) error {
+	exists, err := s.expenseRepo.LevelExists(ctx, companyID, levelID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrLevelNotFound
+	}
+	return nil
+}
+
+// toCents converts an amount to whole cents, so sums and comparisons are
+// exact.
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// wholeCents reports whether amount has at most two decimals.
+func wholeCents(amount float64) bool {
+	return math.Abs(amount*100-math.Round(amount*100)) < 1e-6
+}
+
+func toExpenseCategoryResponse(c *models.ExpenseCategory) *dto.ExpenseCategoryResponse {
+	return &dto.ExpenseCategoryResponse{
+		ID:              c.ID.String(),
+		Name:            c.Name,
+		Description:     c.Description,
+		RequiresReceipt: c.RequiresReceipt,
+		IsActive:        c.IsActive,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+}
+
+func toExpenseLimitResponses(limits []*models.ExpenseLimit) []*dto.ExpenseLimitResponse {
+	responses := make([]*dto.ExpenseLimitResponse, 0, len(limits))
+	for _, l := range limits {
+		responses = append(responses, &dto.ExpenseLimitResponse{
+			ID:         l.ID.String(),
+			LevelID:    l.LevelID.String(),
+			CategoryID: uuidStringPtr(l.CategoryID),
+			Period:     l.Period,
+			Amount:     l.Amount,
+		})
+	}
+	return responses
+}
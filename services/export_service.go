@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type exportColumn[T any] struct {
+	Key      string
+	Header   string
+	Personal bool // Only exported when requested by name
+	Value    func(row *T) string
+}
+
+// employeeExportColumns is the complete list of exportable employee fields.
+// Credentials and session data are not in models.EmployeeExportRow at all,
+// so no column selection can reach them.
+var employeeExportColumns = []exportColumn[models.EmployeeExportRow]{
+	{Key: "id", Header: "ID", Value: func(r *models.EmployeeExportRow) string { return r.ID.String() }},
+	{Key: "employee_code", Header: "Employee Code", Value: func(r *models.EmployeeExportRow) string { return r.EmployeeCode }},
+	{Key: "first_name", Header: "First Name", Value: func(r *models.EmployeeExportRow) string { return r.FirstName }},
+	{Key: "last_name", Header: "Last Name", Value: func(r *models.EmployeeExportRow) string { return r.LastName }},
+	{Key: "email", Header: "Email", Value: func(r *models.EmployeeExportRow) string { return r.Email }},
+	{Key: "phone", Header: "Phone", Value: func(r *models.EmployeeExportRow) string { return r.Phone }},
+	{Key: "department", Header: "Department", Value: func(r *models.EmployeeExportRow) string { return r.DepartmentName }},
+	{Key: "designation", Header: "Designation", Value: func(r *models.EmployeeExportRow) string { return r.DesignationName }},
+	{Key: "level", Header: "Level", Value: func(r *models.EmployeeExportRow) string { return r.LevelName }},
+	{Key: "role", Header: "Role", Value: func(r *models.EmployeeExportRow) string { return r.RoleName }},
+	{Key: "manager", Header: "Manager", Value: func(r *models.EmployeeExportRow) string { return r.ManagerName }},
+	{Key: "manager_code", Header: "Manager Code", Value: func(r *models.EmployeeExportRow) string { return r.ManagerCode }},
+	{Key: "status", Header: "Status", Value: func(r *models.EmployeeExportRow) string { return r.Status }},
+	{Key: "employment_type", Header: "Employment Type", Value: func(r *models.EmployeeExportRow) string { return r.EmploymentType }},
+	{Key: "hire_date", Header: "Hire Date", Value: func(r *models.EmployeeExportRow) string { return r.HireDate.Format(utils.DateLayout) }},
+	{Key: "termination_date", Header: "Termination Date", Value: func(r *models.EmployeeExportRow) string { return exportDate(r.TerminationDate) }},
+	{Key: "probation_end_date", Header: "Probation End Date", Value: func(r *models.EmployeeExportRow) string { return exportDate(r.ProbationEndDate) }},
+	{Key: "gender", Header: "Gender", Personal: true, Value: func(r *models.EmployeeExportRow) string { return r.Gender }},
+	{Key: "date_of_birth", Header: "Date of Birth", Personal: true, Value: func(r *models.EmployeeExportRow) string { return exportDate(r.DateOfBirth) }},
+	{Key: "address", Header: "Address", Personal: true, Value: func(r *models.EmployeeExportRow) string { return r.Address }},
+	{Key: "emergency_contact_name", Header: "Emergency Contact Name", Personal: true, Value: func(r *models.EmployeeExportRow) string { return r.EmergencyContactName }},
+	{Key: "emergency_contact_phone", Header: "Emergency Contact Phone", Personal: true, Value: func(r *models.EmployeeExportRow) string { return r.EmergencyContactPhone }},
+	{Key: "created_at", Header: "Created At", Value: func(r *models.EmployeeExportRow) string { return r.CreatedAt.UTC().Format(time.RFC3339) }},
+}
+
+var departmentExportColumns = []exportColumn[models.DepartmentExportRow]{
+	{Key: "id", Header: "ID", Value: func(r *models.DepartmentExportRow) string { return r.ID.String() }},
+	{Key: "name", Header: "Name", Value: func(r *models.DepartmentExportRow) string { return r.Name }},
+	{Key: "code", Header: "Code", Value: func(r *models.DepartmentExportRow) string { return r.Code }},
+	{Key: "description", Header: "Description", Value: func(r *models.DepartmentExportRow) string { return r.Description }},
+	{Key: "parent_department", Header: "Parent Department", Value: func(r *models.DepartmentExportRow) string { return r.ParentDepartmentName }},
+	{Key: "head_of_department", Header: "Head of Department", Value: func(r *models.DepartmentExportRow) string { return r.HODName }},
+	{Key: "cost_center", Header: "Cost Center", Value: func(r *models.DepartmentExportRow) string { return r.CostCenter }},
+	{Key: "status", Header: "Status", Value: func(r *models.DepartmentExportRow) string { return r.Status }},
+	{Key: "employee_count", Header: "Employee Count", Value: func(r *models.DepartmentExportRow) string { return strconv.Itoa(r.EmployeeCount) }},
+	{Key: "created_at", Header: "Created At", Value: func(r *models.DepartmentExportRow) string { return r.CreatedAt.UTC().Format(time.RFC3339) }},
+}
+
+var expensePayoutExportColumns = []exportColumn[models.ExpensePayoutExportRow]{
+	{Key: "batch_reference", Header: "Batch Reference", Value: func(r *models.ExpensePayoutExportRow) string { return r.BatchReference }},
+	{Key: "claim_id", Header: "Claim ID", Value: func(r *models.ExpensePayoutExportRow) string { return r.ClaimID.String() }},
+	{Key: "employee_code", Header: "Employee Code", Value: func(r *models.ExpensePayoutExportRow) string { return r.EmployeeCode }},
+	{Key: "employee_name", Header: "Employee Name", Value: func(r *models.ExpensePayoutExportRow) string { return r.EmployeeName }},
+	{Key: "email", Header: "Email", Value: func(r *models.ExpensePayoutExportRow) string { return r.Email }},
+	{Key: "title", Header: "Claim", Value: func(r *models.ExpensePayoutExportRow) string { return r.Title }},
+	{Key: "currency", Header: "Currency", Value: func(r *models.ExpensePayoutExportRow) string { return r.Currency }},
+	{Key: "amount", Header: "Amount", Value: func(r *models.ExpensePayoutExportRow) string { return strconv.FormatFloat(r.Amount, 'f', 2, 64) }},
+	{Key: "approved_at", Header: "Approved On", Value: func(r *models.ExpensePayoutExportRow) string { return exportDate(r.ApprovedAt) }},
+	{Key: "reimbursed_at", Header: "Reimbursed On", Value: func(r *models.ExpensePayoutExportRow) string { return exportDate(r.ReimbursedAt) }},
+}
+
+type ExportService struct {
+	exportRepo *repositories.ExportRepository
+}
+
+func NewExportService(exportRepo *repositories.ExportRepository) *ExportService {
+	return &ExportService{
+		exportRepo: exportRepo,
+	}
+}
+
+// ExportEmployees streams the employees matching listRequest to w. Columns
+// default to every non-personal column; personal columns must be named.
+// Validation errors are returned before anything is written to w.
+func (s *ExportService) ExportEmployees(
+	ctx context.Context,
+	companyID uuid.UUID,
+	listRequest *dto.EmployeeListRequest,
+	format string,
+	columns []string,
+	w io.Writer,
+) error {
+	selected, err := selectExportColumns(employeeExportColumns, columns)
+	if err != nil {
+		return err
+	}
+	for field, value := range map[string]string{"department_id": listRequest.DepartmentID, "manager_id": listRequest.ManagerID} {
+		if _, err := uuid.Parse(value); value != "" && err != nil {
+			return &utils.ValidationError{Field: field, Message: "invalid " + field}
+		}
+	}
+
+	return streamExport(format, selected, w, func(emit func(row *models.EmployeeExportRow) error) error {
+		return s.exportRepo.StreamEmployees(ctx, companyID, listRequest, emit)
+	})
+}
+
+// ExportDepartments streams the departments matching listRequest to w.
+func (s *ExportService) ExportDepartments(
+	ctx context.Context,
+	companyID uuid.UUID,
+	listRequest *dto.DepartmentListRequest,
+	format string,
+	columns []string,
+	w io.Writer,
+) error {
+	selected, err := selectExportColumns(departmentExportColumns, columns)
+	if err != nil {
+		return err
+	}
+
+	return streamExport(format, selected, w, func(emit func(row *models.DepartmentExportRow) error) error {
+		return s.exportRepo.StreamDepartments(ctx, companyID, listRequest, emit)
+	})
+}
+
+// ExportExpensePayout streams the claims of a payout batch to w, one row per
+// claimant to pay.
+func (s *ExportService) ExportExpensePayout(
+	ctx context.Context,
+	companyID, batchID uuid.UUID,
+	format string,
+	columns []string,
+	w io.Writer,
+) error {
+	selected, err := selectExportColumns(expensePayoutExportColumns, columns)
+	if err != nil {
+		return err
+	}
+	exists, err := s.exportRepo.ExpensePayoutBatchExists(ctx, companyID, batchID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrExpensePayoutNotFound
+	}
+
+	return streamExport(format, selected, w, func(emit func(row *models.ExpensePayoutExportRow) error) error {
+		return s.exportRepo.StreamExpensePayouts(ctx, companyID, batchID, emit)
+	})
+}
+
+func streamExport[T any](
+	format string,
+	columns []exportColumn[T],
+	w io.Writer,
+	stream func(emit func(row *T) error) error,
+) error {
+	if _, ok := ExportContentType(format); !ok {
+		return &utils.ValidationError{Field: "format", Message: "format must be csv, xlsx or jsonl"}
+	}
+	writer, err := newExportWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, len(columns))
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		keys[i], headers[i] = col.Key, col.Header
+	}
+	if err := writer.WriteHeader(keys, headers); err != nil {
+		return err
+	}
+
+	values := make([]string, len(columns))
+	err = stream(func(row *T) error {
+		for i, col := range columns {
+			values[i] = col.Value(row)
+		}
+		return writer.WriteRow(values)
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// selectExportColumns resolves requested column keys in the order given.
+// With no request it returns every column not marked Personal.
+func selectExportColumns[T any](all []exportColumn[T], requested []string) ([]exportColumn[T], error) {
+	if len(requested) == 0 {
+		var defaults []exportColumn[T]
+		for _, col := range all {
+			if !col.Personal {
+				defaults = append(defaults, col)
+			}
+		}
+		return defaults, nil
+	}
+
+	selected := make([]exportColumn[T], 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, key := range requested {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		found := false
+		for _, col := range all {
+			if col.Key == key {
+				selected = append(selected, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, &utils.ValidationError{Field: "columns", Message: fmt.Sprintf("column %q cannot be exported", key)}
+		}
+	}
+	return selected, nil
+}
+
+func exportDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(utils.DateLayout)
+}
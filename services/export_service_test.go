@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func exportTestRows() []*models.EmployeeExportRow {
+	return []*models.EmployeeExportRow{
+		{ID: uuid.New(), EmployeeCode: "007", FirstName: "Ada", LastName: "Lovelace", Phone: "+44 20 7946 0000", DepartmentName: "R&D <Labs>", HireDate: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{ID: uuid.New(), EmployeeCode: "008", FirstName: "=HYPERLINK(\"x\")", LastName: "Hopper", HireDate: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func runEmployeeExport(t *testing.T, format string, columns []string) []byte {
+	t.Helper()
+	selected, err := selectExportColumns(employeeExportColumns, columns)
+	if err != nil {
+		t.Fatalf("select columns: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = streamExport(format, selected, &buf, func(emit func(row *models.EmployeeExportRow) error) error {
+		for _, row := range exportTestRows() {
+			if err := emit(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("export %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+func TestSelectExportColumns(t *testing.T) {
+	defaults, err := selectExportColumns(employeeExportColumns, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, col := range defaults {
+		if col.Personal {
+			t.Errorf("personal column %s exported by default", col.Key)
+		}
+	}
+
+	for _, key := range []string{"password_hash", "sessions_revoked_at", "salary"} {
+		if _, err := selectExportColumns(employeeExportColumns, []string{"email", key}); err == nil {
+			t.Errorf("expected column %s to be rejected", key)
+		}
+	}
+
+	selected, err := selectExportColumns(employeeExportColumns, []string{"last_name", " Email ", "last_name", "date_of_birth"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 3 || selected[0].Key != "last_name" || selected[1].Key != "email" || selected[2].Key != "date_of_birth" {
+		t.Errorf("unexpected selection %v", selected)
+	}
+}
+
+func TestStreamExport_CSVEscapesFormulas(t *testing.T) {
+	out := string(runEmployeeExport(t, "csv", []string{"employee_code", "first_name", "phone"}))
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || lines[0] != "Employee Code,First Name,Phone" {
+		t.Fatalf("unexpected csv:\n%s", out)
+	}
+	if lines[1] != "007,Ada,+44 20 7946 0000" {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], `008,"'=HYPERLINK`) {
+		t.Errorf("expected formula to be escaped, got %q", lines[2])
+	}
+}
+
+func TestStreamExport_JSONLines(t *testing.T) {
+	out := string(runEmployeeExport(t, "jsonl", []string{"employee_code", "department"}))
+	want := `{"employee_code":"007","department":"R&D <Labs>"}` + "\n" + `{"employee_code":"008","department":""}` + "\n"
+	if out != want {
+		t.Errorf("unexpected jsonl:\n%s", out)
+	}
+}
+
+func TestStreamExport_XLSXRoundTrip(t *testing.T) {
+	out := runEmployeeExport(t, "xlsx", []string{"employee_code", "department", "last_name", "hire_date"})
+
+	header, records, err := readImportTable(out, "xlsx")
+	if err != nil {
+		t.Fatalf("read exported xlsx: %v", err)
+	}
+	if strings.Join(header, "|") != "Employee Code|Department|Last Name|Hire Date" {
+		t.Errorf("unexpected header %v", header)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(records))
+	}
+	if got := strings.Join(records[0].Values, "|"); got != "007|R&D <Labs>|Lovelace|2026-01-05" {
+		t.Errorf("unexpected first row %q", got)
+	}
+	if got := strings.Join(records[1].Values, "|"); got != "008||Hopper|2026-02-01" {
+		t.Errorf("unexpected second row %q", got)
+	}
+}
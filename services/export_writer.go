@@ -0,0 +1,188 @@
+package services
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// exportContentTypes lists the supported export formats.
+var exportContentTypes = map[string]string{
+	"csv":   "text/csv",
+	"xlsx":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"jsonl": "application/x-ndjson",
+}
+
+// ExportContentType returns the MIME type of an export format, or false when
+// the format is not supported.
+func ExportContentType(format string) (string, bool) {
+	contentType, ok := exportContentTypes[format]
+	return contentType, ok
+}
+
+// exportWriter streams a table: one header call followed by any number of
+// rows, then Close to flush the output.
+type exportWriter interface {
+	WriteHeader(keys, headers []string) error
+	WriteRow(values []string) error
+	Close() error
+}
+
+func newExportWriter(format string, w io.Writer) (exportWriter, error) {
+	switch format {
+	case "csv":
+		return &csvExportWriter{w: csv.NewWriter(w)}, nil
+	case "jsonl":
+		return &jsonLinesExportWriter{w: bufio.NewWriter(w)}, nil
+	case "xlsx":
+		return &xlsxExportWriter{zw: zip.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvExportWriter) WriteHeader(_, headers []string) error {
+	return c.w.Write(headers)
+}
+
+func (c *csvExportWriter) WriteRow(values []string) error {
+	for i, v := range values {
+		values[i] = escapeSpreadsheetFormula(v)
+	}
+	return c.w.Write(values)
+}
+
+func (c *csvExportWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonLinesExportWriter writes one JSON object per row keyed by column key,
+// keeping the selected column order.
+type jsonLinesExportWriter struct {
+	w    *bufio.Writer
+	keys []string
+	buf  bytes.Buffer
+}
+
+func (j *jsonLinesExportWriter) WriteHeader(keys, _ []string) error {
+	j.keys = keys
+	return nil
+}
+
+func (j *jsonLinesExportWriter) WriteRow(values []string) error {
+	j.buf.Reset()
+	enc := json.NewEncoder(&j.buf)
+	enc.SetEscapeHTML(false)
+
+	j.buf.WriteByte('{')
+	for i, key := range j.keys {
+		if i > 0 {
+			j.buf.WriteByte(',')
+		}
+		if err := enc.Encode(key); err != nil {
+			return err
+		}
+		j.buf.Truncate(j.buf.Len() - 1) // Encode appends a newline
+		j.buf.WriteByte(':')
+		if err := enc.Encode(values[i]); err != nil {
+			return err
+		}
+		j.buf.Truncate(j.buf.Len() - 1)
+	}
+	j.buf.WriteString("}\n")
+
+	_, err := j.w.Write(j.buf.Bytes())
+	return err
+}
+
+func (j *jsonLinesExportWriter) Close() error {
+	return j.w.Flush()
+}
+
+// xlsxExportWriter writes a single-sheet workbook. The worksheet is the last
+// zip entry so rows can be streamed straight into it; every cell is an inline
+// string, which keeps codes such as "007" intact.
+type xlsxExportWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+var xlsxStaticParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+func (x *xlsxExportWriter) WriteHeader(_, headers []string) error {
+	for _, part := range xlsxStaticParts {
+		f, err := x.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	f, err := x.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(f)
+	x.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return x.WriteRow(headers)
+}
+
+func (x *xlsxExportWriter) WriteRow(values []string) error {
+	x.row++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.row)
+	for _, v := range values {
+		x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(x.sheet, []byte(v)); err != nil {
+			return err
+		}
+		x.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxExportWriter) Close() error {
+	if x.sheet != nil {
+		x.sheet.WriteString(`</sheetData></worksheet>`)
+		if err := x.sheet.Flush(); err != nil {
+			return err
+		}
+	}
+	return x.zw.Close()
+}
+
+// escapeSpreadsheetFormula stops values such as "=HYPERLINK(...)" from being
+// evaluated when a CSV export is opened in a spreadsheet.
+// Phone numbers such as "+234 801 234 5678" are left alone.
+func escapeSpreadsheetFormula(v string) string {
+	if v == "" || !strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return v
+	}
+	if (v[0] == '+' || v[0] == '-') && strings.Trim(v[1:], "0123456789 ()-") == "" {
+		return v
+	}
+	return "'" + v
+}
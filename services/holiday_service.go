@@ -0,0 +1,493 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// maxHolidayRangeDays bounds the date ranges holidays are listed for.
+const maxHolidayRangeDays = 5 * 366
+
+type HolidayService struct {
+	holidayRepo    *repositories.HolidayRepository
+	employeeRepo   *repositories.EmployeeRepository
+	departmentRepo *repositories.DepartmentRepository
+}
+
+func NewHolidayService(
+	holidayRepo *repositories.HolidayRepository,
+	employeeRepo *repositories.EmployeeRepository,
+	departmentRepo *repositories.DepartmentRepository,
+) *HolidayService {
+	return &HolidayService{
+		holidayRepo:    holidayRepo,
+		employeeRepo:   employeeRepo,
+		departmentRepo: departmentRepo,
+	}
+}
+
+func (s *HolidayService) CreateCalendar(ctx context.Context, companyID uuid.UUID, req *dto.CreateHolidayCalendarRequest) (*dto.HolidayCalendarResponse, error) {
+	calendar := &models.HolidayCalendar{
+		CompanyID: companyID,
+		Name:      strings.TrimSpace(req.Name),
+		Country:   strings.TrimSpace(req.Country),
+		IsDefault: req.IsDefault,
+	}
+	if calendar.Name == "" {
+		return nil, &utils.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	var err error
+	if calendar.WorkDays, err = parseWorkDays(req.WorkDays); err != nil {
+		return nil, err
+	}
+	if calendar.DepartmentIDs, err = s.companyDepartments(ctx, companyID, req.DepartmentIDs); err != nil {
+		return nil, err
+	}
+
+	created, err := s.holidayRepo.CreateCalendar(ctx, calendar)
+	if isUniqueViolation(err) {
+		return nil, &utils.ValidationError{Field: "name", Message: "a calendar with this name already exists"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toHolidayCalendarResponse(created), nil
+}
+
+func (s *HolidayService) GetCalendar(ctx context.Context, companyID, calendarID uuid.UUID) (*dto.HolidayCalendarResponse, error) {
+	calendar, err := s.getCompanyCalendar(ctx, companyID, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	return toHolidayCalendarResponse(calendar), nil
+}
+
+func (s *HolidayService) ListCalendars(ctx context.Context, companyID uuid.UUID) ([]*dto.HolidayCalendarResponse, error) {
+	calendars, err := s.holidayRepo.ListCalendars(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.HolidayCalendarResponse, 0, len(calendars))
+	for _, c := range calendars {
+		responses = append(responses, toHolidayCalendarResponse(c))
+	}
+	return responses, nil
+}
+
+func (s *HolidayService) UpdateCalendar(ctx context.Context, companyID, calendarID uuid.UUID, req *dto.UpdateHolidayCalendarRequest) (*dto.HolidayCalendarResponse, error) {
+	calendar, err := s.getCompanyCalendar(ctx, companyID, calendarID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if calendar.Name = strings.TrimSpace(*req.Name); calendar.Name == "" {
+			return nil, &utils.ValidationError{Field: "name", Message: "name cannot be empty"}
+		}
+	}
+	if req.Country != nil {
+		calendar.Country = strings.TrimSpace(*req.Country)
+	}
+	if req.WorkDays != nil {
+		if calendar.WorkDays, err = parseWorkDays(*req.WorkDays); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsDefault != nil {
+		// Another calendar must be made the default instead, so the company
+		// is never left without one.
+		if calendar.IsDefault && !*req.IsDefault {
+			return nil, &utils.ValidationError{Field: "is_default", Message: "make another calendar the default instead"}
+		}
+		calendar.IsDefault = *req.IsDefault
+	}
+	if req.DepartmentIDs != nil {
+		if calendar.DepartmentIDs, err = s.companyDepartments(ctx, companyID, *req.DepartmentIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.holidayRepo.UpdateCalendar(ctx, calendar)
+	if isUniqueViolation(err) {
+		return nil, &utils.ValidationError{Field: "name", Message: "a calendar with this name already exists"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toHolidayCalendarResponse(updated), nil
+}
+
+func (s *HolidayService) DeleteCalendar(ctx context.Context, companyID, calendarID uuid.UUID) error {
+	calendar, err := s.getCompanyCalendar(ctx, companyID, calendarID)
+	if err != nil {
+		return err
+	}
+	if calendar.IsDefault {
+		return &utils.ValidationError{Field: "is_default", Message: "the default calendar cannot be deleted"}
+	}
+	return s.holidayRepo.DeleteCalendar(ctx, calendarID)
+}
+
+func (s *HolidayService) AddHoliday(ctx context.Context, companyID, calendarID uuid.UUID, req *dto.CreateHolidayRequest) (*dto.HolidayResponse, error) {
+	if _, err := s.getCompanyCalendar(ctx, companyID, calendarID); err != nil {
+		return nil, err
+	}
+
+	date, err := utils.ParseDate(req.Date)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "date", Message: "date must be YYYY-MM-DD"}
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, &utils.ValidationError{Field: "name", Message: "name is required"}
+	}
+
+	created, err := s.holidayRepo.CreateHoliday(ctx, &models.CompanyHoliday{
+		CompanyID:   companyID,
+		CalendarID:  calendarID,
+		HolidayDate: date,
+		Name:        name,
+		Recurring:   req.Recurring,
+	})
+	if isUniqueViolation(err) {
+		return nil, &utils.ValidationError{Field: "date", Message: "this holiday is already in the calendar"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toHolidayResponse(created, created.HolidayDate), nil
+}
+
+func (s *HolidayService) DeleteHoliday(ctx context.Context, companyID, calendarID, holidayID uuid.UUID) error {
+	if _, err := s.getCompanyCalendar(ctx, companyID, calendarID); err != nil {
+		return err
+	}
+	holiday, err := s.holidayRepo.GetHolidayByID(ctx, holidayID)
+	if err != nil || holiday.CalendarID != calendarID {
+		return ErrHolidayNotFound
+	}
+	return s.holidayRepo.DeleteHoliday(ctx, holidayID)
+}
+
+// ListHolidays returns every holiday occurrence in the calendar between from
+// and to, with recurring holidays expanded.
+func (s *HolidayService) ListHolidays(ctx context.Context, companyID, calendarID uuid.UUID, from, to time.Time) ([]*dto.HolidayResponse, error) {
+	if err := checkHolidayRange(from, to); err != nil {
+		return nil, err
+	}
+	if _, err := s.getCompanyCalendar(ctx, companyID, calendarID); err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.holidayRepo.ListHolidays(ctx, calendarID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return holidayResponses(holidays, from, to), nil
+}
+
+// ImportICal adds the all-day events of an iCalendar file to a calendar.
+// Multi-day events become one holiday per day. Events already in the
+// calendar are counted as duplicates. Events that cannot be imported are
+// listed with the reason.
+func (s *HolidayService) ImportICal(ctx context.Context, companyID, calendarID uuid.UUID, data []byte) (*dto.HolidayImportResponse, error) {
+	if _, err := s.getCompanyCalendar(ctx, companyID, calendarID); err != nil {
+		return nil, err
+	}
+
+	events, err := parseICalEvents(data)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "file", Message: err.Error()}
+	}
+
+	report := &dto.HolidayImportResponse{Skipped: []string{}, Holidays: []*dto.HolidayResponse{}}
+	var holidays []*models.CompanyHoliday
+	seen := map[string]bool{}
+	for _, event := range events {
+		if event.Skip != "" {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%s: %s", cmp.Or(event.Summary, "(untitled)"), event.Skip))
+			continue
+		}
+		for d := event.Start; !d.After(event.End); d = d.AddDate(0, 0, 1) {
+			key := d.Format(utils.DateLayout) + "|" + event.Summary
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			holidays = append(holidays, &models.CompanyHoliday{
+				CompanyID:   companyID,
+				CalendarID:  calendarID,
+				HolidayDate: d,
+				Name:        event.Summary,
+				Recurring:   event.Recurring,
+			})
+		}
+	}
+
+	inserted, err := s.holidayRepo.ImportHolidays(ctx, holidays)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Imported = len(inserted)
+	report.Duplicates = len(holidays) - len(inserted)
+	for _, h := range inserted {
+		report.Holidays = append(report.Holidays, toHolidayResponse(h, h.HolidayDate))
+	}
+	return report, nil
+}
+
+// WorkCalendarFor builds the calendar used to count an employee's working
+// days between from and to: the work week and holidays of the calendar
+// assigned to their department (or a parent department), falling back to
+// the company default and then to a Monday–Friday week without holidays.
+func (s *HolidayService) WorkCalendarFor(ctx context.Context, employee *models.Employee, from, to time.Time) (*WorkCalendar, error) {
+	calendar, _, err := s.employeeCalendar(ctx, employee, from, to)
+	return calendar, err
+}
+
+// EmployeeWorkCalendar describes the calendar that applies to an employee
+// over a date range.
+func (s *HolidayService) EmployeeWorkCalendar(ctx context.Context, companyID, employeeID uuid.UUID, from, to time.Time) (*dto.WorkCalendarResponse, error) {
+	if err := checkHolidayRange(from, to); err != nil {
+		return nil, err
+	}
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, source, err := s.employeeCalendar(ctx, employee, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.WorkCalendarResponse{
+		CalendarName: "Standard week",
+		WorkDays:     workDayNames(weekdayInts(defaultWorkWeek)),
+		From:         from.Format(utils.DateLayout),
+		To:           to.Format(utils.DateLayout),
+		Holidays:     []*dto.HolidayResponse{},
+	}
+	if source != nil {
+		response.CalendarID = uuidStringPtr(&source.calendar.ID)
+		response.CalendarName = source.calendar.Name
+		response.WorkDays = workDayNames(source.calendar.WorkDays)
+		response.Holidays = holidayResponses(source.holidays, from, to)
+	}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if calendar.IsWorkingDay(d) {
+			response.WorkingDays++
+		}
+	}
+	return response, nil
+}
+
+// calendarSource is the stored calendar a WorkCalendar was built from.
+type calendarSource struct {
+	calendar *models.HolidayCalendar
+	holidays []*models.CompanyHoliday
+}
+
+func (s *HolidayService) employeeCalendar(ctx context.Context, employee *models.Employee, from, to time.Time) (*WorkCalendar, *calendarSource, error) {
+	stored, err := s.holidayRepo.GetEmployeeCalendar(ctx, employee.ID)
+	return s.buildWorkCalendar(ctx, stored, err, from, to)
+}
+
+// DepartmentWorkCalendar builds the calendar that applies to the members of
+// a department between from and to.
+func (s *HolidayService) DepartmentWorkCalendar(ctx context.Context, companyID, departmentID uuid.UUID, from, to time.Time) (*WorkCalendar, error) {
+	stored, err := s.holidayRepo.GetDepartmentCalendar(ctx, companyID, departmentID)
+	calendar, _, err := s.buildWorkCalendar(ctx, stored, err, from, to)
+	return calendar, err
+}
+
+// buildWorkCalendar turns the result of a calendar lookup into a
+// WorkCalendar, using the built-in week when no calendar was found.
+func (s *HolidayService) buildWorkCalendar(ctx context.Context, stored *models.HolidayCalendar, err error, from, to time.Time) (*WorkCalendar, *calendarSource, error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewWorkCalendar(defaultWorkWeek), nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	holidays, err := s.holidayRepo.ListHolidays(ctx, stored.ID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workDays := make([]time.Weekday, 0, len(stored.WorkDays))
+	for _, d := range stored.WorkDays {
+		workDays = append(workDays, time.Weekday(d))
+	}
+	calendar := NewWorkCalendar(workDays)
+	for _, h := range holidays {
+		for _, date := range holidayOccurrences(h, from, to) {
+			calendar.AddHoliday(date, h.Name)
+		}
+	}
+	return calendar, &calendarSource{calendar: stored, holidays: holidays}, nil
+}
+
+func (s *HolidayService) getCompanyCalendar(ctx context.Context, companyID, calendarID uuid.UUID) (*models.HolidayCalendar, error) {
+	calendar, err := s.holidayRepo.GetCalendarByID(ctx, calendarID)
+	if err != nil || calendar.CompanyID != companyID {
+		return nil, ErrHolidayCalendarNotFound
+	}
+	return calendar, nil
+}
+
+// companyDepartments parses department IDs and checks they belong to the
+// company.
+func (s *HolidayService) companyDepartments(ctx context.Context, companyID uuid.UUID, values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, &utils.ValidationError{Field: "department_ids", Message: "invalid department id " + value}
+		}
+		department, err := s.departmentRepo.GetDepartmentByID(ctx, id)
+		if err != nil || department.CompanyID != companyID {
+			return nil, &utils.ValidationError{Field: "department_ids", Message: "department " + value + " not found"}
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// holidayOccurrences returns the dates h falls on between from and to. A
+// recurring holiday on 29 February only occurs in leap years.
+func holidayOccurrences(h *models.CompanyHoliday, from, to time.Time) []time.Time {
+	if !h.Recurring {
+		if h.HolidayDate.Before(from) || h.HolidayDate.After(to) {
+			return nil
+		}
+		return []time.Time{h.HolidayDate}
+	}
+
+	var dates []time.Time
+	for year := max(from.Year(), h.HolidayDate.Year()); year <= to.Year(); year++ {
+		date := time.Date(year, h.HolidayDate.Month(), h.HolidayDate.Day(), 0, 0, 0, 0, time.UTC)
+		if date.Month() != h.HolidayDate.Month() {
+			continue
+		}
+		if !date.Before(from) && !date.After(to) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+func holidayResponses(holidays []*models.CompanyHoliday, from, to time.Time) []*dto.HolidayResponse {
+	responses := []*dto.HolidayResponse{}
+	for _, h := range holidays {
+		for _, date := range holidayOccurrences(h, from, to) {
+			responses = append(responses, toHolidayResponse(h, date))
+		}
+	}
+	slices.SortStableFunc(responses, func(a, b *dto.HolidayResponse) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+	return responses
+}
+
+func checkHolidayRange(from, to time.Time) error {
+	if to.Before(from) {
+		return &utils.ValidationError{Field: "to", Message: "to cannot be before from"}
+	}
+	if to.Sub(from) > maxHolidayRangeDays*24*time.Hour {
+		return &utils.ValidationError{Field: "to", Message: "date range cannot exceed five years"}
+	}
+	return nil
+}
+
+// parseWorkDays converts weekday names (full or three-letter) into
+// time.Weekday numbers, defaulting to Monday–Friday.
+func parseWorkDays(names []string) ([]int, error) {
+	if len(names) == 0 {
+		return weekdayInts(defaultWorkWeek), nil
+	}
+
+	var days []int
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			full := strings.ToLower(d.String())
+			if name == full || name == full[:3] {
+				if !slices.Contains(days, int(d)) {
+					days = append(days, int(d))
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, &utils.ValidationError{Field: "work_days", Message: fmt.Sprintf("unknown weekday %q", name)}
+		}
+	}
+	slices.Sort(days)
+	return days, nil
+}
+
+func weekdayInts(days []time.Weekday) []int {
+	ints := make([]int, 0, len(days))
+	for _, d := range days {
+		ints = append(ints, int(d))
+	}
+	return ints
+}
+
+func workDayNames(days []int) []string {
+	names := make([]string, 0, len(days))
+	for _, d := range days {
+		names = append(names, strings.ToLower(time.Weekday(d).String()))
+	}
+	return names
+}
+
+func toHolidayCalendarResponse(c *models.HolidayCalendar) *dto.HolidayCalendarResponse {
+	departmentIDs := make([]string, 0, len(c.DepartmentIDs))
+	for _, id := range c.DepartmentIDs {
+		departmentIDs = append(departmentIDs, id.String())
+	}
+	return &dto.HolidayCalendarResponse{
+		ID:            c.ID.String(),
+		CompanyID:     c.CompanyID.String(),
+		Name:          c.Name,
+		Country:       c.Country,
+		WorkDays:      workDayNames(c.WorkDays),
+		IsDefault:     c.IsDefault,
+		DepartmentIDs: departmentIDs,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+}
+
+func toHolidayResponse(h *models.CompanyHoliday, date time.Time) *dto.HolidayResponse {
+	return &dto.HolidayResponse{
+		ID:        h.ID.String(),
+		Date:      date.Format(utils.DateLayout),
+		Name:      h.Name,
+		Recurring: h.Recurring,
+		CreatedAt: h.CreatedAt,
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestParseICalEvents(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20260101",
+		"DTEND;VALUE=DATE:20260102",
+		"SUMMARY:New Year's Day",
+		"RRULE:FREQ=YEARLY",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20260330",
+		"DTEND;VALUE=DATE:20260401",
+		"SUMMARY:Eid al-Fitr\\, observed",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=\"Africa/Lagos\":20260612T090000",
+		"SUMMARY:Democracy",
+		"  Day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20260704",
+		"SUMMARY:Weekly standup",
+		"RRULE:FREQ=WEEKLY",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20261225",
+		"SUMMARY:Cancelled party",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := parseICalEvents([]byte(ics))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(events))
+	}
+
+	newYear := events[0]
+	if newYear.Summary != "New Year's Day" || !newYear.Recurring || !newYear.Start.Equal(newYear.End) || newYear.Skip != "" {
+		t.Errorf("unexpected new year event %+v", newYear)
+	}
+
+	eid := events[1]
+	if eid.Summary != "Eid al-Fitr, observed" || eid.End.Format("2006-01-02") != "2026-03-31" {
+		t.Errorf("expected a two-day event ending 31 March, got %+v", eid)
+	}
+
+	if events[2].Summary != "Democracy Day" || events[2].Start.Format("2006-01-02") != "2026-06-12" {
+		t.Errorf("expected a folded summary and a date-time start, got %+v", events[2])
+	}
+	if events[3].Skip == "" || events[4].Skip == "" {
+		t.Error("expected weekly and cancelled events to be skipped")
+	}
+
+	if _, err := parseICalEvents([]byte("BEGIN:VEVENT\r\nEND:VEVENT")); err == nil {
+		t.Error("expected an error for a file without VCALENDAR")
+	}
+}
+
+func TestHolidayOccurrences(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	from, to := date(2026, time.January, 1), date(2028, time.December, 31)
+
+	christmas := &models.CompanyHoliday{HolidayDate: date(2025, time.December, 25), Recurring: true}
+	if got := holidayOccurrences(christmas, from, to); len(got) != 3 {
+		t.Errorf("expected 3 occurrences, got %v", got)
+	}
+
+	leapDay := &models.CompanyHoliday{HolidayDate: date(2024, time.February, 29), Recurring: true}
+	if got := holidayOccurrences(leapDay, from, to); len(got) != 1 || got[0].Year() != 2028 {
+		t.Errorf("expected only the 2028 leap day, got %v", got)
+	}
+
+	future := &models.CompanyHoliday{HolidayDate: date(2027, time.May, 1), Recurring: true}
+	if got := holidayOccurrences(future, from, to); len(got) != 2 {
+		t.Errorf("expected occurrences from 2027 only, got %v", got)
+	}
+
+	oneOff := &models.CompanyHoliday{HolidayDate: date(2025, time.May, 1)}
+	if got := holidayOccurrences(oneOff, from, to); len(got) != 0 {
+		t.Errorf("expected no occurrences outside the range, got %v", got)
+	}
+}
+
+func TestParseWorkDays(t *testing.T) {
+	days, err := parseWorkDays([]string{"Thursday", "sun", "mon", "tue", "wed", "sunday"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := workDayNames(days); strings.Join(got, ",") != "sunday,monday,tuesday,wednesday,thursday" {
+		t.Errorf("unexpected work days %v", got)
+	}
+
+	if days, _ := parseWorkDays(nil); len(days) != 5 || days[0] != int(time.Monday) {
+		t.Errorf("expected Monday–Friday by default, got %v", days)
+	}
+	if _, err := parseWorkDays([]string{"funday"}); err == nil {
+		t.Error("expected an error for an unknown weekday")
+	}
+}
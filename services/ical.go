@@ -0,0 +1,269 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxICalEventDays bounds how many days a single imported event may cover.
+const maxICalEventDays = 31
+
+// icalEvent is an all-day VEVENT read from an iCalendar file.
+type icalEvent struct {
+	Summary   string
+	Start     time.Time
+	End       time.Time // Inclusive
+	Recurring bool      // RRULE:FREQ=YEARLY
+	Skip      string    // Why the event cannot be imported, if it cannot
+}
+
+// icalProperty is one content line: NAME;PARAM=VALUE:value.
+type icalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// parseICalEvents reads the VEVENTs of an iCalendar (RFC 5545) file. Only
+// what a holiday needs is interpreted: DTSTART, DTEND, SUMMARY, STATUS and
+// yearly RRULEs. Events that cannot become holidays are returned with Skip
+// set so the caller can report them.
+func parseICalEvents(data []byte) ([]*icalEvent, error) {
+	lines := unfoldICalLines(data)
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("not an iCalendar file: missing BEGIN:VCALENDAR")
+	}
+
+	var events []*icalEvent
+	var props []icalProperty
+	inEvent := false
+	for i, line := range lines {
+		prop, err := parseICalProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VEVENT"):
+			inEvent, props = true, nil
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VEVENT"):
+			if !inEvent {
+				return nil, fmt.Errorf("line %d: END:VEVENT without BEGIN:VEVENT", i+1)
+			}
+			events = append(events, buildICalEvent(props))
+			inEvent = false
+		case inEvent:
+			props = append(props, prop)
+		}
+	}
+	if inEvent {
+		return nil, fmt.Errorf("unterminated VEVENT")
+	}
+
+	return events, nil
+}
+
+func buildICalEvent(props []icalProperty) *icalEvent {
+	event := &icalEvent{}
+	var start, end string
+	var endIsDate bool
+	for _, p := range props {
+		switch p.Name {
+		case "SUMMARY":
+			event.Summary = strings.TrimSpace(unescapeICalText(p.Value))
+		case "DTSTART":
+			start = p.Value
+		case "DTEND":
+			end = p.Value
+			endIsDate = p.Params["VALUE"] == "DATE" || len(p.Value) == 8
+		case "STATUS":
+			if strings.EqualFold(p.Value, "CANCELLED") {
+				event.Skip = "event is cancelled"
+			}
+		case "RRULE":
+			if strings.Contains(strings.ToUpper(p.Value), "FREQ=YEARLY") {
+				event.Recurring = true
+			} else {
+				event.Skip = "only yearly recurrence is supported"
+			}
+		}
+	}
+
+	if event.Summary == "" && event.Skip == "" {
+		event.Skip = "event has no SUMMARY"
+	}
+
+	var err error
+	if event.Start, err = parseICalDate(start); err != nil {
+		event.Skip = "invalid DTSTART"
+		return event
+	}
+	event.End = event.Start
+	if end != "" {
+		if event.End, err = parseICalDate(end); err != nil {
+			event.Skip = "invalid DTEND"
+			return event
+		}
+		// An all-day DTEND is exclusive.
+		if endIsDate && event.End.After(event.Start) {
+			event.End = event.End.AddDate(0, 0, -1)
+		}
+		if event.End.Before(event.Start) {
+			event.End = event.Start
+		}
+	}
+	if event.End.Sub(event.Start) >= maxICalEventDays*24*time.Hour && event.Skip == "" {
+		event.Skip = fmt.Sprintf("event spans more than %d days", maxICalEventDays)
+	}
+
+	return event
+}
+
+// unfoldICalLines splits data into logical lines, joining the continuation
+// lines that start with a space or tab and dropping blank ones.
+func unfoldICalLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseICalProperty splits a content line into name, parameters and value.
+// Parameter values may be quoted and contain ':' or ';'.
+func parseICalProperty(line string) (icalProperty, error) {
+	prop := icalProperty{Params: map[string]string{}}
+
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("malformed content line %q", line)
+	}
+
+	head := line[:colon]
+	prop.Value = line[colon+1:]
+
+	parts := strings.Split(head, ";")
+	prop.Name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.Params[strings.ToUpper(key)] = strings.ToUpper(strings.Trim(value, `"`))
+	}
+	return prop, nil
+}
+
+// parseICalDate reads the date part of a DATE (20260101) or DATE-TIME
+// (20260101T090000Z) value.
+func parseICalDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+func unescapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, " ", `\N`, " ")
+	return replacer.Replace(value)
+}
+
+// icalMaxLineOctets is the longest content line RFC 5545 allows before it
+// must be folded.
+const icalMaxLineOctets = 75
+
+// icalFeedEvent is an all-day event written to an iCalendar feed.
+type icalFeedEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time // Inclusive
+	Tentative   bool
+}
+
+// writeICalendar renders events as an iCalendar (RFC 5545) file named
+// calendarName. stamp becomes every event's DTSTAMP.
+func writeICalendar(calendarName string, events []*icalFeedEvent, stamp time.Time) []byte {
+	var buf bytes.Buffer
+	write := func(line string) {
+		buf.WriteString(foldICalLine(line))
+		buf.WriteString("\r\n")
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:-//CompanyFlow//Leave Calendar//EN")
+	write("CALSCALE:GREGORIAN")
+	write("METHOD:PUBLISH")
+	write("X-WR-CALNAME:" + escapeICalText(calendarName))
+	for _, event := range events {
+		write("BEGIN:VEVENT")
+		write("UID:" + event.UID)
+		write("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+		write("DTSTART;VALUE=DATE:" + event.Start.Format("20060102"))
+		// DTEND of an all-day event is exclusive.
+		write("DTEND;VALUE=DATE:" + event.End.AddDate(0, 0, 1).Format("20060102"))
+		write("SUMMARY:" + escapeICalText(event.Summary))
+		if event.Description != "" {
+			write("DESCRIPTION:" + escapeICalText(event.Description))
+		}
+		if event.Tentative {
+			write("STATUS:TENTATIVE")
+		} else {
+			write("STATUS:CONFIRMED")
+		}
+		write("TRANSP:TRANSPARENT")
+		write("END:VEVENT")
+	}
+	write("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// foldICalLine splits line into chunks of at most icalMaxLineOctets octets,
+// continuing each on a new line that starts with a space. Lines are only
+// split between runes.
+func foldICalLine(line string) string {
+	if len(line) <= icalMaxLineOctets {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > icalMaxLineOctets {
+			b.WriteString("\r\n ")
+			// The leading space counts towards the continuation line.
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
+
+func escapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
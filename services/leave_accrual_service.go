@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+)
+
+// monthlyAccrualCutoff is the last day of a month an employee can join and
+// still earn that month's accrual.
+const monthlyAccrualCutoff = 15
+
+type LeaveAccrualService struct {
+	accrualRepo *repositories.LeaveAccrualRepository
+}
+
+func NewLeaveAccrualService(accrualRepo *repositories.LeaveAccrualRepository) *LeaveAccrualService {
+	return &LeaveAccrualService{
+		accrualRepo: accrualRepo,
+	}
+}
+
+// RunAccruals opens or tops up every employee's balances for the year of
+// asOf and carries forward unused days from the year before. It is safe to
+// run repeatedly: balances that are already up to date are left alone. It
+// returns the number of balances changed.
+func (s *LeaveAccrualService) RunAccruals(ctx context.Context, asOf time.Time) (int, error) {
+	leaveTypes, err := s.accrualRepo.ListActiveLeaveTypes(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	byCompany := map[uuid.UUID][]*models.LeaveType{}
+	var companies []uuid.UUID
+	for _, lt := range leaveTypes {
+		if _, ok := byCompany[lt.CompanyID]; !ok {
+			companies = append(companies, lt.CompanyID)
+		}
+		byCompany[lt.CompanyID] = append(byCompany[lt.CompanyID], lt)
+	}
+
+	changed := 0
+	for _, companyID := range companies {
+		n, err := s.accrueCompany(ctx, companyID, byCompany[companyID], asOf)
+		if err != nil {
+			return changed, err
+		}
+		changed += n
+	}
+	return changed, nil
+}
+
+func (s *LeaveAccrualService) accrueCompany(ctx context.Context, companyID uuid.UUID, leaveTypes []*models.LeaveType, asOf time.Time) (int, error) {
+	year := asOf.Year()
+
+	employees, err := s.accrualRepo.ListAccrualEmployees(ctx, companyID, year)
+	if err != nil {
+		return 0, err
+	}
+	previous, err := s.accrualRepo.ListCompanyLeaveBalances(ctx, companyID, year-1)
+	if err != nil {
+		return 0, err
+	}
+
+	type balanceKey struct{ employeeID, leaveTypeID uuid.UUID }
+	previousBalances := make(map[balanceKey]*models.LeaveBalance, len(previous))
+	for _, b := range previous {
+		previousBalances[balanceKey{b.EmployeeID, b.LeaveTypeID}] = b
+	}
+
+	var accruals []*models.LeaveAccrual
+	for _, employee := range employees {
+		for _, lt := range leaveTypes {
+			if !leaveTypeAppliesTo(lt, employee) {
+				continue
+			}
+			accruals = append(accruals, &models.LeaveAccrual{
+				EmployeeID:         employee.ID,
+				LeaveTypeID:        lt.ID,
+				Year:               year,
+				AccruedDays:        LeaveEntitlement(lt, employee, year, asOf),
+				CarriedForwardDays: CarryForwardDays(lt, previousBalances[balanceKey{employee.ID, lt.ID}]),
+			})
+		}
+	}
+	if len(accruals) == 0 {
+		return 0, nil
+	}
+
+	return s.accrualRepo.ApplyLeaveAccruals(ctx, accruals)
+}
+
+// leaveTypeAppliesTo reports whether employee should hold a balance for lt.
+// Only the permanent eligibility rules count here; tenure and probation are
+// checked when leave is requested, so the balance is ready once they pass.
+func leaveTypeAppliesTo(lt *models.LeaveType, employee *models.Employee) bool {
+	if len(lt.EligibleEmploymentTypes) > 0 && !slices.Contains(lt.EligibleEmploymentTypes, employee.EmploymentType) {
+		return false
+	}
+	if len(lt.EligibleGenders) > 0 && !slices.Contains(lt.EligibleGenders, strings.ToLower(employee.Gender)) {
+		return false
+	}
+	return true
+}
+
+// LeaveEntitlement returns the days of lt that employee has earned in year
+// as of asOf, rounded to the nearest half day.
+//
+// Under the yearly policy the whole entitlement is granted up front,
+// prorated by the share of the year between the hire date (or 1 January)
+// and the termination date (or 31 December). Under the monthly policy a
+// twelfth is earned at the start of each month the employee is employed,
+// counting the joining month only when they join by the 15th.
+func LeaveEntitlement(lt *models.LeaveType, employee *models.Employee, year int, asOf time.Time) float64 {
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	if employee.HireDate.After(yearEnd) {
+		return 0
+	}
+	if employee.TerminationDate != nil && employee.TerminationDate.Before(yearStart) {
+		return 0
+	}
+
+	if lt.AccrualPolicy == "monthly" {
+		months := 0
+		for m := time.January; m <= time.December; m++ {
+			monthStart := time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+			if monthStart.After(asOf) {
+				break
+			}
+			if employee.HireDate.After(time.Date(year, m, monthlyAccrualCutoff, 0, 0, 0, 0, time.UTC)) {
+				continue
+			}
+			if employee.TerminationDate != nil && employee.TerminationDate.Before(monthStart) {
+				break
+			}
+			months++
+		}
+		return roundToHalfDay(lt.DaysAllowed * float64(months) / 12)
+	}
+
+	from, to := yearStart, yearEnd
+	if employee.HireDate.After(from) {
+		from = employee.HireDate
+	}
+	if employee.TerminationDate != nil && employee.TerminationDate.Before(to) {
+		to = *employee.TerminationDate
+	}
+	daysInYear := yearEnd.Sub(yearStart).Hours()/24 + 1
+	employed := to.Sub(from).Hours()/24 + 1
+	return roundToHalfDay(lt.DaysAllowed * employed / daysInYear)
+}
+
+// CarryForwardDays returns how many unused days of the previous year's
+// balance move into the next year. Days still reserved by pending requests
+// are not carried. A cap of zero means no limit.
+func CarryForwardDays(lt *models.LeaveType, previous *models.LeaveBalance) float64 {
+	if !lt.CarryForwardAllowed || previous == nil {
+		return 0
+	}
+
+	unused := availableLeaveDays(previous)
+	if unused <= 0 {
+		return 0
+	}
+	if lt.MaxCarryForwardDays > 0 {
+		unused = math.Min(unused, lt.MaxCarryForwardDays)
+	}
+	return math.Floor(unused*2) / 2
+}
+
+func roundToHalfDay(days float64) float64 {
+	return math.Round(days*2) / 2
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestLeaveEntitlement(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	yearly := &models.LeaveType{DaysAllowed: 20, AccrualPolicy: "yearly"}
+	monthly := &models.LeaveType{DaysAllowed: 20, AccrualPolicy: "monthly"}
+	terminated := date(2026, time.March, 31)
+
+	tests := []struct {
+		name      string
+		leaveType *models.LeaveType
+		employee  models.Employee
+		asOf      time.Time
+		want      float64
+	}{
+		{"full year", yearly, models.Employee{HireDate: date(2020, time.May, 4)}, date(2026, time.January, 1), 20},
+		{"mid-year hire is prorated", yearly, models.Employee{HireDate: date(2026, time.July, 1)}, date(2026, time.July, 1), 10},
+		{"leaver is prorated", yearly, models.Employee{HireDate: date(2020, time.May, 4), TerminationDate: &terminated}, date(2026, time.January, 1), 5},
+		{"hired next year", yearly, models.Employee{HireDate: date(2027, time.January, 4)}, date(2026, time.December, 1), 0},
+		{"monthly to date", monthly, models.Employee{HireDate: date(2020, time.May, 4)}, date(2026, time.March, 1), 5},
+		{"monthly skips late joining month", monthly, models.Employee{HireDate: date(2026, time.February, 20)}, date(2026, time.April, 10), 3.5},
+		{"monthly counts early joining month", monthly, models.Employee{HireDate: date(2026, time.February, 15)}, date(2026, time.April, 10), 5},
+		{"monthly stops at termination", monthly, models.Employee{HireDate: date(2020, time.May, 4), TerminationDate: &terminated}, date(2026, time.June, 1), 5},
+		{"monthly past year end", monthly, models.Employee{HireDate: date(2020, time.May, 4)}, date(2027, time.January, 1), 20},
+	}
+
+	for _, tt := range tests {
+		if got := LeaveEntitlement(tt.leaveType, &tt.employee, 2026, tt.asOf); got != tt.want {
+			t.Errorf("%s: expected %v days, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCarryForwardDays(t *testing.T) {
+	previous := &models.LeaveBalance{TotalDays: 20, UsedDays: 12, PendingDays: 1}
+
+	tests := []struct {
+		name      string
+		leaveType *models.LeaveType
+		previous  *models.LeaveBalance
+		want      float64
+	}{
+		{"capped", &models.LeaveType{CarryForwardAllowed: true, MaxCarryForwardDays: 5}, previous, 5},
+		{"uncapped", &models.LeaveType{CarryForwardAllowed: true}, previous, 7},
+		{"not allowed", &models.LeaveType{CarryForwardAllowed: false}, previous, 0},
+		{"no previous balance", &models.LeaveType{CarryForwardAllowed: true}, nil, 0},
+		{"overdrawn", &models.LeaveType{CarryForwardAllowed: true}, &models.LeaveBalance{TotalDays: 5, UsedDays: 6}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := CarryForwardDays(tt.leaveType, tt.previous); got != tt.want {
+			t.Errorf("%s: expected %v days, got %v", tt.name, tt.want, got)
+		}
+	}
+}
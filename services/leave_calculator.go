@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"time"
+)
+
+// defaultWorkWeek applies to employees whose company has no holiday
+// calendar.
+var defaultWorkWeek = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// WorkCalendar decides which dates count as working days for leave.
+type WorkCalendar struct {
+	workDays map[time.Weekday]bool
+	holidays map[string]string // date -> holiday name
+}
+
+func NewWorkCalendar(workDays []time.Weekday) *WorkCalendar {
+	c := &WorkCalendar{
+		workDays: make(map[time.Weekday]bool, len(workDays)),
+		holidays: make(map[string]string),
+	}
+	for _, day := range workDays {
+		c.workDays[day] = true
+	}
+	return c
+}
+
+// AddHoliday marks date as a non-working day.
+func (c *WorkCalendar) AddHoliday(date time.Time, name string) {
+	c.holidays[date.Format("2006-01-02")] = name
+}
+
+func (c *WorkCalendar) IsWorkingDay(date time.Time) bool {
+	if !c.workDays[date.Weekday()] {
+		return false
+	}
+	_, holiday := c.holidays[date.Format("2006-01-02")]
+	return !holiday
+}
+
+// CountLeaveDays returns the working days from start to end inclusive. A
+// start half day drops the morning of the first day and an end half day the
+// afternoon of the last; either flag on a one-day request makes it half a
+// day. Half-day flags on non-working days have no effect.
+func (c *WorkCalendar) CountLeaveDays(start, end time.Time, startHalf, endHalf bool) (float64, error) {
+	if end.Before(start) {
+		return 0, errors.New("end_date cannot be before start_date")
+	}
+	if start.Equal(end) && startHalf && endHalf {
+		return 0, errors.New("a one-day request can be at most one half day")
+	}
+
+	days := 0.0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(d) {
+			days++
+		}
+	}
+
+	switch {
+	case start.Equal(end):
+		if (startHalf || endHalf) && days > 0 {
+			days = 0.5
+		}
+	default:
+		if startHalf && c.IsWorkingDay(start) {
+			days -= 0.5
+		}
+		if endHalf && c.IsWorkingDay(end) {
+			days -= 0.5
+		}
+	}
+
+	if days <= 0 {
+		return 0, errors.New("the requested period contains no working days")
+	}
+	return days, nil
+}
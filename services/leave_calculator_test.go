@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountLeaveDays(t *testing.T) {
+	date := func(day int) time.Time { return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC) }
+
+	calendar := NewWorkCalendar(defaultWorkWeek)
+	calendar.AddHoliday(date(11), "Founders' Day") // Wednesday
+
+	tests := []struct {
+		name      string
+		start     int
+		end       int
+		startHalf bool
+		endHalf   bool
+		want      float64
+	}{
+		{"single day", 2, 2, false, false, 1},
+		{"full week", 2, 6, false, false, 5},
+		{"spans weekend", 5, 10, false, false, 4},
+		{"skips holiday", 9, 13, false, false, 4},
+		{"half day", 2, 2, true, false, 0.5},
+		{"afternoon half day", 2, 2, false, true, 0.5},
+		{"half start and end", 2, 4, true, true, 2},
+		{"half flag on weekend ignored", 7, 9, true, false, 1},
+	}
+
+	for _, tt := range tests {
+		got, err := calendar.CountLeaveDays(date(tt.start), date(tt.end), tt.startHalf, tt.endHalf)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %v days, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	errorCases := []struct {
+		name      string
+		start     int
+		end       int
+		startHalf bool
+		endHalf   bool
+	}{
+		{"end before start", 5, 4, false, false},
+		{"only weekend", 7, 8, false, false},
+		{"only holiday", 11, 11, false, false},
+		{"both halves on one day", 2, 2, true, true},
+	}
+	for _, tt := range errorCases {
+		if _, err := calendar.CountLeaveDays(date(tt.start), date(tt.end), tt.startHalf, tt.endHalf); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestCountLeaveDaysSundayToThursday(t *testing.T) {
+	calendar := NewWorkCalendar([]time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday})
+
+	// Thursday 5 March to Sunday 8 March 2026 spans a Friday-Saturday weekend.
+	got, err := calendar.CountLeaveDays(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), false, false)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got != 2 {
+		t.Errorf("expected 2 days, got %v", got)
+	}
+}
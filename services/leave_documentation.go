@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// documentationRequired reports whether a request of leave type lt from
+// start to end needs supporting documentation.
+func documentationRequired(lt *models.LeaveType, start, end time.Time) bool {
+	if !lt.RequiresDocumentation {
+		return false
+	}
+	consecutiveDays := int(end.Sub(start).Hours()/24) + 1
+	return consecutiveDays > lt.DocumentationAfterDays
+}
+
+// applyDocumentationRequirement sets the documentation status of a new
+// request. Without a grace period the attachment must come with the
+// request; otherwise it is due documentation_grace_days after the leave
+// ends.
+func applyDocumentationRequirement(lt *models.LeaveType, request *models.LeaveRequest) error {
+	switch {
+	case !documentationRequired(lt, request.StartDate, request.EndDate):
+		request.DocumentationStatus = "not_required"
+	case request.AttachmentURL != "":
+		request.DocumentationStatus = "provided"
+	case lt.DocumentationGraceDays == 0:
+		message := lt.Name + " requires supporting documentation"
+		if lt.DocumentationAfterDays > 0 {
+			message += fmt.Sprintf(" for absences longer than %d consecutive days", lt.DocumentationAfterDays)
+		}
+		return &utils.ValidationError{Field: "attachment_url", Message: message}
+	default:
+		due := request.EndDate.AddDate(0, 0, lt.DocumentationGraceDays)
+		request.DocumentationStatus = "awaiting"
+		request.DocumentationDueDate = &due
+	}
+	return nil
+}
+
+// AttachLeaveDocument adds the supporting document to a pending or approved
+// request, on behalf of the requester or one of their approvers.
+func (s *LeaveRequestService) AttachLeaveDocument(
+	ctx context.Context,
+	companyID, employeeID, requestID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.AttachLeaveDocumentRequest,
+) (*dto.LeaveRequestResponse, error) {
+	attachmentURL := strings.TrimSpace(req.AttachmentURL)
+	if parsed, err := url.Parse(attachmentURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, &utils.ValidationError{Field: "attachment_url", Message: "attachment_url must be an http(s) URL"}
+	}
+
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	request, err := s.leaveRequestRepo.GetLeaveRequestByID(ctx, requestID)
+	if err != nil || request.EmployeeID != employeeID {
+		return nil, ErrLeaveRequestNotFound
+	}
+
+	if actorID != nil && *actorID != employee.ID {
+		isApprover, err := s.isLeaveApprover(ctx, employee, *actorID)
+		if err != nil {
+			return nil, err
+		}
+		if !isApprover {
+			return nil, forbidden("only the requester, their manager or HR can attach documents")
+		}
+	}
+
+	updated, err := s.leaveRequestRepo.AttachLeaveDocument(ctx, requestID, attachmentURL)
+	if errors.Is(err, repositories.ErrLeaveRequestStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: fmt.Sprintf("documents can only be attached to pending or approved requests; this one is %s", request.Status)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toLeaveRequestResponse(updated), nil
+}
+
+// EnforceLeaveDocumentation handles requests whose documentation was due
+// before asOf. Depending on the leave type the request is either flagged
+// overdue, with the employee and HR notified, or also rejected and its days
+// returned to the balance. It returns the number of requests handled.
+func (s *LeaveRequestService) EnforceLeaveDocumentation(ctx context.Context, asOf time.Time) (int, error) {
+	overdue, err := s.leaveRequestRepo.ListOverdueLeaveDocumentation(ctx, asOf)
+	if err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	for _, o := range overdue {
+		if o.DocumentationStatus == "awaiting" {
+			flagged, err := s.leaveRequestRepo.MarkLeaveDocumentationOverdue(ctx, o.ID)
+			if err != nil {
+				return handled, err
+			}
+			if !flagged {
+				// The document arrived after the list was read.
+				continue
+			}
+		}
+
+		request := &o.LeaveRequest
+		if o.OverdueAction == "revert" {
+			reason := fmt.Sprintf("Required documentation was not provided by %s", o.DocumentationDueDate.Format(utils.DateLayout))
+			request, _, err = s.leaveRequestRepo.TransitionLeaveRequest(ctx, o.ID, o.Status, &models.ApprovalHistory{
+				Action:   "rejected",
+				Comments: reason,
+			})
+			if errors.Is(err, repositories.ErrLeaveRequestStatusChanged) {
+				continue
+			}
+			if err != nil {
+				return handled, err
+			}
+		}
+
+		s.notifyDocumentationOverdue(ctx, o, request)
+		handled++
+	}
+
+	if handled > 0 {
+		log.Printf("handled %d leave requests with overdue documentation", handled)
+	}
+	return handled, nil
+}
+
+// notifyDocumentationOverdue tells the employee and HR that a request's
+// documentation is overdue and what happened to the request.
+func (s *LeaveRequestService) notifyDocumentationOverdue(ctx context.Context, o *models.OverdueLeaveDocumentation, request *models.LeaveRequest) {
+	body := fmt.Sprintf("Supporting documentation for %s from %s to %s was due by %s and has not been provided.",
+		o.LeaveTypeName, request.StartDate.Format(utils.DateLayout), request.EndDate.Format(utils.DateLayout),
+		o.DocumentationDueDate.Format(utils.DateLayout))
+	if request.Status == "rejected" {
+		body += " The request has been rejected and its days returned to the balance."
+	} else {
+		body += " Please upload it as soon as possible."
+	}
+
+	if _, err := s.notificationService.NotifyHR(ctx, models.Notification{
+		CompanyID:  o.CompanyID,
+		Type:       "leave_documentation_overdue",
+		Title:      "Leave documentation overdue",
+		Body:       body,
+		EntityType: "leave_request",
+		EntityID:   &request.ID,
+		DedupeKey:  fmt.Sprintf("leave_documentation_overdue:%s:%s", request.ID, request.Status),
+	}, request.EmployeeID); err != nil {
+		log.Printf("notify overdue documentation for leave request %s: %v", request.ID, err)
+	}
+}
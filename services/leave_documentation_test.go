@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestApplyDocumentationRequirement(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.May, d, 0, 0, 0, 0, time.UTC) }
+	sick := &models.LeaveType{Name: "Sick Leave", RequiresDocumentation: true, DocumentationAfterDays: 2, DocumentationGraceDays: 7}
+	strict := &models.LeaveType{Name: "Maternity Leave", RequiresDocumentation: true}
+	annual := &models.LeaveType{Name: "Annual Leave"}
+
+	tests := []struct {
+		name       string
+		leaveType  *models.LeaveType
+		start, end time.Time
+		attachment string
+		status     string
+		due        *time.Time
+		wantErr    bool
+	}{
+		{"not required by type", annual, day(4), day(15), "", "not_required", nil, false},
+		{"within threshold", sick, day(4), day(5), "", "not_required", nil, false},
+		{"beyond threshold with attachment", sick, day(4), day(6), "https://files.example.com/note.pdf", "provided", nil, false},
+		{"beyond threshold awaiting", sick, day(4), day(6), "", "awaiting", ptr(day(13)), false},
+		{"no grace period", strict, day(4), day(4), "", "", nil, true},
+		{"no grace period with attachment", strict, day(4), day(4), "https://files.example.com/cert.pdf", "provided", nil, false},
+	}
+
+	for _, tt := range tests {
+		request := &models.LeaveRequest{StartDate: tt.start, EndDate: tt.end, AttachmentURL: tt.attachment}
+		err := applyDocumentationRequirement(tt.leaveType, request)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if request.DocumentationStatus != tt.status {
+			t.Errorf("%s: expected status %s, got %s", tt.name, tt.status, request.DocumentationStatus)
+		}
+		if (request.DocumentationDueDate == nil) != (tt.due == nil) ||
+			(tt.due != nil && !request.DocumentationDueDate.Equal(*tt.due)) {
+			t.Errorf("%s: expected due date %v, got %v", tt.name, tt.due, request.DocumentationDueDate)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type LeaveLedgerService struct {
+	employeeRepo     *repositories.EmployeeRepository
+	leaveTypeRepo    *repositories.LeaveTypeRepository
+	leaveRequestRepo *repositories.LeaveRequestRepository
+	ledgerRepo       *repositories.LeaveLedgerRepository
+}
+
+func NewLeaveLedgerService(
+	employeeRepo *repositories.EmployeeRepository,
+	leaveTypeRepo *repositories.LeaveTypeRepository,
+	leaveRequestRepo *repositories.LeaveRequestRepository,
+	ledgerRepo *repositories.LeaveLedgerRepository,
+) *LeaveLedgerService {
+	return &LeaveLedgerService{
+		employeeRepo:     employeeRepo,
+		leaveTypeRepo:    leaveTypeRepo,
+		leaveRequestRepo: leaveRequestRepo,
+		ledgerRepo:       ledgerRepo,
+	}
+}
+
+// BalanceStatement lists, for each of an employee's balances in year, the
+// ledger entries that produced it with the running available balance.
+func (s *LeaveLedgerService) BalanceStatement(ctx context.Context, companyID, employeeID uuid.UUID, year int) (*dto.LeaveStatementResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+
+	balances, err := s.leaveRequestRepo.ListLeaveBalances(ctx, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.ledgerRepo.ListLedgerEntries(ctx, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+	leaveTypes, err := s.leaveTypeRepo.ListLeaveTypes(ctx, companyID, "")
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(leaveTypes))
+	for _, lt := range leaveTypes {
+		names[lt.ID] = lt.Name
+	}
+
+	byBalance := make(map[uuid.UUID][]*models.LeaveLedgerEntry)
+	for _, e := range entries {
+		byBalance[e.BalanceID] = append(byBalance[e.BalanceID], e)
+	}
+
+	response := &dto.LeaveStatementResponse{
+		EmployeeID: employeeID.String(),
+		Year:       year,
+		LeaveTypes: make([]*dto.LeaveTypeStatement, 0, len(balances)),
+	}
+	for _, b := range balances {
+		statement := buildLeaveStatement(b, byBalance[b.ID])
+		statement.LeaveTypeName = names[b.LeaveTypeID]
+		response.LeaveTypes = append(response.LeaveTypes, statement)
+	}
+	return response, nil
+}
+
+// AdjustBalance credits (or, with negative days, debits) an employee's
+// balance by hand. The reason is kept in the ledger.
+func (s *LeaveLedgerService) AdjustBalance(
+	ctx context.Context,
+	companyID, employeeID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.LeaveAdjustmentRequest,
+) (*dto.LeaveBalanceResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, &utils.ValidationError{Field: "reason", Message: "reason is required"}
+	}
+	if req.Days == 0 || !isHalfDayMultiple(req.Days) {
+		return nil, &utils.ValidationError{Field: "days", Message: "days must be a non-zero multiple of 0.5"}
+	}
+
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	leaveTypeID, err := uuid.Parse(req.LeaveTypeID)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "leave_type_id", Message: "invalid leave_type_id"}
+	}
+	leaveType, err := s.leaveTypeRepo.GetLeaveTypeByID(ctx, leaveTypeID)
+	if err != nil || leaveType.CompanyID != companyID {
+		return nil, &utils.ValidationError{Field: "leave_type_id", Message: "leave type not found"}
+	}
+
+	today := utils.Today()
+	year := req.Year
+	if year == 0 {
+		year = today.Year()
+	}
+
+	balance, err := s.ledgerRepo.AdjustLeaveBalance(ctx, employee.ID, leaveType.ID, year,
+		LeaveEntitlement(leaveType, employee, year, today),
+		&models.LeaveLedgerEntry{TotalDelta: req.Days, ActorID: actorID, Reason: reason},
+	)
+	if errors.Is(err, repositories.ErrNegativeLeaveBalance) {
+		return nil, &utils.ValidationError{Field: "days", Message: err.Error()}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toLeaveBalanceResponse(balance), nil
+}
+
+// CheckConsistency recomputes a company's balances for year from the ledger
+// and reports the ones that do not match.
+func (s *LeaveLedgerService) CheckConsistency(ctx context.Context, companyID uuid.UUID, year int) (*dto.LedgerConsistencyResponse, error) {
+	discrepancies, err := s.ledgerRepo.ListDiscrepancies(ctx, companyID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.LedgerConsistencyResponse{
+		Year:          year,
+		Consistent:    len(discrepancies) == 0,
+		Discrepancies: make([]*dto.LeaveBalanceDiscrepancyResponse, 0, len(discrepancies)),
+	}
+	for _, d := range discrepancies {
+		response.Discrepancies = append(response.Discrepancies, &dto.LeaveBalanceDiscrepancyResponse{
+			Balance:              toLeaveBalanceResponse(&d.Balance),
+			EmployeeID:           d.Balance.EmployeeID.String(),
+			LedgerTotal:          d.LedgerTotal,
+			LedgerUsed:           d.LedgerUsed,
+			LedgerPending:        d.LedgerPending,
+			LedgerCarriedForward: d.LedgerCarriedForward,
+		})
+	}
+	return response, nil
+}
+
+// buildLeaveStatement replays entries (oldest first) and reports whether
+// they add up to balance.
+func buildLeaveStatement(balance *models.LeaveBalance, entries []*models.LeaveLedgerEntry) *dto.LeaveTypeStatement {
+	statement := &dto.LeaveTypeStatement{
+		LeaveTypeID: balance.LeaveTypeID.String(),
+		Entries:     make([]*dto.LeaveLedgerEntryResponse, 0, len(entries)),
+		Balance:     toLeaveBalanceResponse(balance),
+	}
+
+	var replayed models.LeaveBalance
+	for _, e := range entries {
+		replayed.TotalDays += e.TotalDelta
+		replayed.UsedDays += e.UsedDelta
+		replayed.PendingDays += e.PendingDelta
+		replayed.CarriedForwardDays += e.CarriedForwardDelta
+
+		statement.Entries = append(statement.Entries, &dto.LeaveLedgerEntryResponse{
+			ID:                  e.ID.String(),
+			EntryType:           e.EntryType,
+			TotalDelta:          e.TotalDelta,
+			UsedDelta:           e.UsedDelta,
+			PendingDelta:        e.PendingDelta,
+			CarriedForwardDelta: e.CarriedForwardDelta,
+			AvailableAfter:      availableLeaveDays(&replayed),
+			LeaveRequestID:      uuidStringPtr(e.LeaveRequestID),
+			ActorID:             uuidStringPtr(e.ActorID),
+			Reason:              e.Reason,
+			CreatedAt:           e.CreatedAt,
+		})
+	}
+
+	statement.Consistent = replayed.TotalDays == balance.TotalDays &&
+		replayed.UsedDays == balance.UsedDays &&
+		replayed.PendingDays == balance.PendingDays &&
+		replayed.CarriedForwardDays == balance.CarriedForwardDays
+	return statement
+}
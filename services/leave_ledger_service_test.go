@@ -0,0 +1,41 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestBuildLeaveStatement(t *testing.T) {
+	requestID := uuid.New()
+	entries := []*models.LeaveLedgerEntry{
+		{EntryType: "accrual", TotalDelta: 20},
+		{EntryType: "carry_forward", CarriedForwardDelta: 3},
+		{EntryType: "reservation", PendingDelta: 5, LeaveRequestID: &requestID},
+		{EntryType: "consumption", PendingDelta: -5, UsedDelta: 5, LeaveRequestID: &requestID},
+		{EntryType: "adjustment", TotalDelta: -1.5},
+	}
+	balance := &models.LeaveBalance{TotalDays: 18.5, CarriedForwardDays: 3, UsedDays: 5}
+
+	statement := buildLeaveStatement(balance, entries)
+	if !statement.Consistent {
+		t.Error("expected the entries to add up to the balance")
+	}
+
+	want := []float64{20, 23, 18, 18, 16.5}
+	for i, e := range statement.Entries {
+		if e.AvailableAfter != want[i] {
+			t.Errorf("entry %d (%s): expected %.1f available, got %.1f", i, e.EntryType, want[i], e.AvailableAfter)
+		}
+	}
+	if *statement.Entries[2].LeaveRequestID != requestID.String() {
+		t.Error("expected the reservation to name its request")
+	}
+
+	balance.UsedDays = 6
+	if buildLeaveStatement(balance, entries).Consistent {
+		t.Error("expected a drifted balance to be reported inconsistent")
+	}
+}
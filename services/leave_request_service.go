@@ -0,0 +1,745 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// leaveActions maps the action verbs accepted by the API onto the status a
+// request ends up in.
+var leaveActions = map[string]string{
+	"approve":         "approved",
+	"reject":          "rejected",
+	"request-changes": "changes_requested",
+	"resubmit":        "pending",
+	"cancel":          "cancelled",
+	"withdraw":        "withdrawn",
+}
+
+type LeaveRequestService struct {
+	employeeRepo        *repositories.EmployeeRepository
+	leaveTypeRepo       *repositories.LeaveTypeRepository
+	leaveRequestRepo    *repositories.LeaveRequestRepository
+	holidayService      *HolidayService
+	approvalHistoryRepo *repositories.ApprovalHistoryRepository
+	notificationService *NotificationService
+	approvalService     *ApprovalService
+}
+
+// leaveApprovalEntity is the approval entity type of leave requests.
+const leaveApprovalEntity = "leave_request"
+
+func NewLeaveRequestService(
+	employeeRepo *repositories.EmployeeRepository,
+	leaveTypeRepo *repositories.LeaveTypeRepository,
+	leaveRequestRepo *repositories.LeaveRequestRepository,
+	holidayService *HolidayService,
+	approvalHistoryRepo *repositories.ApprovalHistoryRepository,
+	notificationService *NotificationService,
+	approvalService *ApprovalService,
+) *LeaveRequestService {
+	s := &LeaveRequestService{
+		employeeRepo:        employeeRepo,
+		leaveTypeRepo:       leaveTypeRepo,
+		leaveRequestRepo:    leaveRequestRepo,
+		holidayService:      holidayService,
+		approvalHistoryRepo: approvalHistoryRepo,
+		notificationService: notificationService,
+		approvalService:     approvalService,
+	}
+	approvalService.RegisterTarget(leaveApprovalEntity, s)
+	return s
+}
+
+// SubmitLeaveRequest validates a request against the leave type's
+// eligibility rules, computes its working days and reserves them against the
+// employee's balance. Its approval workflow starts with it.
+func (s *LeaveRequestService) SubmitLeaveRequest(
+	ctx context.Context,
+	companyID, employeeID uuid.UUID,
+	req *dto.SubmitLeaveRequest,
+) (*dto.LeaveRequestBalanceResponse, error) {
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if employee.Status == "terminated" || employee.Status == "inactive" {
+		return nil, &utils.ValidationError{Field: "employee", Message: fmt.Sprintf("%s employees cannot request leave", employee.Status)}
+	}
+
+	leaveType, err := s.leaveTypeForRequest(ctx, companyID, req.LeaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := s.prepareLeaveRequest(ctx, employee, leaveType, req)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := s.approvalService.PrepareStart(ctx, leaveApprovalSubject(employee, leaveType, request))
+	if err != nil {
+		return nil, err
+	}
+
+	// A balance the accrual job has not opened yet starts with what the
+	// employee has earned so far.
+	openingDays := LeaveEntitlement(leaveType, employee, request.StartDate.Year(), utils.Today())
+	created, balance, err := s.leaveRequestRepo.SubmitLeaveRequest(ctx, request, openingDays, start.Request, start.Revision)
+	if err != nil {
+		return nil, leaveBookingError(err, request, balance)
+	}
+	s.approvalService.Started(ctx, start.Request, leaveApprovalSubject(employee, leaveType, created))
+
+	return &dto.LeaveRequestBalanceResponse{
+		Request: toLeaveRequestResponse(created),
+		Balance: toLeaveBalanceResponse(balance),
+	}, nil
+}
+
+// leaveBookingError explains why the days of request could not be booked
+// against balance.
+func leaveBookingError(err error, request *models.LeaveRequest, balance *models.LeaveBalance) error {
+	switch {
+	case errors.Is(err, repositories.ErrLeaveOverlap):
+		return &utils.ValidationError{Field: "start_date", Message: err.Error()}
+	case errors.Is(err, repositories.ErrInsufficientLeaveBalance):
+		return &utils.ValidationError{
+			Field:   "days_requested",
+			Message: fmt.Sprintf("%s: %.1f days requested but only %.1f available", err.Error(), request.DaysRequested, availableLeaveDays(balance)),
+		}
+	}
+	return err
+}
+
+// prepareLeaveRequest builds a request from a submission or edit, checking
+// it against the leave type's eligibility rules and working out its days and
+// documentation.
+func (s *LeaveRequestService) prepareLeaveRequest(
+	ctx context.Context,
+	employee *models.Employee,
+	leaveType *models.LeaveType,
+	req *dto.SubmitLeaveRequest,
+) (*models.LeaveRequest, error) {
+	request, err := buildLeaveRequest(employee, req)
+	if err != nil {
+		return nil, err
+	}
+	if reasons := LeaveIneligibilityReasons(leaveType, employee, request.StartDate); len(reasons) > 0 {
+		return nil, &utils.ValidationError{Field: "leave_type_id", Message: leaveType.Name + ": " + strings.Join(reasons, "; ")}
+	}
+
+	calendar, err := s.holidayService.WorkCalendarFor(ctx, employee, request.StartDate, request.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if request.DaysRequested, err = calendar.CountLeaveDays(request.StartDate, request.EndDate, request.StartHalfDay, request.EndHalfDay); err != nil {
+		return nil, &utils.ValidationError{Field: "end_date", Message: err.Error()}
+	}
+	if err := applyDocumentationRequirement(leaveType, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (s *LeaveRequestService) GetLeaveRequest(ctx context.Context, companyID, employeeID, requestID uuid.UUID) (*dto.LeaveRequestResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+
+	request, err := s.leaveRequestRepo.GetLeaveRequestByID(ctx, requestID)
+	if err != nil || request.EmployeeID != employeeID {
+		return nil, ErrLeaveRequestNotFound
+	}
+	return toLeaveRequestResponse(request), nil
+}
+
+func (s *LeaveRequestService) ListLeaveRequests(ctx context.Context, companyID, employeeID uuid.UUID, status string) ([]*dto.LeaveRequestResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+
+	requests, err := s.leaveRequestRepo.ListLeaveRequests(ctx, employeeID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.LeaveRequestResponse, 0, len(requests))
+	for _, r := range requests {
+		responses = append(responses, toLeaveRequestResponse(r))
+	}
+	return responses, nil
+}
+
+func (s *LeaveRequestService) ListLeaveBalances(ctx context.Context, companyID, employeeID uuid.UUID, year int) ([]*dto.LeaveBalanceResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+
+	balances, err := s.leaveRequestRepo.ListLeaveBalances(ctx, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.LeaveBalanceResponse, 0, len(balances))
+	for _, b := range balances {
+		responses = append(responses, toLeaveBalanceResponse(b))
+	}
+	return responses, nil
+}
+
+// ActOnLeaveRequest applies one of the leave actions (approve, reject,
+// request-changes, resubmit, cancel, withdraw) on behalf of actorID and
+// returns the request with its updated balance. Approvals, rejections and
+// requests for changes go through the request's approval workflow, so an
+// approval may only move it on to the next step.
+func (s *LeaveRequestService) ActOnLeaveRequest(
+	ctx context.Context,
+	companyID, employeeID, requestID uuid.UUID,
+	actorID *uuid.UUID,
+	action string,
+	req *dto.LeaveActionRequest,
+) (*dto.LeaveRequestBalanceResponse, error) {
+	toStatus, ok := leaveActions[action]
+	if !ok {
+		return nil, &utils.ValidationError{Field: "action", Message: "action must be one of approve, reject, request-changes, resubmit, cancel, withdraw"}
+	}
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	request, err := s.leaveRequestRepo.GetLeaveRequestByID(ctx, requestID)
+	if err != nil || request.EmployeeID != employeeID {
+		return nil, ErrLeaveRequestNotFound
+	}
+
+	isRequester := *actorID == employee.ID
+	comments := strings.TrimSpace(req.Comments)
+
+	switch toStatus {
+	case "approved", "rejected", "changes_requested":
+		// The approval engine checks the actor against the current step.
+		if err := checkLeaveTransition(request, toStatus, isRequester, !isRequester, comments, utils.Today()); err != nil {
+			return nil, err
+		}
+		decision := toStatus
+		if toStatus == "changes_requested" {
+			decision = actionRequestChanges
+		}
+		if _, err := s.approvalService.Decide(ctx, companyID, leaveApprovalEntity, requestID, *actorID, decision, comments); err != nil {
+			return nil, err
+		}
+		return s.leaveRequestWithBalance(ctx, requestID)
+	case "pending":
+		if err := checkLeaveTransition(request, toStatus, isRequester, false, comments, utils.Today()); err != nil {
+			return nil, err
+		}
+		return s.resubmitLeaveRequest(ctx, employee, request, comments)
+	}
+
+	isApprover := false
+	if !isRequester {
+		if isApprover, err = s.isLeaveApprover(ctx, employee, *actorID); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkLeaveTransition(request, toStatus, isRequester, isApprover, comments, utils.Today()); err != nil {
+		return nil, err
+	}
+
+	updated, balance, err := s.leaveRequestRepo.TransitionLeaveRequest(ctx, requestID, request.Status, &models.ApprovalHistory{
+		ApproverID: actorID,
+		Action:     toStatus,
+		Comments:   comments,
+	})
+	if errors.Is(err, repositories.ErrLeaveRequestStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: "leave request was changed by someone else; reload and try again"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !isRequester {
+		s.notifyRequester(ctx, employee, updated, comments)
+	}
+
+	return &dto.LeaveRequestBalanceResponse{
+		Request: toLeaveRequestResponse(updated),
+		Balance: toLeaveBalanceResponse(balance),
+	}, nil
+}
+
+// UpdateLeaveRequest lets the requester edit a request sent back for
+// changes, rebooking its days. It stays with them until they resubmit it.
+func (s *LeaveRequestService) UpdateLeaveRequest(
+	ctx context.Context,
+	companyID, employeeID, requestID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.UpdateLeaveRequest,
+) (*dto.LeaveRequestBalanceResponse, error) {
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	request, err := s.leaveRequestRepo.GetLeaveRequestByID(ctx, requestID)
+	if err != nil || request.EmployeeID != employeeID {
+		return nil, ErrLeaveRequestNotFound
+	}
+	if actorID == nil || *actorID != employee.ID {
+		return nil, forbidden("only the requester can edit a request")
+	}
+	if request.Status != "changes_requested" {
+		return nil, &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only requests sent back for changes can be edited; this one is %s", request.Status)}
+	}
+
+	leaveType, err := s.leaveTypeRepo.GetLeaveTypeByID(ctx, request.LeaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	edited, err := s.prepareLeaveRequest(ctx, employee, leaveType, mergeLeaveUpdate(request, req))
+	if err != nil {
+		return nil, err
+	}
+	edited.ID = request.ID
+
+	openingDays := LeaveEntitlement(leaveType, employee, edited.StartDate.Year(), utils.Today())
+	updated, balance, err := s.leaveRequestRepo.EditLeaveRequest(ctx, edited, openingDays, actorID)
+	if errors.Is(err, repositories.ErrLeaveRequestStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: "leave request was changed by someone else; reload and try again"}
+	}
+	if err != nil {
+		return nil, leaveBookingError(err, edited, balance)
+	}
+
+	return &dto.LeaveRequestBalanceResponse{
+		Request: toLeaveRequestResponse(updated),
+		Balance: toLeaveBalanceResponse(balance),
+	}, nil
+}
+
+// resubmitLeaveRequest puts a request sent back for changes before its
+// approvers again, at the step the approval engine picks.
+func (s *LeaveRequestService) resubmitLeaveRequest(
+	ctx context.Context,
+	employee *models.Employee,
+	request *models.LeaveRequest,
+	comments string,
+) (*dto.LeaveRequestBalanceResponse, error) {
+	leaveType, err := s.leaveTypeRepo.GetLeaveTypeByID(ctx, request.LeaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	subject := leaveApprovalSubject(employee, leaveType, request)
+	resubmission, err := s.approvalService.PrepareResubmission(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.leaveRequestRepo.ResubmitLeaveRequest(ctx, request.ID, resubmission.Step, &models.ApprovalHistory{
+		ApproverID: &employee.ID,
+		Action:     "resubmitted",
+		Comments:   comments,
+	}, resubmission.Revision)
+	if errors.Is(err, repositories.ErrLeaveRequestStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: "leave request was changed by someone else; reload and try again"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subject.Status = updated.Status
+	s.approvalService.Resubmitted(ctx, subject)
+	return s.leaveRequestWithBalance(ctx, request.ID)
+}
+
+// ApprovalSubject implements ApprovalTarget for leave requests.
+func (s *LeaveRequestService) ApprovalSubject(ctx context.Context, companyID, requestID uuid.UUID) (*ApprovalSubject, error) {
+	request, err := s.leaveRequestRepo.GetLeaveRequestByID(ctx, requestID)
+	if err != nil {
+		return nil, ErrLeaveRequestNotFound
+	}
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, request.EmployeeID)
+	if err != nil {
+		return nil, ErrLeaveRequestNotFound
+	}
+	leaveType, err := s.leaveTypeRepo.GetLeaveTypeByID(ctx, request.LeaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	return leaveApprovalSubject(employee, leaveType, request), nil
+}
+
+// ApplyApprovalDecision implements ApprovalTarget for leave requests: an
+// intermediate approval moves the request to the next step, a final
+// decision approves or rejects it and a request for changes sends it back,
+// telling the requester of either.
+func (s *LeaveRequestService) ApplyApprovalDecision(ctx context.Context, subject *ApprovalSubject, decision *ApprovalDecision) error {
+	history := &models.ApprovalHistory{
+		ApproverID: decision.ActorID,
+		OnBehalfOf: decision.OnBehalfOf,
+		Action:     decision.Action,
+		Comments:   decision.Comments,
+	}
+
+	var err error
+	var updated *models.LeaveRequest
+	switch {
+	case decision.Returned():
+		if updated, err = s.leaveRequestRepo.ReturnLeaveRequest(ctx, subject.EntityID, decision.Step, history); err == nil {
+			s.notifyRequester(ctx, subject.Requester, updated, decision.Comments)
+		}
+	case decision.Final():
+		if updated, _, err = s.leaveRequestRepo.FinalizeLeaveRequest(ctx, subject.EntityID, decision.Step, history); err == nil {
+			s.notifyRequester(ctx, subject.Requester, updated, decision.Comments)
+		}
+	default:
+		_, err = s.leaveRequestRepo.AdvanceLeaveRequest(ctx, subject.EntityID, decision.Step, decision.NextStep, history)
+	}
+	if errors.Is(err, repositories.ErrLeaveRequestStatusChanged) {
+		return &utils.ValidationError{Field: "status", Message: "leave request was changed by someone else; reload and try again"}
+	}
+	return err
+}
+
+// leaveRequestWithBalance returns the request with the balance it draws on.
+func (s *LeaveRequestService) leaveRequestWithBalance(ctx context.Context, requestID uuid.UUID) (*dto.LeaveRequestBalanceResponse, error) {
+	request, err := s.leaveRequestRepo.GetLeaveRequestByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.leaveRequestRepo.ListLeaveBalances(ctx, request.EmployeeID, request.StartDate.Year())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.LeaveRequestBalanceResponse{Request: toLeaveRequestResponse(request)}
+	for _, b := range balances {
+		if b.LeaveTypeID == request.LeaveTypeID {
+			response.Balance = toLeaveBalanceResponse(b)
+		}
+	}
+	return response, nil
+}
+
+func leaveApprovalSubject(employee *models.Employee, leaveType *models.LeaveType, request *models.LeaveRequest) *ApprovalSubject {
+	return &ApprovalSubject{
+		EntityType:   leaveApprovalEntity,
+		EntityID:     request.ID,
+		WorkflowType: "leave",
+		Requester:    employee,
+		Title: fmt.Sprintf("%s for %s %s (%s to %s)", leaveType.Name, employee.FirstName, employee.LastName,
+			request.StartDate.Format(utils.DateLayout), request.EndDate.Format(utils.DateLayout)),
+		Status:   request.Status,
+		Revision: leaveRevision(request),
+	}
+}
+
+// leaveRevision is what approvers review of a leave request.
+func leaveRevision(request *models.LeaveRequest) map[string]any {
+	return map[string]any{
+		"leave_type_id":  request.LeaveTypeID.String(),
+		"start_date":     request.StartDate.Format(utils.DateLayout),
+		"end_date":       request.EndDate.Format(utils.DateLayout),
+		"start_half_day": request.StartHalfDay,
+		"end_half_day":   request.EndHalfDay,
+		"days_requested": request.DaysRequested,
+		"reason":         request.Reason,
+		"attachment_url": request.AttachmentURL,
+	}
+}
+
+func (s *LeaveRequestService) ListApprovalHistory(ctx context.Context, companyID, employeeID, requestID uuid.UUID) ([]*dto.ApprovalHistoryResponse, error) {
+	if _, err := s.GetLeaveRequest(ctx, companyID, employeeID, requestID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.approvalHistoryRepo.ListApprovalHistory(ctx, leaveApprovalEntity, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ApprovalHistoryResponse, 0, len(history))
+	for _, h := range history {
+		responses = append(responses, toApprovalHistoryResponse(h))
+	}
+	return responses, nil
+}
+
+// isLeaveApprover reports whether actorID may decide on employee's leave:
+// their manager or an HR manager of the same company.
+func (s *LeaveRequestService) isLeaveApprover(ctx context.Context, employee *models.Employee, actorID uuid.UUID) (bool, error) {
+	if employee.ManagerID != nil && *employee.ManagerID == actorID {
+		return true, nil
+	}
+
+	hr, err := s.employeeRepo.GetEmployeesByRoleName(ctx, employee.CompanyID, hrRoleName)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range hr {
+		if e.ID == actorID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// notifyRequester tells the employee that someone else acted on their
+// request. Failures are logged rather than undoing the transition.
+func (s *LeaveRequestService) notifyRequester(ctx context.Context, employee *models.Employee, request *models.LeaveRequest, comments string) {
+	title := "Leave request " + request.Status
+	body := fmt.Sprintf("Your leave from %s to %s has been %s.",
+		request.StartDate.Format(utils.DateLayout), request.EndDate.Format(utils.DateLayout), request.Status)
+	dedupeKey := fmt.Sprintf("leave_%s:%s", request.Status, request.ID)
+	if request.Status == "changes_requested" {
+		title = "Changes requested to leave request"
+		body = fmt.Sprintf("Your leave from %s to %s needs changes before it can be approved; edit and resubmit it.",
+			request.StartDate.Format(utils.DateLayout), request.EndDate.Format(utils.DateLayout))
+		// A request can be sent back more than once.
+		dedupeKey += fmt.Sprintf(":%d", request.UpdatedAt.Unix())
+	}
+	if comments != "" {
+		body += " Comments: " + comments
+	}
+
+	if _, err := s.notificationService.Notify(ctx, models.Notification{
+		CompanyID:  employee.CompanyID,
+		Type:       "leave_" + request.Status,
+		Title:      title,
+		Body:       body,
+		EntityType: "leave_request",
+		EntityID:   &request.ID,
+		DedupeKey:  dedupeKey,
+	}, employee.ID); err != nil {
+		log.Printf("notify leave request %s %s: %v", request.ID, request.Status, err)
+	}
+}
+
+// checkLeaveTransition validates an action against the request's status and
+// the actor's relationship to it. Approvers may not act on their own
+// requests; only the requester may resubmit or withdraw, and approved leave
+// can only be cancelled before it starts.
+func checkLeaveTransition(request *models.LeaveRequest, toStatus string, isRequester, isApprover bool, comments string, today time.Time) error {
+	switch toStatus {
+	case "approved", "rejected":
+		if !isApprover {
+			return forbidden("only the employee's manager or HR can decide on this request")
+		}
+		if request.Status != "pending" {
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only pending requests can be %s; this one is %s", toStatus, request.Status)}
+		}
+		if toStatus == "rejected" && comments == "" {
+			return &utils.ValidationError{Field: "comments", Message: "a reason is required when rejecting"}
+		}
+	case "changes_requested":
+		if !isApprover {
+			return forbidden("only an approver can request changes to this request")
+		}
+		if request.Status != "pending" {
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("changes can only be requested to pending requests; this one is %s", request.Status)}
+		}
+		if comments == "" {
+			return &utils.ValidationError{Field: "comments", Message: "say what needs to change when requesting changes"}
+		}
+	case "pending":
+		if !isRequester {
+			return forbidden("only the requester can resubmit a request")
+		}
+		if request.Status != "changes_requested" {
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only requests sent back for changes can be resubmitted; this one is %s", request.Status)}
+		}
+	case "withdrawn":
+		if !isRequester {
+			return forbidden("only the requester can withdraw a request")
+		}
+		if request.Status != "pending" && request.Status != "changes_requested" {
+			return &utils.ValidationError{Field: "status", Message: "only pending requests can be withdrawn; cancel approved leave instead"}
+		}
+	case "cancelled":
+		if !isRequester && !isApprover {
+			return forbidden("only the requester, their manager or HR can cancel a request")
+		}
+		switch request.Status {
+		case "pending", "changes_requested":
+			if isRequester {
+				return &utils.ValidationError{Field: "status", Message: "withdraw a pending request instead of cancelling it"}
+			}
+		case "approved":
+			if !request.StartDate.After(today) {
+				return &utils.ValidationError{Field: "status", Message: "leave that has already started cannot be cancelled"}
+			}
+		default:
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("cannot cancel a %s request", request.Status)}
+		}
+	}
+	return nil
+}
+
+func (s *LeaveRequestService) leaveTypeForRequest(ctx context.Context, companyID uuid.UUID, value string) (*models.LeaveType, error) {
+	leaveTypeID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "leave_type_id", Message: "invalid leave_type_id"}
+	}
+	leaveType, err := s.leaveTypeRepo.GetLeaveTypeByID(ctx, leaveTypeID)
+	if err != nil || leaveType.CompanyID != companyID {
+		return nil, &utils.ValidationError{Field: "leave_type_id", Message: "leave type not found"}
+	}
+	return leaveType, nil
+}
+
+// buildLeaveRequest parses and checks the dates of a submission.
+func buildLeaveRequest(employee *models.Employee, req *dto.SubmitLeaveRequest) (*models.LeaveRequest, error) {
+	startDate, err := utils.ParseDate(req.StartDate)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "start_date", Message: "start_date must be YYYY-MM-DD"}
+	}
+	endDate, err := utils.ParseDate(req.EndDate)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "end_date", Message: "end_date must be YYYY-MM-DD"}
+	}
+	if endDate.Before(startDate) {
+		return nil, &utils.ValidationError{Field: "end_date", Message: "end_date cannot be before start_date"}
+	}
+	if startDate.Year() != endDate.Year() {
+		return nil, &utils.ValidationError{Field: "end_date", Message: "leave cannot span two calendar years; submit one request per year"}
+	}
+	if startDate.Before(employee.HireDate) {
+		return nil, &utils.ValidationError{Field: "start_date", Message: "start_date cannot be before hire_date"}
+	}
+	if startDate.Equal(endDate) && req.StartHalfDay && req.EndHalfDay {
+		return nil, &utils.ValidationError{Field: "end_half_day", Message: "a one-day request can be at most one half day"}
+	}
+
+	return &models.LeaveRequest{
+		EmployeeID:    employee.ID,
+		LeaveTypeID:   uuid.MustParse(req.LeaveTypeID),
+		StartDate:     startDate,
+		EndDate:       endDate,
+		StartHalfDay:  req.StartHalfDay,
+		EndHalfDay:    req.EndHalfDay,
+		Reason:        strings.TrimSpace(req.Reason),
+		AttachmentURL: strings.TrimSpace(req.AttachmentURL),
+	}, nil
+}
+
+// mergeLeaveUpdate applies the fields set in req over request, as a
+// submission to validate afresh.
+func mergeLeaveUpdate(request *models.LeaveRequest, req *dto.UpdateLeaveRequest) *dto.SubmitLeaveRequest {
+	merged := &dto.SubmitLeaveRequest{
+		LeaveTypeID:   request.LeaveTypeID.String(),
+		StartDate:     request.StartDate.Format(utils.DateLayout),
+		EndDate:       request.EndDate.Format(utils.DateLayout),
+		StartHalfDay:  request.StartHalfDay,
+		EndHalfDay:    request.EndHalfDay,
+		Reason:        request.Reason,
+		AttachmentURL: request.AttachmentURL,
+	}
+	if req.StartDate != nil {
+		merged.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		merged.EndDate = *req.EndDate
+	}
+	if req.StartHalfDay != nil {
+		merged.StartHalfDay = *req.StartHalfDay
+	}
+	if req.EndHalfDay != nil {
+		merged.EndHalfDay = *req.EndHalfDay
+	}
+	if req.Reason != nil {
+		merged.Reason = *req.Reason
+	}
+	if req.AttachmentURL != nil {
+		merged.AttachmentURL = *req.AttachmentURL
+	}
+	return merged
+}
+
+func availableLeaveDays(b *models.LeaveBalance) float64 {
+	return b.TotalDays + b.CarriedForwardDays - b.UsedDays - b.PendingDays
+}
+
+func toLeaveRequestResponse(r *models.LeaveRequest) *dto.LeaveRequestResponse {
+	return &dto.LeaveRequestResponse{
+		ID:                   r.ID.String(),
+		EmployeeID:           r.EmployeeID.String(),
+		LeaveTypeID:          r.LeaveTypeID.String(),
+		StartDate:            r.StartDate,
+		EndDate:              r.EndDate,
+		StartHalfDay:         r.StartHalfDay,
+		EndHalfDay:           r.EndHalfDay,
+		DaysRequested:        r.DaysRequested,
+		Reason:               r.Reason,
+		AttachmentURL:        r.AttachmentURL,
+		DocumentationStatus:  r.DocumentationStatus,
+		DocumentationDueDate: r.DocumentationDueDate,
+		Status:               r.Status,
+		CurrentStep:          r.CurrentStep,
+		ApprovedBy:           uuidStringPtr(r.ApprovedBy),
+		ApprovedAt:           r.ApprovedAt,
+		RejectionReason:      r.RejectionReason,
+		CreatedAt:            r.CreatedAt,
+		UpdatedAt:            r.UpdatedAt,
+	}
+}
+
+func toApprovalHistoryResponse(h *models.ApprovalHistory) *dto.ApprovalHistoryResponse {
+	return &dto.ApprovalHistoryResponse{
+		ID:             h.ID.String(),
+		StepNumber:     h.StepNumber,
+		ApproverID:     uuidStringPtr(h.ApproverID),
+		ApproverName:   h.ApproverName,
+		OnBehalfOf:     uuidStringPtr(h.OnBehalfOf),
+		OnBehalfOfName: h.OnBehalfOfName,
+		Action:         h.Action,
+		Summary:        approvalHistorySummary(h),
+		Comments:       h.Comments,
+		CreatedAt:      h.CreatedAt,
+	}
+}
+
+// approvalHistorySummary describes an action, e.g. "approved by Ada Obi on
+// behalf of Tunde Bello".
+func approvalHistorySummary(h *models.ApprovalHistory) string {
+	if h.ApproverID == nil {
+		return h.Action + " automatically"
+	}
+	summary := h.Action
+	if h.Action == actionRequestChanges {
+		summary = "changes requested"
+	}
+	if h.ApproverName != "" {
+		summary += " by " + h.ApproverName
+	}
+	if h.OnBehalfOf != nil && h.OnBehalfOfName != "" {
+		summary += " on behalf of " + h.OnBehalfOfName
+	}
+	return summary
+}
+
+func toLeaveBalanceResponse(b *models.LeaveBalance) *dto.LeaveBalanceResponse {
+	return &dto.LeaveBalanceResponse{
+		LeaveTypeID:        b.LeaveTypeID.String(),
+		Year:               b.Year,
+		TotalDays:          b.TotalDays,
+		CarriedForwardDays: b.CarriedForwardDays,
+		UsedDays:           b.UsedDays,
+		PendingDays:        b.PendingDays,
+		AvailableDays:      availableLeaveDays(b),
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+func TestCheckLeaveTransition(t *testing.T) {
+	today := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	pending := &models.LeaveRequest{Status: "pending", StartDate: today.AddDate(0, 0, 7)}
+	approvedFuture := &models.LeaveRequest{Status: "approved", StartDate: today.AddDate(0, 0, 7)}
+	approvedStarted := &models.LeaveRequest{Status: "approved", StartDate: today}
+	rejected := &models.LeaveRequest{Status: "rejected", StartDate: today.AddDate(0, 0, 7)}
+	returned := &models.LeaveRequest{Status: "changes_requested", StartDate: today.AddDate(0, 0, 7)}
+
+	tests := []struct {
+		name        string
+		request     *models.LeaveRequest
+		toStatus    string
+		isRequester bool
+		isApprover  bool
+		comments    string
+		ok          bool
+	}{
+		{"manager approves", pending, "approved", false, true, "", true},
+		{"requester cannot approve", pending, "approved", true, false, "", false},
+		{"approve twice", approvedFuture, "approved", false, true, "", false},
+		{"reject needs reason", pending, "rejected", false, true, "", false},
+		{"reject with reason", pending, "rejected", false, true, "team offsite", true},
+		{"requester withdraws", pending, "withdrawn", true, false, "", true},
+		{"manager cannot withdraw", pending, "withdrawn", false, true, "", false},
+		{"withdraw approved", approvedFuture, "withdrawn", true, false, "", false},
+		{"requester cancels future leave", approvedFuture, "cancelled", true, false, "", true},
+		{"cancel started leave", approvedStarted, "cancelled", true, false, "", false},
+		{"requester cancels pending", pending, "cancelled", true, false, "", false},
+		{"HR cancels pending", pending, "cancelled", false, true, "", true},
+		{"cancel rejected", rejected, "cancelled", false, true, "", false},
+		{"stranger cancels", approvedFuture, "cancelled", false, false, "", false},
+		{"manager requests changes", pending, "changes_requested", false, true, "split into two weeks", true},
+		{"request changes needs comments", pending, "changes_requested", false, true, "", false},
+		{"requester cannot request changes", pending, "changes_requested", true, false, "fix it", false},
+		{"request changes to approved", approvedFuture, "changes_requested", false, true, "fix it", false},
+		{"requester resubmits", returned, "pending", true, false, "", true},
+		{"manager cannot resubmit", returned, "pending", false, true, "", false},
+		{"resubmit pending", pending, "pending", true, false, "", false},
+		{"requester withdraws returned", returned, "withdrawn", true, false, "", true},
+		{"approve returned", returned, "approved", false, true, "", false},
+	}
+
+	for _, tt := range tests {
+		err := checkLeaveTransition(tt.request, tt.toStatus, tt.isRequester, tt.isApprover, tt.comments, today)
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestBuildLeaveRequest(t *testing.T) {
+	employee := &models.Employee{ID: uuid.New(), HireDate: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)}
+	existing := &models.LeaveRequest{
+		LeaveTypeID: uuid.New(),
+		StartDate:   time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC),
+	}
+	newYear := "2027-01-04"
+	sameYear := "2026-12-31"
+
+	tests := []struct {
+		name      string
+		req       *dto.SubmitLeaveRequest
+		wantField string
+	}{
+		{"within a year", &dto.SubmitLeaveRequest{LeaveTypeID: uuid.NewString(), StartDate: "2026-12-21", EndDate: "2026-12-31"}, ""},
+		{"across two years", &dto.SubmitLeaveRequest{LeaveTypeID: uuid.NewString(), StartDate: "2026-12-28", EndDate: "2027-01-04"}, "end_date"},
+		{"edited into the next year", mergeLeaveUpdate(existing, &dto.UpdateLeaveRequest{EndDate: &newYear}), "end_date"},
+		{"edited within the year", mergeLeaveUpdate(existing, &dto.UpdateLeaveRequest{EndDate: &sameYear}), ""},
+		{"end before start", &dto.SubmitLeaveRequest{LeaveTypeID: uuid.NewString(), StartDate: "2026-12-21", EndDate: "2026-12-18"}, "end_date"},
+		{"before hire date", &dto.SubmitLeaveRequest{LeaveTypeID: uuid.NewString(), StartDate: "2024-01-02", EndDate: "2024-01-09"}, "start_date"},
+	}
+
+	for _, tt := range tests {
+		_, err := buildLeaveRequest(employee, tt.req)
+		if tt.wantField == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		var validationErr *utils.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+			t.Errorf("%s: expected error on %s, got %v", tt.name, tt.wantField, err)
+		}
+	}
+}
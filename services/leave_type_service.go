@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+const defaultLeaveColor = "#3498db"
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+type LeaveTypeService struct {
+	leaveTypeRepo *repositories.LeaveTypeRepository
+	employeeRepo  *repositories.EmployeeRepository
+}
+
+func NewLeaveTypeService(
+	leaveTypeRepo *repositories.LeaveTypeRepository,
+	employeeRepo *repositories.EmployeeRepository,
+) *LeaveTypeService {
+	return &LeaveTypeService{
+		leaveTypeRepo: leaveTypeRepo,
+		employeeRepo:  employeeRepo,
+	}
+}
+
+func (s *LeaveTypeService) CreateLeaveType(ctx context.Context, companyID uuid.UUID, req *dto.CreateLeaveTypeRequest) (*dto.LeaveTypeResponse, error) {
+	lt := &models.LeaveType{
+		CompanyID:                  companyID,
+		Name:                       strings.TrimSpace(req.Name),
+		Code:                       strings.ToUpper(strings.TrimSpace(req.Code)),
+		Description:                req.Description,
+		DaysAllowed:                req.DaysAllowed,
+		IsPaid:                     req.IsPaid == nil || *req.IsPaid,
+		RequiresDocumentation:      req.RequiresDocumentation,
+		CarryForwardAllowed:        req.CarryForwardAllowed,
+		MaxCarryForwardDays:        req.MaxCarryForwardDays,
+		ColorCode:                  req.ColorCode,
+		Status:                     "active",
+		EligibleEmploymentTypes:    req.EligibleEmploymentTypes,
+		EligibleGenders:            req.EligibleGenders,
+		MinTenureDays:              req.MinTenureDays,
+		AllowedDuringProbation:     req.AllowedDuringProbation == nil || *req.AllowedDuringProbation,
+		AccrualPolicy:              req.AccrualPolicy,
+		DocumentationAfterDays:     req.DocumentationAfterDays,
+		DocumentationGraceDays:     req.DocumentationGraceDays,
+		DocumentationOverdueAction: req.DocumentationOverdueAction,
+	}
+	if lt.ColorCode == "" {
+		lt.ColorCode = defaultLeaveColor
+	}
+
+	if err := normalizeLeaveType(lt); err != nil {
+		return nil, err
+	}
+
+	created, err := s.leaveTypeRepo.CreateLeaveType(ctx, lt)
+	if isUniqueViolation(err) {
+		return nil, &utils.ValidationError{Field: "name", Message: "a leave type with this name or code already exists"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toLeaveTypeResponse(created), nil
+}
+
+func (s *LeaveTypeService) GetLeaveType(ctx context.Context, companyID, leaveTypeID uuid.UUID) (*dto.LeaveTypeResponse, error) {
+	lt, err := s.getCompanyLeaveType(ctx, companyID, leaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+	return toLeaveTypeResponse(lt), nil
+}
+
+func (s *LeaveTypeService) ListLeaveTypes(ctx context.Context, companyID uuid.UUID, status string) ([]*dto.LeaveTypeResponse, error) {
+	if status != "" && status != "active" && status != "inactive" {
+		return nil, &utils.ValidationError{Field: "status", Message: "status must be active or inactive"}
+	}
+
+	leaveTypes, err := s.leaveTypeRepo.ListLeaveTypes(ctx, companyID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.LeaveTypeResponse, 0, len(leaveTypes))
+	for _, lt := range leaveTypes {
+		responses = append(responses, toLeaveTypeResponse(lt))
+	}
+	return responses, nil
+}
+
+func (s *LeaveTypeService) UpdateLeaveType(ctx context.Context, companyID, leaveTypeID uuid.UUID, req *dto.UpdateLeaveTypeRequest) (*dto.LeaveTypeResponse, error) {
+	lt, err := s.getCompanyLeaveType(ctx, companyID, leaveTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		lt.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Code != nil {
+		lt.Code = strings.ToUpper(strings.TrimSpace(*req.Code))
+	}
+	if req.Description != nil {
+		lt.Description = *req.Description
+	}
+	if req.DaysAllowed != nil {
+		lt.DaysAllowed = *req.DaysAllowed
+	}
+	if req.IsPaid != nil {
+		lt.IsPaid = *req.IsPaid
+	}
+	if req.RequiresDocumentation != nil {
+		lt.RequiresDocumentation = *req.RequiresDocumentation
+	}
+	if req.CarryForwardAllowed != nil {
+		lt.CarryForwardAllowed = *req.CarryForwardAllowed
+	}
+	if req.MaxCarryForwardDays != nil {
+		lt.MaxCarryForwardDays = *req.MaxCarryForwardDays
+	}
+	if req.ColorCode != nil {
+		lt.ColorCode = *req.ColorCode
+	}
+	if req.Status != nil {
+		if *req.Status != "active" && *req.Status != "inactive" {
+			return nil, &utils.ValidationError{Field: "status", Message: "status must be active or inactive"}
+		}
+		lt.Status = *req.Status
+	}
+	if req.EligibleEmploymentTypes != nil {
+		lt.EligibleEmploymentTypes = *req.EligibleEmploymentTypes
+	}
+	if req.EligibleGenders != nil {
+		lt.EligibleGenders = *req.EligibleGenders
+	}
+	if req.MinTenureDays != nil {
+		lt.MinTenureDays = *req.MinTenureDays
+	}
+	if req.AllowedDuringProbation != nil {
+		lt.AllowedDuringProbation = *req.AllowedDuringProbation
+	}
+	if req.AccrualPolicy != nil {
+		lt.AccrualPolicy = *req.AccrualPolicy
+	}
+	if req.DocumentationAfterDays != nil {
+		lt.DocumentationAfterDays = *req.DocumentationAfterDays
+	}
+	if req.DocumentationGraceDays != nil {
+		lt.DocumentationGraceDays = *req.DocumentationGraceDays
+	}
+	if req.DocumentationOverdueAction != nil {
+		lt.DocumentationOverdueAction = *req.DocumentationOverdueAction
+	}
+
+	if err := normalizeLeaveType(lt); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.leaveTypeRepo.UpdateLeaveType(ctx, lt)
+	if isUniqueViolation(err) {
+		return nil, &utils.ValidationError{Field: "name", Message: "a leave type with this name or code already exists"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toLeaveTypeResponse(updated), nil
+}
+
+// DeleteLeaveType deactivates a leave type. A hard delete is only allowed
+// while no balances or requests refer to it, since those would cascade.
+func (s *LeaveTypeService) DeleteLeaveType(ctx context.Context, companyID, leaveTypeID uuid.UUID, hardDelete bool) error {
+	if _, err := s.getCompanyLeaveType(ctx, companyID, leaveTypeID); err != nil {
+		return err
+	}
+
+	if hardDelete {
+		inUse, err := s.leaveTypeRepo.IsLeaveTypeInUse(ctx, leaveTypeID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return &utils.ValidationError{Field: "leave_type", Message: "leave type has balances or requests; deactivate it instead"}
+		}
+	}
+
+	return s.leaveTypeRepo.DeleteLeaveType(ctx, leaveTypeID, !hardDelete)
+}
+
+// ListEligibility evaluates every active leave type of the company against
+// the employee's eligibility rules.
+func (s *LeaveTypeService) ListEligibility(ctx context.Context, companyID, employeeID uuid.UUID) ([]*dto.LeaveEligibilityResponse, error) {
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	leaveTypes, err := s.leaveTypeRepo.ListLeaveTypes(ctx, companyID, "active")
+	if err != nil {
+		return nil, err
+	}
+
+	today := utils.Today()
+	responses := make([]*dto.LeaveEligibilityResponse, 0, len(leaveTypes))
+	for _, lt := range leaveTypes {
+		reasons := LeaveIneligibilityReasons(lt, employee, today)
+		responses = append(responses, &dto.LeaveEligibilityResponse{
+			LeaveType: toLeaveTypeResponse(lt),
+			Eligible:  len(reasons) == 0,
+			Reasons:   reasons,
+		})
+	}
+	return responses, nil
+}
+
+func (s *LeaveTypeService) getCompanyLeaveType(ctx context.Context, companyID, leaveTypeID uuid.UUID) (*models.LeaveType, error) {
+	lt, err := s.leaveTypeRepo.GetLeaveTypeByID(ctx, leaveTypeID)
+	if err != nil || lt.CompanyID != companyID {
+		return nil, ErrLeaveTypeNotFound
+	}
+	return lt, nil
+}
+
+// LeaveIneligibilityReasons lists every rule of lt the employee fails on
+// asOf. An empty result means the employee may take this leave.
+func LeaveIneligibilityReasons(lt *models.LeaveType, employee *models.Employee, asOf time.Time) []string {
+	reasons := []string{}
+
+	if lt.Status != "active" {
+		reasons = append(reasons, "leave type is inactive")
+	}
+	if len(lt.EligibleEmploymentTypes) > 0 && !slices.Contains(lt.EligibleEmploymentTypes, employee.EmploymentType) {
+		reasons = append(reasons, fmt.Sprintf("not available to %s employees", strings.ReplaceAll(employee.EmploymentType, "_", " ")))
+	}
+	if len(lt.EligibleGenders) > 0 && !slices.Contains(lt.EligibleGenders, strings.ToLower(employee.Gender)) {
+		reasons = append(reasons, "not available for the employee's recorded gender")
+	}
+	if lt.MinTenureDays > 0 {
+		if eligibleFrom := employee.HireDate.AddDate(0, 0, lt.MinTenureDays); asOf.Before(eligibleFrom) {
+			reasons = append(reasons, fmt.Sprintf("requires %d days of service (eligible from %s)", lt.MinTenureDays, eligibleFrom.Format(utils.DateLayout)))
+		}
+	}
+	if !lt.AllowedDuringProbation && employee.Status == "probation" {
+		reasons = append(reasons, "not available during probation")
+	}
+
+	return reasons
+}
+
+// normalizeLeaveType validates lt and lower-cases its eligibility lists.
+func normalizeLeaveType(lt *models.LeaveType) error {
+	if lt.Name == "" {
+		return &utils.ValidationError{Field: "name", Message: "name is required"}
+	}
+	if lt.DaysAllowed < 0 {
+		return &utils.ValidationError{Field: "days_allowed", Message: "days_allowed cannot be negative"}
+	}
+	if !isHalfDayMultiple(lt.DaysAllowed) {
+		return &utils.ValidationError{Field: "days_allowed", Message: "days_allowed must be a multiple of 0.5"}
+	}
+	if lt.MaxCarryForwardDays < 0 || !isHalfDayMultiple(lt.MaxCarryForwardDays) {
+		return &utils.ValidationError{Field: "max_carry_forward_days", Message: "max_carry_forward_days must be a non-negative multiple of 0.5"}
+	}
+	if !lt.CarryForwardAllowed {
+		lt.MaxCarryForwardDays = 0
+	}
+	if lt.AccrualPolicy == "" {
+		lt.AccrualPolicy = "yearly"
+	}
+	if lt.AccrualPolicy != "yearly" && lt.AccrualPolicy != "monthly" {
+		return &utils.ValidationError{Field: "accrual_policy", Message: "accrual_policy must be yearly or monthly"}
+	}
+	if lt.DocumentationAfterDays < 0 {
+		return &utils.ValidationError{Field: "documentation_after_days", Message: "documentation_after_days cannot be negative"}
+	}
+	if lt.DocumentationGraceDays < 0 {
+		return &utils.ValidationError{Field: "documentation_grace_days", Message: "documentation_grace_days cannot be negative"}
+	}
+	if lt.DocumentationOverdueAction == "" {
+		lt.DocumentationOverdueAction = "flag"
+	}
+	if lt.DocumentationOverdueAction != "flag" && lt.DocumentationOverdueAction != "revert" {
+		return &utils.ValidationError{Field: "documentation_overdue_action", Message: "documentation_overdue_action must be flag or revert"}
+	}
+	if lt.MinTenureDays < 0 {
+		return &utils.ValidationError{Field: "min_tenure_days", Message: "min_tenure_days cannot be negative"}
+	}
+	if !hexColorPattern.MatchString(lt.ColorCode) {
+		return &utils.ValidationError{Field: "color_code", Message: "color_code must be a hex color such as #3498db"}
+	}
+
+	types := make([]string, 0, len(lt.EligibleEmploymentTypes))
+	for _, t := range lt.EligibleEmploymentTypes {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !slices.Contains(employmentTypes, t) {
+			return &utils.ValidationError{Field: "eligible_employment_types", Message: "employment types must be one of " + strings.Join(employmentTypes, ", ")}
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	lt.EligibleEmploymentTypes = types
+
+	genders := make([]string, 0, len(lt.EligibleGenders))
+	for _, g := range lt.EligibleGenders {
+		g = strings.ToLower(strings.TrimSpace(g))
+		if g != "" && !slices.Contains(genders, g) {
+			genders = append(genders, g)
+		}
+	}
+	lt.EligibleGenders = genders
+
+	return nil
+}
+
+func isHalfDayMultiple(days float64) bool {
+	return days*2 == float64(int64(days*2))
+}
+
+func toLeaveTypeResponse(lt *models.LeaveType) *dto.LeaveTypeResponse {
+	return &dto.LeaveTypeResponse{
+		ID:                         lt.ID.String(),
+		CompanyID:                  lt.CompanyID.String(),
+		Name:                       lt.Name,
+		Code:                       lt.Code,
+		Description:                lt.Description,
+		DaysAllowed:                lt.DaysAllowed,
+		IsPaid:                     lt.IsPaid,
+		RequiresDocumentation:      lt.RequiresDocumentation,
+		CarryForwardAllowed:        lt.CarryForwardAllowed,
+		MaxCarryForwardDays:        lt.MaxCarryForwardDays,
+		ColorCode:                  lt.ColorCode,
+		Status:                     lt.Status,
+		EligibleEmploymentTypes:    lt.EligibleEmploymentTypes,
+		EligibleGenders:            lt.EligibleGenders,
+		MinTenureDays:              lt.MinTenureDays,
+		AllowedDuringProbation:     lt.AllowedDuringProbation,
+		AccrualPolicy:              lt.AccrualPolicy,
+		DocumentationAfterDays:     lt.DocumentationAfterDays,
+		DocumentationGraceDays:     lt.DocumentationGraceDays,
+		DocumentationOverdueAction: lt.DocumentationOverdueAction,
+		CreatedAt:                  lt.CreatedAt,
+		UpdatedAt:                  lt.UpdatedAt,
+	}
+}
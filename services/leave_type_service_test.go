@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestLeaveIneligibilityReasons(t *testing.T) {
+	asOf := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	annual := &models.LeaveType{
+		Status:                  "active",
+		EligibleEmploymentTypes: []string{"full_time", "part_time"},
+		MinTenureDays:           90,
+		AllowedDuringProbation:  false,
+	}
+	maternity := &models.LeaveType{
+		Status:                 "active",
+		EligibleGenders:        []string{"female"},
+		AllowedDuringProbation: true,
+	}
+
+	tests := []struct {
+		name      string
+		leaveType *models.LeaveType
+		employee  models.Employee
+		reasons   int
+	}{
+		{"eligible", annual, models.Employee{EmploymentType: "full_time", Status: "active", HireDate: asOf.AddDate(-1, 0, 0)}, 0},
+		{"intern", annual, models.Employee{EmploymentType: "intern", Status: "active", HireDate: asOf.AddDate(-1, 0, 0)}, 1},
+		{"new probationer", annual, models.Employee{EmploymentType: "full_time", Status: "probation", HireDate: asOf.AddDate(0, 0, -30)}, 2},
+		{"tenure reached today", annual, models.Employee{EmploymentType: "full_time", Status: "active", HireDate: asOf.AddDate(0, 0, -90)}, 0},
+		{"gender matches case-insensitively", maternity, models.Employee{Gender: "Female", Status: "probation"}, 0},
+		{"gender mismatch", maternity, models.Employee{Gender: "male", Status: "active"}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := LeaveIneligibilityReasons(tt.leaveType, &tt.employee, asOf); len(got) != tt.reasons {
+			t.Errorf("%s: expected %d reasons, got %v", tt.name, tt.reasons, got)
+		}
+	}
+}
+
+func TestNormalizeLeaveType(t *testing.T) {
+	lt := &models.LeaveType{
+		Name:                    "Annual",
+		DaysAllowed:             20.5,
+		ColorCode:               "#abc",
+		MaxCarryForwardDays:     5,
+		EligibleEmploymentTypes: []string{" Full_Time", "full_time", "contract"},
+		EligibleGenders:         []string{"", "Female"},
+	}
+	if err := normalizeLeaveType(lt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lt.EligibleEmploymentTypes) != 2 || lt.EligibleEmploymentTypes[0] != "full_time" {
+		t.Errorf("unexpected employment types %v", lt.EligibleEmploymentTypes)
+	}
+	if len(lt.EligibleGenders) != 1 || lt.EligibleGenders[0] != "female" {
+		t.Errorf("unexpected genders %v", lt.EligibleGenders)
+	}
+	if lt.MaxCarryForwardDays != 0 {
+		t.Error("expected max_carry_forward_days to be cleared when carry forward is off")
+	}
+
+	for name, bad := range map[string]models.LeaveType{
+		"quarter day":        {Name: "A", DaysAllowed: 1.25, ColorCode: "#fff"},
+		"bad color":          {Name: "A", DaysAllowed: 1, ColorCode: "blue"},
+		"bad employment":     {Name: "A", DaysAllowed: 1, ColorCode: "#fff", EligibleEmploymentTypes: []string{"volunteer"}},
+		"missing name":       {DaysAllowed: 1, ColorCode: "#fff"},
+		"negative tenure":    {Name: "A", DaysAllowed: 1, ColorCode: "#fff", MinTenureDays: -1},
+		"bad overdue action": {Name: "A", DaysAllowed: 1, ColorCode: "#fff", DocumentationOverdueAction: "delete"},
+	} {
+		if err := normalizeLeaveType(&bad); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
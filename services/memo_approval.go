@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// memoActions are the action verbs accepted by the API for memos going
+// through approval.
+var memoActions = []string{"submit", "approve", "reject", "request-changes", "resubmit"}
+
+// memoDecisions maps the deciding actions onto approval engine actions.
+var memoDecisions = map[string]string{
+	"approve":         "approved",
+	"reject":          "rejected",
+	"request-changes": actionRequestChanges,
+}
+
+// memoAdminRoles are the system roles whose announcements are published
+// without approval.
+var memoAdminRoles = []string{"Super Admin", hrRoleName}
+
+// ActOnMemo applies one of the memo approval actions (submit, approve,
+// reject, request-changes, resubmit) on behalf of actorID. Approvals,
+// rejections and requests for changes go through the company's memo
+// workflow, so an approval may only move the memo on to the next step.
+func (s *MemoService) ActOnMemo(
+	ctx context.Context,
+	companyID, memoID uuid.UUID,
+	actorID *uuid.UUID,
+	action string,
+	req *dto.MemoActionRequest,
+) (*dto.MemoResponse, error) {
+	if !slices.Contains(memoActions, action) {
+		return nil, &utils.ValidationError{Field: "action", Message: "action must be one of " + strings.Join(memoActions, ", ")}
+	}
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+
+	memo, err := s.getVisibleMemo(ctx, companyID, memoID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	needsApproval, err := s.requiresApproval(ctx, memo)
+	if err != nil {
+		return nil, err
+	}
+	comments := strings.TrimSpace(req.Comments)
+	if err := checkMemoAction(memo, action, *actorID == memo.EmployeeID, needsApproval, comments); err != nil {
+		return nil, err
+	}
+
+	var updated *models.Memo
+	switch action {
+	case "submit":
+		sender, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, memo.EmployeeID)
+		if err != nil {
+			return nil, err
+		}
+		if updated, err = s.memoRepo.SubmitMemo(ctx, memo.ID); err != nil {
+			break
+		}
+		// The memo stands even if its workflow cannot be started now; the
+		// first decision on it starts it instead.
+		if _, err := s.approvalService.Start(ctx, memoApprovalSubject(sender, updated)); err != nil {
+			log.Printf("start approval of memo %s: %v", memo.ID, err)
+		}
+	case "approve", "reject", "request-changes":
+		if _, err := s.approvalService.Decide(ctx, companyID, memoApprovalEntity, memo.ID, *actorID, memoDecisions[action], comments); err != nil {
+			return nil, err
+		}
+		updated, err = s.memoRepo.GetMemoByID(ctx, memo.ID)
+	case "resubmit":
+		return s.resubmitMemo(ctx, memo, comments)
+	}
+	if errors.Is(err, repositories.ErrMemoStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: "the memo was changed by someone else; reload and try again"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toMemoResponse(updated), nil
+}
+
+// resubmitMemo puts a memo sent back for changes before its approvers
+// again, at the step the approval engine picks.
+func (s *MemoService) resubmitMemo(ctx context.Context, memo *models.Memo, comments string) (*dto.MemoResponse, error) {
+	sender, err := findCompanyEmployee(ctx, s.employeeRepo, memo.CompanyID, memo.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	subject := memoApprovalSubject(sender, memo)
+	resubmission, err := s.approvalService.PrepareResubmission(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.memoRepo.ResubmitMemo(ctx, memo.ID, resubmission.Step, &models.ApprovalHistory{
+		ApproverID: &sender.ID,
+		Action:     "resubmitted",
+		Comments:   comments,
+	}, resubmission.Revision)
+	if errors.Is(err, repositories.ErrMemoStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: "the memo was changed by someone else; reload and try again"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subject.Status = updated.Status
+	s.approvalService.Resubmitted(ctx, subject)
+	return toMemoResponse(updated), nil
+}
+
+// ListMemoApprovalHistory returns the approval decisions taken on a memo
+// to those who may see it.
+func (s *MemoService) ListMemoApprovalHistory(ctx context.Context, companyID, memoID uuid.UUID, actorID *uuid.UUID) ([]*dto.ApprovalHistoryResponse, error) {
+	if _, err := s.getVisibleMemo(ctx, companyID, memoID, actorID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.approvalHistoryRepo.ListApprovalHistory(ctx, memoApprovalEntity, memoID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ApprovalHistoryResponse, 0, len(history))
+	for _, h := range history {
+		responses = append(responses, toApprovalHistoryResponse(h))
+	}
+	return responses, nil
+}
+
+// ApprovalSubject implements ApprovalTarget for memos.
+func (s *MemoService) ApprovalSubject(ctx context.Context, companyID, memoID uuid.UUID) (*ApprovalSubject, error) {
+	memo, err := s.memoRepo.GetMemoByID(ctx, memoID)
+	if err != nil || memo.CompanyID != companyID {
+		return nil, ErrMemoNotFound
+	}
+	sender, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, memo.EmployeeID)
+	if err != nil {
+		return nil, ErrMemoNotFound
+	}
+	return memoApprovalSubject(sender, memo), nil
+}
+
+// ApplyApprovalDecision implements ApprovalTarget for memos: an
+// intermediate approval moves the memo to the next step, a final decision
+// approves or rejects it and a request for changes sends it back, telling
+// the sender of either.
+func (s *MemoService) ApplyApprovalDecision(ctx context.Context, subject *ApprovalSubject, decision *ApprovalDecision) error {
+	history := &models.ApprovalHistory{
+		ApproverID: decision.ActorID,
+		OnBehalfOf: decision.OnBehalfOf,
+		Action:     decision.Action,
+		Comments:   decision.Comments,
+	}
+
+	var err error
+	var updated *models.Memo
+	switch {
+	case decision.Returned():
+		if updated, err = s.memoRepo.ReturnMemo(ctx, subject.EntityID, decision.Step, history); err == nil {
+			s.notifySender(ctx, subject.Requester, updated, decision.Comments)
+		}
+	case decision.Final():
+		if updated, err = s.memoRepo.FinalizeMemo(ctx, subject.EntityID, decision.Step, history); err == nil {
+			s.notifySender(ctx, subject.Requester, updated, decision.Comments)
+		}
+	default:
+		_, err = s.memoRepo.AdvanceMemo(ctx, subject.EntityID, decision.Step, decision.NextStep, history)
+	}
+	if errors.Is(err, repositories.ErrMemoStatusChanged) {
+		return &utils.ValidationError{Field: "status", Message: "the memo was changed by someone else; reload and try again"}
+	}
+	return err
+}
+
+// notifySender tells the sender how their memo's approval ended, or that
+// it was sent back for changes. Failures are logged rather than undoing the
+// decision.
+func (s *MemoService) notifySender(ctx context.Context, sender *models.Employee, memo *models.Memo, comments string) {
+	title := "Memo " + memo.Status
+	body := fmt.Sprintf("Your memo %q has been %s.", memo.Title, memo.Status)
+	dedupeKey := fmt.Sprintf("memo_%s:%s", memo.Status, memo.ID)
+	switch {
+	case memo.Status == "changes_requested":
+		title = "Changes requested to memo"
+		body = fmt.Sprintf("Your memo %q needs changes before it can be approved; edit and resubmit it.", memo.Title)
+		// A memo can be sent back more than once.
+		dedupeKey += fmt.Sprintf(":%d", memo.UpdatedAt.Unix())
+	case memo.Status == "approved" && memo.MemoType != "request":
+		body = fmt.Sprintf("Your memo %q has been approved and can now be published.", memo.Title)
+	case memo.MemoType == "request":
+		title = "Request " + memo.Status
+		body = fmt.Sprintf("Your request %q has been %s.", memo.Title, memo.Status)
+	}
+	if comments != "" {
+		body += " Comments: " + comments
+	}
+
+	if _, err := s.notificationService.Notify(ctx, models.Notification{
+		CompanyID:  sender.CompanyID,
+		Type:       "memo_" + memo.Status,
+		Title:      title,
+		Body:       body,
+		EntityType: memoApprovalEntity,
+		EntityID:   &memo.ID,
+		DedupeKey:  dedupeKey,
+	}, sender.ID); err != nil {
+		log.Printf("notify memo %s %s: %v", memo.ID, memo.Status, err)
+	}
+}
+
+// requiresApproval reports whether memo must be approved before it is
+// published, as memoNeedsApproval decides for its type and sender.
+func (s *MemoService) requiresApproval(ctx context.Context, memo *models.Memo) (bool, error) {
+	if memo.MemoType != "announcement" {
+		return memoNeedsApproval(memo.MemoType, false), nil
+	}
+	admin, err := s.isMemoAdmin(ctx, memo.CompanyID, memo.EmployeeID)
+	if err != nil {
+		return false, err
+	}
+	return memoNeedsApproval(memo.MemoType, admin), nil
+}
+
+// isMemoAdmin reports whether employeeID holds one of memoAdminRoles.
+func (s *MemoService) isMemoAdmin(ctx context.Context, companyID, employeeID uuid.UUID) (bool, error) {
+	return hasAnyRole(ctx, s.employeeRepo, companyID, employeeID, memoAdminRoles...)
+}
+
+// memoNeedsApproval reports whether a memo of memoType goes through the
+// memo approval workflow: requests always do, and announcements do unless
+// an admin sends them.
+func memoNeedsApproval(memoType string, senderIsAdmin bool) bool {
+	switch memoType {
+	case "request":
+		return true
+	case "announcement":
+		return !senderIsAdmin
+	default:
+		return false
+	}
+}
+
+// checkMemoPublishable checks that a memo may be published: a draft that
+// needs no approval, or an approved memo, with somebody to send it to.
+func checkMemoPublishable(memo *models.Memo, needsApproval bool) error {
+	switch {
+	case memo.Status == "approved":
+	case memo.Status == "draft" && needsApproval:
+		return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("%s memos must be approved before they are published; submit it for approval", memo.MemoType)}
+	case memo.Status == "pending" || memo.Status == "changes_requested":
+		return &utils.ValidationError{Field: "status", Message: "the memo has not been approved yet"}
+	case memo.Status != "draft":
+		return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("a %s memo cannot be published", memo.Status)}
+	}
+	if len(memo.Targets) == 0 {
+		return &utils.ValidationError{Field: "targets", Message: "add at least one target before publishing"}
+	}
+	return nil
+}
+
+// checkMemoAction validates an action against the memo's status and the
+// actor's relationship to it. Only the sender may submit or resubmit a
+// memo, and never decide on it; the approval engine checks deciders
+// against the current step.
+func checkMemoAction(memo *models.Memo, action string, isSender, needsApproval bool, comments string) error {
+	switch action {
+	case "submit", "resubmit":
+		if !isSender {
+			return forbidden("only the sender can " + action + " a memo")
+		}
+	default:
+		if isSender {
+			return forbidden("senders cannot decide on their own memos")
+		}
+	}
+
+	switch action {
+	case "submit":
+		if memo.Status != "draft" {
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only draft memos can be submitted; this one is %s", memo.Status)}
+		}
+		if !needsApproval {
+			return &utils.ValidationError{Field: "memo_type", Message: "this memo needs no approval; publish it instead"}
+		}
+		if memo.MemoType != "request" && len(memo.Targets) == 0 {
+			return &utils.ValidationError{Field: "targets", Message: "add at least one target before submitting"}
+		}
+	case "resubmit":
+		if memo.Status != "changes_requested" {
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only memos sent back for changes can be resubmitted; this one is %s", memo.Status)}
+		}
+	default:
+		if memo.Status != "pending" {
+			return &utils.ValidationError{Field: "status", Message: fmt.Sprintf("only pending memos can be decided; this one is %s", memo.Status)}
+		}
+		if action == "reject" && comments == "" {
+			return &utils.ValidationError{Field: "comments", Message: "a reason is required when rejecting"}
+		}
+		if action == "request-changes" && comments == "" {
+			return &utils.ValidationError{Field: "comments", Message: "say what needs to change when requesting changes"}
+		}
+	}
+	return nil
+}
+
+func memoApprovalSubject(sender *models.Employee, memo *models.Memo) *ApprovalSubject {
+	return &ApprovalSubject{
+		EntityType:   memoApprovalEntity,
+		EntityID:     memo.ID,
+		WorkflowType: "memo",
+		Requester:    sender,
+		Title:        fmt.Sprintf("%s memo %q from %s %s", strings.ToUpper(memo.MemoType[:1])+memo.MemoType[1:], memo.Title, sender.FirstName, sender.LastName),
+		Status:       memo.Status,
+		Revision:     memoRevision(memo),
+	}
+}
+
+// memoRevision is what approvers review of a memo.
+func memoRevision(memo *models.Memo) map[string]any {
+	targets := make([]any, 0, len(memo.Targets))
+	for _, t := range memo.Targets {
+		targets = append(targets, map[string]any{
+			"type": t.TargetType,
+			"id":   uuidString(t.TargetID),
+		})
+	}
+	return map[string]any{
+		"title":    memo.Title,
+		"content":  memo.Content,
+		"priority": memo.Priority,
+		"targets":  targets,
+	}
+}
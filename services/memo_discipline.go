@@ -0,0 +1,503 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// disciplinaryOutcomeLabels describes how a disciplinary case ended.
+var disciplinaryOutcomeLabels = map[string]string{
+	"no_action":       "no further action",
+	"verbal_warning":  "a verbal warning",
+	"written_warning": "a written warning",
+	"final_warning":   "a final written warning",
+	"suspension":      "suspension",
+	"demotion":        "demotion",
+	"termination":     "termination of employment",
+}
+
+// canViewDiscipline reports whether viewerID may see disciplinary matters
+// concerning subjectID: the subject themselves, anyone above them in the
+// reporting line, and HR.
+func (s *MemoService) canViewDiscipline(ctx context.Context, companyID, subjectID, viewerID uuid.UUID) (bool, error) {
+	inChain, err := s.employeeRepo.IsInManagementChain(ctx, subjectID, viewerID)
+	if err != nil || inChain {
+		return inChain, err
+	}
+	return isHR(ctx, s.employeeRepo, companyID, viewerID)
+}
+
+// canSeeDisciplinaryMemo reports whether viewerID may see a disciplinary
+// memo: its sender, and whoever canViewDiscipline allows for its subject.
+func (s *MemoService) canSeeDisciplinaryMemo(ctx context.Context, memo *models.Memo, viewerID uuid.UUID) (bool, error) {
+	if memo.EmployeeID == viewerID {
+		return true, nil
+	}
+	if memo.SubjectEmployeeID == nil {
+		return isHR(ctx, s.employeeRepo, memo.CompanyID, viewerID)
+	}
+	return s.canViewDiscipline(ctx, memo.CompanyID, *memo.SubjectEmployeeID, viewerID)
+}
+
+// checkMemoSubject checks that only disciplinary memos have a subject and
+// that the sender of one is HR or one of the subject's managers. A
+// disciplinary memo goes to its subject alone, so its targets are set to
+// them.
+func (s *MemoService) checkMemoSubject(ctx context.Context, memo *models.Memo) error {
+	if memo.MemoType != "disciplinary" {
+		if memo.SubjectEmployeeID != nil {
+			return &utils.ValidationError{Field: "subject_id", Message: "only disciplinary memos have a subject"}
+		}
+		return nil
+	}
+
+	if memo.SubjectEmployeeID == nil {
+		return &utils.ValidationError{Field: "subject_id", Message: "disciplinary memos need a subject_id"}
+	}
+	subjectID := *memo.SubjectEmployeeID
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, memo.CompanyID, subjectID); err != nil {
+		if errors.Is(err, ErrEmployeeNotFound) {
+			return &utils.ValidationError{Field: "subject_id", Message: "employee not found"}
+		}
+		return err
+	}
+	if subjectID == memo.EmployeeID {
+		return &utils.ValidationError{Field: "subject_id", Message: "you cannot send a disciplinary memo about yourself"}
+	}
+	ok, err := s.canViewDiscipline(ctx, memo.CompanyID, subjectID, memo.EmployeeID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return forbidden("only HR and the employee's managers can send disciplinary memos")
+	}
+
+	for _, t := range memo.Targets {
+		if t.TargetType != "individual" || *t.TargetID != subjectID {
+			return &utils.ValidationError{Field: "targets", Message: "disciplinary memos go only to their subject"}
+		}
+	}
+	memo.Targets = []*models.MemoTarget{{TargetType: "individual", TargetID: &subjectID}}
+	return nil
+}
+
+// ListDisciplinaryMemos returns the disciplinary memos about an employee to
+// those allowed to see them.
+func (s *MemoService) ListDisciplinaryMemos(ctx context.Context, companyID, subjectID uuid.UUID, actorID *uuid.UUID) ([]*dto.MemoResponse, error) {
+	if err := s.checkCanViewDiscipline(ctx, companyID, subjectID, actorID); err != nil {
+		return nil, err
+	}
+
+	memos, err := s.memoRepo.ListDisciplinaryMemos(ctx, subjectID, *actorID)
+	if err != nil {
+		return nil, err
+	}
+	return toMemoResponses(memos), nil
+}
+
+// ListMemoThread returns the responses, appeals and replies under a
+// published disciplinary memo.
+func (s *MemoService) ListMemoThread(ctx context.Context, companyID, memoID uuid.UUID, actorID *uuid.UUID) ([]*dto.MemoThreadEntryResponse, error) {
+	memo, err := s.threadMemo(ctx, companyID, memoID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := s.memoRepo.ListMemoThread(ctx, memo.ID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*dto.MemoThreadEntryResponse, 0, len(thread))
+	for _, e := range thread {
+		responses = append(responses, toMemoThreadEntryResponse(e))
+	}
+	return responses, nil
+}
+
+// AddMemoThreadEntry adds to the thread under a published disciplinary
+// memo. The subject's responses and appeals go to the sender and HR;
+// everyone else's replies go to the subject.
+func (s *MemoService) AddMemoThreadEntry(
+	ctx context.Context,
+	companyID, memoID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.CreateMemoThreadEntryRequest,
+) (*dto.MemoThreadEntryResponse, error) {
+	memo, err := s.threadMemo(ctx, companyID, memoID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	isSubject := *memo.SubjectEmployeeID == *actorID
+	kind, err := memoThreadEntryKind(req.Kind, isSubject)
+	if err != nil {
+		return nil, err
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, &utils.ValidationError{Field: "content", Message: "content is required"}
+	}
+
+	entry, err := s.memoRepo.CreateMemoThreadEntry(ctx, &models.MemoThreadEntry{
+		MemoID:   memo.ID,
+		AuthorID: actorID,
+		Kind:     kind,
+		Content:  content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notification := models.Notification{
+		CompanyID:  companyID,
+		Type:       "memo_" + kind,
+		Title:      fmt.Sprintf("New %s on %s", kind, memo.ReferenceNumber),
+		Body:       entry.AuthorName + " wrote on " + memo.ReferenceNumber + ".",
+		EntityType: "memo",
+		EntityID:   &memo.ID,
+		DedupeKey:  "memo_thread:" + entry.ID.String(),
+	}
+	if isSubject {
+		s.notificationService.NotifyHR(ctx, notification, memo.EmployeeID)
+	} else {
+		s.notificationService.Notify(ctx, notification, *memo.SubjectEmployeeID)
+	}
+	return toMemoThreadEntryResponse(entry), nil
+}
+
+// threadMemo returns a published disciplinary memo that actorID may see.
+func (s *MemoService) threadMemo(ctx context.Context, companyID, memoID uuid.UUID, actorID *uuid.UUID) (*models.Memo, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	memo, err := s.getVisibleMemo(ctx, companyID, memoID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if memo.MemoType != "disciplinary" || memo.SubjectEmployeeID == nil {
+		return nil, &utils.ValidationError{Field: "memo", Message: "only disciplinary memos have a response thread"}
+	}
+	if memo.Status != "published" {
+		return nil, &utils.ValidationError{Field: "status", Message: "the memo has not been published"}
+	}
+	return memo, nil
+}
+
+// memoThreadEntryKind resolves the kind of a thread entry. The subject
+// responds or appeals; everyone else replies.
+func memoThreadEntryKind(requested string, isSubject bool) (string, error) {
+	switch {
+	case requested == "" && isSubject:
+		return "response", nil
+	case requested == "":
+		return "reply", nil
+	case isSubject && (requested == "response" || requested == "appeal"):
+		return requested, nil
+	case !isSubject && requested == "reply":
+		return requested, nil
+	case isSubject:
+		return "", &utils.ValidationError{Field: "kind", Message: "the subject can post a response or an appeal"}
+	default:
+		return "", &utils.ValidationError{Field: "kind", Message: "only the subject can respond or appeal; others reply"}
+	}
+}
+
+// CreateDisciplinaryCase opens a case about an employee, linking any
+// disciplinary memos given. Only HR can open cases.
+func (s *MemoService) CreateDisciplinaryCase(ctx context.Context, companyID uuid.UUID, actorID *uuid.UUID, req *dto.CreateDisciplinaryCaseRequest) (*dto.DisciplinaryCaseResponse, error) {
+	if err := s.checkMemoHR(ctx, companyID, actorID, "only HR can manage disciplinary cases"); err != nil {
+		return nil, err
+	}
+	employeeID, err := uuid.Parse(req.EmployeeID)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "employee_id", Message: "invalid employee_id"}
+	}
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, &utils.ValidationError{Field: "title", Message: "title is required"}
+	}
+
+	memos := make([]*models.Memo, 0, len(req.MemoIDs))
+	for i, value := range req.MemoIDs {
+		field := fmt.Sprintf("memo_ids[%d]", i)
+		memoID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, &utils.ValidationError{Field: field, Message: "invalid memo id"}
+		}
+		memo, err := s.caseMemo(ctx, companyID, employeeID, memoID, field)
+		if err != nil {
+			return nil, err
+		}
+		memos = append(memos, memo)
+	}
+
+	created, err := s.memoRepo.CreateDisciplinaryCase(ctx, &models.DisciplinaryCase{
+		CompanyID:   companyID,
+		EmployeeID:  employeeID,
+		Title:       title,
+		Description: strings.TrimSpace(req.Description),
+		OpenedBy:    actorID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, memo := range memos {
+		if err := s.memoRepo.SetMemoCase(ctx, memo.ID, &created.ID); err != nil {
+			return nil, err
+		}
+	}
+	return s.getDisciplinaryCase(ctx, created, *actorID)
+}
+
+// ListDisciplinaryCases returns the company's cases to HR, narrowed to one
+// employee when employeeID is set. Others must name an employee whose
+// cases they may see.
+func (s *MemoService) ListDisciplinaryCases(ctx context.Context, companyID uuid.UUID, actorID *uuid.UUID, employeeID, status string) ([]*dto.DisciplinaryCaseResponse, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	employee, err := parseOptionalUUID(employeeID)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "employee_id", Message: "invalid employee_id"}
+	}
+	if status != "" && status != "open" && status != "closed" {
+		return nil, &utils.ValidationError{Field: "status", Message: "status must be open or closed"}
+	}
+
+	hr, err := isHR(ctx, s.employeeRepo, companyID, *actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !hr {
+		if employee == nil {
+			return nil, &utils.ValidationError{Field: "employee_id", Message: "employee_id is required"}
+		}
+		if err := s.checkCanViewDiscipline(ctx, companyID, *employee, actorID); err != nil {
+			return nil, err
+		}
+	}
+
+	cases, err := s.memoRepo.ListDisciplinaryCases(ctx, companyID, employee, status)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*dto.DisciplinaryCaseResponse, 0, len(cases))
+	for _, c := range cases {
+		responses = append(responses, toDisciplinaryCaseResponse(c))
+	}
+	return responses, nil
+}
+
+// GetDisciplinaryCase returns a case with its memos to those allowed to see
+// the employee's disciplinary matters.
+func (s *MemoService) GetDisciplinaryCase(ctx context.Context, companyID, caseID uuid.UUID, actorID *uuid.UUID) (*dto.DisciplinaryCaseResponse, error) {
+	c, err := s.getCompanyCase(ctx, companyID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCanViewDiscipline(ctx, companyID, c.EmployeeID, actorID); err != nil {
+		return nil, err
+	}
+	return s.getDisciplinaryCase(ctx, c, *actorID)
+}
+
+// LinkCaseMemo adds a disciplinary memo about the case's employee to an
+// open case. Only HR can link memos.
+func (s *MemoService) LinkCaseMemo(ctx context.Context, companyID, caseID uuid.UUID, actorID *uuid.UUID, req *dto.LinkCaseMemoRequest) (*dto.DisciplinaryCaseResponse, error) {
+	memoID, err := uuid.Parse(req.MemoID)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "memo_id", Message: "invalid memo_id"}
+	}
+	return s.setCaseMemo(ctx, companyID, caseID, memoID, actorID, true)
+}
+
+// UnlinkCaseMemo removes a memo from an open case. Only HR can unlink
+// memos.
+func (s *MemoService) UnlinkCaseMemo(ctx context.Context, companyID, caseID, memoID uuid.UUID, actorID *uuid.UUID) (*dto.DisciplinaryCaseResponse, error) {
+	return s.setCaseMemo(ctx, companyID, caseID, memoID, actorID, false)
+}
+
+func (s *MemoService) setCaseMemo(ctx context.Context, companyID, caseID, memoID uuid.UUID, actorID *uuid.UUID, link bool) (*dto.DisciplinaryCaseResponse, error) {
+	if err := s.checkMemoHR(ctx, companyID, actorID, "only HR can manage disciplinary cases"); err != nil {
+		return nil, err
+	}
+	c, err := s.getCompanyCase(ctx, companyID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != "open" {
+		return nil, &utils.ValidationError{Field: "status", Message: "the case is closed"}
+	}
+	memo, err := s.caseMemo(ctx, companyID, c.EmployeeID, memoID, "memo_id")
+	if err != nil {
+		return nil, err
+	}
+
+	var target *uuid.UUID
+	switch {
+	case link && memo.CaseID != nil && *memo.CaseID != c.ID:
+		return nil, &utils.ValidationError{Field: "memo_id", Message: "the memo belongs to another case"}
+	case link:
+		target = &c.ID
+	case memo.CaseID == nil || *memo.CaseID != c.ID:
+		return nil, ErrMemoNotFound
+	}
+
+	err = s.memoRepo.SetMemoCase(ctx, memo.ID, target)
+	if errors.Is(err, repositories.ErrDisciplinaryCaseClosed) {
+		return nil, &utils.ValidationError{Field: "status", Message: "the case is closed"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.getDisciplinaryCase(ctx, c, *actorID)
+}
+
+// CloseDisciplinaryCase records the outcome of an open case and tells the
+// employee. Only HR can close cases.
+func (s *MemoService) CloseDisciplinaryCase(ctx context.Context, companyID, caseID uuid.UUID, actorID *uuid.UUID, req *dto.CloseDisciplinaryCaseRequest) (*dto.DisciplinaryCaseResponse, error) {
+	if err := s.checkMemoHR(ctx, companyID, actorID, "only HR can manage disciplinary cases"); err != nil {
+		return nil, err
+	}
+	c, err := s.getCompanyCase(ctx, companyID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	label, ok := disciplinaryOutcomeLabels[req.Outcome]
+	if !ok {
+		return nil, &utils.ValidationError{Field: "outcome", Message: "unknown outcome " + req.Outcome}
+	}
+
+	closed, err := s.memoRepo.CloseDisciplinaryCase(ctx, c.ID, req.Outcome, strings.TrimSpace(req.Notes), actorID)
+	if errors.Is(err, repositories.ErrDisciplinaryCaseClosed) {
+		return nil, &utils.ValidationError{Field: "status", Message: "the case is already closed"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.notificationService.Notify(ctx, models.Notification{
+		CompanyID:  companyID,
+		Type:       "disciplinary_case_closed",
+		Title:      "Disciplinary case closed",
+		Body:       fmt.Sprintf("The case %q has been closed with %s.", closed.Title, label),
+		EntityType: "disciplinary_case",
+		EntityID:   &closed.ID,
+		DedupeKey:  "disciplinary_case_closed:" + closed.ID.String(),
+	}, closed.EmployeeID)
+	return s.getDisciplinaryCase(ctx, closed, *actorID)
+}
+
+// getDisciplinaryCase returns c with the memos linked to it that viewerID
+// may see.
+func (s *MemoService) getDisciplinaryCase(ctx context.Context, c *models.DisciplinaryCase, viewerID uuid.UUID) (*dto.DisciplinaryCaseResponse, error) {
+	memos, err := s.memoRepo.ListCaseMemos(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]*models.Memo, 0, len(memos))
+	for _, m := range memos {
+		if m.Status != "draft" || m.EmployeeID == viewerID {
+			visible = append(visible, m)
+		}
+	}
+
+	response := toDisciplinaryCaseResponse(c)
+	response.Memos = toMemoResponses(visible)
+	return response, nil
+}
+
+func (s *MemoService) getCompanyCase(ctx context.Context, companyID, caseID uuid.UUID) (*models.DisciplinaryCase, error) {
+	c, err := s.memoRepo.GetDisciplinaryCaseByID(ctx, caseID)
+	if err != nil || c.CompanyID != companyID {
+		return nil, ErrDisciplinaryCaseNotFound
+	}
+	return c, nil
+}
+
+// caseMemo returns a disciplinary memo of the company about employeeID.
+func (s *MemoService) caseMemo(ctx context.Context, companyID, employeeID, memoID uuid.UUID, field string) (*models.Memo, error) {
+	memo, err := s.memoRepo.GetMemoByID(ctx, memoID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && memo.CompanyID != companyID) {
+		return nil, &utils.ValidationError{Field: field, Message: "memo not found"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if memo.MemoType != "disciplinary" || memo.SubjectEmployeeID == nil || *memo.SubjectEmployeeID != employeeID {
+		return nil, &utils.ValidationError{Field: field, Message: "only disciplinary memos about the case's employee can be linked"}
+	}
+	return memo, nil
+}
+
+func (s *MemoService) checkCanViewDiscipline(ctx context.Context, companyID, subjectID uuid.UUID, actorID *uuid.UUID) error {
+	if actorID == nil {
+		return &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, subjectID); err != nil {
+		return err
+	}
+	ok, err := s.canViewDiscipline(ctx, companyID, subjectID, *actorID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return forbidden("only the employee, their managers and HR can see disciplinary records")
+	}
+	return nil
+}
+
+// checkMemoHR checks that actorID holds the HR role, failing with message.
+func (s *MemoService) checkMemoHR(ctx context.Context, companyID uuid.UUID, actorID *uuid.UUID, message string) error {
+	if actorID == nil {
+		return &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	ok, err := isHR(ctx, s.employeeRepo, companyID, *actorID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return forbidden(message)
+	}
+	return nil
+}
+
+func toMemoThreadEntryResponse(e *models.MemoThreadEntry) *dto.MemoThreadEntryResponse {
+	return &dto.MemoThreadEntryResponse{
+		ID:         e.ID.String(),
+		AuthorID:   uuidStringPtr(e.AuthorID),
+		AuthorName: e.AuthorName,
+		Kind:       e.Kind,
+		Content:    e.Content,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+func toDisciplinaryCaseResponse(c *models.DisciplinaryCase) *dto.DisciplinaryCaseResponse {
+	return &dto.DisciplinaryCaseResponse{
+		ID:           c.ID.String(),
+		EmployeeID:   c.EmployeeID.String(),
+		Title:        c.Title,
+		Description:  c.Description,
+		Status:       c.Status,
+		Outcome:      c.Outcome,
+		OutcomeNotes: c.OutcomeNotes,
+		OpenedBy:     uuidStringPtr(c.OpenedBy),
+		ClosedBy:     uuidStringPtr(c.ClosedBy),
+		ClosedAt:     c.ClosedAt,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
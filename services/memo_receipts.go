@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// Recipients who have not read an urgent memo, or not acknowledged one that
+// requires it, are reminded every memoReminderInterval after publication,
+// up to maxMemoReminders times.
+const (
+	memoReminderInterval = 24 * time.Hour
+	maxMemoReminders     = 3
+)
+
+// ListReceivedMemos returns the memos published to an employee, only the
+// unread ones when unreadOnly is set. Only the employee and HR can list
+// them, and disciplinary memos are left out unless canSeeDisciplinaryMemo
+// allows the actor to see them.
+func (s *MemoService) ListReceivedMemos(ctx context.Context, companyID, employeeID uuid.UUID, actorID *uuid.UUID, unreadOnly bool) ([]*dto.ReceivedMemoResponse, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+	if *actorID != employeeID {
+		ok, err := isHR(ctx, s.employeeRepo, companyID, *actorID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, forbidden("only the employee and HR can see the memos they received")
+		}
+	}
+
+	received, err := s.memoRepo.ListReceivedMemos(ctx, employeeID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ReceivedMemoResponse, 0, len(received))
+	for _, r := range received {
+		if r.Memo.MemoType == "disciplinary" {
+			ok, err := s.canSeeDisciplinaryMemo(ctx, &r.Memo, *actorID)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		responses = append(responses, toReceivedMemoResponse(&r.Memo, &r.Receipt))
+	}
+	return responses, nil
+}
+
+// MarkMemoRead records that an employee has read a memo published to them.
+// Reading it again keeps the first read time.
+func (s *MemoService) MarkMemoRead(ctx context.Context, companyID, employeeID, memoID uuid.UUID, actorID *uuid.UUID) (*dto.ReceivedMemoResponse, error) {
+	return s.markMemoRead(ctx, companyID, employeeID, memoID, actorID, false)
+}
+
+// AcknowledgeMemo records that an employee confirms a memo that requires
+// acknowledgement, which also marks it read.
+func (s *MemoService) AcknowledgeMemo(ctx context.Context, companyID, employeeID, memoID uuid.UUID, actorID *uuid.UUID) (*dto.ReceivedMemoResponse, error) {
+	return s.markMemoRead(ctx, companyID, employeeID, memoID, actorID, true)
+}
+
+// markMemoRead records the read, and the acknowledgement when acknowledge
+// is set. Only the employee can read or acknowledge their own memos.
+func (s *MemoService) markMemoRead(ctx context.Context, companyID, employeeID, memoID uuid.UUID, actorID *uuid.UUID, acknowledge bool) (*dto.ReceivedMemoResponse, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	if *actorID != employeeID {
+		return nil, forbidden("only the employee can mark their memos read or acknowledged")
+	}
+
+	memo, err := s.memoRepo.GetMemoByID(ctx, memoID)
+	if err != nil || memo.CompanyID != companyID || memo.Status != "published" {
+		return nil, ErrMemoNotFound
+	}
+	if acknowledge && !memo.RequiresAcknowledgement {
+		return nil, &utils.ValidationError{Field: "memo", Message: "this memo does not require acknowledgement"}
+	}
+
+	receipt, err := s.memoRepo.MarkMemoRead(ctx, memoID, employeeID, acknowledge)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMemoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toReceivedMemoResponse(memo, receipt), nil
+}
+
+// GetMemoDeliveryReport shows who has read a published memo and who has
+// acknowledged it. Only its sender and HR can see it.
+func (s *MemoService) GetMemoDeliveryReport(ctx context.Context, companyID, memoID uuid.UUID, actorID *uuid.UUID) (*dto.MemoDeliveryReport, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	memo, err := s.getVisibleMemo(ctx, companyID, memoID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if memo.EmployeeID != *actorID {
+		ok, err := isHR(ctx, s.employeeRepo, companyID, *actorID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, forbidden("only the sender and HR can see who has read this memo")
+		}
+	}
+	if memo.Status != "published" {
+		return nil, &utils.ValidationError{Field: "status", Message: "the memo has not been published"}
+	}
+
+	recipients, err := s.memoRepo.ListMemoRecipients(ctx, memoID)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeMemoDelivery(memo, recipients), nil
+}
+
+// SendMemoReminders reminds recipients who have not read, or not
+// acknowledged, urgent memos. It returns the number of reminders sent.
+func (s *MemoService) SendMemoReminders(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.memoRepo.ListDueMemoReminders(ctx, now, memoReminderInterval, maxMemoReminders)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, d := range due {
+		reminded, err := s.memoRepo.MarkMemoReminded(ctx, d.Receipt.ID, d.Receipt.ReminderCount)
+		if err != nil {
+			return sent, err
+		}
+		if !reminded {
+			continue
+		}
+
+		body := fmt.Sprintf("Urgent memo %s is still unread. Please read it.", d.ReferenceNumber)
+		if d.Receipt.IsRead {
+			body = fmt.Sprintf("Urgent memo %s needs your acknowledgement.", d.ReferenceNumber)
+		}
+		if _, err := s.notificationService.Notify(ctx, models.Notification{
+			CompanyID:  d.CompanyID,
+			Type:       "memo_reminder",
+			Title:      "Reminder: " + d.Title,
+			Body:       body,
+			EntityType: "memo",
+			EntityID:   &d.ID,
+			DedupeKey:  fmt.Sprintf("memo_reminder:%s:%d", d.ID, d.Receipt.ReminderCount+1),
+		}, d.Receipt.EmployeeID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		log.Printf("sent %d urgent memo reminders", sent)
+	}
+	return sent, nil
+}
+
+// summarizeMemoDelivery counts and lists the recipients of memo who have
+// and have not read it and, when it requires acknowledgement, those who
+// have yet to acknowledge it.
+func summarizeMemoDelivery(memo *models.Memo, recipients []*models.MemoRecipient) *dto.MemoDeliveryReport {
+	report := &dto.MemoDeliveryReport{
+		MemoID:                  memo.ID.String(),
+		ReferenceNumber:         memo.ReferenceNumber,
+		RequiresAcknowledgement: memo.RequiresAcknowledgement,
+		RecipientCount:          len(recipients),
+		Read:                    []*dto.MemoReceiptResponse{},
+		Unread:                  []*dto.MemoReceiptResponse{},
+	}
+	if memo.RequiresAcknowledgement {
+		report.Unacknowledged = []*dto.MemoReceiptResponse{}
+	}
+
+	for _, r := range recipients {
+		receipt := toMemoReceiptResponse(r)
+		if r.IsRead {
+			report.ReadCount++
+			report.Read = append(report.Read, receipt)
+		} else {
+			report.UnreadCount++
+			report.Unread = append(report.Unread, receipt)
+		}
+		if !memo.RequiresAcknowledgement {
+			continue
+		}
+		if r.AcknowledgedAt != nil {
+			report.AcknowledgedCount++
+		} else {
+			report.Unacknowledged = append(report.Unacknowledged, receipt)
+		}
+	}
+	return report
+}
+
+func toMemoReceiptResponse(r *models.MemoRecipient) *dto.MemoReceiptResponse {
+	return &dto.MemoReceiptResponse{
+		EmployeeID:     r.EmployeeID.String(),
+		EmployeeName:   r.EmployeeName,
+		IsRead:         r.IsRead,
+		ReadAt:         r.ReadAt,
+		AcknowledgedAt: r.AcknowledgedAt,
+		ReminderCount:  r.ReminderCount,
+	}
+}
+
+func toReceivedMemoResponse(m *models.Memo, r *models.MemoRecipient) *dto.ReceivedMemoResponse {
+	return &dto.ReceivedMemoResponse{
+		ID:                      m.ID.String(),
+		SenderID:                m.EmployeeID.String(),
+		MemoType:                m.MemoType,
+		Title:                   m.Title,
+		Content:                 m.Content,
+		ReferenceNumber:         m.ReferenceNumber,
+		Priority:                m.Priority,
+		RequiresAcknowledgement: m.RequiresAcknowledgement,
+		PublishedAt:             m.PublishedAt,
+		IsRead:                  r.IsRead,
+		ReadAt:                  r.ReadAt,
+		AcknowledgedAt:          r.AcknowledgedAt,
+	}
+}
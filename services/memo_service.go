@@ -0,0 +1,456 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// memoReferenceTokens are the placeholders a reference pattern may use.
+var memoReferenceTokens = []string{"{YYYY}", "{YY}", "{MM}", "{SEQ}"}
+
+const memoApprovalEntity = "memo"
+
+type MemoService struct {
+	employeeRepo        *repositories.EmployeeRepository
+	memoRepo            *repositories.MemoRepository
+	approvalHistoryRepo *repositories.ApprovalHistoryRepository
+	notificationService *NotificationService
+	approvalService     *ApprovalService
+}
+
+func NewMemoService(
+	employeeRepo *repositories.EmployeeRepository,
+	memoRepo *repositories.MemoRepository,
+	approvalHistoryRepo *repositories.ApprovalHistoryRepository,
+	notificationService *NotificationService,
+	approvalService *ApprovalService,
+) *MemoService {
+	s := &MemoService{
+		employeeRepo:        employeeRepo,
+		memoRepo:            memoRepo,
+		approvalHistoryRepo: approvalHistoryRepo,
+		notificationService: notificationService,
+		approvalService:     approvalService,
+	}
+	approvalService.RegisterTarget(memoApprovalEntity, s)
+	return s
+}
+
+// CreateMemo saves a draft memo sent by the acting employee.
+func (s *MemoService) CreateMemo(ctx context.Context, companyID uuid.UUID, actorID *uuid.UUID, req *dto.CreateMemoRequest) (*dto.MemoResponse, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, *actorID); err != nil {
+		return nil, err
+	}
+
+	memo := &models.Memo{
+		CompanyID:               companyID,
+		EmployeeID:              *actorID,
+		MemoType:                req.MemoType,
+		Title:                   strings.TrimSpace(req.Title),
+		Content:                 strings.TrimSpace(req.Content),
+		Priority:                req.Priority,
+		RequiresAcknowledgement: req.RequiresAcknowledgement || req.MemoType == "disciplinary",
+	}
+	if memo.Priority == "" {
+		memo.Priority = "normal"
+	}
+	if err := checkMemoFields(memo); err != nil {
+		return nil, err
+	}
+	targets, err := s.buildMemoTargets(ctx, companyID, req.Targets)
+	if err != nil {
+		return nil, err
+	}
+	memo.Targets = targets
+	if memo.SubjectEmployeeID, err = parseOptionalUUID(req.SubjectID); err != nil {
+		return nil, &utils.ValidationError{Field: "subject_id", Message: "invalid subject_id"}
+	}
+	if err := s.checkMemoSubject(ctx, memo); err != nil {
+		return nil, err
+	}
+
+	created, err := s.memoRepo.CreateMemo(ctx, memo)
+	if err != nil {
+		return nil, err
+	}
+	return toMemoResponse(created), nil
+}
+
+// GetMemo returns a memo. Drafts are only visible to their sender and
+// disciplinary memos only to those canSeeDisciplinaryMemo allows.
+func (s *MemoService) GetMemo(ctx context.Context, companyID, memoID uuid.UUID, actorID *uuid.UUID) (*dto.MemoResponse, error) {
+	memo, err := s.getVisibleMemo(ctx, companyID, memoID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	return toMemoResponse(memo), nil
+}
+
+// ListMemos returns the company's memos, with the acting employee's own
+// drafts, optionally narrowed by sender, status and type. Disciplinary memos
+// are never listed here; see ListDisciplinaryMemos.
+func (s *MemoService) ListMemos(ctx context.Context, companyID uuid.UUID, actorID *uuid.UUID, senderID, status, memoType string) ([]*dto.MemoResponse, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	sender, err := parseOptionalUUID(senderID)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: "sender_id", Message: "invalid sender_id"}
+	}
+
+	memos, err := s.memoRepo.ListMemos(ctx, companyID, *actorID, sender, status, memoType)
+	if err != nil {
+		return nil, err
+	}
+	return toMemoResponses(memos), nil
+}
+
+// UpdateMemo edits a draft, or a memo its approvers sent back for changes.
+// Only its sender can edit it, and the type of a memo sent back is fixed.
+func (s *MemoService) UpdateMemo(ctx context.Context, companyID, memoID uuid.UUID, actorID *uuid.UUID, req *dto.UpdateMemoRequest) (*dto.MemoResponse, error) {
+	memo, err := s.senderMemo(ctx, companyID, memoID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if memo.Status != "draft" && memo.Status != "changes_requested" {
+		return nil, &utils.ValidationError{Field: "status", Message: fmt.Sprintf("a %s memo cannot be edited", memo.Status)}
+	}
+
+	if req.MemoType != nil {
+		if memo.Status != "draft" && *req.MemoType != memo.MemoType {
+			return nil, &utils.ValidationError{Field: "memo_type", Message: "the type of a submitted memo cannot be changed"}
+		}
+		memo.MemoType = *req.MemoType
+	}
+	if req.Title != nil {
+		memo.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Content != nil {
+		memo.Content = strings.TrimSpace(*req.Content)
+	}
+	if req.Priority != nil {
+		memo.Priority = *req.Priority
+	}
+	if req.RequiresAcknowledgement != nil {
+		memo.RequiresAcknowledgement = *req.RequiresAcknowledgement
+	}
+	memo.RequiresAcknowledgement = memo.RequiresAcknowledgement || memo.MemoType == "disciplinary"
+	if err := checkMemoFields(memo); err != nil {
+		return nil, err
+	}
+	if req.Targets != nil {
+		if memo.Targets, err = s.buildMemoTargets(ctx, companyID, *req.Targets); err != nil {
+			return nil, err
+		}
+	}
+	if req.SubjectID != nil {
+		if memo.SubjectEmployeeID, err = parseOptionalUUID(*req.SubjectID); err != nil {
+			return nil, &utils.ValidationError{Field: "subject_id", Message: "invalid subject_id"}
+		}
+	} else if memo.MemoType != "disciplinary" {
+		memo.SubjectEmployeeID = nil
+	}
+	if err := s.checkMemoSubject(ctx, memo); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.memoRepo.EditMemo(ctx, memo)
+	if errors.Is(err, repositories.ErrMemoStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: "the memo can no longer be edited"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toMemoResponse(updated), nil
+}
+
+// PublishMemo numbers a memo, sends it to everyone its targets cover and
+// notifies them. Only its sender can publish it: straight from draft, or
+// once approved when memoNeedsApproval says it must go through approval
+// first.
+func (s *MemoService) PublishMemo(ctx context.Context, companyID, memoID uuid.UUID, actorID *uuid.UUID) (*dto.MemoResponse, error) {
+	memo, err := s.senderMemo(ctx, companyID, memoID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	needsApproval, err := s.requiresApproval(ctx, memo)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMemoPublishable(memo, needsApproval); err != nil {
+		return nil, err
+	}
+
+	published, err := s.memoRepo.PublishMemo(ctx, memo.ID, memo.Status, formatMemoReference)
+	switch {
+	case errors.Is(err, repositories.ErrMemoStatusChanged):
+		return nil, &utils.ValidationError{Field: "status", Message: "the memo was changed by someone else; reload and try again"}
+	case errors.Is(err, repositories.ErrMemoHasNoRecipients):
+		return nil, &utils.ValidationError{Field: "targets", Message: err.Error()}
+	case err != nil:
+		return nil, err
+	}
+
+	s.notifyRecipients(ctx, published)
+	return toMemoResponse(published), nil
+}
+
+func (s *MemoService) notifyRecipients(ctx context.Context, memo *models.Memo) {
+	recipientIDs, err := s.memoRepo.ListMemoRecipientIDs(ctx, memo.ID)
+	if err != nil {
+		return
+	}
+
+	body := memo.ReferenceNumber
+	if sender, err := s.employeeRepo.GetEmployeeByID(ctx, memo.EmployeeID); err == nil {
+		body += " from " + sender.FirstName + " " + sender.LastName
+	}
+	s.notificationService.Notify(ctx, models.Notification{
+		CompanyID:  memo.CompanyID,
+		Type:       "memo_published",
+		Title:      memo.Title,
+		Body:       body,
+		EntityType: "memo",
+		EntityID:   &memo.ID,
+		DedupeKey:  "memo_published:" + memo.ID.String(),
+	}, recipientIDs...)
+}
+
+func (s *MemoService) GetMemoSettings(ctx context.Context, companyID uuid.UUID) (*dto.MemoSettingsResponse, error) {
+	pattern, err := s.memoRepo.GetMemoReferencePattern(ctx, companyID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCompanyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toMemoSettingsResponse(pattern), nil
+}
+
+// SetMemoSettings changes the company's reference pattern. Memos already
+// published keep their numbers. Only HR can change it.
+func (s *MemoService) SetMemoSettings(ctx context.Context, companyID uuid.UUID, actorID *uuid.UUID, req *dto.MemoSettingsRequest) (*dto.MemoSettingsResponse, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	ok, err := isHR(ctx, s.employeeRepo, companyID, *actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, forbidden("only HR can change memo settings")
+	}
+
+	pattern := strings.TrimSpace(req.ReferencePattern)
+	if err := validateMemoReferencePattern(pattern); err != nil {
+		return nil, err
+	}
+
+	err = s.memoRepo.SetMemoReferencePattern(ctx, companyID, pattern)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCompanyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toMemoSettingsResponse(pattern), nil
+}
+
+// getVisibleMemo returns a memo of the company that actorID may see.
+func (s *MemoService) getVisibleMemo(ctx context.Context, companyID, memoID uuid.UUID, actorID *uuid.UUID) (*models.Memo, error) {
+	memo, err := s.memoRepo.GetMemoByID(ctx, memoID)
+	if err != nil || memo.CompanyID != companyID {
+		return nil, ErrMemoNotFound
+	}
+	if memo.Status == "draft" && (actorID == nil || *actorID != memo.EmployeeID) {
+		return nil, ErrMemoNotFound
+	}
+	if memo.MemoType == "disciplinary" {
+		if actorID == nil {
+			return nil, ErrMemoNotFound
+		}
+		ok, err := s.canSeeDisciplinaryMemo(ctx, memo, *actorID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrMemoNotFound
+		}
+	}
+	return memo, nil
+}
+
+// senderMemo returns a memo of the company sent by actorID.
+func (s *MemoService) senderMemo(ctx context.Context, companyID, memoID uuid.UUID, actorID *uuid.UUID) (*models.Memo, error) {
+	if actorID == nil {
+		return nil, &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	memo, err := s.getVisibleMemo(ctx, companyID, memoID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if memo.EmployeeID != *actorID {
+		return nil, forbidden("only the sender can change this memo")
+	}
+	return memo, nil
+}
+
+// buildMemoTargets parses targets and checks that each one is the
+// company's. Repeated targets are kept once.
+func (s *MemoService) buildMemoTargets(ctx context.Context, companyID uuid.UUID, reqs []dto.MemoTargetRequest) ([]*models.MemoTarget, error) {
+	targets, err := parseMemoTargets(reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, target := range targets {
+		if target.TargetID == nil {
+			continue
+		}
+		exists, err := s.memoRepo.MemoTargetExists(ctx, companyID, target.TargetType, *target.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, &utils.ValidationError{Field: fmt.Sprintf("targets[%d].id", i), Message: target.TargetType + " not found"}
+		}
+	}
+	return targets, nil
+}
+
+// parseMemoTargets parses target requests, dropping repeats. Every target
+// but company names the employee, department, role or level it covers.
+func parseMemoTargets(reqs []dto.MemoTargetRequest) ([]*models.MemoTarget, error) {
+	targets := make([]*models.MemoTarget, 0, len(reqs))
+	seen := make(map[string]bool, len(reqs))
+	for i, req := range reqs {
+		field := fmt.Sprintf("targets[%d]", i)
+		target := &models.MemoTarget{TargetType: req.Type}
+		switch req.Type {
+		case "company":
+			if req.ID != "" {
+				return nil, &utils.ValidationError{Field: field + ".id", Message: "company targets take no id"}
+			}
+		case "individual", "department", "role", "level":
+			id, err := uuid.Parse(req.ID)
+			if err != nil {
+				return nil, &utils.ValidationError{Field: field + ".id", Message: "a valid " + req.Type + " id is required"}
+			}
+			target.TargetID = &id
+		default:
+			return nil, &utils.ValidationError{Field: field + ".type", Message: "type must be one of individual, department, role, level, company"}
+		}
+
+		key := req.Type + ":" + uuidString(target.TargetID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func checkMemoFields(memo *models.Memo) error {
+	switch {
+	case memo.Title == "":
+		return &utils.ValidationError{Field: "title", Message: "title is required"}
+	case memo.Content == "":
+		return &utils.ValidationError{Field: "content", Message: "content is required"}
+	}
+	return nil
+}
+
+// validateMemoReferencePattern checks that pattern numbers memos uniquely:
+// it needs {SEQ}, which restarts every year, and so a year as well. Only
+// the known placeholders may appear in braces.
+func validateMemoReferencePattern(pattern string) error {
+	if pattern == "" {
+		return &utils.ValidationError{Field: "reference_pattern", Message: "reference_pattern is required"}
+	}
+	if !strings.Contains(pattern, "{SEQ}") {
+		return &utils.ValidationError{Field: "reference_pattern", Message: "reference_pattern must contain {SEQ}"}
+	}
+	if !strings.Contains(pattern, "{YYYY}") && !strings.Contains(pattern, "{YY}") {
+		return &utils.ValidationError{Field: "reference_pattern", Message: "reference_pattern must contain {YYYY} or {YY}"}
+	}
+
+	rest := pattern
+	for _, token := range memoReferenceTokens {
+		rest = strings.ReplaceAll(rest, token, "")
+	}
+	if strings.ContainsAny(rest, "{}") {
+		return &utils.ValidationError{
+			Field:   "reference_pattern",
+			Message: "reference_pattern may only use " + strings.Join(memoReferenceTokens, ", "),
+		}
+	}
+	return nil
+}
+
+// formatMemoReference fills in pattern for the sequence-th memo published
+// at at. {SEQ} is padded to four digits.
+func formatMemoReference(pattern string, at time.Time, sequence int) string {
+	return strings.NewReplacer(
+		"{YYYY}", strconv.Itoa(at.Year()),
+		"{YY}", fmt.Sprintf("%02d", at.Year()%100),
+		"{MM}", fmt.Sprintf("%02d", int(at.Month())),
+		"{SEQ}", fmt.Sprintf("%04d", sequence),
+	).Replace(pattern)
+}
+
+func toMemoSettingsResponse(pattern string) *dto.MemoSettingsResponse {
+	return &dto.MemoSettingsResponse{
+		ReferencePattern: pattern,
+		Example:          formatMemoReference(pattern, time.Now(), 1),
+	}
+}
+
+func toMemoResponses(memos []*models.Memo) []*dto.MemoResponse {
+	responses := make([]*dto.MemoResponse, 0, len(memos))
+	for _, m := range memos {
+		responses = append(responses, toMemoResponse(m))
+	}
+	return responses
+}
+
+func toMemoResponse(m *models.Memo) *dto.MemoResponse {
+	targets := make([]*dto.MemoTargetResponse, 0, len(m.Targets))
+	for _, t := range m.Targets {
+		targets = append(targets, &dto.MemoTargetResponse{Type: t.TargetType, ID: uuidStringPtr(t.TargetID)})
+	}
+	return &dto.MemoResponse{
+		ID:                      m.ID.String(),
+		SenderID:                m.EmployeeID.String(),
+		MemoType:                m.MemoType,
+		Title:                   m.Title,
+		Content:                 m.Content,
+		ReferenceNumber:         m.ReferenceNumber,
+		Status:                  m.Status,
+		CurrentStep:             m.CurrentStep,
+		Priority:                m.Priority,
+		Targets:                 targets,
+		RequiresAcknowledgement: m.RequiresAcknowledgement,
+		SubjectID:               uuidStringPtr(m.SubjectEmployeeID),
+		CaseID:                  uuidStringPtr(m.CaseID),
+		RecipientCount:          m.RecipientCount,
+		PublishedAt:             m.PublishedAt,
+		CreatedAt:               m.CreatedAt,
+		UpdatedAt:               m.UpdatedAt,
+	}
+}
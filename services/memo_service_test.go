@@ -0,0 +1,242 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+func TestFormatMemoReference(t *testing.T) {
+	at := time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		pattern  string
+		sequence int
+		want     string
+	}{
+		{"default pattern", "MEMO/{YYYY}/{SEQ}", 42, "MEMO/2026/0042"},
+		{"department prefix", "HR/{YYYY}/{SEQ}", 7, "HR/2026/0007"},
+		{"short year and month", "{YY}{MM}-{SEQ}", 1, "2603-0001"},
+		{"sequence past padding", "HR/{YYYY}/{SEQ}", 12345, "HR/2026/12345"},
+	}
+
+	for _, tt := range tests {
+		if got := formatMemoReference(tt.pattern, at, tt.sequence); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestValidateMemoReferencePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		wantErr bool
+	}{
+		{"default pattern", "MEMO/{YYYY}/{SEQ}", false},
+		{"short year", "{YY}-{MM}-{SEQ}", false},
+		{"no sequence", "HR/{YYYY}", true},
+		{"no year", "HR/{SEQ}", true},
+		{"unknown token", "{DEPT}/{YYYY}/{SEQ}", true},
+		{"stray brace", "HR/{YYYY}/{SEQ}}", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		if err := validateMemoReferencePattern(tt.pattern); (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
+
+func TestParseMemoTargets(t *testing.T) {
+	id := uuid.NewString()
+	tests := []struct {
+		name      string
+		reqs      []dto.MemoTargetRequest
+		want      int
+		wantField string
+	}{
+		{"whole company", []dto.MemoTargetRequest{{Type: "company"}}, 1, ""},
+		{"mixed targets", []dto.MemoTargetRequest{{Type: "department", ID: id}, {Type: "role", ID: id}, {Type: "individual", ID: uuid.NewString()}}, 3, ""},
+		{"repeats kept once", []dto.MemoTargetRequest{{Type: "level", ID: id}, {Type: "level", ID: id}, {Type: "company"}, {Type: "company"}}, 2, ""},
+		{"company with id", []dto.MemoTargetRequest{{Type: "company", ID: id}}, 0, "targets[0].id"},
+		{"missing id", []dto.MemoTargetRequest{{Type: "company"}, {Type: "department"}}, 0, "targets[1].id"},
+		{"unknown type", []dto.MemoTargetRequest{{Type: "team", ID: id}}, 0, "targets[0].type"},
+	}
+
+	for _, tt := range tests {
+		targets, err := parseMemoTargets(tt.reqs)
+		if tt.wantField == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			} else if len(targets) != tt.want {
+				t.Errorf("%s: expected %d targets, got %d", tt.name, tt.want, len(targets))
+			}
+			continue
+		}
+		var validationErr *utils.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+			t.Errorf("%s: expected error on %s, got %v", tt.name, tt.wantField, err)
+		}
+	}
+}
+
+func TestSummarizeMemoDelivery(t *testing.T) {
+	now := time.Now()
+	recipients := []*models.MemoRecipient{
+		{EmployeeID: uuid.New(), EmployeeName: "Ada Obi", IsRead: true, ReadAt: &now, AcknowledgedAt: &now},
+		{EmployeeID: uuid.New(), EmployeeName: "Bola Ade", IsRead: true, ReadAt: &now},
+		{EmployeeID: uuid.New(), EmployeeName: "Chi Eze"},
+	}
+
+	report := summarizeMemoDelivery(&models.Memo{RequiresAcknowledgement: true}, recipients)
+	if report.RecipientCount != 3 || report.ReadCount != 2 || report.UnreadCount != 1 || report.AcknowledgedCount != 1 {
+		t.Errorf("unexpected counts %+v", report)
+	}
+	if len(report.Read) != 2 || len(report.Unread) != 1 || report.Unread[0].EmployeeName != "Chi Eze" {
+		t.Errorf("unexpected read lists %+v %+v", report.Read, report.Unread)
+	}
+	if len(report.Unacknowledged) != 2 {
+		t.Errorf("expected 2 unacknowledged, got %d", len(report.Unacknowledged))
+	}
+
+	report = summarizeMemoDelivery(&models.Memo{}, recipients)
+	if report.AcknowledgedCount != 0 || report.Unacknowledged != nil {
+		t.Errorf("expected no acknowledgement tracking, got %d and %v", report.AcknowledgedCount, report.Unacknowledged)
+	}
+}
+
+func TestMemoThreadEntryKind(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested string
+		isSubject bool
+		want      string
+		wantErr   bool
+	}{
+		{"subject default", "", true, "response", false},
+		{"subject appeals", "appeal", true, "appeal", false},
+		{"subject cannot reply", "reply", true, "", true},
+		{"manager default", "", false, "reply", false},
+		{"manager replies", "reply", false, "reply", false},
+		{"manager cannot appeal", "appeal", false, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := memoThreadEntryKind(tt.requested, tt.isSubject)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		} else if got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestMemoNeedsApproval(t *testing.T) {
+	tests := []struct {
+		name     string
+		memoType string
+		admin    bool
+		want     bool
+	}{
+		{"request from employee", "request", false, true},
+		{"request from admin", "request", true, true},
+		{"announcement from employee", "announcement", false, true},
+		{"announcement from admin", "announcement", true, false},
+		{"general memo", "general", false, false},
+		{"disciplinary memo", "disciplinary", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := memoNeedsApproval(tt.memoType, tt.admin); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCheckMemoPublishable(t *testing.T) {
+	id := uuid.New()
+	targets := []*models.MemoTarget{{TargetType: "individual", TargetID: &id}}
+	tests := []struct {
+		name          string
+		memo          *models.Memo
+		needsApproval bool
+		wantField     string
+	}{
+		{"draft without approval", &models.Memo{Status: "draft", Targets: targets}, false, ""},
+		{"approved memo", &models.Memo{Status: "approved", Targets: targets}, true, ""},
+		{"draft needing approval", &models.Memo{MemoType: "announcement", Status: "draft", Targets: targets}, true, "status"},
+		{"pending memo", &models.Memo{Status: "pending", Targets: targets}, true, "status"},
+		{"rejected memo", &models.Memo{Status: "rejected", Targets: targets}, true, "status"},
+		{"already published", &models.Memo{Status: "published", Targets: targets}, false, "status"},
+		{"approved without targets", &models.Memo{Status: "approved"}, true, "targets"},
+	}
+
+	for _, tt := range tests {
+		err := checkMemoPublishable(tt.memo, tt.needsApproval)
+		if tt.wantField == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		var validationErr *utils.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+			t.Errorf("%s: expected error on %s, got %v", tt.name, tt.wantField, err)
+		}
+	}
+}
+
+func TestCheckMemoAction(t *testing.T) {
+	id := uuid.New()
+	targets := []*models.MemoTarget{{TargetType: "company", TargetID: nil}, {TargetType: "individual", TargetID: &id}}
+	tests := []struct {
+		name          string
+		memo          *models.Memo
+		action        string
+		isSender      bool
+		needsApproval bool
+		comments      string
+		wantField     string
+	}{
+		{"sender submits request", &models.Memo{MemoType: "request", Status: "draft"}, "submit", true, true, "", ""},
+		{"sender submits announcement", &models.Memo{MemoType: "announcement", Status: "draft", Targets: targets}, "submit", true, true, "", ""},
+		{"announcement without targets", &models.Memo{MemoType: "announcement", Status: "draft"}, "submit", true, true, "", "targets"},
+		{"memo needing no approval", &models.Memo{MemoType: "general", Status: "draft"}, "submit", true, false, "", "memo_type"},
+		{"someone else submits", &models.Memo{MemoType: "request", Status: "draft"}, "submit", false, true, "", "actor"},
+		{"sender approves own memo", &models.Memo{Status: "pending"}, "approve", true, true, "", "actor"},
+		{"approver approves", &models.Memo{Status: "pending"}, "approve", false, true, "", ""},
+		{"approve a draft", &models.Memo{Status: "draft"}, "approve", false, true, "", "status"},
+		{"reject without reason", &models.Memo{Status: "pending"}, "reject", false, true, "", "comments"},
+		{"request changes with comments", &models.Memo{Status: "pending"}, "request-changes", false, true, "shorten it", ""},
+		{"resubmit a pending memo", &models.Memo{Status: "pending"}, "resubmit", true, true, "", "status"},
+		{"resubmit after changes", &models.Memo{Status: "changes_requested"}, "resubmit", true, true, "", ""},
+	}
+
+	for _, tt := range tests {
+		err := checkMemoAction(tt.memo, tt.action, tt.isSender, tt.needsApproval, tt.comments)
+		if tt.wantField == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		if tt.wantField == "actor" {
+			if !errors.Is(err, ErrForbidden) {
+				t.Errorf("%s: expected a forbidden error, got %v", tt.name, err)
+			}
+			continue
+		}
+		var validationErr *utils.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+			t.Errorf("%s: expected error on %s, got %v", tt.name, tt.wantField, err)
+		}
+	}
+}
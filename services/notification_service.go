@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+)
+
+// hrRoleName is the system role whose holders receive HR notifications.
+const hrRoleName = "HR Manager"
+
+// isHR reports whether employeeID holds the HR role in the company.
+func isHR(ctx context.Context, employeeRepo *repositories.EmployeeRepository, companyID, employeeID uuid.UUID) (bool, error) {
+	return hasAnyRole(ctx, employeeRepo, companyID, employeeID, hrRoleName)
+}
+
+// hasAnyRole reports whether employeeID holds one of roles in the company.
+func hasAnyRole(ctx context.Context, employeeRepo *repositories.EmployeeRepository, companyID, employeeID uuid.UUID, roles ...string) (bool, error) {
+	for _, role := range roles {
+		holders, err := employeeRepo.GetEmployeesByRoleName(ctx, companyID, role)
+		if err != nil {
+			return false, err
+		}
+		for _, e := range holders {
+			if e.ID == employeeID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+type NotificationService struct {
+	notificationRepo *repositories.NotificationRepository
+	employeeRepo     *repositories.EmployeeRepository
+}
+
+func NewNotificationService(
+	notificationRepo *repositories.NotificationRepository,
+	employeeRepo *repositories.EmployeeRepository,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		employeeRepo:     employeeRepo,
+	}
+}
+
+// Notify sends a copy of notification to every recipient, skipping
+// recipients who already received one with the same dedupe key. It returns
+// the number of notifications actually sent.
+func (s *NotificationService) Notify(ctx context.Context, notification models.Notification, recipientIDs ...uuid.UUID) (int, error) {
+	sent := 0
+	seen := make(map[uuid.UUID]bool, len(recipientIDs))
+	for _, recipientID := range recipientIDs {
+		if seen[recipientID] {
+			continue
+		}
+		seen[recipientID] = true
+
+		n := notification
+		n.RecipientID = recipientID
+		created, err := s.notificationRepo.CreateNotification(ctx, &n)
+		if err != nil {
+			return sent, err
+		}
+		if created {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// NotifyHR sends notification to every HR manager of the company in addition
+// to the explicitly listed recipients.
+func (s *NotificationService) NotifyHR(ctx context.Context, notification models.Notification, recipientIDs ...uuid.UUID) (int, error) {
+	hr, err := s.employeeRepo.GetEmployeesByRoleName(ctx, notification.CompanyID, hrRoleName)
+	if err != nil {
+		return 0, err
+	}
+	for _, employee := range hr {
+		recipientIDs = append(recipientIDs, employee.ID)
+	}
+	if len(recipientIDs) == 0 {
+		log.Printf("no recipients for %s notification in company %s", notification.Type, notification.CompanyID)
+	}
+	return s.Notify(ctx, notification, recipientIDs...)
+}
+
+func (s *NotificationService) ListNotifications(ctx context.Context, companyID, employeeID uuid.UUID, unreadOnly bool) ([]*dto.NotificationResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+
+	notifications, err := s.notificationRepo.ListNotifications(ctx, employeeID, unreadOnly, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.NotificationResponse, 0, len(notifications))
+	for _, n := range notifications {
+		responses = append(responses, &dto.NotificationResponse{
+			ID:         n.ID.String(),
+			Type:       n.Type,
+			Title:      n.Title,
+			Body:       n.Body,
+			EntityType: n.EntityType,
+			EntityID:   uuidStringPtr(n.EntityID),
+			IsRead:     n.IsRead,
+			ReadAt:     n.ReadAt,
+			CreatedAt:  n.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, companyID, employeeID, notificationID uuid.UUID) error {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return err
+	}
+	if err := s.notificationRepo.MarkRead(ctx, notificationID, employeeID); err != nil {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// offboardingItems is the checklist created for every termination. Automated
+// items are completed by ExecuteOffboarding; the rest are tracked by HR.
+var offboardingItems = []models.OffboardingChecklistItem{
+	{Code: "revoke_feed_tokens", Title: "Revoke calendar feed tokens", IsAutomated: true},
+	{Code: "reassign_direct_reports", Title: "Reassign direct reports", IsAutomated: true},
+	{Code: "transfer_hod_posts", Title: "Transfer or clear head of department posts", IsAutomated: true},
+	{Code: "cancel_pending_leave", Title: "Cancel future pending leave requests", IsAutomated: true},
+	{Code: "reassign_approval_steps", Title: "Reassign approval workflow steps", IsAutomated: true},
+	{Code: "revoke_sessions", Title: "Revoke sessions and access tokens"},
+	{Code: "recover_equipment", Title: "Recover company equipment, access cards and keys"},
+	{Code: "disable_external_accounts", Title: "Disable email and third-party accounts"},
+	{Code: "knowledge_transfer", Title: "Complete knowledge transfer"},
+	{Code: "exit_interview", Title: "Conduct exit interview"},
+	{Code: "final_settlement", Title: "Process final pay and benefits settlement"},
+}
+
+type OffboardingService struct {
+	employeeRepo     *repositories.EmployeeRepository
+	offboardingRepo  *repositories.OffboardingRepository
+	lifecycleService *EmployeeLifecycleService
+}
+
+func NewOffboardingService(
+	employeeRepo *repositories.EmployeeRepository,
+	offboardingRepo *repositories.OffboardingRepository,
+	lifecycleService *EmployeeLifecycleService,
+) *OffboardingService {
+	return &OffboardingService{
+		employeeRepo:     employeeRepo,
+		offboardingRepo:  offboardingRepo,
+		lifecycleService: lifecycleService,
+	}
+}
+
+// Initiate terminates an employee and opens their offboarding checklist.
+// When the termination takes effect today the automated steps run straight
+// away; otherwise RunDueOffboardings picks them up once the scheduled
+// termination has been applied.
+func (s *OffboardingService) Initiate(
+	ctx context.Context,
+	companyID, employeeID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.OffboardingRequest,
+) (*dto.OffboardingChecklistResponse, error) {
+	transition, audit, checklist, err := s.prepareOffboarding(ctx, companyID, employeeID, actorID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, created, err := s.offboardingRepo.CreateChecklistWithTransition(ctx, transition, audit, checklist)
+	if errors.Is(err, repositories.ErrEmployeeStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: err.Error()}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startOffboarding(ctx, transition, created)
+}
+
+// prepareOffboarding validates a termination and builds its transition and
+// checklist without storing them. The audit entry is nil when the
+// termination is to be scheduled rather than applied now.
+func (s *OffboardingService) prepareOffboarding(
+	ctx context.Context,
+	companyID, employeeID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.OffboardingRequest,
+) (*models.EmployeeStatusTransition, *models.AuditLog, *models.OffboardingChecklist, error) {
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	checklist := &models.OffboardingChecklist{
+		CompanyID:   companyID,
+		EmployeeID:  employeeID,
+		InitiatedBy: actorID,
+	}
+	if checklist.NewManagerID, err = s.parseSuccessor(ctx, employee, "new_manager_id", req.NewManagerID); err != nil {
+		return nil, nil, nil, err
+	}
+	if checklist.HODSuccessorID, err = s.parseSuccessor(ctx, employee, "hod_successor_id", req.HODSuccessorID); err != nil {
+		return nil, nil, nil, err
+	}
+	if checklist.ApproverSuccessorID, err = s.parseSuccessor(ctx, employee, "approver_successor_id", req.ApproverSuccessorID); err != nil {
+		return nil, nil, nil, err
+	}
+	if checklist.ApproverSuccessorID == nil {
+		checklist.ApproverSuccessorID = checklist.NewManagerID
+	}
+
+	if checklist.NewManagerID == nil {
+		reports, err := s.employeeRepo.GetDirectReports(ctx, employeeID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if len(reports) > 0 {
+			return nil, nil, nil, &utils.ValidationError{Field: "new_manager_id", Message: "new_manager_id is required for employees with direct reports"}
+		}
+	} else {
+		// The new manager may be one of the leaver's direct reports (they move
+		// up a level) but not someone further down their team.
+		below, err := s.employeeRepo.IsInManagementChain(ctx, *checklist.NewManagerID, employeeID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		newManager, err := s.employeeRepo.GetEmployeeByID(ctx, *checklist.NewManagerID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if below && (newManager.ManagerID == nil || *newManager.ManagerID != employeeID) {
+			return nil, nil, nil, &utils.ValidationError{Field: "new_manager_id", Message: "new manager cannot be an indirect report of the leaving employee"}
+		}
+	}
+
+	transition, err := s.lifecycleService.prepareStatusTransition(ctx, companyID, employeeID, actorID, &dto.StatusTransitionRequest{
+		ToStatus:        "terminated",
+		TerminationDate: req.TerminationDate,
+		Reason:          req.Reason,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// A termination effective today or earlier is applied with the
+	// checklist; a later one is scheduled alongside it.
+	var audit *models.AuditLog
+	if !transition.EffectiveDate.After(utils.Today()) {
+		audit = transitionAuditLog(transition, "employee_status_changed", false)
+	}
+
+	checklist.TerminationDate = *transition.TerminationDate
+	for i := range offboardingItems {
+		item := offboardingItems[i]
+		checklist.Items = append(checklist.Items, &item)
+	}
+
+	return transition, audit, checklist, nil
+}
+
+// startOffboarding runs the automated steps of a stored checklist once its
+// termination has been applied and returns the checklist as it stands.
+func (s *OffboardingService) startOffboarding(ctx context.Context, transition *models.EmployeeStatusTransition, checklist *models.OffboardingChecklist) (*dto.OffboardingChecklistResponse, error) {
+	if transition.Status == "applied" {
+		if err := s.execute(ctx, checklist); err != nil {
+			return nil, err
+		}
+		updated, err := s.offboardingRepo.GetChecklistByID(ctx, checklist.ID)
+		if err != nil {
+			return nil, err
+		}
+		checklist = updated
+	}
+
+	return toOffboardingChecklistResponse(checklist), nil
+}
+
+func (s *OffboardingService) GetChecklist(ctx context.Context, companyID, employeeID uuid.UUID) (*dto.OffboardingChecklistResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+
+	checklist, err := s.offboardingRepo.GetLatestChecklistForEmployee(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrChecklistNotFound
+		}
+		return nil, err
+	}
+	return toOffboardingChecklistResponse(checklist), nil
+}
+
+// UpdateChecklistItem completes or skips a manual checklist item.
+func (s *OffboardingService) UpdateChecklistItem(
+	ctx context.Context,
+	companyID, checklistID, itemID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.UpdateChecklistItemRequest,
+) (*dto.OffboardingChecklistResponse, error) {
+	if req.Status != "completed" && req.Status != "skipped" {
+		return nil, &utils.ValidationError{Field: "status", Message: "status must be completed or skipped"}
+	}
+
+	checklist, err := s.offboardingRepo.GetChecklistByID(ctx, checklistID)
+	if err != nil || checklist.CompanyID != companyID {
+		return nil, ErrChecklistNotFound
+	}
+	for _, item := range checklist.Items {
+		if item.ID == itemID && item.IsAutomated {
+			return nil, &utils.ValidationError{Field: "item", Message: "automated items are completed by the system"}
+		}
+	}
+
+	if err := s.offboardingRepo.UpdateItemStatus(ctx, checklistID, itemID, req.Status, req.Notes, actorID); err != nil {
+		return nil, &utils.ValidationError{Field: "item", Message: err.Error()}
+	}
+
+	updated, err := s.offboardingRepo.GetChecklistByID(ctx, checklistID)
+	if err != nil {
+		return nil, err
+	}
+	return toOffboardingChecklistResponse(updated), nil
+}
+
+// RunDueOffboardings runs the automated steps of checklists whose scheduled
+// termination has taken effect. It returns the number processed.
+func (s *OffboardingService) RunDueOffboardings(ctx context.Context, asOf time.Time) (int, error) {
+	due, err := s.offboardingRepo.GetDueChecklists(ctx, asOf)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, checklist := range due {
+		if err := s.execute(ctx, checklist); err != nil {
+			return i, err
+		}
+	}
+
+	if len(due) > 0 {
+		log.Printf("ran automated offboarding for %d employees", len(due))
+	}
+	return len(due), nil
+}
+
+func (s *OffboardingService) execute(ctx context.Context, checklist *models.OffboardingChecklist) error {
+	_, err := s.offboardingRepo.ExecuteOffboarding(ctx, checklist.ID, &models.AuditLog{
+		CompanyID:        checklist.CompanyID,
+		UserID:           checklist.InitiatedBy,
+		TargetEmployeeID: &checklist.EmployeeID,
+		Action:           "employee_offboarded",
+		EntityType:       "offboarding_checklist",
+	})
+	return err
+}
+
+// parseSuccessor validates an optional successor ID: it must name an active
+// employee of the same company other than the leaver.
+func (s *OffboardingService) parseSuccessor(ctx context.Context, leaver *models.Employee, field, value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, &utils.ValidationError{Field: field, Message: "invalid " + field}
+	}
+	if id == leaver.ID {
+		return nil, &utils.ValidationError{Field: field, Message: field + " cannot be the leaving employee"}
+	}
+
+	successor, err := findCompanyEmployee(ctx, s.employeeRepo, leaver.CompanyID, id)
+	if err != nil {
+		if errors.Is(err, ErrEmployeeNotFound) {
+			return nil, &utils.ValidationError{Field: field, Message: field + " not found in this company"}
+		}
+		return nil, err
+	}
+	if successor.Status == "terminated" {
+		return nil, &utils.ValidationError{Field: field, Message: field + " cannot be a terminated employee"}
+	}
+
+	return &id, nil
+}
+
+func toOffboardingChecklistResponse(c *models.OffboardingChecklist) *dto.OffboardingChecklistResponse {
+	items := make([]*dto.OffboardingChecklistItemResponse, 0, len(c.Items))
+	for _, item := range c.Items {
+		items = append(items, &dto.OffboardingChecklistItemResponse{
+			ID:          item.ID.String(),
+			Code:        item.Code,
+			Title:       item.Title,
+			IsAutomated: item.IsAutomated,
+			Status:      item.Status,
+			Notes:       item.Notes,
+			CompletedBy: uuidStringPtr(item.CompletedBy),
+			CompletedAt: item.CompletedAt,
+		})
+	}
+
+	return &dto.OffboardingChecklistResponse{
+		ID:                    c.ID.String(),
+		EmployeeID:            c.EmployeeID.String(),
+		TerminationDate:       c.TerminationDate,
+		NewManagerID:          uuidStringPtr(c.NewManagerID),
+		HODSuccessorID:        uuidStringPtr(c.HODSuccessorID),
+		ApproverSuccessorID:   uuidStringPtr(c.ApproverSuccessorID),
+		Status:                c.Status,
+		AutomationCompletedAt: c.AutomationCompletedAt,
+		CompletedAt:           c.CompletedAt,
+		Items:                 items,
+		CreatedAt:             c.CreatedAt,
+	}
+}
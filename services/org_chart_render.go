@@ -0,0 +1,150 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+)
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ")
+
+// RenderOrgChartDOT renders an org chart as a Graphviz digraph. Photo URLs are
+// attached as node URLs since Graphviz can only embed local images.
+func RenderOrgChartDOT(roots []*dto.OrgChartNode) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("digraph OrgChart {\n")
+	buf.WriteString("\trankdir=TB;\n")
+	buf.WriteString("\tnode [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\", fontname=\"Helvetica\"];\n")
+	buf.WriteString("\tedge [arrowhead=none];\n")
+
+	var walk func(node *dto.OrgChartNode)
+	walk = func(node *dto.OrgChartNode) {
+		lines := orgChartLabel(node)
+		for i, line := range lines {
+			lines[i] = dotEscaper.Replace(line)
+		}
+		fmt.Fprintf(&buf, "\t\"%s\" [label=\"%s\"", node.ID, strings.Join(lines, `\n`))
+		if node.PhotoURL != "" {
+			fmt.Fprintf(&buf, ", URL=\"%s\"", dotEscaper.Replace(node.PhotoURL))
+		}
+		buf.WriteString("];\n")
+
+		for _, child := range node.Children {
+			walk(child)
+			fmt.Fprintf(&buf, "\t\"%s\" -> \"%s\";\n", node.ID, child.ID)
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+const (
+	svgNodeWidth  = 220
+	svgNodeHeight = 72
+	svgPhotoSize  = 48
+	svgHGap       = 24
+	svgVGap       = 56
+	svgMargin     = 20
+)
+
+type svgBox struct {
+	node *dto.OrgChartNode
+	x, y float64
+}
+
+// RenderOrgChartSVG renders an org chart as a standalone SVG document. Leaves
+// are laid out left to right and every parent is centred above its children.
+func RenderOrgChartSVG(roots []*dto.OrgChartNode) []byte {
+	var boxes []*svgBox
+	boxByNode := make(map[*dto.OrgChartNode]*svgBox)
+	nextX := float64(svgMargin)
+	maxDepth := 0
+
+	var place func(node *dto.OrgChartNode, level int) *svgBox
+	place = func(node *dto.OrgChartNode, level int) *svgBox {
+		if level > maxDepth {
+			maxDepth = level
+		}
+		box := &svgBox{node: node, y: float64(svgMargin + level*(svgNodeHeight+svgVGap))}
+		boxes = append(boxes, box)
+		boxByNode[node] = box
+
+		if len(node.Children) == 0 {
+			box.x = nextX
+			nextX += svgNodeWidth + svgHGap
+			return box
+		}
+
+		var first, last *svgBox
+		for i, child := range node.Children {
+			childBox := place(child, level+1)
+			if i == 0 {
+				first = childBox
+			}
+			last = childBox
+		}
+		box.x = (first.x + last.x) / 2
+		return box
+	}
+
+	for _, root := range roots {
+		place(root, 0)
+	}
+
+	width := nextX - svgHGap + svgMargin
+	if len(boxes) == 0 {
+		width = 2 * svgMargin
+	}
+	height := float64(2*svgMargin + (maxDepth+1)*svgNodeHeight + maxDepth*svgVGap)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		width, height, width, height)
+
+	// Connectors first so the boxes are drawn on top of them.
+	for _, box := range boxes {
+		for _, child := range box.node.Children {
+			childBox := boxByNode[child]
+			fromX := box.x + svgNodeWidth/2
+			fromY := box.y + svgNodeHeight
+			toX := childBox.x + svgNodeWidth/2
+			midY := fromY + svgVGap/2
+			fmt.Fprintf(&buf, `  <path d="M%.0f %.0f V%.0f H%.0f V%.0f" fill="none" stroke="#95a5a6" stroke-width="1.5"/>`+"\n",
+				fromX, fromY, midY, toX, childBox.y)
+		}
+	}
+
+	for _, box := range boxes {
+		fmt.Fprintf(&buf, `  <g id="node-%s">`+"\n", box.node.ID)
+		fmt.Fprintf(&buf, `    <rect x="%.0f" y="%.0f" width="%d" height="%d" rx="8" fill="#ffffff" stroke="#34495e" stroke-width="1.5"/>`+"\n",
+			box.x, box.y, svgNodeWidth, svgNodeHeight)
+
+		textX := box.x + 12
+		if box.node.PhotoURL != "" {
+			fmt.Fprintf(&buf, `    <image x="%.0f" y="%.0f" width="%d" height="%d" href="%s" xlink:href="%s" preserveAspectRatio="xMidYMid slice"/>`+"\n",
+				box.x+12, box.y+(svgNodeHeight-svgPhotoSize)/2, svgPhotoSize, svgPhotoSize,
+				html.EscapeString(box.node.PhotoURL), html.EscapeString(box.node.PhotoURL))
+			textX += svgPhotoSize + 10
+		}
+
+		for i, line := range orgChartLabel(box.node) {
+			weight, size := "normal", 12
+			if i == 0 {
+				weight, size = "bold", 14
+			}
+			fmt.Fprintf(&buf, `    <text x="%.0f" y="%.0f" font-size="%d" font-weight="%s" fill="#2c3e50">%s</text>`+"\n",
+				textX, box.y+24+float64(i*18), size, weight, html.EscapeString(line))
+		}
+		buf.WriteString("  </g>\n")
+	}
+
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+type OrgChartService struct {
+	orgChartRepo *repositories.OrgChartRepository
+}
+
+func NewOrgChartService(orgChartRepo *repositories.OrgChartRepository) *OrgChartService {
+	return &OrgChartService{
+		orgChartRepo: orgChartRepo,
+	}
+}
+
+// GetOrgChart loads the people or department hierarchy described by req and
+// returns it as a forest of nested nodes. Rendering to DOT or SVG is left to
+// RenderOrgChartDOT and RenderOrgChartSVG.
+func (s *OrgChartService) GetOrgChart(ctx context.Context, companyID uuid.UUID, req *dto.OrgChartRequest) ([]*dto.OrgChartNode, error) {
+	if req.MaxDepth < 0 {
+		return nil, &utils.ValidationError{Field: "max_depth", Message: "max_depth cannot be negative"}
+	}
+
+	var rootID *uuid.UUID
+	if req.RootID != "" {
+		id, err := uuid.Parse(req.RootID)
+		if err != nil {
+			return nil, &utils.ValidationError{Field: "root_id", Message: "invalid root_id"}
+		}
+		rootID = &id
+	}
+
+	var (
+		nodes    []*models.OrgChartNode
+		nodeType string
+		err      error
+	)
+	switch req.Type {
+	case "", "people":
+		nodeType = "employee"
+		nodes, err = s.orgChartRepo.GetPeopleTree(ctx, companyID, rootID, req.MaxDepth)
+	case "department":
+		nodeType = "department"
+		nodes, err = s.orgChartRepo.GetDepartmentTree(ctx, companyID, rootID, req.MaxDepth)
+	default:
+		return nil, &utils.ValidationError{Field: "type", Message: "type must be people or department"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if rootID != nil && len(nodes) == 0 {
+		if nodeType == "department" {
+			return nil, ErrDepartmentNotFound
+		}
+		return nil, ErrEmployeeNotFound
+	}
+
+	return buildOrgChartTree(nodes, nodeType), nil
+}
+
+// buildOrgChartTree nests flat rows under their parents. Rows whose parent
+// is not part of the result become roots, so a chart rooted mid-hierarchy
+// does not dangle from an absent manager.
+func buildOrgChartTree(nodes []*models.OrgChartNode, nodeType string) []*dto.OrgChartNode {
+	byID := make(map[uuid.UUID]*dto.OrgChartNode, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID] = &dto.OrgChartNode{
+			ID:          node.ID.String(),
+			Type:        nodeType,
+			Name:        node.Name,
+			Designation: node.Designation,
+			PhotoURL:    node.PhotoURL,
+			HeadName:    node.HeadName,
+			Depth:       node.Depth,
+			Children:    []*dto.OrgChartNode{},
+		}
+	}
+
+	roots := []*dto.OrgChartNode{}
+	for _, node := range nodes {
+		current := byID[node.ID]
+		if node.ParentID != nil {
+			if parent, ok := byID[*node.ParentID]; ok && parent != current {
+				parent.Children = append(parent.Children, current)
+				continue
+			}
+		}
+		roots = append(roots, current)
+	}
+
+	return roots
+}
+
+// orgChartLabel returns the text lines shown for a node in rendered charts.
+func orgChartLabel(node *dto.OrgChartNode) []string {
+	lines := []string{node.Name}
+	if node.HeadName != "" {
+		lines = append(lines, fmt.Sprintf("Head: %s", node.HeadName))
+	}
+	if node.Designation != "" {
+		lines = append(lines, node.Designation)
+	}
+	return lines
+}
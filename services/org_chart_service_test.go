@@ -0,0 +1,51 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestBuildOrgChartTree(t *testing.T) {
+	ceoID, ctoID, engID := uuid.New(), uuid.New(), uuid.New()
+	outsideID := uuid.New()
+
+	nodes := []*models.OrgChartNode{
+		{ID: ceoID, ParentID: &outsideID, Depth: 0, Name: "Ada Lovelace", Designation: "CEO"},
+		{ID: ctoID, ParentID: &ceoID, Depth: 1, Name: "Alan Turing", Designation: "CTO"},
+		{ID: engID, ParentID: &ctoID, Depth: 2, Name: "Grace <Hopper>", PhotoURL: "https://example.com/g.png?a=1&b=2"},
+	}
+
+	roots := buildOrgChartTree(nodes, "employee")
+	if len(roots) != 1 {
+		t.Fatalf("expected 1 root, got %d", len(roots))
+	}
+	if roots[0].ID != ceoID.String() {
+		t.Errorf("expected ceo as root, got %s", roots[0].Name)
+	}
+	if len(roots[0].Children) != 1 || len(roots[0].Children[0].Children) != 1 {
+		t.Fatal("expected ceo -> cto -> engineer nesting")
+	}
+
+	dot := string(RenderOrgChartDOT(roots))
+	if !strings.Contains(dot, `"`+ceoID.String()+`" -> "`+ctoID.String()+`"`) {
+		t.Error("expected ceo -> cto edge in DOT output")
+	}
+	if !strings.Contains(dot, `Ada Lovelace\nCEO`) {
+		t.Error("expected name and designation in DOT label")
+	}
+
+	svg := string(RenderOrgChartSVG(roots))
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Error("expected a complete SVG document")
+	}
+	if !strings.Contains(svg, "Grace &lt;Hopper&gt;") {
+		t.Error("expected names to be escaped in SVG")
+	}
+	if !strings.Contains(svg, `href="https://example.com/g.png?a=1&amp;b=2"`) {
+		t.Error("expected photo URL to be embedded and escaped in SVG")
+	}
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+// probationReminderDays is how far ahead the daily job warns managers and HR
+// about probations that are about to end.
+const probationReminderDays = 14
+
+// probationDecisions maps request decisions onto the recorded outcome.
+var probationDecisions = map[string]string{
+	"confirm":   "confirmed",
+	"extend":    "extended",
+	"terminate": "terminated",
+}
+
+type ProbationService struct {
+	employeeRepo        *repositories.EmployeeRepository
+	probationRepo       *repositories.ProbationRepository
+	notificationService *NotificationService
+	lifecycleService    *EmployeeLifecycleService
+	offboardingService  *OffboardingService
+}
+
+func NewProbationService(
+	employeeRepo *repositories.EmployeeRepository,
+	probationRepo *repositories.ProbationRepository,
+	notificationService *NotificationService,
+	lifecycleService *EmployeeLifecycleService,
+	offboardingService *OffboardingService,
+) *ProbationService {
+	return &ProbationService{
+		employeeRepo:        employeeRepo,
+		probationRepo:       probationRepo,
+		notificationService: notificationService,
+		lifecycleService:    lifecycleService,
+		offboardingService:  offboardingService,
+	}
+}
+
+// ListUpcoming returns the company's employees whose probation ends within
+// withinDays, including those already overdue for a decision.
+func (s *ProbationService) ListUpcoming(ctx context.Context, companyID uuid.UUID, withinDays int) ([]*dto.UpcomingProbationResponse, error) {
+	if withinDays < 0 {
+		return nil, &utils.ValidationError{Field: "within_days", Message: "within_days cannot be negative"}
+	}
+
+	today := utils.Today()
+	employees, err := s.probationRepo.GetProbationsEndingBy(ctx, &companyID, today.AddDate(0, 0, withinDays))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.UpcomingProbationResponse, 0, len(employees))
+	for _, employee := range employees {
+		responses = append(responses, &dto.UpcomingProbationResponse{
+			EmployeeID:       employee.ID.String(),
+			EmployeeCode:     employee.EmployeeCode,
+			FullName:         employee.FirstName + " " + employee.LastName,
+			ManagerID:        uuidStringPtr(employee.ManagerID),
+			HireDate:         employee.HireDate,
+			ProbationEndDate: *employee.ProbationEndDate,
+			DaysRemaining:    daysBetween(today, *employee.ProbationEndDate),
+		})
+	}
+	return responses, nil
+}
+
+// NotifyUpcomingExpiries tells each employee's manager and the company's HR
+// managers about probations ending within probationReminderDays of asOf.
+// Each recipient is told once per end date, so extending a probation
+// produces a fresh reminder. It returns the number of notifications sent.
+func (s *ProbationService) NotifyUpcomingExpiries(ctx context.Context, asOf time.Time) (int, error) {
+	employees, err := s.probationRepo.GetProbationsEndingBy(ctx, nil, asOf.AddDate(0, 0, probationReminderDays))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, employee := range employees {
+		endDate := employee.ProbationEndDate.Format(utils.DateLayout)
+		name := employee.FirstName + " " + employee.LastName
+
+		body := fmt.Sprintf("%s's probation ends on %s. Please confirm, extend or terminate.", name, endDate)
+		if employee.ProbationEndDate.Before(asOf) {
+			body = fmt.Sprintf("%s's probation ended on %s and is awaiting a decision.", name, endDate)
+		}
+
+		var recipients []uuid.UUID
+		if employee.ManagerID != nil {
+			recipients = append(recipients, *employee.ManagerID)
+		}
+
+		n, err := s.notificationService.NotifyHR(ctx, models.Notification{
+			CompanyID:  employee.CompanyID,
+			Type:       "probation_expiry",
+			Title:      "Probation review due: " + name,
+			Body:       body,
+			EntityType: "employee",
+			EntityID:   &employee.ID,
+			DedupeKey:  fmt.Sprintf("probation_expiry:%s:%s", employee.ID, endDate),
+		}, recipients...)
+		sent += n
+		if err != nil {
+			return sent, err
+		}
+	}
+
+	if sent > 0 {
+		log.Printf("sent %d probation expiry notifications", sent)
+	}
+	return sent, nil
+}
+
+// Decide records the outcome of an employee's probation. Confirmation moves
+// the employee to active, extension pushes probation_end_date out and
+// termination starts the offboarding process. The review is recorded in the
+// same transaction as the change it decides.
+func (s *ProbationService) Decide(
+	ctx context.Context,
+	companyID, employeeID uuid.UUID,
+	actorID *uuid.UUID,
+	req *dto.ProbationDecisionRequest,
+) (*dto.ProbationReviewResponse, error) {
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := buildProbationReview(employee, req)
+	if err != nil {
+		return nil, err
+	}
+	review.DecidedBy = actorID
+	audit := probationAuditLog(review)
+
+	var recorded *models.ProbationReview
+	switch review.Decision {
+	case "extended":
+		recorded, err = s.probationRepo.ExtendProbation(ctx, review, audit)
+	case "confirmed":
+		var transition *models.EmployeeStatusTransition
+		if transition, err = s.lifecycleService.prepareStatusTransition(ctx, companyID, employeeID, actorID, &dto.StatusTransitionRequest{
+			ToStatus: "active",
+			Reason:   "probation confirmed: " + review.Comments,
+		}); err != nil {
+			return nil, err
+		}
+		recorded, err = s.probationRepo.ConfirmProbation(ctx, transition, transitionAuditLog(transition, "employee_status_changed", false), review, audit)
+	case "terminated":
+		terminationDate := req.TerminationDate
+		if terminationDate == "" {
+			terminationDate = utils.Today().Format(utils.DateLayout)
+		}
+		var transition *models.EmployeeStatusTransition
+		var transitionAudit *models.AuditLog
+		var checklist *models.OffboardingChecklist
+		if transition, transitionAudit, checklist, err = s.offboardingService.prepareOffboarding(ctx, companyID, employeeID, actorID, &dto.OffboardingRequest{
+			TerminationDate: terminationDate,
+			Reason:          "probation not confirmed: " + review.Comments,
+			NewManagerID:    req.NewManagerID,
+		}); err != nil {
+			return nil, err
+		}
+		transition, checklist, recorded, err = s.probationRepo.TerminateProbation(ctx, transition, transitionAudit, checklist, review, audit)
+		if err == nil {
+			_, err = s.offboardingService.startOffboarding(ctx, transition, checklist)
+		}
+	}
+	if errors.Is(err, repositories.ErrEmployeeStatusChanged) {
+		return nil, &utils.ValidationError{Field: "status", Message: "employee is no longer on probation"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toProbationReviewResponse(recorded), nil
+}
+
+func (s *ProbationService) ListReviews(ctx context.Context, companyID, employeeID uuid.UUID) ([]*dto.ProbationReviewResponse, error) {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return nil, err
+	}
+
+	reviews, err := s.probationRepo.ListReviews(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ProbationReviewResponse, 0, len(reviews))
+	for _, r := range reviews {
+		responses = append(responses, toProbationReviewResponse(r))
+	}
+	return responses, nil
+}
+
+// buildProbationReview validates a decision against the employee's current
+// probation.
+func buildProbationReview(employee *models.Employee, req *dto.ProbationDecisionRequest) (*models.ProbationReview, error) {
+	if employee.Status != "probation" {
+		return nil, &utils.ValidationError{Field: "status", Message: "employee is not on probation"}
+	}
+
+	decision, ok := probationDecisions[req.Decision]
+	if !ok {
+		return nil, &utils.ValidationError{Field: "decision", Message: "decision must be confirm, extend or terminate"}
+	}
+
+	review := &models.ProbationReview{
+		CompanyID:       employee.CompanyID,
+		EmployeeID:      employee.ID,
+		Decision:        decision,
+		PreviousEndDate: employee.ProbationEndDate,
+		Comments:        strings.TrimSpace(req.Comments),
+	}
+	if review.Comments == "" {
+		return nil, &utils.ValidationError{Field: "comments", Message: "comments are required for a probation decision"}
+	}
+
+	if decision == "extended" {
+		if req.ExtendUntil == "" {
+			return nil, &utils.ValidationError{Field: "extend_until", Message: "extend_until is required when extending probation"}
+		}
+		extendUntil, err := utils.ParseDate(req.ExtendUntil)
+		if err != nil {
+			return nil, &utils.ValidationError{Field: "extend_until", Message: "extend_until must be YYYY-MM-DD"}
+		}
+		if employee.ProbationEndDate != nil && !extendUntil.After(*employee.ProbationEndDate) {
+			return nil, &utils.ValidationError{Field: "extend_until", Message: "extend_until must be after the current probation end date"}
+		}
+		if !extendUntil.After(employee.HireDate) {
+			return nil, &utils.ValidationError{Field: "extend_until", Message: "extend_until must be after hire_date"}
+		}
+		review.NewEndDate = &extendUntil
+	}
+
+	return review, nil
+}
+
+func probationAuditLog(r *models.ProbationReview) *models.AuditLog {
+	newValues := map[string]any{"decision": r.Decision}
+	if r.NewEndDate != nil {
+		newValues["probation_end_date"] = r.NewEndDate.Format(utils.DateLayout)
+	}
+	oldValues := map[string]any{"probation_end_date": nil}
+	if r.PreviousEndDate != nil {
+		oldValues["probation_end_date"] = r.PreviousEndDate.Format(utils.DateLayout)
+	}
+
+	return &models.AuditLog{
+		CompanyID:        r.CompanyID,
+		UserID:           r.DecidedBy,
+		TargetEmployeeID: &r.EmployeeID,
+		Action:           "probation_" + r.Decision,
+		EntityType:       "probation_review",
+		OldValues:        oldValues,
+		NewValues:        newValues,
+		Metadata:         map[string]any{"comments": r.Comments},
+	}
+}
+
+// daysBetween returns the number of whole days from one date to another,
+// negative when to is earlier.
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func toProbationReviewResponse(r *models.ProbationReview) *dto.ProbationReviewResponse {
+	return &dto.ProbationReviewResponse{
+		ID:              r.ID.String(),
+		EmployeeID:      r.EmployeeID.String(),
+		Decision:        r.Decision,
+		PreviousEndDate: r.PreviousEndDate,
+		NewEndDate:      r.NewEndDate,
+		Comments:        r.Comments,
+		DecidedBy:       uuidStringPtr(r.DecidedBy),
+		CreatedAt:       r.CreatedAt,
+	}
+}
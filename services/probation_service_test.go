@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestBuildProbationReview(t *testing.T) {
+	endDate := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	employee := &models.Employee{
+		ID:               uuid.New(),
+		CompanyID:        uuid.New(),
+		Status:           "probation",
+		HireDate:         time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		ProbationEndDate: &endDate,
+	}
+
+	if _, err := buildProbationReview(employee, &dto.ProbationDecisionRequest{Decision: "confirm"}); err == nil {
+		t.Error("expected decision without comments to fail")
+	}
+
+	if _, err := buildProbationReview(employee, &dto.ProbationDecisionRequest{Decision: "promote", Comments: "great"}); err == nil {
+		t.Error("expected unknown decision to fail")
+	}
+
+	if _, err := buildProbationReview(employee, &dto.ProbationDecisionRequest{Decision: "extend", Comments: "needs time"}); err == nil {
+		t.Error("expected extension without extend_until to fail")
+	}
+
+	if _, err := buildProbationReview(employee, &dto.ProbationDecisionRequest{Decision: "extend", Comments: "needs time", ExtendUntil: "2026-04-01"}); err == nil {
+		t.Error("expected extension before the current end date to fail")
+	}
+
+	review, err := buildProbationReview(employee, &dto.ProbationDecisionRequest{Decision: "extend", Comments: " needs time ", ExtendUntil: "2026-05-15"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if review.Decision != "extended" || review.Comments != "needs time" {
+		t.Errorf("unexpected review: %+v", review)
+	}
+	if review.NewEndDate == nil || !review.NewEndDate.Equal(time.Date(2026, 5, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected new end date 2026-05-15, got %v", review.NewEndDate)
+	}
+	if review.PreviousEndDate == nil || !review.PreviousEndDate.Equal(endDate) {
+		t.Errorf("expected previous end date %v, got %v", endDate, review.PreviousEndDate)
+	}
+
+	employee.Status = "active"
+	if _, err := buildProbationReview(employee, &dto.ProbationDecisionRequest{Decision: "confirm", Comments: "ok"}); err == nil {
+		t.Error("expected decision for an employee not on probation to fail")
+	}
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/falasefemi2/companyflowlow/dto"
+	"github.com/falasefemi2/companyflowlow/models"
+	"github.com/falasefemi2/companyflowlow/repositories"
+	"github.com/falasefemi2/companyflowlow/utils"
+)
+
+const (
+	// maxTeamCalendarDays bounds the range of a single team calendar query.
+	maxTeamCalendarDays = 366
+	// defaultMaxAbsentPercent is the share of a team that may be away on the
+	// same day before the calendar warns about it.
+	defaultMaxAbsentPercent = 30
+	// calendarFeedPastDays and calendarFeedFutureDays bound the leave
+	// included in an iCalendar feed relative to today.
+	calendarFeedPastDays   = 90
+	calendarFeedFutureDays = 365
+)
+
+type TeamCalendarService struct {
+	employeeRepo   *repositories.EmployeeRepository
+	departmentRepo *repositories.DepartmentRepository
+	calendarRepo   *repositories.LeaveCalendarRepository
+	holidayService *HolidayService
+}
+
+func NewTeamCalendarService(
+	employeeRepo *repositories.EmployeeRepository,
+	departmentRepo *repositories.DepartmentRepository,
+	calendarRepo *repositories.LeaveCalendarRepository,
+	holidayService *HolidayService,
+) *TeamCalendarService {
+	return &TeamCalendarService{
+		employeeRepo:   employeeRepo,
+		departmentRepo: departmentRepo,
+		calendarRepo:   calendarRepo,
+		holidayService: holidayService,
+	}
+}
+
+// TeamCalendar lists the approved (and optionally pending) leave of a
+// manager's reports or a department's members between from and to, with a
+// per-day count of who is away and a warning for every working day on which
+// more than MaxAbsent people are.
+func (s *TeamCalendarService) TeamCalendar(ctx context.Context, companyID uuid.UUID, req *dto.TeamCalendarRequest, from, to time.Time) (*dto.TeamCalendarResponse, error) {
+	if to.Before(from) {
+		return nil, &utils.ValidationError{Field: "to", Message: "to cannot be before from"}
+	}
+	if to.Sub(from) >= maxTeamCalendarDays*24*time.Hour {
+		return nil, &utils.ValidationError{Field: "to", Message: fmt.Sprintf("date range cannot exceed %d days", maxTeamCalendarDays)}
+	}
+	if req.MaxAbsent < 0 {
+		return nil, &utils.ValidationError{Field: "max_absent", Message: "max_absent cannot be negative"}
+	}
+
+	team, calendar, err := s.team(ctx, companyID, req, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []string{"approved"}
+	if req.IncludePending {
+		statuses = append(statuses, "pending")
+	}
+
+	var entries []*models.TeamLeaveEntry
+	if len(team) > 0 {
+		ids := make([]uuid.UUID, 0, len(team))
+		for _, member := range team {
+			ids = append(ids, member.ID)
+		}
+		entries, err = s.calendarRepo.ListTeamLeave(ctx, ids, statuses, from, to)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	maxAbsent := req.MaxAbsent
+	if maxAbsent == 0 {
+		maxAbsent = defaultMaxAbsent(len(team))
+	}
+
+	response := &dto.TeamCalendarResponse{
+		From:      from.Format(utils.DateLayout),
+		To:        to.Format(utils.DateLayout),
+		TeamSize:  len(team),
+		MaxAbsent: maxAbsent,
+		Entries:   make([]*dto.TeamLeaveEntryResponse, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, toTeamLeaveEntryResponse(entry))
+	}
+	response.Days, response.Warnings = teamAbsences(entries, calendar, from, to, len(team), maxAbsent)
+	return response, nil
+}
+
+// team resolves the members of the requested team and the work calendar
+// their absences are counted against: the manager's for a manager's reports
+// and the department's for a department.
+func (s *TeamCalendarService) team(ctx context.Context, companyID uuid.UUID, req *dto.TeamCalendarRequest, from, to time.Time) ([]*models.Employee, *WorkCalendar, error) {
+	switch {
+	case req.ManagerID != "" && req.DepartmentID != "":
+		return nil, nil, &utils.ValidationError{Field: "manager_id", Message: "use either manager_id or department_id, not both"}
+
+	case req.ManagerID != "":
+		managerID, err := uuid.Parse(req.ManagerID)
+		if err != nil {
+			return nil, nil, &utils.ValidationError{Field: "manager_id", Message: "invalid manager_id"}
+		}
+		manager, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, managerID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		maxDepth := 0
+		if req.DirectOnly {
+			maxDepth = 1
+		}
+		reports, err := s.employeeRepo.GetAllReports(ctx, manager.ID, maxDepth)
+		if err != nil {
+			return nil, nil, err
+		}
+		team := make([]*models.Employee, 0, len(reports))
+		for _, report := range reports {
+			team = append(team, &report.Employee)
+		}
+
+		calendar, err := s.holidayService.WorkCalendarFor(ctx, manager, from, to)
+		if err != nil {
+			return nil, nil, err
+		}
+		return team, calendar, nil
+
+	case req.DepartmentID != "":
+		departmentID, err := uuid.Parse(req.DepartmentID)
+		if err != nil {
+			return nil, nil, &utils.ValidationError{Field: "department_id", Message: "invalid department_id"}
+		}
+		department, err := s.departmentRepo.GetDepartmentByID(ctx, departmentID)
+		if err != nil || department.CompanyID != companyID {
+			return nil, nil, ErrDepartmentNotFound
+		}
+
+		team, err := s.calendarRepo.ListDepartmentMembers(ctx, department.ID, req.IncludeSubDepartments)
+		if err != nil {
+			return nil, nil, err
+		}
+		calendar, err := s.holidayService.DepartmentWorkCalendar(ctx, companyID, department.ID, from, to)
+		if err != nil {
+			return nil, nil, err
+		}
+		return team, calendar, nil
+
+	default:
+		return nil, nil, &utils.ValidationError{Field: "manager_id", Message: "manager_id or department_id is required"}
+	}
+}
+
+// calendarFeedAdminRoles may manage any employee's calendar feed.
+var calendarFeedAdminRoles = []string{"Super Admin", hrRoleName}
+
+// RotateCalendarFeed issues a new private iCalendar feed token for an
+// employee, revoking any previous one, and returns the subscription URL
+// under feedBaseURL. Only the employee themselves or HR may do so.
+func (s *TeamCalendarService) RotateCalendarFeed(
+	ctx context.Context,
+	companyID, employeeID uuid.UUID,
+	actorID *uuid.UUID,
+	feedBaseURL string,
+) (*dto.CalendarFeedResponse, error) {
+	employee, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCalendarFeedAccess(ctx, companyID, employeeID, actorID); err != nil {
+		return nil, err
+	}
+	if employee.Status == "terminated" {
+		return nil, &utils.ValidationError{Field: "employee", Message: "terminated employees cannot subscribe to calendars"}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	createdAt, err := s.calendarRepo.SetCalendarFeedToken(ctx, employee.ID, hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CalendarFeedResponse{
+		URL:       feedBaseURL + "/calendar-feeds/" + token + ".ics",
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// RevokeCalendarFeed disables an employee's calendar feed. Only the
+// employee themselves or HR may do so.
+func (s *TeamCalendarService) RevokeCalendarFeed(ctx context.Context, companyID, employeeID uuid.UUID, actorID *uuid.UUID) error {
+	if _, err := findCompanyEmployee(ctx, s.employeeRepo, companyID, employeeID); err != nil {
+		return err
+	}
+	if err := s.checkCalendarFeedAccess(ctx, companyID, employeeID, actorID); err != nil {
+		return err
+	}
+	err := s.calendarRepo.DeleteCalendarFeedToken(ctx, employeeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCalendarFeedNotFound
+	}
+	return err
+}
+
+// checkCalendarFeedAccess returns ErrCalendarFeedForbidden unless actorID is
+// the employee or holds one of calendarFeedAdminRoles.
+func (s *TeamCalendarService) checkCalendarFeedAccess(ctx context.Context, companyID, employeeID uuid.UUID, actorID *uuid.UUID) error {
+	if actorID == nil {
+		return &utils.ValidationError{Field: "actor", Message: "the acting employee is required"}
+	}
+	if *actorID == employeeID {
+		return nil
+	}
+	admin, err := hasAnyRole(ctx, s.employeeRepo, companyID, *actorID, calendarFeedAdminRoles...)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return ErrCalendarFeedForbidden
+	}
+	return nil
+}
+
+// CalendarFeed renders the iCalendar feed behind token: the owner's own
+// leave and that of everyone reporting to them, approved or pending.
+func (s *TeamCalendarService) CalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	owner, err := s.calendarRepo.GetCalendarFeedEmployee(ctx, hashFeedToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.employeeRepo.GetAllReports(ctx, owner.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	ids := []uuid.UUID{owner.ID}
+	for _, report := range reports {
+		ids = append(ids, report.ID)
+	}
+
+	today := utils.Today()
+	entries, err := s.calendarRepo.ListTeamLeave(ctx, ids, []string{"approved", "pending"},
+		today.AddDate(0, 0, -calendarFeedPastDays), today.AddDate(0, 0, calendarFeedFutureDays))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*icalFeedEvent, 0, len(entries))
+	for _, entry := range entries {
+		events = append(events, leaveFeedEvent(entry))
+	}
+	name := fmt.Sprintf("Leave – %s %s", owner.FirstName, owner.LastName)
+	return writeICalendar(name, events, time.Now()), nil
+}
+
+// hashFeedToken returns the value stored for a feed token, so a leaked
+// database does not expose working subscription URLs.
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// defaultMaxAbsent is defaultMaxAbsentPercent of teamSize, and at least one.
+func defaultMaxAbsent(teamSize int) int {
+	return max(1, teamSize*defaultMaxAbsentPercent/100)
+}
+
+// teamAbsences counts, for every working day between from and to, the team
+// members on leave, and warns about the days on which more than maxAbsent
+// are. Half days count as away. Days nobody is away are omitted.
+func teamAbsences(entries []*models.TeamLeaveEntry, calendar *WorkCalendar, from, to time.Time, teamSize, maxAbsent int) ([]*dto.TeamCalendarDayResponse, []string) {
+	days := []*dto.TeamCalendarDayResponse{}
+	warnings := []string{}
+
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !calendar.IsWorkingDay(d) {
+			continue
+		}
+
+		var away []string
+		seen := map[uuid.UUID]bool{}
+		for _, entry := range entries {
+			if d.Before(entry.StartDate) || d.After(entry.EndDate) || seen[entry.EmployeeID] {
+				continue
+			}
+			seen[entry.EmployeeID] = true
+			away = append(away, entry.EmployeeID.String())
+		}
+		if len(away) == 0 {
+			continue
+		}
+
+		day := &dto.TeamCalendarDayResponse{
+			Date:        d.Format(utils.DateLayout),
+			Absent:      len(away),
+			EmployeeIDs: away,
+			OverLimit:   len(away) > maxAbsent,
+		}
+		days = append(days, day)
+		if day.OverLimit {
+			warnings = append(warnings, fmt.Sprintf("%s: %d of %d team members are away (limit %d)", day.Date, day.Absent, teamSize, maxAbsent))
+		}
+	}
+	return days, warnings
+}
+
+func leaveFeedEvent(entry *models.TeamLeaveEntry) *icalFeedEvent {
+	summary := fmt.Sprintf("%s – %s", entry.EmployeeName, entry.LeaveTypeName)
+	if entry.Status == "pending" {
+		summary += " (pending)"
+	}
+	return &icalFeedEvent{
+		UID:         entry.RequestID.String() + "@companyflow",
+		Summary:     summary,
+		Description: fmt.Sprintf("%g working day(s), %s", entry.DaysRequested, entry.Status),
+		Start:       entry.StartDate,
+		End:         entry.EndDate,
+		Tentative:   entry.Status == "pending",
+	}
+}
+
+func toTeamLeaveEntryResponse(e *models.TeamLeaveEntry) *dto.TeamLeaveEntryResponse {
+	return &dto.TeamLeaveEntryResponse{
+		RequestID:     e.RequestID.String(),
+		EmployeeID:    e.EmployeeID.String(),
+		EmployeeName:  e.EmployeeName,
+		LeaveTypeID:   e.LeaveTypeID.String(),
+		LeaveTypeName: e.LeaveTypeName,
+		ColorCode:     e.ColorCode,
+		StartDate:     e.StartDate,
+		EndDate:       e.EndDate,
+		StartHalfDay:  e.StartHalfDay,
+		EndHalfDay:    e.EndHalfDay,
+		DaysRequested: e.DaysRequested,
+		Status:        e.Status,
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/falasefemi2/companyflowlow/models"
+)
+
+func TestTeamAbsences(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC) }
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+	calendar := NewWorkCalendar(defaultWorkWeek)
+	calendar.AddHoliday(day(4), "Founders' Day")
+
+	entries := []*models.TeamLeaveEntry{
+		{EmployeeID: alice, StartDate: day(2), EndDate: day(6)},
+		{EmployeeID: bob, StartDate: day(3), EndDate: day(3), StartHalfDay: true},
+		{EmployeeID: carol, StartDate: day(3), EndDate: day(9)},
+		// A second request of the same employee must not count twice.
+		{EmployeeID: carol, StartDate: day(3), EndDate: day(3)},
+	}
+
+	days, warnings := teamAbsences(entries, calendar, day(2), day(9), 10, 2)
+
+	// Mar 2 (Mon) to Mar 9 (Mon) without the weekend and the holiday.
+	want := map[string]int{"2026-03-02": 1, "2026-03-03": 3, "2026-03-05": 2, "2026-03-06": 2, "2026-03-09": 1}
+	if len(days) != len(want) {
+		t.Fatalf("expected %d days, got %d", len(want), len(days))
+	}
+	for _, d := range days {
+		if d.Absent != want[d.Date] {
+			t.Errorf("%s: expected %d absent, got %d", d.Date, want[d.Date], d.Absent)
+		}
+		if d.OverLimit != (d.Absent > 2) {
+			t.Errorf("%s: unexpected over_limit %v", d.Date, d.OverLimit)
+		}
+	}
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "2026-03-03: 3 of 10") {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+}
+
+func TestDefaultMaxAbsent(t *testing.T) {
+	for size, want := range map[int]int{0: 1, 3: 1, 10: 3, 25: 7} {
+		if got := defaultMaxAbsent(size); got != want {
+			t.Errorf("team of %d: expected %d, got %d", size, want, got)
+		}
+	}
+}
+
+func TestWriteICalendar(t *testing.T) {
+	events := []*icalFeedEvent{{
+		UID:       "abc@companyflow",
+		Summary:   "Ada Lovelace – Annual; long, " + strings.Repeat("é", 40),
+		Start:     time.Date(2026, time.December, 30, 0, 0, 0, 0, time.UTC),
+		End:       time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC),
+		Tentative: true,
+	}}
+	out := string(writeICalendar("Leave", events, time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)))
+
+	for _, want := range []string{
+		"DTSTART;VALUE=DATE:20261230\r\n",
+		"DTEND;VALUE=DATE:20270101\r\n",
+		"DTSTAMP:20261001T090000Z\r\n",
+		`Annual\; long\, `,
+		"STATUS:TENTATIVE\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > icalMaxLineOctets {
+			t.Errorf("line longer than %d octets: %q", icalMaxLineOctets, line)
+		}
+	}
+
+	// The feed must read back through the importer's parser.
+	parsed, err := parseICalEvents([]byte(out))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(parsed) != 1 || parsed[0].Summary != "Ada Lovelace – Annual; long, "+strings.Repeat("é", 40) {
+		t.Errorf("unexpected round trip %+v", parsed)
+	}
+}
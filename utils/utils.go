@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
 
@@ -43,10 +45,37 @@ func ParseIntParam(r *http.Request, key string) (int, error) {
 	return id, nil
 }
 
+func ParseUUIDParam(r *http.Request, key string) (uuid.UUID, error) {
+	vars := mux.Vars(r)
+	idStr, ok := vars[key]
+	if !ok {
+		return uuid.Nil, errors.New("missing path parameter")
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid path parameter")
+	}
+
+	return id, nil
+}
+
 func DecodeJSONBody(r *http.Request, v interface{}) error {
 	return json.NewDecoder(r.Body).Decode(v)
 }
 
+// DateLayout is the format used for calendar dates in requests (YYYY-MM-DD).
+const DateLayout = "2006-01-02"
+
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse(DateLayout, value)
+}
+
+// Today returns the current UTC date at midnight.
+func Today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
 func IsValidEmail(email string) bool {
 	pattern := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 	re := regexp.MustCompile(pattern)